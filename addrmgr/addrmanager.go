@@ -16,6 +16,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -55,6 +56,7 @@ type serializedKnownAddress struct {
 	Addr        string
 	Src         string
 	Attempts    int
+	Failures    int
 	TimeStamp   int64
 	LastAttempt int64
 	LastSuccess int64
@@ -70,8 +72,9 @@ type serializedAddrManager struct {
 }
 
 type localAddress struct {
-	na    *wire.NetAddress
-	score AddressPriority
+	na       *wire.NetAddress
+	score    AddressPriority
+	priority AddressPriority // Highest priority the address was added with.
 }
 
 // LocalAddr represents network address information for a local address.
@@ -102,6 +105,24 @@ const (
 	ManualPrio
 )
 
+// addressPriorityStrings is a map of address priorities back to their constant
+// names for pretty printing.
+var addressPriorityStrings = map[AddressPriority]string{
+	InterfacePrio: "interface",
+	BoundPrio:     "bound",
+	UpnpPrio:      "upnp",
+	HTTPPrio:      "http",
+	ManualPrio:    "manual",
+}
+
+// String returns the AddressPriority in human-readable form.
+func (p AddressPriority) String() string {
+	if s, ok := addressPriorityStrings[p]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown AddressPriority (%d)", int(p))
+}
+
 const (
 	// needAddressThreshold is the number of addresses under which the
 	// address manager will claim to need more addresses.
@@ -395,6 +416,7 @@ func (a *AddrManager) savePeers() {
 		ska.TimeStamp = v.na.Timestamp.Unix()
 		ska.Src = NetAddressKey(v.srcAddr)
 		ska.Attempts = v.attempts
+		ska.Failures = v.failures
 		ska.LastAttempt = v.lastattempt.Unix()
 		ska.LastSuccess = v.lastsuccess.Unix()
 		// Tried and refs are implicit in the rest of the structure
@@ -500,6 +522,7 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 				"%s: %v", v.Src, err)
 		}
 		ka.attempts = v.Attempts
+		ka.failures = v.Failures
 		ka.lastattempt = time.Unix(v.LastAttempt, 0)
 		ka.lastsuccess = time.Unix(v.LastSuccess, 0)
 		a.addrIndex[NetAddressKey(ka.na)] = ka
@@ -645,6 +668,59 @@ func (a *AddrManager) numAddresses() int {
 	return a.nTried + a.nNew
 }
 
+// AddressCounts returns the number of tried and new addresses known to the
+// address manager.
+func (a *AddrManager) AddressCounts() (numTried, numNew int) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	return a.nTried, a.nNew
+}
+
+// AddrManagerStats houses statistics about the addresses known to the address
+// manager.
+type AddrManagerStats struct {
+	// New is the number of addresses in the new set.
+	New int
+
+	// Tried is the number of addresses in the tried set.
+	Tried int
+
+	// Total is the total number of addresses in both sets.
+	Total int
+
+	// IPv4, IPv6, and Onion are the number of known addresses of each network
+	// type.
+	IPv4  int
+	IPv6  int
+	Onion int
+}
+
+// Stats returns statistics about the addresses known to the address manager.
+// All counts are computed under the address manager lock so they are
+// consistent with each other.
+func (a *AddrManager) Stats() AddrManagerStats {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	stats := AddrManagerStats{
+		New:   a.nNew,
+		Tried: a.nTried,
+		Total: a.numAddresses(),
+	}
+	for _, ka := range a.addrIndex {
+		switch getNetwork(ka.na) {
+		case IPv4Address:
+			stats.IPv4++
+		case IPv6Address:
+			stats.IPv6++
+		case OnionAddress:
+			stats.Onion++
+		}
+	}
+	return stats
+}
+
 // NeedMoreAddresses returns whether or not the address manager needs more
 // addresses.
 func (a *AddrManager) NeedMoreAddresses() bool {
@@ -700,6 +776,95 @@ func (a *AddrManager) AddressCache() []*wire.NetAddress {
 	return allAddr[0:numAddresses]
 }
 
+// GoodAddresses returns a random sample of up to the provided number of known
+// routable addresses that have been successfully connected to and are not
+// considered low quality.  All such addresses are returned when the count is
+// zero.  The returned addresses are copies, so they may be freely modified by
+// the caller.
+func (a *AddrManager) GoodAddresses(count int) []*wire.NetAddress {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	goodAddrs := make([]*wire.NetAddress, 0, len(a.addrIndex))
+	for _, v := range a.addrIndex {
+		// Skip low quality addresses and addresses that never succeeded.
+		if v.isBad() || v.lastsuccess.IsZero() {
+			continue
+		}
+
+		// Skip addresses that can't be connected to by other nodes.
+		if !IsRoutable(v.na) {
+			continue
+		}
+
+		na := *v.na
+		goodAddrs = append(goodAddrs, &na)
+	}
+
+	numAddrs := len(goodAddrs)
+	if count == 0 || count > numAddrs {
+		count = numAddrs
+	}
+
+	// Fisher-Yates shuffle the array.  Only the first count entries need to
+	// be shuffled since the rest are thrown away.
+	for i := 0; i < count; i++ {
+		j := a.rand.Intn(numAddrs-i) + i
+		goodAddrs[i], goodAddrs[j] = goodAddrs[j], goodAddrs[i]
+	}
+
+	return goodAddrs[:count]
+}
+
+// KnownAddressInfo describes an address known to the address manager along
+// with the information the address manager tracks about it.
+type KnownAddressInfo struct {
+	// Addr is the known address.  Its timestamp is the last time the
+	// address was seen.
+	Addr wire.NetAddress
+
+	// Source is the address of the peer the address was learned from.
+	Source wire.NetAddress
+
+	// LastAttempt is the last time a connection to the address was
+	// attempted.  It is the zero time when it has never been attempted.
+	LastAttempt time.Time
+
+	// Tried is whether the address is in the tried set as opposed to the
+	// new set.
+	Tried bool
+}
+
+// KnownAddresses returns information about up to the provided number of
+// addresses known to the address manager ordered by the time they were last
+// seen from most to least recent.  All addresses are returned when the count is
+// zero.
+func (a *AddrManager) KnownAddresses(count int) []KnownAddressInfo {
+	a.mtx.Lock()
+	infos := make([]KnownAddressInfo, 0, len(a.addrIndex))
+	for _, ka := range a.addrIndex {
+		infos = append(infos, KnownAddressInfo{
+			Addr:        *ka.na,
+			Source:      *ka.srcAddr,
+			LastAttempt: ka.lastattempt,
+			Tried:       ka.tried,
+		})
+	}
+	a.mtx.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		ti, tj := infos[i].Addr.Timestamp, infos[j].Addr.Timestamp
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return NetAddressKey(&infos[i].Addr) < NetAddressKey(&infos[j].Addr)
+	})
+	if count > 0 && count < len(infos) {
+		infos = infos[:count]
+	}
+	return infos
+}
+
 // reset resets the address manager by reinitialising the random source
 // and allocating fresh empty bucket storage.
 func (a *AddrManager) reset() {
@@ -805,35 +970,60 @@ func (a *AddrManager) GetAddress() *KnownAddress {
 			}
 			factor *= 1.2
 		}
-	} else {
-		// New node.
-		for {
-			// Pick a random bucket.
-			bucket := a.rand.Intn(len(a.addrNew))
-			if len(a.addrNew[bucket]) == 0 {
-				continue
-			}
+	}
 
-			// Then, a random entry in it.
-			var ka *KnownAddress
-			nth := a.rand.Intn(len(a.addrNew[bucket]))
-			for _, value := range a.addrNew[bucket] {
-				if nth == 0 {
-					ka = value
-				}
-				nth--
-			}
-			randval := a.rand.Intn(large)
-			if float64(randval) < (factor * ka.chance() * float64(large)) {
-				log.Tracef("Selected %v from new bucket",
-					NetAddressKey(ka.na))
-				return ka
+	// New node.
+	return a.pickNew()
+}
+
+// pickNew returns a random address from the new table with preference given
+// to ones that have not been used recently.
+//
+// This function MUST be called with the address manager lock held and at least
+// one address in the new table.
+func (a *AddrManager) pickNew() *KnownAddress {
+	large := 1 << 30
+	factor := 1.0
+	for {
+		// Pick a random bucket.
+		bucket := a.rand.Intn(len(a.addrNew))
+		if len(a.addrNew[bucket]) == 0 {
+			continue
+		}
+
+		// Then, a random entry in it.
+		var ka *KnownAddress
+		nth := a.rand.Intn(len(a.addrNew[bucket]))
+		for _, value := range a.addrNew[bucket] {
+			if nth == 0 {
+				ka = value
 			}
-			factor *= 1.2
+			nth--
+		}
+		randval := a.rand.Intn(large)
+		if float64(randval) < (factor * ka.chance() * float64(large)) {
+			log.Tracef("Selected %v from new bucket",
+				NetAddressKey(ka.na))
+			return ka
 		}
+		factor *= 1.2
 	}
 }
 
+// GetNewTableAddress returns a single random address from the new table, which
+// houses addresses that have not been successfully connected to yet, or nil
+// when there are none.  It is intended for feeler connections that test
+// whether addresses are reachable so they may be promoted to the tried table.
+func (a *AddrManager) GetNewTableAddress() *KnownAddress {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.nNew == 0 {
+		return nil
+	}
+	return a.pickNew()
+}
+
 func (a *AddrManager) find(addr *wire.NetAddress) *KnownAddress {
 	return a.addrIndex[NetAddressKey(addr)]
 }
@@ -883,6 +1073,50 @@ func (a *AddrManager) Connected(addr *wire.NetAddress) {
 	}
 }
 
+// Failed records a failed connection to the given address, which includes
+// connections that were established but did not complete the version
+// handshake.  Addresses in the new table, which have never been successfully
+// connected to, are evicted from the address manager once they fail the
+// provided threshold number of consecutive times in order to limit the
+// effectiveness of flooding it with unreachable addresses.  A threshold of 0
+// disables eviction.  It returns whether or not the address was evicted.  If
+// the address is unknown to the address manager it will be ignored.
+func (a *AddrManager) Failed(addr *wire.NetAddress, threshold int) bool {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.find(addr)
+	if ka == nil {
+		return false
+	}
+
+	ka.mtx.Lock()
+	ka.failures++
+	failures := ka.failures
+	ka.mtx.Unlock()
+	a.addrChanged = true
+
+	// Addresses in the tried table have been successfully connected to
+	// before, so they are only deprioritized by their failed attempts.
+	if ka.tried || threshold <= 0 || failures < threshold {
+		return false
+	}
+
+	// Remove the address from all new buckets and the index.
+	addrKey := NetAddressKey(addr)
+	for i := range a.addrNew {
+		if _, ok := a.addrNew[i][addrKey]; ok {
+			delete(a.addrNew[i], addrKey)
+			ka.refs--
+		}
+	}
+	a.nNew--
+	delete(a.addrIndex, addrKey)
+	log.Debugf("Evicted address %s after %d consecutive failed connections",
+		addrKey, failures)
+	return true
+}
+
 // Good marks the given address as good.  To be called after a successful
 // connection and version exchange.  If the address is unknown to the address
 // manager it will be ignored.
@@ -901,6 +1135,7 @@ func (a *AddrManager) Good(addr *wire.NetAddress) {
 	ka.lastsuccess = now
 	ka.lastattempt = now
 	ka.attempts = 0
+	ka.failures = 0
 
 	// move to tried set, optionally evicting other addresses if needed.
 	if ka.tried {
@@ -1010,10 +1245,12 @@ func (a *AddrManager) AddLocalAddress(na *wire.NetAddress, priority AddressPrior
 	if !ok || la.score < priority {
 		if ok {
 			la.score = priority + 1
+			la.priority = priority
 		} else {
 			a.localAddresses[key] = &localAddress{
-				na:    na,
-				score: priority,
+				na:       na,
+				score:    priority,
+				priority: priority,
 			}
 		}
 	}
@@ -1048,6 +1285,45 @@ func (a *AddrManager) FetchLocalAddresses() []LocalAddr {
 	return addrs
 }
 
+// LocalAddressInfo describes a local address known to the address manager
+// along with the priority information used to select the address to advertise.
+type LocalAddressInfo struct {
+	// Addr is the local address.
+	Addr wire.NetAddress
+
+	// Priority is the highest priority the address was added with, which
+	// identifies how it was discovered.
+	Priority AddressPriority
+
+	// Score is the score used when selecting the best local address to
+	// advertise.  It is higher than the priority when the address was
+	// discovered multiple times.
+	Score int32
+}
+
+// LocalAddresses returns information about all local addresses known to the
+// address manager ordered from highest to lowest score.
+func (a *AddrManager) LocalAddresses() []LocalAddressInfo {
+	a.lamtx.Lock()
+	infos := make([]LocalAddressInfo, 0, len(a.localAddresses))
+	for _, la := range a.localAddresses {
+		infos = append(infos, LocalAddressInfo{
+			Addr:     *la.na,
+			Priority: la.priority,
+			Score:    int32(la.score),
+		})
+	}
+	a.lamtx.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Score != infos[j].Score {
+			return infos[i].Score > infos[j].Score
+		}
+		return NetAddressKey(&infos[i].Addr) < NetAddressKey(&infos[j].Addr)
+	})
+	return infos
+}
+
 const (
 	// Unreachable represents a publicly unreachable connection state
 	// between two addresses.
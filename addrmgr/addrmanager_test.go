@@ -374,12 +374,244 @@ func TestGood(t *testing.T) {
 		t.Errorf("Number of addresses is too many: %d vs %d", numAddrs, addrsToAdd)
 	}
 
+	numTried, numNew := n.AddressCounts()
+	if numTried == 0 || numTried+numNew != numAddrs {
+		t.Errorf("Unexpected address counts: got %d tried and %d new, want "+
+			"%d total", numTried, numNew, numAddrs)
+	}
+
 	numCache := len(n.AddressCache())
 	if numCache >= numAddrs/4 {
 		t.Errorf("Number of addresses in cache: got %d, want %d", numCache, numAddrs/4)
 	}
 }
 
+func TestFailed(t *testing.T) {
+	n := New("testfailed", lookupFunc)
+	const threshold = 3
+
+	// Ensure failures for unknown addresses are ignored.
+	unknown := wire.NewNetAddressIPPort(net.IPv4(173, 144, 173, 110), 9108, 0)
+	if n.Failed(unknown, threshold) {
+		t.Fatal("Failed: evicted unknown address")
+	}
+
+	// Add two new addresses and mark one of them good.
+	newAddr := wire.NewNetAddressIPPort(net.IPv4(173, 144, 1, 1), 9108, 0)
+	triedAddr := wire.NewNetAddressIPPort(net.IPv4(174, 144, 1, 1), 9108, 0)
+	srcAddr := wire.NewNetAddressIPPort(net.IPv4(173, 144, 173, 111), 9108, 0)
+	n.AddAddresses([]*wire.NetAddress{newAddr, triedAddr}, srcAddr)
+	n.Good(triedAddr)
+
+	// Ensure a success resets the consecutive failures.
+	for i := 0; i < threshold-1; i++ {
+		if n.Failed(newAddr, threshold) {
+			t.Fatalf("Failed: evicted address after %d failures", i+1)
+		}
+	}
+	n.Good(newAddr)
+	n.Failed(newAddr, threshold)
+	if ka := n.find(newAddr); ka == nil || ka.failures != 1 {
+		t.Fatalf("Failed: consecutive failures not reset by success")
+	}
+
+	// Ensure addresses in the tried table are never evicted and a threshold
+	// of 0 disables eviction.
+	for i := 0; i < threshold*2; i++ {
+		if n.Failed(triedAddr, threshold) {
+			t.Fatal("Failed: evicted tried address")
+		}
+	}
+	n = New("testfailed", lookupFunc)
+	n.AddAddress(newAddr, srcAddr)
+	for i := 0; i < threshold*2; i++ {
+		if n.Failed(newAddr, 0) {
+			t.Fatal("Failed: evicted address with eviction disabled")
+		}
+	}
+
+	// Ensure addresses in the new table are evicted once they reach the
+	// threshold.
+	n = New("testfailed", lookupFunc)
+	n.AddAddress(newAddr, srcAddr)
+	for i := 0; i < threshold-1; i++ {
+		if n.Failed(newAddr, threshold) {
+			t.Fatalf("Failed: evicted address after %d failures", i+1)
+		}
+	}
+	if !n.Failed(newAddr, threshold) {
+		t.Fatal("Failed: address not evicted after reaching threshold")
+	}
+	if numTried, numNew := n.AddressCounts(); numTried != 0 || numNew != 0 {
+		t.Fatalf("Failed: unexpected address counts after eviction -- got "+
+			"%d tried and %d new, want none", numTried, numNew)
+	}
+	if n.GetAddress() != nil {
+		t.Fatal("Failed: evicted address is still returned")
+	}
+}
+
+func TestGoodAddresses(t *testing.T) {
+	n := New("testgoodaddresses", lookupFunc)
+
+	// Ensure no addresses are returned from an empty address manager.
+	if addrs := n.GoodAddresses(0); len(addrs) != 0 {
+		t.Fatalf("GoodAddresses: got %d addresses from empty manager",
+			len(addrs))
+	}
+
+	// Add some addresses and only mark half of them good.
+	const numAddrs = 20
+	addrs := make([]*wire.NetAddress, numAddrs)
+	for i := 0; i < numAddrs; i++ {
+		s := fmt.Sprintf("173.144.%d.%d:9108", i+1, i+1)
+		var err error
+		addrs[i], err = n.DeserializeNetAddress(s)
+		if err != nil {
+			t.Fatalf("Failed to turn %s into an address: %v", s, err)
+		}
+	}
+	srcAddr := wire.NewNetAddressIPPort(net.IPv4(173, 144, 173, 111), 9108, 0)
+	n.AddAddresses(addrs, srcAddr)
+	good := make(map[string]struct{})
+	for _, addr := range addrs[:numAddrs/2] {
+		n.Good(addr)
+		good[NetAddressKey(addr)] = struct{}{}
+	}
+
+	// Ensure only the good addresses are returned when requesting all of
+	// them.
+	goodAddrs := n.GoodAddresses(0)
+	if len(goodAddrs) != len(good) {
+		t.Fatalf("GoodAddresses: wrong number of addresses -- got %d, "+
+			"want %d", len(goodAddrs), len(good))
+	}
+	for _, addr := range goodAddrs {
+		if _, ok := good[NetAddressKey(addr)]; !ok {
+			t.Fatalf("GoodAddresses: unexpected address %s",
+				NetAddressKey(addr))
+		}
+	}
+
+	// Ensure the number of returned addresses is limited by the count.
+	if got := len(n.GoodAddresses(3)); got != 3 {
+		t.Fatalf("GoodAddresses: wrong number of addresses -- got %d, "+
+			"want 3", got)
+	}
+
+	// Ensure modifying a returned address does not modify the address
+	// manager.
+	goodAddrs[0].Port = 1
+	for _, addr := range n.GoodAddresses(0) {
+		if addr.Port == 1 {
+			t.Fatal("GoodAddresses: returned address is not a copy")
+		}
+	}
+}
+
+func TestKnownAddresses(t *testing.T) {
+	n := New("testknownaddresses", lookupFunc)
+
+	// Ensure no addresses are returned from an empty address manager.
+	if infos := n.KnownAddresses(0); len(infos) != 0 {
+		t.Fatalf("KnownAddresses: got %d addresses from empty manager",
+			len(infos))
+	}
+
+	// Add some addresses with increasing timestamps and mark one of them
+	// good so it moves to the tried set.
+	const numAddrs = 5
+	addrs := make([]*wire.NetAddress, numAddrs)
+	for i := 0; i < numAddrs; i++ {
+		s := fmt.Sprintf("173.144.%d.%d:9108", i+1, i+1)
+		var err error
+		addrs[i], err = n.DeserializeNetAddress(s)
+		if err != nil {
+			t.Fatalf("Failed to turn %s into an address: %v", s, err)
+		}
+		addrs[i].Timestamp = time.Unix(int64(1e9+i), 0)
+	}
+	srcAddr := wire.NewNetAddressIPPort(net.IPv4(173, 144, 173, 111), 9108, 0)
+	n.AddAddresses(addrs, srcAddr)
+	n.Good(addrs[2])
+
+	// Ensure all addresses are returned ordered from most to least recently
+	// seen along with their source and set.
+	infos := n.KnownAddresses(0)
+	if len(infos) != numAddrs {
+		t.Fatalf("KnownAddresses: wrong number of addresses -- got %d, "+
+			"want %d", len(infos), numAddrs)
+	}
+	for i, info := range infos {
+		want := addrs[numAddrs-1-i]
+		if NetAddressKey(&info.Addr) != NetAddressKey(want) {
+			t.Fatalf("KnownAddresses: unexpected address at index %d -- "+
+				"got %s, want %s", i, NetAddressKey(&info.Addr),
+				NetAddressKey(want))
+		}
+		if NetAddressKey(&info.Source) != NetAddressKey(srcAddr) {
+			t.Fatalf("KnownAddresses: unexpected source -- got %s, want %s",
+				NetAddressKey(&info.Source), NetAddressKey(srcAddr))
+		}
+		wantTried := want == addrs[2]
+		if info.Tried != wantTried {
+			t.Fatalf("KnownAddresses: unexpected tried flag for %s -- got "+
+				"%v, want %v", NetAddressKey(&info.Addr), info.Tried,
+				wantTried)
+		}
+	}
+
+	// Ensure the number of returned addresses is limited by the count.
+	if got := len(n.KnownAddresses(3)); got != 3 {
+		t.Fatalf("KnownAddresses: wrong number of addresses -- got %d, "+
+			"want 3", got)
+	}
+}
+
+// TestStats ensures the address manager statistics report the expected number
+// of addresses in each set and of each network type.
+func TestStats(t *testing.T) {
+	n := New("teststats", lookupFunc)
+
+	// Ensure an empty address manager reports no addresses.
+	if stats := n.Stats(); stats != (AddrManagerStats{}) {
+		t.Fatalf("Stats: unexpected stats for empty manager -- got %+v",
+			stats)
+	}
+
+	// Add a mix of address types and mark one of them good so it moves to the
+	// tried set.
+	addrStrs := []string{
+		"173.144.1.1:9108",
+		"173.144.2.2:9108",
+		"[2001:470::1]:9108",
+		"aaaaaaaaaaaaaaaa.onion:9108",
+	}
+	addrs := make([]*wire.NetAddress, 0, len(addrStrs))
+	for _, s := range addrStrs {
+		addr, err := n.DeserializeNetAddress(s)
+		if err != nil {
+			t.Fatalf("Failed to turn %s into an address: %v", s, err)
+		}
+		addrs = append(addrs, addr)
+	}
+	srcAddr := wire.NewNetAddressIPPort(net.IPv4(173, 144, 173, 111), 9108, 0)
+	n.AddAddresses(addrs, srcAddr)
+	n.Good(addrs[0])
+
+	want := AddrManagerStats{
+		New:   3,
+		Tried: 1,
+		Total: 4,
+		IPv4:  2,
+		IPv6:  1,
+		Onion: 1,
+	}
+	if stats := n.Stats(); stats != want {
+		t.Fatalf("Stats: unexpected stats -- got %+v, want %+v", stats, want)
+	}
+}
+
 func TestGetAddress(t *testing.T) {
 	n := New("testgetaddress", lookupFunc)
 
@@ -417,6 +649,36 @@ func TestGetAddress(t *testing.T) {
 	}
 }
 
+func TestGetNewTableAddress(t *testing.T) {
+	n := New("testgetnewtableaddress", lookupFunc)
+
+	// Get an address from an empty set (should error)
+	if rv := n.GetNewTableAddress(); rv != nil {
+		t.Errorf("GetNewTableAddress failed: got: %v want: %v\n", rv, nil)
+	}
+
+	// Add a new address and get it
+	err := n.addAddressByIP(someIP + ":8333")
+	if err != nil {
+		t.Fatalf("Adding address failed: %v", err)
+	}
+	ka := n.GetNewTableAddress()
+	if ka == nil {
+		t.Fatalf("Did not get an address where there is one in the new table")
+	}
+	if ka.NetAddress().IP.String() != someIP {
+		t.Errorf("Wrong IP: got %v, want %v", ka.NetAddress().IP.String(), someIP)
+	}
+
+	// Mark this as a good address which moves it to the tried table and
+	// ensure it is no longer returned.
+	n.Good(ka.NetAddress())
+	if rv := n.GetNewTableAddress(); rv != nil {
+		t.Errorf("GetNewTableAddress returned tried address: got: %v "+
+			"want: %v\n", rv, nil)
+	}
+}
+
 func TestGetBestLocalAddress(t *testing.T) {
 	localAddrs := []wire.NetAddress{
 		{IP: net.ParseIP("192.168.0.100")},
@@ -523,6 +785,68 @@ func TestGetBestLocalAddress(t *testing.T) {
 	*/
 }
 
+func TestLocalAddresses(t *testing.T) {
+	amgr := New("testlocaladdresses", nil)
+
+	// Ensure no addresses are returned when there are no local addresses.
+	if infos := amgr.LocalAddresses(); len(infos) != 0 {
+		t.Fatalf("LocalAddresses: got %d addresses from empty manager",
+			len(infos))
+	}
+
+	// Add local addresses discovered via various methods, including one that
+	// is later discovered again with a higher priority.
+	adds := []struct {
+		ip       string
+		priority AddressPriority
+	}{
+		{"204.124.8.1", InterfacePrio},
+		{"204.124.8.2", BoundPrio},
+		{"204.124.8.3", UpnpPrio},
+		{"204.124.8.4", ManualPrio},
+		{"204.124.8.2", UpnpPrio},
+	}
+	for _, add := range adds {
+		na := wire.NetAddress{IP: net.ParseIP(add.ip), Port: 9108}
+		if err := amgr.AddLocalAddress(&na, add.priority); err != nil {
+			t.Fatalf("AddLocalAddress: unexpected error: %v", err)
+		}
+	}
+
+	// Ensure the addresses are returned ordered by score along with the
+	// highest priority they were added with.
+	tests := []struct {
+		ip       string
+		priority AddressPriority
+		score    int32
+	}{
+		{"204.124.8.4", ManualPrio, int32(ManualPrio)},
+		{"204.124.8.2", UpnpPrio, int32(UpnpPrio) + 1},
+		{"204.124.8.3", UpnpPrio, int32(UpnpPrio)},
+		{"204.124.8.1", InterfacePrio, int32(InterfacePrio)},
+	}
+	infos := amgr.LocalAddresses()
+	if len(infos) != len(tests) {
+		t.Fatalf("LocalAddresses: wrong number of addresses -- got %d, "+
+			"want %d", len(infos), len(tests))
+	}
+	for i, test := range tests {
+		info := infos[i]
+		if !info.Addr.IP.Equal(net.ParseIP(test.ip)) {
+			t.Fatalf("LocalAddresses: unexpected address at index %d -- "+
+				"got %s, want %s", i, info.Addr.IP, test.ip)
+		}
+		if info.Priority != test.priority {
+			t.Fatalf("LocalAddresses: unexpected priority for %s -- got "+
+				"%v, want %v", test.ip, info.Priority, test.priority)
+		}
+		if info.Score != test.score {
+			t.Fatalf("LocalAddresses: unexpected score for %s -- got %d, "+
+				"want %d", test.ip, info.Score, test.score)
+		}
+	}
+}
+
 func TestNetAddressKey(t *testing.T) {
 	addNaTests()
 
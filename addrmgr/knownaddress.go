@@ -19,6 +19,7 @@ type KnownAddress struct {
 	na          *wire.NetAddress
 	srcAddr     *wire.NetAddress
 	attempts    int
+	failures    int // consecutive failed connections since last success
 	lastattempt time.Time
 	lastsuccess time.Time
 	tried       bool
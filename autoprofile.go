@@ -0,0 +1,134 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+const (
+	// autoProfileSampleInterval is the interval at which the runtime stats
+	// are sampled by the automatic profiling watchdog.
+	autoProfileSampleInterval = time.Second * 10
+
+	// autoProfileCooldown is the minimum amount of time that must elapse
+	// between consecutive automatic profiles of the same kind.  It prevents
+	// the watchdog from filling the disk when usage hovers around a
+	// threshold.
+	autoProfileCooldown = time.Minute * 10
+)
+
+// autoProfileTrigger tracks the state of a single threshold monitored by the
+// automatic profiling watchdog.  A profile is only written when the monitored
+// value crosses above the threshold, so a value that remains above it does not
+// produce a stream of profiles.
+type autoProfileTrigger struct {
+	name      string
+	threshold uint64
+	armed     bool
+	lastFired time.Time
+}
+
+// check returns whether or not a profile should be written for the provided
+// sampled value and updates the trigger state accordingly.
+func (t *autoProfileTrigger) check(value uint64, now time.Time) bool {
+	if t.threshold == 0 {
+		return false
+	}
+	if value < t.threshold {
+		t.armed = true
+		return false
+	}
+	if !t.armed || now.Sub(t.lastFired) < autoProfileCooldown {
+		return false
+	}
+	t.armed = false
+	t.lastFired = now
+	return true
+}
+
+// writeAutoProfile writes the named pprof profile to a timestamped file in the
+// provided directory and returns the path of the written file.
+func writeAutoProfile(dir, name string, now time.Time) (string, error) {
+	fileName := filepath.Join(dir, fmt.Sprintf("auto-%s-%s.pprof", name,
+		now.UTC().Format("20060102T150405Z")))
+	f, err := os.Create(fileName)
+	if err != nil {
+		return "", err
+	}
+	err = pprof.Lookup(name).WriteTo(f, 0)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(fileName)
+		return "", err
+	}
+	return fileName, nil
+}
+
+// autoProfileWatchdog periodically samples the runtime memory and goroutine
+// statistics and writes a heap or goroutine profile to the configured
+// directory when the respective configured threshold is crossed.  It must be
+// run as a goroutine and returns when the provided context is canceled.
+func autoProfileWatchdog(ctx context.Context, dir string, heapThreshold, goroutineThreshold uint64) {
+	heapTrigger := autoProfileTrigger{
+		name:      "heap",
+		threshold: heapThreshold,
+		armed:     true,
+	}
+	goroutineTrigger := autoProfileTrigger{
+		name:      "goroutine",
+		threshold: goroutineThreshold,
+		armed:     true,
+	}
+
+	ticker := time.NewTicker(autoProfileSampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		var memStats runtime.MemStats
+		runtime.ReadMemStats(&memStats)
+		numGoroutines := uint64(runtime.NumGoroutine())
+
+		now := time.Now()
+		samples := []struct {
+			trigger *autoProfileTrigger
+			value   uint64
+			desc    string
+		}{
+			{&heapTrigger, memStats.HeapAlloc, fmt.Sprintf("heap "+
+				"allocation of %d MiB", memStats.HeapAlloc/(1024*1024))},
+			{&goroutineTrigger, numGoroutines, fmt.Sprintf("%d "+
+				"goroutines", numGoroutines)},
+		}
+		for _, sample := range samples {
+			if !sample.trigger.check(sample.value, now) {
+				continue
+			}
+
+			fileName, err := writeAutoProfile(dir, sample.trigger.name, now)
+			if err != nil {
+				dcrdLog.Errorf("Unable to write automatic %s profile: %v",
+					sample.trigger.name, err)
+				continue
+			}
+			dcrdLog.Warnf("Wrote %s profile to %s due to %s exceeding the "+
+				"configured threshold", sample.trigger.name, fileName,
+				sample.desc)
+		}
+	}
+}
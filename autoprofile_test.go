@@ -0,0 +1,47 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAutoProfileTrigger ensures the automatic profiling triggers only fire
+// when the threshold is crossed, re-arm once the value drops below it, and
+// respect the cooldown between consecutive profiles.
+func TestAutoProfileTrigger(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name   string
+		value  uint64
+		offset time.Duration
+		want   bool
+	}{
+		{"below threshold", 50, 0, false},
+		{"crosses threshold", 150, autoProfileCooldown, true},
+		{"remains above threshold", 200, autoProfileCooldown * 3, false},
+		{"drops below threshold", 50, autoProfileCooldown * 3, false},
+		{"crosses again within cooldown", 150, autoProfileCooldown + time.Minute, false},
+		{"still above after cooldown", 150, autoProfileCooldown * 4, true},
+		{"re-armed", 50, autoProfileCooldown * 5, false},
+		{"crosses again after cooldown", 100, autoProfileCooldown * 5, true},
+	}
+
+	trigger := autoProfileTrigger{name: "heap", threshold: 100, armed: true}
+	for _, test := range tests {
+		got := trigger.check(test.value, start.Add(test.offset))
+		if got != test.want {
+			t.Fatalf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+
+	// Ensure a disabled trigger never fires.
+	disabled := autoProfileTrigger{name: "goroutine", armed: true}
+	if disabled.check(1<<32, start) {
+		t.Fatal("disabled trigger fired")
+	}
+}
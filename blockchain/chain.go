@@ -146,6 +146,8 @@ type BlockChain struct {
 	timeSource          MedianTimeSource
 	notifications       NotificationCallback
 	sigCache            *txscript.SigCache
+	utxoCache           *utxoCache
+	assumeValid         *chaincfg.Checkpoint
 	indexManager        IndexManager
 	interrupt           <-chan struct{}
 
@@ -162,6 +164,18 @@ type BlockChain struct {
 	noVerify      bool
 	noCheckpoints bool
 
+	// batchWrites tracks whether or not the database is currently batching
+	// writes due to the chain not being current.  It is protected by the
+	// chain lock.
+	batchWrites bool
+
+	// assumeValidHeaders houses the hashes of the block headers that are
+	// known to lead to the assumed valid block keyed by their height.  It
+	// allows the ancestors of the assumed valid block to skip script
+	// validation before it has been connected.  It is protected by the chain
+	// lock.
+	assumeValidHeaders map[int64]chainhash.Hash
+
 	// These fields are related to the memory block index.  They both have
 	// their own locks, however they are often also protected by the chain
 	// lock to help prevent logic races when blocks are being processed.
@@ -771,6 +785,13 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *dcrutil.Block,
 		return err
 	}
 
+	// Update the utxo cache to reflect the modifications that were committed
+	// to the database prior to committing the view since that clears the
+	// flags that track which entries were modified.
+	if b.utxoCache != nil {
+		b.utxoCache.commit(view)
+	}
+
 	// Prune fully spent entries and mark all entries in the view unmodified
 	// now that the modifications have been committed to the database.
 	view.commit()
@@ -942,6 +963,13 @@ func (b *BlockChain) disconnectBlock(node *blockNode, block, parent *dcrutil.Blo
 		return err
 	}
 
+	// Update the utxo cache to reflect the modifications that were committed
+	// to the database prior to committing the view since that clears the
+	// flags that track which entries were modified.
+	if b.utxoCache != nil {
+		b.utxoCache.commit(view)
+	}
+
 	// Prune fully spent entries and mark all entries in the view unmodified
 	// now that the modifications have been committed to the database.
 	view.commit()
@@ -1060,7 +1088,7 @@ func (b *BlockChain) reorganizeChainInternal(targetTip *blockNode) error {
 	// utxos created by the blocks.  In addition, if a block votes against its
 	// parent, the regular transactions are reconnected.
 	tip := b.bestChain.Tip()
-	view := NewUtxoViewpoint()
+	view := b.newUtxoViewpoint()
 	view.SetBestHash(&tip.hash)
 	var nextBlockToDetach *dcrutil.Block
 	for tip != nil && tip != fork {
@@ -1415,7 +1443,7 @@ func (b *BlockChain) connectBestChain(node *blockNode, block, parent *dcrutil.Bl
 		// flushed when a valid block is connected, and the worst case
 		// scenario if a block is invalid is it would need to be
 		// revalidated after a restart.
-		view := NewUtxoViewpoint()
+		view := b.newUtxoViewpoint()
 		view.SetBestHash(parentHash)
 		var stxos []spentTxOut
 		if !fastAdd {
@@ -1431,7 +1459,13 @@ func (b *BlockChain) connectBestChain(node *blockNode, block, parent *dcrutil.Bl
 		}
 		if !isKnownValid {
 			b.index.SetStatusFlags(node, statusValid)
-			b.flushBlockIndexWarnOnly()
+
+			// Avoid a separate database transaction for the status
+			// change when batching writes since connecting the block
+			// below writes the modified block index entries as well.
+			if !b.batchWrites {
+				b.flushBlockIndexWarnOnly()
+			}
 		}
 
 		// In the fast add case the code to check the block connection
@@ -1539,6 +1573,39 @@ func (b *BlockChain) isCurrent() bool {
 	return tip.timestamp >= minus24Hours
 }
 
+// maybeUpdateBatchWrites enables batched database writes when the chain is not
+// current, such as during the initial block download, and disables them once
+// it becomes current, provided the database supports batching.
+//
+// Batching writes trades durability for throughput since an unexpected
+// shutdown rolls the database back to the most recent time it was written to
+// persistent storage.  This is acceptable while the chain is not current
+// because it only means the affected blocks have to be connected again.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) maybeUpdateBatchWrites() {
+	batcher, ok := b.db.(database.BatchWriter)
+	if !ok {
+		return
+	}
+
+	batchWrites := !b.isCurrent()
+	if batchWrites == b.batchWrites {
+		return
+	}
+	if err := batcher.SetBatchMode(batchWrites); err != nil {
+		log.Warnf("Unable to update database batch mode: %v", err)
+		return
+	}
+	b.batchWrites = batchWrites
+
+	if batchWrites {
+		log.Infof("Batching database writes until the chain is current")
+		return
+	}
+	log.Infof("Chain is current -- database writes are no longer batched")
+}
+
 // IsCurrent returns whether or not the chain believes it is current.  Several
 // factors are used to guess, but the key factors that allow the chain to
 // believe it is current are:
@@ -2022,6 +2089,33 @@ type Config struct {
 	// signature cache.
 	SigCache *txscript.SigCache
 
+	// UtxoCacheMaxSize is the approximate maximum size in bytes of the
+	// in-memory cache of unspent transaction outputs that is consulted
+	// before the database when fetching utxos.  Larger values trade memory
+	// for fewer database accesses during validation.
+	//
+	// This field can be zero to disable the cache.
+	UtxoCacheMaxSize uint64
+
+	// AssumeValid specifies a block that is trusted to be valid along with
+	// all of its ancestors.  Transaction script validation, which is by far
+	// the most time consuming portion of block validation, is skipped for
+	// that block and its ancestors, while all other validation is still
+	// performed.  A block at the height of the assumed valid block that has
+	// a different hash is rejected.  Scripts are only skipped for blocks
+	// that are known to lead to the assumed valid block, either because it
+	// is part of the best chain or because their headers were provided via
+	// SetAssumeValidHeaders, so blocks that do not lead to it are always
+	// fully validated.
+	//
+	// This can significantly reduce the time it takes to perform the initial
+	// chain sync, however, it also means that invalid scripts in the skipped
+	// blocks would go unnoticed, so it must only ever be set to a block that
+	// has been independently verified to be valid.
+	//
+	// This field can be nil to validate the scripts of all blocks.
+	AssumeValid *chaincfg.Checkpoint
+
 	// SubsidyCache defines a subsidy cache to use when calculating and
 	// validating block and vote subsidies.
 	//
@@ -2079,6 +2173,7 @@ func New(config *Config) (*BlockChain, error) {
 		timeSource:                    config.TimeSource,
 		notifications:                 config.Notifications,
 		sigCache:                      config.SigCache,
+		assumeValid:                   config.AssumeValid,
 		indexManager:                  config.IndexManager,
 		interrupt:                     config.Interrupt,
 		subsidyCache:                  subsidyCache,
@@ -2095,6 +2190,9 @@ func New(config *Config) (*BlockChain, error) {
 		calcVoterVersionIntervalCache: make(map[[chainhash.HashSize]byte]uint32),
 		calcStakeVersionCache:         make(map[[chainhash.HashSize]byte]uint32),
 	}
+	if config.UtxoCacheMaxSize > 0 {
+		b.utxoCache = newUtxoCache(config.UtxoCacheMaxSize)
+	}
 	b.pruner = newChainPruner(&b)
 
 	// Initialize the chain state from the passed database.  When the db
@@ -2119,6 +2217,9 @@ func New(config *Config) (*BlockChain, error) {
 		return nil, err
 	}
 
+	// Batch database writes while the chain is not current.
+	b.maybeUpdateBatchWrites()
+
 	log.Infof("Blockchain database version info: chain: %d, compression: "+
 		"%d, block index: %d", b.dbInfo.version, b.dbInfo.compVer,
 		b.dbInfo.bidxVer)
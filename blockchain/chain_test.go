@@ -20,6 +20,7 @@ import (
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrd/txscript/v2"
 	"github.com/decred/dcrd/wire"
 )
 
@@ -117,6 +118,52 @@ func TestBlockchainFunctions(t *testing.T) {
 			"want %v, got %v", expectedVal, val)
 	}
 
+	// Ensure the ticket pool value as of the current best block matches the
+	// current ticket pool value.
+	tip := chain.BestSnapshot()
+	val, err = chain.TicketPoolValueByHash(&tip.Hash)
+	if err != nil {
+		t.Fatalf("Failed to get ticket pool value by hash: %v", err)
+	}
+	if val != expectedVal {
+		t.Errorf("Failed to get correct result for ticket pool value by "+
+			"hash; want %v, got %v", expectedVal, val)
+	}
+
+	// Ensure the ticket pool value as of an older block, which includes
+	// tickets that have since been spent, matches the value of its live
+	// tickets as determined from their purchases.
+	oldHash, err := chain.BlockHashByHeight(150)
+	if err != nil {
+		t.Fatalf("Failed to get block hash: %v", err)
+	}
+	oldNode := chain.index.LookupNode(oldHash)
+	chain.chainLock.Lock()
+	oldStakeNode, err := chain.fetchStakeNode(oldNode)
+	chain.chainLock.Unlock()
+	if err != nil {
+		t.Fatalf("Failed to fetch stake node: %v", err)
+	}
+	ticketValues := make(map[chainhash.Hash]int64)
+	for i := 1; i <= 168; i++ {
+		bl, _ := dcrutil.NewBlockFromBytes(blockChain[int64(i)])
+		for _, stx := range bl.STransactions() {
+			ticketValues[*stx.Hash()] = stx.MsgTx().TxOut[0].Value
+		}
+	}
+	var oldExpectedVal dcrutil.Amount
+	for _, ticket := range oldStakeNode.LiveTickets() {
+		oldExpectedVal += dcrutil.Amount(ticketValues[ticket])
+	}
+	val, err = chain.TicketPoolValueByHash(oldHash)
+	if err != nil {
+		t.Fatalf("Failed to get ticket pool value by hash: %v", err)
+	}
+	if val != oldExpectedVal {
+		t.Errorf("Failed to get correct result for ticket pool value by "+
+			"hash at height 150; want %v, got %v", oldExpectedVal, val)
+	}
+
 	a, _ := dcrutil.DecodeAddress("SsbKpMkPnadDcZFFZqRPY8nvdFagrktKuzB", params)
 	hs, err := chain.TicketsWithAddress(a)
 	if err != nil {
@@ -788,3 +835,240 @@ func TestLocateInventory(t *testing.T) {
 		}
 	}
 }
+
+// TestAssumeValid ensures the assumed valid block and its ancestors are
+// reported as not requiring script validation once it is part of the best
+// chain, blocks that fork from it or are above it are not, nothing is assumed
+// valid while it is unknown or not part of the best chain, and blocks at its
+// height with a different hash are rejected.
+func TestAssumeValid(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	bc := newFakeChain(params)
+	genesis := bc.bestChain.Genesis()
+	mainNodes := chainedFakeNodes(genesis, 10)
+	forkNodes := chainedFakeNodes(mainNodes[4], 5)
+	assumeValidNode := mainNodes[7]
+	bc.assumeValid = &chaincfg.Checkpoint{
+		Height: assumeValidNode.height,
+		Hash:   &assumeValidNode.hash,
+	}
+
+	// Ensure nothing is assumed valid prior to the assumed valid block being
+	// known.
+	for _, node := range append(mainNodes, forkNodes...) {
+		if bc.isAssumedValid(node) {
+			t.Fatalf("block %d below unknown assumed valid block is "+
+				"assumed valid", node.height)
+		}
+	}
+
+	// Ensure nothing is assumed valid when the assumed valid block is known
+	// but is not part of the best chain.
+	for _, node := range append(mainNodes, forkNodes...) {
+		bc.index.AddNode(node)
+	}
+	bc.bestChain.SetTip(forkNodes[len(forkNodes)-1])
+	for _, node := range append(mainNodes, forkNodes...) {
+		if bc.isAssumedValid(node) {
+			t.Fatalf("block %d is assumed valid when the assumed valid "+
+				"block is not in the best chain", node.height)
+		}
+	}
+
+	// Ensure only the assumed valid block and its ancestors are treated as
+	// assumed valid once it is part of the best chain.
+	bc.bestChain.SetTip(mainNodes[len(mainNodes)-1])
+	for _, node := range mainNodes {
+		want := node.height <= assumeValidNode.height
+		if got := bc.isAssumedValid(node); got != want {
+			t.Fatalf("isAssumedValid(%d): got %v, want %v", node.height,
+				got, want)
+		}
+	}
+	for _, node := range forkNodes {
+		if bc.isAssumedValid(node) {
+			t.Fatalf("fork block %d is assumed valid", node.height)
+		}
+	}
+
+	// Ensure only a block at the assumed valid height with a different hash
+	// fails verification.
+	if !bc.verifyAssumeValid(assumeValidNode.height, &assumeValidNode.hash) {
+		t.Fatal("assumed valid block failed verification")
+	}
+	if bc.verifyAssumeValid(forkNodes[2].height, &forkNodes[2].hash) {
+		t.Fatal("fork block at the assumed valid height passed verification")
+	}
+	if !bc.verifyAssumeValid(forkNodes[1].height, &forkNodes[1].hash) {
+		t.Fatal("fork block below the assumed valid height failed " +
+			"verification")
+	}
+
+	// Ensure nothing is assumed valid without an assumed valid block.
+	bc.assumeValid = nil
+	if bc.isAssumedValid(mainNodes[0]) {
+		t.Fatal("block is assumed valid without an assumed valid block")
+	}
+}
+
+// TestAssumeValidHeaders ensures blocks below an assumed valid block that has
+// not been connected yet skip script validation once their headers are known to
+// lead to it and that invalid headers are not accepted.
+func TestAssumeValidHeaders(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "assumevalidheaderstest")
+	defer teardownFunc()
+
+	// Generate and accept enough blocks to reach stake validation height.
+	g.AdvanceToStakeValidationHeight()
+	parentHash := g.Tip().BlockHash()
+
+	// Create a block with an invalid regular transaction signature script
+	// followed by the block that will be assumed valid without accepting
+	// either of them.
+	//
+	//   ... -> bsv# -> bav0 -> bav1
+	outs := g.OldestCoinbaseOuts()
+	g.NextBlock("bav0", &outs[0], outs[1:], func(b *wire.MsgBlock) {
+		b.Transactions[1].TxIn[0].SignatureScript = []byte{0x01,
+			txscript.OP_FALSE}
+	})
+	g.SaveTipCoinbaseOuts()
+	outs = g.OldestCoinbaseOuts()
+	g.NextBlock("bav1", nil, outs[1:])
+	g.SaveTipCoinbaseOuts()
+	bav0 := g.BlockByName("bav0")
+	bav1 := g.BlockByName("bav1")
+	bav0Hash, bav1Hash := bav0.BlockHash(), bav1.BlockHash()
+	g.chain.assumeValid = &chaincfg.Checkpoint{
+		Height: int64(bav1.Header.Height),
+		Hash:   &bav1Hash,
+	}
+
+	// Ensure the scripts are validated before the headers are known.
+	err := g.chain.CheckConnectBlockTemplate(dcrutil.NewBlock(bav0))
+	if rerr, ok := err.(RuleError); !ok || rerr.ErrorCode != ErrScriptValidation {
+		t.Fatalf("unexpected error before headers are known -- got %v, "+
+			"want %v", err, ErrScriptValidation)
+	}
+
+	// Ensure headers that do not end with the assumed valid block or that do
+	// not connect to a known block are rejected.
+	var unknownHash chainhash.Hash
+	tests := []struct {
+		name   string
+		parent *chainhash.Hash
+		hashes []chainhash.Hash
+	}{{
+		name:   "no headers",
+		parent: &parentHash,
+	}, {
+		name:   "missing assumed valid block",
+		parent: &parentHash,
+		hashes: []chainhash.Hash{bav0Hash},
+	}, {
+		name:   "wrong height",
+		parent: &parentHash,
+		hashes: []chainhash.Hash{bav0Hash, bav0Hash, bav1Hash},
+	}, {
+		name:   "unknown parent",
+		parent: &unknownHash,
+		hashes: []chainhash.Hash{bav0Hash, bav1Hash},
+	}}
+	for _, test := range tests {
+		err := g.chain.SetAssumeValidHeaders(test.parent, test.hashes)
+		if err == nil {
+			t.Fatalf("%q: headers were accepted", test.name)
+		}
+	}
+
+	// Ensure the block with the invalid script is accepted since its scripts
+	// are skipped once its header is known to lead to the assumed valid block
+	// even though the assumed valid block has not been connected yet.
+	hashes := []chainhash.Hash{bav0Hash, bav1Hash}
+	if err := g.chain.SetAssumeValidHeaders(&parentHash, hashes); err != nil {
+		t.Fatalf("unexpected error setting headers: %v", err)
+	}
+	g.AcceptBlock("bav0")
+	g.AcceptBlock("bav1")
+	g.ExpectTip("bav1")
+}
+
+// TestStakeNodeFromDatabase ensures stake nodes reconstructed from the stake
+// database, which is used for blocks that are too deep in history to
+// reconstruct from memory, match the stake nodes reconstructed from memory for
+// both main chain and side chain blocks.
+func TestStakeNodeFromDatabase(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "stakenodefromdbtest")
+	defer teardownFunc()
+
+	// Generate and accept enough blocks to reach stake validation height
+	// followed by a few more blocks along with a side chain.
+	//
+	//   ... -> bsv# -> bsn0 -> bsn1 -> bsn2
+	//                      \-> bsn1a
+	g.AdvanceToStakeValidationHeight()
+	for i := 0; i < 3; i++ {
+		outs := g.OldestCoinbaseOuts()
+		g.NextBlock(fmt.Sprintf("bsn%d", i), nil, outs[1:])
+		g.SaveTipCoinbaseOuts()
+		g.AcceptTipBlock()
+	}
+	g.SetTip("bsn0")
+	g.NextBlock("bsn1a", nil, nil)
+	g.AcceptedToSideChainWithExpectedTip("bsn2")
+
+	nodeByName := func(blockName string) *blockNode {
+		hash := g.BlockByName(blockName).BlockHash()
+		return g.chain.index.LookupNode(&hash)
+	}
+	tests := []struct {
+		name string
+		node *blockNode
+	}{{
+		name: "current best block",
+		node: nodeByName("bsn2"),
+	}, {
+		name: "main chain block prior to stake enabled height",
+		node: g.chain.bestChain.NodeByHeight(params.StakeEnabledHeight - 1),
+	}, {
+		name: "main chain block after stake enabled height",
+		node: g.chain.bestChain.NodeByHeight(params.StakeEnabledHeight + 5),
+	}, {
+		name: "side chain block",
+		node: nodeByName("bsn1a"),
+	}}
+	for _, test := range tests {
+		got, err := g.chain.stakeNodeFromDatabase(test.node)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+
+		g.chain.chainLock.Lock()
+		want, err := g.chain.fetchStakeNode(test.node)
+		g.chain.chainLock.Unlock()
+		if err != nil {
+			t.Fatalf("%q: unable to fetch stake node: %v", test.name, err)
+		}
+
+		if got.Height() != want.Height() {
+			t.Fatalf("%q: unexpected height -- got %d, want %d", test.name,
+				got.Height(), want.Height())
+		}
+		if got.FinalState() != want.FinalState() {
+			t.Fatalf("%q: unexpected final state -- got %x, want %x",
+				test.name, got.FinalState(), want.FinalState())
+		}
+		if !reflect.DeepEqual(got.LiveTickets(), want.LiveTickets()) {
+			t.Fatalf("%q: unexpected live tickets -- got %v, want %v",
+				test.name, got.LiveTickets(), want.LiveTickets())
+		}
+		if !reflect.DeepEqual(got.MissedTickets(), want.MissedTickets()) {
+			t.Fatalf("%q: unexpected missed tickets -- got %v, want %v",
+				test.name, got.MissedTickets(), want.MissedTickets())
+		}
+	}
+}
@@ -97,6 +97,89 @@ func (b *BlockChain) verifyCheckpoint(height int64, hash *chainhash.Hash) bool {
 	return true
 }
 
+// verifyAssumeValid returns whether the passed block height and hash
+// combination match the configured assumed valid block.  It also returns true
+// if there is no assumed valid block or it is at a different height.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) verifyAssumeValid(height int64, hash *chainhash.Hash) bool {
+	if b.assumeValid == nil || b.assumeValid.Height != height {
+		return true
+	}
+
+	if !b.assumeValid.Hash.IsEqual(hash) {
+		return false
+	}
+
+	log.Infof("Verified assumed valid block at height %d/block %s",
+		b.assumeValid.Height, b.assumeValid.Hash)
+	return true
+}
+
+// isAssumedValid returns whether or not the passed node is the configured
+// assumed valid block or one of its ancestors and therefore does not need its
+// transaction scripts validated.
+//
+// Once the assumed valid block is part of the best chain, only it and its
+// ancestors are assumed valid.  Prior to that, which is the case during the
+// initial chain sync, only blocks whose headers were provided via
+// SetAssumeValidHeaders are assumed valid since those headers are known to
+// lead to the assumed valid block.  This prevents blocks that fork from the
+// assumed valid chain, or blocks below a mistyped assumed valid hash, from
+// skipping validation.
+//
+// This function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) isAssumedValid(node *blockNode) bool {
+	if b.assumeValid == nil || node.height > b.assumeValid.Height {
+		return false
+	}
+
+	assumeValidNode := b.index.LookupNode(b.assumeValid.Hash)
+	if assumeValidNode != nil && b.bestChain.Contains(assumeValidNode) {
+		return assumeValidNode.Ancestor(node.height) == node
+	}
+	hash, ok := b.assumeValidHeaders[node.height]
+	return ok && hash == node.hash
+}
+
+// SetAssumeValidHeaders records the provided block hashes as the headers that
+// lead from the block with the given parent hash to the configured assumed
+// valid block so the blocks they describe skip script validation when they are
+// connected prior to the assumed valid block.  The parent block must already be
+// known and the hashes must be ordered by height starting with its child and
+// ending with the assumed valid block.
+//
+// The caller is responsible for ensuring each header properly connects to the
+// previous one.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) SetAssumeValidHeaders(parentHash *chainhash.Hash, hashes []chainhash.Hash) error {
+	if b.assumeValid == nil {
+		return fmt.Errorf("no assumed valid block is configured")
+	}
+
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	parent := b.index.LookupNode(parentHash)
+	if parent == nil {
+		return fmt.Errorf("parent block %s is not known", parentHash)
+	}
+	if parent.height+int64(len(hashes)) != b.assumeValid.Height ||
+		len(hashes) == 0 || hashes[len(hashes)-1] != *b.assumeValid.Hash {
+
+		return fmt.Errorf("headers from block %s do not end with the "+
+			"assumed valid block %s at height %d", parentHash,
+			b.assumeValid.Hash, b.assumeValid.Height)
+	}
+
+	b.assumeValidHeaders = make(map[int64]chainhash.Hash, len(hashes))
+	for i := range hashes {
+		b.assumeValidHeaders[parent.height+int64(i)+1] = hashes[i]
+	}
+	return nil
+}
+
 // findPreviousCheckpoint finds the most recent checkpoint that is already
 // available in the downloaded portion of the block chain and returns the
 // associated block node.  It returns nil if a checkpoint can't be found (this
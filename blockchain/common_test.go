@@ -105,6 +105,10 @@ func chainSetup(dbName string, params *chaincfg.Params) (*BlockChain, func(), er
 		ChainParams: &paramsCopy,
 		TimeSource:  NewMedianTime(),
 		SigCache:    txscript.NewSigCache(1000),
+
+		// Use a small utxo cache to ensure it remains coherent with the
+		// database, including when entries are evicted.
+		UtxoCacheMaxSize: 64 * 1024,
 	})
 
 	if err != nil {
@@ -263,6 +263,63 @@ func (b *BlockChain) CalcNextRequiredDifficulty(timestamp time.Time) (uint32, er
 	return difficulty, err
 }
 
+// estimateNextWorkDifficulty estimates the required difficulty for the first
+// block of the next difficulty retarget interval after the passed node by
+// pretending the remaining blocks in the current interval are mined at the
+// average rate of the most recent window of blocks.  It also returns the
+// height of the block the estimated difficulty applies to.
+//
+// This function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) estimateNextWorkDifficulty(curNode *blockNode) (uint32, int64, error) {
+	windowSize := b.chainParams.WorkDiffWindowSize
+	nextRetargetHeight := (curNode.height/windowSize + 1) * windowSize
+
+	// Determine the average time between the most recent blocks, limited
+	// to the most recent window, falling back to the target time per block
+	// when there are not any blocks to average.
+	avgBlockTime := int64(b.chainParams.TargetTimePerBlock / time.Second)
+	numBlocks := windowSize
+	if curNode.height < numBlocks {
+		numBlocks = curNode.height
+	}
+	if numBlocks > 0 {
+		oldNode := curNode.RelativeAncestor(numBlocks)
+		avgBlockTime = (curNode.timestamp - oldNode.timestamp) / numBlocks
+	}
+
+	// Extend the chain with temporary nodes at the average block time up to
+	// the final block of the current interval.  The nodes are never added to
+	// the block index and only contain the fields that are required by the
+	// difficulty calculation.
+	estimateNode := curNode
+	for height := curNode.height + 1; height < nextRetargetHeight; height++ {
+		estimateNode = &blockNode{
+			parent:    estimateNode,
+			height:    height,
+			bits:      estimateNode.bits,
+			timestamp: estimateNode.timestamp + avgBlockTime,
+		}
+	}
+
+	blockTime := time.Unix(estimateNode.timestamp+avgBlockTime, 0)
+	bits, err := b.calcNextRequiredDifficulty(estimateNode, blockTime)
+	return bits, nextRetargetHeight, err
+}
+
+// EstimateNextWorkDifficulty estimates the required proof-of-work difficulty
+// for the first block of the next difficulty retarget interval by pretending
+// the remaining blocks in the current interval are mined at the average rate
+// of the most recent window of blocks.  It returns the estimated difficulty in
+// compact form along with the height of the block it applies to.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) EstimateNextWorkDifficulty() (uint32, int64, error) {
+	b.chainLock.Lock()
+	bits, height, err := b.estimateNextWorkDifficulty(b.bestChain.Tip())
+	b.chainLock.Unlock()
+	return bits, height, err
+}
+
 // mergeDifficulty takes an original stake difficulty and two new, scaled
 // stake difficulties, merges the new difficulties, and outputs a new
 // merged stake difficulty.
@@ -1150,3 +1150,70 @@ func TestMinDifficultyReduction(t *testing.T) {
 		}
 	}
 }
+
+// TestEstimateNextWorkDifficulty ensures the estimated proof-of-work difficulty
+// for the next retarget interval matches the difficulty that is calculated once
+// the remaining blocks in the interval are mined at the same rate.
+func TestEstimateNextWorkDifficulty(t *testing.T) {
+	// Create chain params based on regnet params, but set the fields related to
+	// proof-of-work difficulty to specific values expected by the tests.
+	params := chaincfg.RegNetParams()
+	params.ReduceMinDifficulty = false
+	params.TargetTimePerBlock = time.Minute * 2
+	params.WorkDiffAlpha = 1
+	params.WorkDiffWindowSize = 144
+	params.WorkDiffWindows = 20
+	params.TargetTimespan = params.TargetTimePerBlock *
+		time.Duration(params.WorkDiffWindowSize)
+	params.RetargetAdjustmentFactor = 4
+
+	// Create several intervals of blocks at the target time followed by a
+	// partial interval of blocks taking half the target time.
+	bc := newFakeChain(params)
+	node := bc.bestChain.Tip()
+	blockTime := time.Unix(node.timestamp, 0)
+	addBlocks := func(numBlocks int64, blockInterval time.Duration) {
+		t.Helper()
+		for i := int64(0); i < numBlocks; i++ {
+			blockTime = blockTime.Add(blockInterval)
+			diff, err := bc.calcNextRequiredDifficulty(node, blockTime)
+			if err != nil {
+				t.Fatalf("calcNextRequiredDifficulty: unexpected err: %v", err)
+			}
+			node = newFakeNode(node, 1, 1, diff, blockTime)
+			bc.index.AddNode(node)
+			bc.bestChain.SetTip(node)
+		}
+	}
+	windowSize := params.WorkDiffWindowSize
+	addBlocks(windowSize*4-1, params.TargetTimePerBlock)
+	addBlocks(windowSize*3/2, params.TargetTimePerBlock/2)
+
+	// Estimate the difficulty for the next interval and ensure it applies to
+	// the expected height.
+	estimate, height, err := bc.EstimateNextWorkDifficulty()
+	if err != nil {
+		t.Fatalf("EstimateNextWorkDifficulty: unexpected err: %v", err)
+	}
+	wantHeight := (node.height/windowSize + 1) * windowSize
+	if height != wantHeight {
+		t.Fatalf("EstimateNextWorkDifficulty: unexpected height -- got %d, "+
+			"want %d", height, wantHeight)
+	}
+
+	// Mine the remainder of the interval at the same rate and ensure the
+	// actual difficulty matches the estimate.
+	addBlocks(wantHeight-node.height-1, params.TargetTimePerBlock/2)
+	diff, err := bc.calcNextRequiredDifficulty(node,
+		blockTime.Add(params.TargetTimePerBlock/2))
+	if err != nil {
+		t.Fatalf("calcNextRequiredDifficulty: unexpected err: %v", err)
+	}
+	if estimate != diff {
+		t.Fatalf("EstimateNextWorkDifficulty: did not get expected "+
+			"difficulty -- got %d, want %d", estimate, diff)
+	}
+	if estimate == node.bits {
+		t.Fatal("EstimateNextWorkDifficulty: difficulty did not change")
+	}
+}
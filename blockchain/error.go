@@ -123,7 +123,8 @@ const (
 	ErrBadMerkleRoot
 
 	// ErrBadCheckpoint indicates a block that is expected to be at a
-	// checkpoint height does not match the expected one.
+	// checkpoint height, or the height of the assumed valid block, does not
+	// match the expected one.
 	ErrBadCheckpoint
 
 	// ErrForkTooOld indicates a block is attempting to fork the block chain
@@ -20,5 +20,6 @@ require (
 
 replace (
 	github.com/decred/dcrd/chaincfg/v2 => ../chaincfg
+	github.com/decred/dcrd/database/v2 => ../database
 	github.com/decred/dcrd/gcs/v2 => ../gcs
 )
@@ -340,6 +340,23 @@ func dbFetchAddrIndexEntries(bucket internalBucket, addrKey [addrKeySize]byte, n
 	return results, numToSkip, nil
 }
 
+// dbFetchAddrIndexEntryCount returns the total number of entries in the
+// address index for the given address key without deserializing any of them.
+func dbFetchAddrIndexEntryCount(bucket internalBucket, addrKey [addrKeySize]byte) uint32 {
+	var numEntries uint32
+	for level := uint8(0); ; level++ {
+		curLevelKey := keyForLevel(addrKey, level)
+		levelData := bucket.Get(curLevelKey[:])
+		if levelData == nil {
+			// Stop when there are no more levels.
+			break
+		}
+		numEntries += uint32(len(levelData) / txEntrySize)
+	}
+
+	return numEntries
+}
+
 // minEntriesToReachLevel returns the minimum number of entries that are
 // required to reach the given address index level.
 func minEntriesToReachLevel(level uint8) int {
@@ -920,6 +937,25 @@ func (idx *AddrIndex) EntriesForAddress(dbTx database.Tx, addr dcrutil.Address,
 	return entries, skipped, err
 }
 
+// NumEntriesForAddress returns the total number of transactions confirmed in
+// blocks that involve the passed address.  It only consults the index levels
+// for the address, so it is much cheaper than loading the entries themselves.
+//
+// NOTE: This count only includes transactions confirmed in blocks.  See the
+// UnconfirmedTxnsForAddress method for obtaining unconfirmed transactions
+// that involve a given address.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) NumEntriesForAddress(dbTx database.Tx, addr dcrutil.Address) (uint32, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return 0, err
+	}
+
+	addrIdxBucket := dbTx.Metadata().Bucket(addrIndexKey)
+	return dbFetchAddrIndexEntryCount(addrIdxBucket, addrKey), nil
+}
+
 // indexUnconfirmedAddresses modifies the unconfirmed (memory-only) address
 // index to include mappings for the addresses encoded by the passed public key
 // script to the transaction.
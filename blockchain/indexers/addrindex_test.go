@@ -271,6 +271,16 @@ nextTest:
 					test.name, numDelete, err)
 				continue nextTest
 			}
+
+			// Ensure the count of entries matches the expected
+			// number.
+			gotNum := dbFetchAddrIndexEntryCount(bucket, test.key)
+			if gotNum != uint32(numExpected) {
+				t.Errorf("dbFetchAddrIndexEntryCount (%s) delete "+
+					"%d: unexpected count -- got %d, want %d",
+					test.name, numDelete, gotNum, numExpected)
+				continue nextTest
+			}
 		}
 	}
 }
@@ -8,6 +8,8 @@ package indexers
 import (
 	"errors"
 	"fmt"
+	"runtime"
+	"sync"
 
 	"github.com/decred/dcrd/blockchain/v2"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -25,6 +27,12 @@ const (
 
 	// cfIndexVersion is the current version of the committed filter index.
 	cfIndexVersion = 2
+
+	// maxPendingFilters is the maximum number of blocks the committed filters
+	// may be precomputed for ahead of the blocks being connected.  It bounds
+	// the memory used by filters for blocks that never end up connected, such
+	// as those that fail validation.
+	maxPendingFilters = 64
 )
 
 // Committed filters come in two flavors: basic and extended. They are
@@ -102,10 +110,29 @@ func dbDeleteFilterHeader(dbTx database.Tx, key []byte, h *chainhash.Hash) error
 	return idx.Delete(h[:])
 }
 
+// pendingFilters houses the committed filters for a block that are computed in
+// the background ahead of the block being connected to the main chain.  The
+// filters and error must only be accessed after the done channel is closed.
+type pendingFilters struct {
+	height   int64
+	done     chan struct{}
+	regular  *gcs.FilterV1
+	extended *gcs.FilterV1
+	err      error
+}
+
 // CFIndex implements a committed filter (cf) by hash index.
 type CFIndex struct {
 	db          database.DB
 	chainParams *chaincfg.Params
+
+	// These fields are used to compute the filters for blocks in a pool of
+	// workers concurrently with the validation of the blocks.  The work
+	// semaphore limits the number of filters computed at once and the
+	// pending filters are protected by the pending mutex.
+	workSem    chan struct{}
+	pendingMtx sync.Mutex
+	pending    map[chainhash.Hash]*pendingFilters
 }
 
 // Ensure the CFIndex type implements the Indexer interface.
@@ -210,26 +237,111 @@ func storeFilter(dbTx database.Tx, block *dcrutil.Block, f *gcs.FilterV1, filter
 	return dbStoreFilterHeader(dbTx, hkey, h, fh[:])
 }
 
+// makeFilters builds the regular and extended committed filters for the
+// provided block.
+func makeFilters(block *wire.MsgBlock) (*gcs.FilterV1, *gcs.FilterV1, error) {
+	regular, err := blockcf.Regular(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	extended, err := blockcf.Extended(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return regular, extended, nil
+}
+
+// PrecomputeFilters starts building the committed filters for the provided
+// block in the background so they are available, or at least closer to being
+// available, by the time the block is connected to the main chain.  This
+// allows the filter construction, which is CPU bound, to proceed concurrently
+// with the validation of the block.  The filters are only stored once the
+// block is connected.
+//
+// This function is safe for concurrent access.
+func (idx *CFIndex) PrecomputeFilters(block *dcrutil.Block) {
+	idx.pendingMtx.Lock()
+	defer idx.pendingMtx.Unlock()
+
+	// Nothing to do when the filters are already being computed or too
+	// many blocks are already pending.  The filters are computed when the
+	// block is connected in that case.
+	hash := *block.Hash()
+	if _, ok := idx.pending[hash]; ok || len(idx.pending) >= maxPendingFilters {
+		return
+	}
+
+	pending := &pendingFilters{
+		height: block.Height(),
+		done:   make(chan struct{}),
+	}
+	idx.pending[hash] = pending
+	go func() {
+		idx.workSem <- struct{}{}
+		pending.regular, pending.extended, pending.err = makeFilters(
+			block.MsgBlock())
+		<-idx.workSem
+		close(pending.done)
+	}()
+}
+
+// DiscardFilters removes any filters that are being precomputed for the block
+// with the provided hash.  It is intended to be used for blocks that were
+// precomputed but did not end up connected to the main chain, such as those
+// that were rejected or became orphans, so they do not occupy the limited
+// pending slots.
+//
+// This function is safe for concurrent access.
+func (idx *CFIndex) DiscardFilters(hash *chainhash.Hash) {
+	idx.pendingMtx.Lock()
+	delete(idx.pending, *hash)
+	idx.pendingMtx.Unlock()
+}
+
+// blockFilters returns the regular and extended committed filters for the
+// provided block.  The filters are waited on when they are being precomputed
+// and built directly otherwise.
+//
+// Any precomputed filters for the block along with those for all other blocks
+// at or below its height are removed since they are either no longer needed or
+// belong to blocks that are unlikely to be connected next.
+//
+// This function is safe for concurrent access.
+func (idx *CFIndex) blockFilters(block *dcrutil.Block) (*gcs.FilterV1, *gcs.FilterV1, error) {
+	idx.pendingMtx.Lock()
+	pending := idx.pending[*block.Hash()]
+	height := block.Height()
+	for hash, p := range idx.pending {
+		if p.height <= height {
+			delete(idx.pending, hash)
+		}
+	}
+	idx.pendingMtx.Unlock()
+
+	if pending == nil {
+		return makeFilters(block.MsgBlock())
+	}
+	<-pending.done
+	return pending.regular, pending.extended, pending.err
+}
+
 // ConnectBlock is invoked by the index manager when a new block has been
 // connected to the main chain. This indexer adds a hash-to-cf mapping for
 // every passed block. This is part of the Indexer interface.
 func (idx *CFIndex) ConnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, view *blockchain.UtxoViewpoint) error {
-	f, err := blockcf.Regular(block.MsgBlock())
-	if err != nil {
-		return err
-	}
-
-	err = storeFilter(dbTx, block, f, wire.GCSFilterRegular)
+	regular, extended, err := idx.blockFilters(block)
 	if err != nil {
 		return err
 	}
 
-	f, err = blockcf.Extended(block.MsgBlock())
+	err = storeFilter(dbTx, block, regular, wire.GCSFilterRegular)
 	if err != nil {
 		return err
 	}
 
-	return storeFilter(dbTx, block, f, wire.GCSFilterExtended)
+	return storeFilter(dbTx, block, extended, wire.GCSFilterExtended)
 }
 
 // DisconnectBlock is invoked by the index manager when a block has been
@@ -293,7 +405,12 @@ func (idx *CFIndex) FilterHeaderByBlockHash(h *chainhash.Hash, filterType wire.F
 // in turn is used by the blockchain package. This allows the index to be
 // seamlessly maintained along with the chain.
 func NewCfIndex(db database.DB, chainParams *chaincfg.Params) *CFIndex {
-	return &CFIndex{db: db, chainParams: chainParams}
+	return &CFIndex{
+		db:          db,
+		chainParams: chainParams,
+		workSem:     make(chan struct{}, runtime.NumCPU()),
+		pending:     make(map[chainhash.Hash]*pendingFilters),
+	}
 }
 
 // DropCfIndex drops the CF index from the provided database if exists.
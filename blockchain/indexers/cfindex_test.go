@@ -0,0 +1,85 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrutil/v2"
+)
+
+// testBlockAtHeight returns a copy of the regression network genesis block
+// modified to claim the provided height so it has a unique hash.
+func testBlockAtHeight(height uint32) *dcrutil.Block {
+	msgBlock := chaincfg.RegNetParams().GenesisBlock
+	msgBlockCopy := *msgBlock
+	msgBlockCopy.Header.Height = height
+	return dcrutil.NewBlock(&msgBlockCopy)
+}
+
+// TestPrecomputeFilters ensures precomputed committed filters match the
+// filters built directly, are removed once a block at or above their height is
+// connected, and are limited to the max number of pending filters.
+func TestPrecomputeFilters(t *testing.T) {
+	idx := NewCfIndex(nil, chaincfg.RegNetParams())
+
+	// Ensure the precomputed filters match the directly built ones.
+	block := testBlockAtHeight(1)
+	idx.PrecomputeFilters(block)
+	idx.PrecomputeFilters(testBlockAtHeight(2))
+	regular, extended, err := idx.blockFilters(block)
+	if err != nil {
+		t.Fatalf("unexpected error building filters: %v", err)
+	}
+	wantRegular, wantExtended, err := makeFilters(block.MsgBlock())
+	if err != nil {
+		t.Fatalf("unexpected error building filters: %v", err)
+	}
+	if !bytes.Equal(regular.Bytes(), wantRegular.Bytes()) {
+		t.Fatal("precomputed regular filter does not match")
+	}
+	if !bytes.Equal(extended.Bytes(), wantExtended.Bytes()) {
+		t.Fatal("precomputed extended filter does not match")
+	}
+
+	// Ensure only the filters for the connected block were removed.
+	if len(idx.pending) != 1 {
+		t.Fatalf("unexpected number of pending filters -- got %d, want 1",
+			len(idx.pending))
+	}
+
+	// Ensure discarding filters only removes those for the provided block.
+	discarded := testBlockAtHeight(4)
+	idx.PrecomputeFilters(discarded)
+	idx.DiscardFilters(discarded.Hash())
+	if _, ok := idx.pending[*discarded.Hash()]; ok {
+		t.Fatal("discarded filters are still pending")
+	}
+	if len(idx.pending) != 1 {
+		t.Fatalf("unexpected number of pending filters -- got %d, want 1",
+			len(idx.pending))
+	}
+
+	// Ensure filters for blocks at or below the height of a connected block
+	// are removed even when the connected block was not precomputed.
+	if _, _, err := idx.blockFilters(testBlockAtHeight(3)); err != nil {
+		t.Fatalf("unexpected error building filters: %v", err)
+	}
+	if len(idx.pending) != 0 {
+		t.Fatalf("unexpected number of pending filters -- got %d, want 0",
+			len(idx.pending))
+	}
+
+	// Ensure the number of pending filters is limited.
+	for i := uint32(0); i < maxPendingFilters*2; i++ {
+		idx.PrecomputeFilters(testBlockAtHeight(i + 10))
+	}
+	if len(idx.pending) != maxPendingFilters {
+		t.Fatalf("unexpected number of pending filters -- got %d, want %d",
+			len(idx.pending), maxPendingFilters)
+	}
+}
@@ -554,6 +554,20 @@ func (m *Manager) Init(chain *blockchain.BlockChain, interrupt <-chan struct{})
 	log.Infof("Catching up indexes from height %d to %d", lowestHeight,
 		bestHeight)
 
+	// Batch the database writes while catching up when the database supports
+	// it since each block is indexed in a separate transaction.  Disabling
+	// batch mode again flushes everything that was indexed to the database.
+	if batcher, ok := m.db.(database.BatchWriter); ok {
+		if err := batcher.SetBatchMode(true); err != nil {
+			return err
+		}
+		defer func() {
+			if err := batcher.SetBatchMode(false); err != nil {
+				log.Errorf("Unable to flush caught up indexes: %v", err)
+			}
+		}()
+	}
+
 	var cachedParent *dcrutil.Block
 	for height := lowestHeight + 1; height <= bestHeight; height++ {
 		if interruptRequested(interrupt) {
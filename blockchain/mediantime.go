@@ -13,11 +13,17 @@ import (
 )
 
 const (
-	// maxAllowedOffsetSeconds is the maximum number of seconds in either
-	// direction that local clock will be adjusted.  When the median time
-	// of the network is outside of this range, no offset will be applied.
+	// maxAllowedOffsetSeconds is the default maximum number of seconds in
+	// either direction that local clock will be adjusted.  When the median
+	// time of the network is outside of this range, no offset will be
+	// applied.
 	maxAllowedOffsetSecs = 70 * 60 // 1 hour 10 minutes
 
+	// DefaultMaxTimeOffset is the default maximum amount of time in either
+	// direction the local clock will be adjusted by the median time source
+	// returned by NewMedianTime.
+	DefaultMaxTimeOffset = maxAllowedOffsetSecs * time.Second
+
 	// similarTimeSecs is the number of seconds in either direction from the
 	// local clock that is used to determine that it is likely wrong and
 	// hence to show a warning.
@@ -80,6 +86,7 @@ type medianTime struct {
 	knownIDs           map[string]struct{}
 	offsets            []int64
 	offsetSecs         int64
+	maxOffsetSecs      int64
 	invalidTimeChecked bool
 }
 
@@ -160,7 +167,7 @@ func (m *medianTime) AddTimeSample(sourceID string, timeVal time.Time) {
 
 	// Set the new offset when the median offset is within the allowed
 	// offset range.
-	if math.Abs(float64(median)) < maxAllowedOffsetSecs {
+	if math.Abs(float64(median)) < float64(m.maxOffsetSecs) {
 		m.offsetSecs = median
 	} else {
 		// The median offset of all added time data is larger than the
@@ -212,8 +219,19 @@ func (m *medianTime) Offset() time.Duration {
 // expects the time samples to be added from the timestamp field of the version
 // message received from remote peers that successfully connect and negotiate.
 func NewMedianTime() MedianTimeSource {
+	return NewMedianTimeWithMaxOffset(DefaultMaxTimeOffset)
+}
+
+// NewMedianTimeWithMaxOffset returns a new instance of concurrency-safe
+// implementation of the MedianTimeSource interface that is the same as the one
+// returned by NewMedianTime except the local clock is only adjusted when the
+// median offset of the time samples is less than the provided maximum offset in
+// either direction.  The maximum offset is truncated to seconds and a maximum
+// offset of less than one second disables adjustment of the local clock.
+func NewMedianTimeWithMaxOffset(maxOffset time.Duration) MedianTimeSource {
 	return &medianTime{
-		knownIDs: make(map[string]struct{}),
-		offsets:  make([]int64, 0, maxMedianTimeEntries),
+		knownIDs:      make(map[string]struct{}),
+		offsets:       make([]int64, 0, maxMedianTimeEntries),
+		maxOffsetSecs: int64(maxOffset / time.Second),
 	}
 }
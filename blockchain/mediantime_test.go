@@ -103,3 +103,58 @@ func TestMedianTime(t *testing.T) {
 		}
 	}
 }
+
+// TestMedianTimeMaxOffset ensures the median time source only adjusts the local
+// clock when the median offset is within the configured maximum offset and
+// never adjusts it when the maximum offset is zero.
+func TestMedianTimeMaxOffset(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxOffset  time.Duration
+		in         []int64
+		wantOffset int64
+	}{{
+		name:       "default max offset",
+		maxOffset:  DefaultMaxTimeOffset,
+		in:         []int64{600, 601, 602, 603, 604},
+		wantOffset: 602,
+	}, {
+		name:       "within reduced max offset",
+		maxOffset:  time.Minute,
+		in:         []int64{-30, -20, -10, 0, 10},
+		wantOffset: -10,
+	}, {
+		name:       "outside reduced max offset",
+		maxOffset:  time.Minute,
+		in:         []int64{600, 601, 602, 603, 604},
+		wantOffset: 0,
+	}, {
+		name:       "disabled",
+		maxOffset:  0,
+		in:         []int64{-30, -20, -10, 0, 10},
+		wantOffset: 0,
+	}}
+
+	for _, test := range tests {
+		filter := NewMedianTimeWithMaxOffset(test.maxOffset)
+		for j, offset := range test.in {
+			now := time.Unix(time.Now().Unix(), 0)
+			tOffset := now.Add(time.Duration(offset) * time.Second)
+			filter.AddTimeSample(strconv.Itoa(j), tOffset)
+		}
+
+		// Since it is possible that the time.Now call in AddTimeSample
+		// and the time.Now calls here in the tests will be off by one
+		// second, allow a fudge factor to compensate.
+		gotOffset := filter.Offset()
+		wantOffset := time.Duration(test.wantOffset) * time.Second
+		wantOffset2 := time.Duration(test.wantOffset-1) * time.Second
+		if test.wantOffset == 0 {
+			wantOffset2 = 0
+		}
+		if gotOffset != wantOffset && gotOffset != wantOffset2 {
+			t.Fatalf("%q: unexpected offset -- got %v, want %v or %v",
+				test.name, gotOffset, wantOffset, wantOffset2)
+		}
+	}
+}
@@ -209,5 +209,9 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block, flags BehaviorFlags) (in
 
 	log.Debugf("Accepted block %v", blockHash)
 
+	// Start or stop batching database writes as needed now that the block
+	// might have changed whether or not the chain is current.
+	b.maybeUpdateBatchWrites()
+
 	return forkLen, false, nil
 }
@@ -8,6 +8,7 @@ package blockchain
 import (
 	"fmt"
 
+	"github.com/decred/dcrd/blockchain/stake/v2"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/database/v2"
 	"github.com/decred/dcrd/dcrutil/v2"
@@ -186,6 +187,18 @@ func (b *BlockChain) CheckMissedTickets(hashes []chainhash.Hash) []bool {
 	return existsSlice
 }
 
+// CheckRevokedTicket returns whether or not a ticket exists in the revoked
+// ticket treap of the best node.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) CheckRevokedTicket(hash chainhash.Hash) bool {
+	b.chainLock.RLock()
+	sn := b.bestChain.Tip().stakeNode
+	b.chainLock.RUnlock()
+
+	return sn.ExistsRevokedTicket(hash)
+}
+
 // CheckExpiredTicket returns whether or not a ticket was ever expired.
 //
 // This function is safe for concurrent access.
@@ -242,3 +255,93 @@ func (b *BlockChain) TicketPoolValue() (dcrutil.Amount, error) {
 	}
 	return dcrutil.Amount(amt), nil
 }
+
+// TicketPoolValueByHash returns the value of all the locked funds in the ticket
+// pool as of the block with the given hash, including side chain blocks.
+//
+// The value of live tickets that are still unspent is loaded from the utxo set,
+// however, tickets that have been spent since the block require loading the
+// blocks they were purchased in.  Further, the stake node for the block must be
+// reconstructed when it has already been pruned from memory.  In order to
+// bound the amount of work done while holding the chain lock and to ensure no
+// reconstructed stake nodes are retained in memory, the stake node for blocks
+// that do not share a common ancestor with the main chain within
+// minMemoryNodes of the current best block is reconstructed from the stake
+// database without holding the chain lock instead.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) TicketPoolValueByHash(hash *chainhash.Hash) (dcrutil.Amount, error) {
+	b.chainLock.Lock()
+	node := b.index.LookupNode(hash)
+	if node == nil {
+		b.chainLock.Unlock()
+		return 0, fmt.Errorf("block %s is not known", hash)
+	}
+	if node.height < b.chainParams.StakeEnabledHeight {
+		b.chainLock.Unlock()
+		return 0, nil
+	}
+	var sn *stake.Node
+	var err error
+	tipHeight := b.bestChain.Tip().height
+	if fork := b.bestChain.FindFork(node); fork != nil &&
+		tipHeight-fork.height <= minMemoryNodes {
+
+		sn, err = b.fetchStakeNode(node)
+		b.chainLock.Unlock()
+	} else {
+		b.chainLock.Unlock()
+		sn, err = b.stakeNodeFromDatabase(node)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Sum the value of the live tickets that are still unspent and keep
+	// track of the ones that have been spent since the block.
+	var amt int64
+	spent := make(map[chainhash.Hash]struct{})
+	err = b.db.View(func(dbTx database.Tx) error {
+		for _, ticket := range sn.LiveTickets() {
+			utxo, err := dbFetchUtxoEntry(dbTx, &ticket)
+			if err != nil {
+				return err
+			}
+			if utxo == nil || utxo.sparseOutputs[0] == nil {
+				spent[ticket] = struct{}{}
+				continue
+			}
+
+			amt += utxo.sparseOutputs[0].amount
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Load the value of the spent tickets from the blocks they were purchased
+	// in.  Tickets must reach maturity before they become live, so there is
+	// no need to look at the blocks after the final purchase height.
+	purchaseHeight := node.height - int64(b.chainParams.TicketMaturity)
+	for n := node.Ancestor(purchaseHeight); n != nil && len(spent) > 0; n = n.parent {
+		block, err := b.fetchBlockByNode(n)
+		if err != nil {
+			return 0, err
+		}
+		for _, stx := range block.STransactions() {
+			if _, ok := spent[*stx.Hash()]; !ok {
+				continue
+			}
+
+			amt += stx.MsgTx().TxOut[0].Value
+			delete(spent, *stx.Hash())
+		}
+	}
+	if len(spent) > 0 {
+		return 0, fmt.Errorf("unable to find the purchase of %d live "+
+			"tickets as of block %s", len(spent), hash)
+	}
+
+	return dcrutil.Amount(amt), nil
+}
@@ -203,3 +203,102 @@ func (b *BlockChain) fetchStakeNode(node *blockNode) (*stake.Node, error) {
 
 	return node.stakeNode, nil
 }
+
+// stakeNodeFromDatabase returns the stake node associated with the requested
+// node by loading the best stake node from the stake database and undoing the
+// effects of each main chain block back to, and including, the fork point of
+// the requested node, and then, in the case the requested node is on a side
+// chain, replaying the effects of each block on the side chain.
+//
+// Unlike fetchStakeNode, the intermediate stake nodes are not cached in the
+// block nodes and all state is read from a single database transaction, so
+// the chain state lock is not required.  This makes it suitable for nodes deep
+// in history for which reconstructing and caching all of the intermediate
+// stake nodes while holding the chain state lock would be prohibitive.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) stakeNodeFromDatabase(node *blockNode) (*stake.Node, error) {
+	var stakeNode *stake.Node
+	err := b.db.View(func(dbTx database.Tx) error {
+		// Load the best stake node as of the best chain state in the
+		// same database transaction to ensure they are consistent.
+		state, err := dbFetchBestState(dbTx)
+		if err != nil {
+			return err
+		}
+		tip := b.index.LookupNode(&state.hash)
+		if tip == nil {
+			return AssertError(fmt.Sprintf("stakeNodeFromDatabase: cannot "+
+				"find chain tip %s in block index", state.hash))
+		}
+		stakeNode, err = stake.LoadBestNode(dbTx, uint32(tip.height),
+			tip.hash, tip.Header(), b.chainParams)
+		if err != nil {
+			return err
+		}
+
+		// Find the fork point of the requested node with the chain that
+		// ends with the loaded tip.
+		fork := node
+		for fork != nil && tip.Ancestor(fork.height) != fork {
+			fork = fork.parent
+		}
+		if fork == nil {
+			return AssertError(fmt.Sprintf("stakeNodeFromDatabase: no "+
+				"common ancestor between %s and chain tip %s", node.hash,
+				tip.hash))
+		}
+
+		// Undo the effects from the tip back to, and including, the fork
+		// point.
+		for n := tip; n != fork; n = n.parent {
+			stakeNode, err = stakeNode.DisconnectNode(n.parent.lotteryIV(),
+				nil, nil, dbTx)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Replay the effects of the blocks on the side chain up to the
+		// requested node, if any.
+		attachNodes := make([]*blockNode, node.height-fork.height)
+		for n := node; n != fork; n = n.parent {
+			attachNodes[n.height-fork.height-1] = n
+		}
+		for _, n := range attachNodes {
+			block, err := dbFetchBlockByNode(dbTx, n)
+			if err != nil {
+				return err
+			}
+			spentTickets := stake.FindSpentTicketsInBlock(block.MsgBlock())
+
+			var newTickets []chainhash.Hash
+			if n.height >= b.chainParams.StakeEnabledHeight {
+				matureNode := n.RelativeAncestor(int64(
+					b.chainParams.TicketMaturity))
+				matureBlock, err := dbFetchBlockByNode(dbTx, matureNode)
+				if err != nil {
+					return err
+				}
+				for _, stx := range matureBlock.MsgBlock().STransactions {
+					if stake.IsSStx(stx) {
+						newTickets = append(newTickets, stx.TxHash())
+					}
+				}
+			}
+
+			stakeNode, err = stakeNode.ConnectNode(n.lotteryIV(),
+				spentTickets.VotedTickets, spentTickets.RevokedTickets,
+				newTickets)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stakeNode, nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"sync"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// utxoEntryOverhead is the approximate number of bytes of memory used by
+	// a cached utxo entry excluding its outputs.  It accounts for the map key
+	// and bucket overhead in the cache as well as the entry struct and its
+	// sparse outputs map.
+	utxoEntryOverhead = 160
+
+	// utxoOutputOverhead is the approximate number of bytes of memory used by
+	// each output of a cached utxo entry excluding its public key script.
+	utxoOutputOverhead = 64
+)
+
+// utxoCache provides a size-bounded in-memory cache of unspent transaction
+// output entries in front of the utxo set in the database in order to avoid
+// repeated database lookups and deserialization when fetching the utxos
+// referenced by blocks and transactions.
+//
+// The cache is write-through.  Every modification made to the utxo set in the
+// database when connecting or disconnecting a block is applied to the cache
+// once the database transaction commits successfully, so the cache never holds
+// state that has not already been handed to the database.  This means it never
+// needs to be flushed, remains coherent across chain reorganizations, and is
+// unaffected by unclean shutdowns.  Batching of the resulting database writes
+// is handled by the database layer.
+//
+// Entries are evicted at random when adding a new entry would cause the cache
+// to exceed its maximum size.  Random eviction is used since the access
+// pattern of the utxo set does not lend itself to any particular eviction
+// strategy being meaningfully better and it avoids additional bookkeeping.
+//
+// All entries are stored and returned as deep copies so callers are free to
+// mutate the returned entries without affecting the cache.
+//
+// The cache is safe for concurrent access, however, callers must ensure the
+// cache is only populated from the database while the chain lock is held so
+// that entries loaded from the database can't race with modifications to the
+// utxo set.
+type utxoCache struct {
+	mtx       sync.Mutex
+	maxSize   uint64
+	totalSize uint64
+	entries   map[chainhash.Hash]*UtxoEntry
+}
+
+// newUtxoCache returns a new utxo cache that is limited to approximately the
+// provided maximum size in bytes.
+func newUtxoCache(maxSize uint64) *utxoCache {
+	return &utxoCache{
+		maxSize: maxSize,
+		entries: make(map[chainhash.Hash]*UtxoEntry),
+	}
+}
+
+// utxoEntrySize returns the approximate number of bytes of memory used by the
+// provided utxo entry when it is stored in the cache.
+func utxoEntrySize(entry *UtxoEntry) uint64 {
+	size := uint64(utxoEntryOverhead + len(entry.stakeExtra))
+	for _, output := range entry.sparseOutputs {
+		size += uint64(utxoOutputOverhead + len(output.pkScript))
+	}
+	return size
+}
+
+// lookupEntry returns a copy of the cached utxo entry for the provided
+// transaction hash or nil when it is not in the cache.
+//
+// This function is safe for concurrent access.
+func (c *utxoCache) lookupEntry(txHash *chainhash.Hash) *UtxoEntry {
+	c.mtx.Lock()
+	entry := c.entries[*txHash].Clone()
+	c.mtx.Unlock()
+	return entry
+}
+
+// removeEntry removes the entry for the provided transaction hash from the
+// cache if it exists.
+//
+// This function MUST be called with the cache lock held.
+func (c *utxoCache) removeEntry(txHash *chainhash.Hash) {
+	if entry, ok := c.entries[*txHash]; ok {
+		c.totalSize -= utxoEntrySize(entry)
+		delete(c.entries, *txHash)
+	}
+}
+
+// addEntry adds a copy of the provided utxo entry to the cache, excluding any
+// outputs that are spent, while evicting random entries as needed to stay
+// within the maximum size.  Any existing entry for the transaction hash is
+// replaced.  Entries that are fully spent are removed from the cache instead.
+//
+// This function MUST be called with the cache lock held.
+func (c *utxoCache) addEntry(txHash *chainhash.Hash, entry *UtxoEntry) {
+	c.removeEntry(txHash)
+	if entry.IsFullySpent() {
+		return
+	}
+
+	// Spent outputs are not stored in the database, so they are pruned from
+	// the cached copy as well to match.
+	entry = entry.Clone()
+	for outputIndex, output := range entry.sparseOutputs {
+		if output.spent {
+			delete(entry.sparseOutputs, outputIndex)
+		}
+	}
+
+	// Don't bother caching entries that are too large to ever fit.
+	size := utxoEntrySize(entry)
+	if size > c.maxSize {
+		return
+	}
+
+	// Evict random entries until there is enough space for the new one.
+	// Go's map iteration order is randomized, so simply removing the first
+	// entries encountered results in random eviction.
+	for hash := range c.entries {
+		if c.totalSize+size <= c.maxSize {
+			break
+		}
+		hashCopy := hash
+		c.removeEntry(&hashCopy)
+	}
+
+	c.entries[*txHash] = entry
+	c.totalSize += size
+}
+
+// maybeAddEntry adds a copy of the provided utxo entry that was loaded from the
+// database to the cache.
+//
+// This function is safe for concurrent access.
+func (c *utxoCache) maybeAddEntry(txHash *chainhash.Hash, entry *UtxoEntry) {
+	c.mtx.Lock()
+	c.addEntry(txHash, entry)
+	c.mtx.Unlock()
+}
+
+// commit updates the cache to reflect all entries in the provided view that
+// are marked modified.  It must be called after the modifications in the view
+// have been successfully committed to the database and before the view itself
+// is committed since that clears the modified flags.
+//
+// This function is safe for concurrent access.
+func (c *utxoCache) commit(view *UtxoViewpoint) {
+	c.mtx.Lock()
+	for txHash, entry := range view.entries {
+		if entry == nil || !entry.modified {
+			continue
+		}
+
+		hash := txHash
+		c.addEntry(&hash, entry)
+	}
+	c.mtx.Unlock()
+}
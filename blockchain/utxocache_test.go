@@ -0,0 +1,109 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// testUtxoEntry returns a utxo entry with the provided number of unspent
+// outputs that each have a 25-byte public key script.
+func testUtxoEntry(numOutputs uint32) *UtxoEntry {
+	entry := newUtxoEntry(1, 100, 1, false, false, stake.TxTypeRegular)
+	for i := uint32(0); i < numOutputs; i++ {
+		entry.sparseOutputs[i] = &utxoOutput{
+			pkScript: make([]byte, 25),
+			amount:   int64(i+1) * 1e8,
+		}
+	}
+	return entry
+}
+
+// TestUtxoCache ensures the utxo cache returns independent copies of the
+// entries it holds, prunes spent outputs, stays within its maximum size, and
+// reflects the modifications in a committed view.
+func TestUtxoCache(t *testing.T) {
+	entrySize := utxoEntrySize(testUtxoEntry(2))
+	cache := newUtxoCache(entrySize * 3)
+
+	// Ensure a lookup for an entry that was never added returns nil.
+	hash1 := chainhash.Hash{0x01}
+	if entry := cache.lookupEntry(&hash1); entry != nil {
+		t.Fatalf("unexpected entry for %v", hash1)
+	}
+
+	// Ensure mutating an entry returned by the cache does not affect the
+	// cached entry.
+	cache.maybeAddEntry(&hash1, testUtxoEntry(2))
+	entry := cache.lookupEntry(&hash1)
+	if entry == nil {
+		t.Fatalf("missing entry for %v", hash1)
+	}
+	entry.SpendOutput(0)
+	if cache.lookupEntry(&hash1).IsOutputSpent(0) {
+		t.Fatal("mutating a returned entry modified the cached entry")
+	}
+
+	// Ensure spent outputs are pruned from the cached copy.
+	cache.maybeAddEntry(&hash1, entry)
+	if got := len(cache.entries[hash1].sparseOutputs); got != 1 {
+		t.Fatalf("unexpected number of cached outputs -- got %d, want 1",
+			got)
+	}
+	if !cache.lookupEntry(&hash1).IsOutputSpent(0) {
+		t.Fatal("spent output is not reported as spent")
+	}
+
+	// Ensure adding more entries than fit evicts entries to stay within the
+	// maximum size.
+	for i := byte(2); i < 10; i++ {
+		cache.maybeAddEntry(&chainhash.Hash{i}, testUtxoEntry(2))
+		if cache.totalSize > cache.maxSize {
+			t.Fatalf("cache size %d exceeds max size %d", cache.totalSize,
+				cache.maxSize)
+		}
+	}
+	if len(cache.entries) != 3 {
+		t.Fatalf("unexpected number of cached entries -- got %d, want 3",
+			len(cache.entries))
+	}
+
+	// Ensure entries that can never fit are not cached.
+	hashBig := chainhash.Hash{0xff}
+	cache.maybeAddEntry(&hashBig, testUtxoEntry(100))
+	if cache.lookupEntry(&hashBig) != nil {
+		t.Fatal("entry larger than the max size was cached")
+	}
+
+	// Ensure committing a view removes fully spent modified entries, adds
+	// new modified entries, and ignores unmodified entries.
+	var spentHash, newHash, unmodifiedHash chainhash.Hash
+	for hash := range cache.entries {
+		spentHash = hash
+		break
+	}
+	newHash = chainhash.Hash{0xaa}
+	unmodifiedHash = chainhash.Hash{0xbb}
+	view := NewUtxoViewpoint()
+	view.entries[spentHash] = cache.lookupEntry(&spentHash)
+	view.entries[spentHash].SpendOutput(0)
+	view.entries[spentHash].SpendOutput(1)
+	view.entries[newHash] = testUtxoEntry(2)
+	view.entries[newHash].modified = true
+	view.entries[unmodifiedHash] = testUtxoEntry(2)
+	cache.commit(view)
+	if cache.lookupEntry(&spentHash) != nil {
+		t.Fatal("fully spent entry is still cached")
+	}
+	if cache.lookupEntry(&newHash) == nil {
+		t.Fatal("new entry was not cached")
+	}
+	if cache.lookupEntry(&unmodifiedHash) != nil {
+		t.Fatal("unmodified entry was cached")
+	}
+}
@@ -250,6 +250,10 @@ func newUtxoEntry(txVersion uint16, height uint32, index uint32, isCoinBase bool
 type UtxoViewpoint struct {
 	entries  map[chainhash.Hash]*UtxoEntry
 	bestHash chainhash.Hash
+
+	// cache is the optional utxo cache to consult before the database when
+	// fetching entries that are not already in the view.
+	cache *utxoCache
 }
 
 // BestHash returns the hash of the best block in the chain the view currently
@@ -733,6 +737,22 @@ func (view *UtxoViewpoint) fetchUtxosMain(db database.DB, filteredSet viewFilter
 		return nil
 	}
 
+	// Serve as many of the requested entries as possible from the utxo cache
+	// when there is one and only load the remaining entries from the
+	// database.
+	if view.cache != nil {
+		for hash := range filteredSet {
+			hashCopy := hash
+			if entry := view.cache.lookupEntry(&hashCopy); entry != nil {
+				view.entries[hash] = entry
+				delete(filteredSet, hash)
+			}
+		}
+		if len(filteredSet) == 0 {
+			return nil
+		}
+	}
+
 	// Load the unspent transaction output information for the requested set
 	// of transactions from the point of view of the end of the main chain.
 	//
@@ -750,6 +770,9 @@ func (view *UtxoViewpoint) fetchUtxosMain(db database.DB, filteredSet viewFilter
 			}
 
 			view.entries[hash] = entry
+			if entry != nil && view.cache != nil {
+				view.cache.maybeAddEntry(&hashCopy, entry)
+			}
 		}
 
 		return nil
@@ -862,6 +885,7 @@ func (view *UtxoViewpoint) clone() *UtxoViewpoint {
 	clonedView := &UtxoViewpoint{
 		entries:  make(map[chainhash.Hash]*UtxoEntry),
 		bestHash: view.bestHash,
+		cache:    view.cache,
 	}
 
 	for txHash, entry := range view.entries {
@@ -878,6 +902,14 @@ func NewUtxoViewpoint() *UtxoViewpoint {
 	}
 }
 
+// newUtxoViewpoint returns a new empty unspent transaction output view that
+// consults the utxo cache of the chain, if it is enabled, before the database.
+func (b *BlockChain) newUtxoViewpoint() *UtxoViewpoint {
+	view := NewUtxoViewpoint()
+	view.cache = b.utxoCache
+	return view
+}
+
 // FetchUtxoView loads utxo details about the input transactions referenced by
 // the passed transaction from the point of view of the end of the main chain
 // while taking into account whether or not the transactions in the regular tree
@@ -896,7 +928,7 @@ func (b *BlockChain) FetchUtxoView(tx *dcrutil.Tx, includePrevRegularTxns bool)
 	// because the code below requires the parent block and the genesis
 	// block doesn't have one.
 	tip := b.bestChain.Tip()
-	view := NewUtxoViewpoint()
+	view := b.newUtxoViewpoint()
 	view.SetBestHash(&tip.hash)
 	if tip.height == 0 {
 		return view, nil
@@ -971,6 +1003,13 @@ func (b *BlockChain) FetchUtxoEntry(txHash *chainhash.Hash) (*UtxoEntry, error)
 	b.chainLock.RLock()
 	defer b.chainLock.RUnlock()
 
+	// Attempt to serve the entry from the utxo cache first when enabled.
+	if b.utxoCache != nil {
+		if entry := b.utxoCache.lookupEntry(txHash); entry != nil {
+			return entry, nil
+		}
+	}
+
 	var entry *UtxoEntry
 	err := b.db.View(func(dbTx database.Tx) error {
 		var err error
@@ -980,6 +1019,9 @@ func (b *BlockChain) FetchUtxoEntry(txHash *chainhash.Hash) (*UtxoEntry, error)
 	if err != nil {
 		return nil, err
 	}
+	if entry != nil && b.utxoCache != nil {
+		b.utxoCache.maybeAddEntry(txHash, entry)
+	}
 
 	return entry, nil
 }
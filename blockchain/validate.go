@@ -940,6 +940,14 @@ func (b *BlockChain) checkBlockHeaderPositional(header *wire.BlockHeader, prevNo
 		return ruleError(ErrBadCheckpoint, str)
 	}
 
+	// Ensure chain matches up to the assumed valid block when one is
+	// configured since script validation is skipped for its ancestors.
+	if !b.verifyAssumeValid(blockHeight, &blockHash) {
+		str := fmt.Sprintf("block at height %d does not match the assumed "+
+			"valid block hash", blockHeight)
+		return ruleError(ErrBadCheckpoint, str)
+	}
+
 	// Find the previous checkpoint and prevent blocks which fork the main
 	// chain before it.  This prevents storage of new, otherwise valid,
 	// blocks which build off of old blocks that are likely at a much easier
@@ -2883,6 +2891,12 @@ func (b *BlockChain) checkConnectBlock(node *blockNode, block, parent *dcrutil.B
 	if checkpoint != nil && node.height <= checkpoint.Height {
 		runScripts = false
 	}
+
+	// Similarly, don't run scripts for the configured assumed valid block and
+	// its ancestors.  See isAssumedValid for details.
+	if runScripts && b.isAssumedValid(node) {
+		runScripts = false
+	}
 	var scriptFlags txscript.ScriptFlags
 	if runScripts {
 		var err error
@@ -3124,7 +3138,7 @@ func (b *BlockChain) CheckConnectBlockTemplate(block *dcrutil.Block) error {
 			return ruleError(ErrMissingParent, err.Error())
 		}
 
-		view := NewUtxoViewpoint()
+		view := b.newUtxoViewpoint()
 		view.SetBestHash(&tip.hash)
 
 		return b.checkConnectBlock(newNode, block, parent, view, nil)
@@ -3134,7 +3148,7 @@ func (b *BlockChain) CheckConnectBlockTemplate(block *dcrutil.Block) error {
 	// current tip due to the previous checks, so undo the transactions and
 	// spend information for the tip block to reach the point of view of the
 	// block template.
-	view := NewUtxoViewpoint()
+	view := b.newUtxoViewpoint()
 	view.SetBestHash(&tip.hash)
 	tipBlock, err := b.fetchMainChainBlockByNode(tip)
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/decred/dcrd/dcrutil/v2"
 	"github.com/decred/dcrd/fees/v2"
 	"github.com/decred/dcrd/mempool/v3"
+	"github.com/decred/dcrd/peer/v2"
 	"github.com/decred/dcrd/wire"
 )
 
@@ -63,11 +64,75 @@ const (
 	// otherwise arise from sending old orphan blocks and forcing nodes to
 	// do expensive lottery data calculations for them.
 	maxReorgDepthNotify = 6
+
+	// syncRateWindow is the trailing window of time over which the rate of
+	// blocks connected during the initial block download is measured in
+	// order to estimate the time remaining until the chain is synced.
+	syncRateWindow = time.Minute * 5
 )
 
 // zeroHash is the zero value hash (all zeros).  It is defined as a convenience.
 var zeroHash chainhash.Hash
 
+// syncRateSample houses the height of a connected block along with the time it
+// was connected.
+type syncRateSample struct {
+	height int64
+	time   time.Time
+}
+
+// syncRateTracker tracks timestamped heights of blocks connected during the
+// initial block download over a trailing window in order to measure the rate
+// at which the chain is being synced.
+//
+// The tracker is safe for concurrent access.
+type syncRateTracker struct {
+	mtx     sync.Mutex
+	window  time.Duration
+	samples []syncRateSample
+}
+
+// prune removes all samples that are older than the window as of the provided
+// time.
+//
+// This function MUST be called with the tracker lock held.
+func (t *syncRateTracker) prune(now time.Time) {
+	cutoff := now.Add(-t.window)
+	var numExpired int
+	for numExpired < len(t.samples) && t.samples[numExpired].time.Before(cutoff) {
+		numExpired++
+	}
+	t.samples = t.samples[numExpired:]
+}
+
+// addSample records that a block at the provided height was connected at the
+// provided time.
+func (t *syncRateTracker) addSample(height int64, now time.Time) {
+	t.mtx.Lock()
+	t.prune(now)
+	t.samples = append(t.samples, syncRateSample{height: height, time: now})
+	t.mtx.Unlock()
+}
+
+// rate returns the number of blocks connected per second over the trailing
+// window as of the provided time.  Zero is returned when there are not enough
+// samples within the window to determine the rate.
+func (t *syncRateTracker) rate(now time.Time) float64 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.prune(now)
+	if len(t.samples) < 2 {
+		return 0
+	}
+	first, last := t.samples[0], t.samples[len(t.samples)-1]
+	elapsed := last.time.Sub(first.time).Seconds()
+	if elapsed <= 0 || last.height <= first.height {
+		return 0
+	}
+	return float64(last.height-first.height) / elapsed
+}
+
 // newPeerMsg signifies a newly connected peer to the block handler.
 type newPeerMsg struct {
 	peer *serverPeer
@@ -75,9 +140,13 @@ type newPeerMsg struct {
 
 // blockMsg packages a Decred block message and the peer it came from together
 // so the block handler has access to that information.
+//
+// The cmpct flag indicates the block was reconstructed from a compact block,
+// which peers are permitted to send without it being requested.
 type blockMsg struct {
 	block *dcrutil.Block
 	peer  *serverPeer
+	cmpct bool
 }
 
 // invMsg packages a Decred inv message and the peer it came from together
@@ -324,6 +393,10 @@ type blockManager struct {
 	// peers.
 	syncHeightMtx sync.Mutex
 	syncHeight    int64
+
+	// syncRate tracks the rate of blocks connected during the initial block
+	// download.
+	syncRate syncRateTracker
 }
 
 // resetHeaderState sets the headers-first mode state to values appropriate for
@@ -349,26 +422,47 @@ func (b *blockManager) SyncHeight() int64 {
 	return b.syncHeight
 }
 
+// SyncRate returns the number of blocks per second connected during the
+// initial block download over a trailing window.  Zero is returned when the
+// rate is unknown, such as when the chain is not being synced.
+//
+// This function is safe for concurrent access.
+func (b *blockManager) SyncRate() float64 {
+	return b.syncRate.rate(time.Now())
+}
+
+// assumeValidHeaderCheckpoint returns the configured assumed valid block when
+// it is after the passed height so the headers leading up to it are downloaded
+// and verified the same way as those leading up to a checkpoint.  It returns
+// nil otherwise.
+func assumeValidHeaderCheckpoint(height int64) *chaincfg.Checkpoint {
+	if cfg.assumeValid == nil || height >= cfg.assumeValid.Height {
+		return nil
+	}
+	return cfg.assumeValid
+}
+
 // findNextHeaderCheckpoint returns the next checkpoint after the passed height.
-// It returns nil when there is not one either because the height is already
-// later than the final checkpoint or some other reason such as disabled
-// checkpoints.
+// The configured assumed valid block is treated as the final checkpoint when it
+// is after all other checkpoints.  It returns nil when there is not one either
+// because the height is already later than the final checkpoint or some other
+// reason such as disabled checkpoints.
 func (b *blockManager) findNextHeaderCheckpoint(height int64) *chaincfg.Checkpoint {
-	// There is no next checkpoint if checkpoints are disabled or there are
-	// none for this current network.
+	// There is no next checkpoint aside from the assumed valid block if
+	// checkpoints are disabled or there are none for this current network.
 	if cfg.DisableCheckpoints {
-		return nil
+		return assumeValidHeaderCheckpoint(height)
 	}
 	checkpoints := b.cfg.Chain.Checkpoints()
 	if len(checkpoints) == 0 {
-		return nil
+		return assumeValidHeaderCheckpoint(height)
 	}
 
-	// There is no next checkpoint if the height is already after the final
-	// checkpoint.
+	// There is no next checkpoint aside from the assumed valid block if the
+	// height is already after the final checkpoint.
 	finalCheckpoint := &checkpoints[len(checkpoints)-1]
 	if height >= finalCheckpoint.Height {
-		return nil
+		return assumeValidHeaderCheckpoint(height)
 	}
 
 	// Find the next checkpoint.
@@ -463,14 +557,17 @@ func (b *blockManager) startSync(peers *list.List) {
 		// and compared against the value in the header which proves the
 		// full block hasn't been tampered with.
 		//
+		// The same approach is used to learn about the blocks leading up
+		// to the assumed valid block, if any, although those blocks are
+		// fully validated aside from their scripts.
+		//
 		// Once we have passed the final checkpoint, or checkpoints are
 		// disabled, use standard inv messages learn about the blocks
 		// and fully validate them.  Finally, regression test mode does
 		// not support the headers-first approach so do normal block
 		// downloads when in regression test mode.
 		if b.nextCheckpoint != nil &&
-			best.Height < b.nextCheckpoint.Height &&
-			!cfg.DisableCheckpoints {
+			best.Height < b.nextCheckpoint.Height {
 
 			err := bestPeer.PushGetHeadersMsg(locator, b.nextCheckpoint.Hash)
 			if err != nil {
@@ -525,6 +622,26 @@ func (b *blockManager) syncMiningStateAfterSync(sp *serverPeer) {
 	}()
 }
 
+// sendHeadersAfterSync polls the blockManager for the current sync state; once
+// the manager is synced, it sends the peer the request to announce new blocks
+// via headers that was deferred during version negotiation.
+func (b *blockManager) sendHeadersAfterSync(sp *serverPeer) {
+	go func() {
+		for {
+			time.Sleep(3 * time.Second)
+			if !sp.Connected() {
+				return
+			}
+			if b.IsCurrent() {
+				if msg := sp.deferredHeadersRequest(); msg != nil {
+					sp.QueueMessage(msg, nil)
+				}
+				return
+			}
+		}
+	}()
+}
+
 // handleNewPeerMsg deals with new peers that have signalled they may
 // be considered as a sync peer (they have already successfully negotiated).  It
 // also starts syncing if needed.  It is invoked from the syncHandler goroutine.
@@ -777,11 +894,45 @@ func calcTxTreeMerkleRoot(transactions []*dcrutil.Tx) chainhash.Hash {
 	return standalone.CalcMerkleRootInPlace(leaves)
 }
 
+// notifyStakeDifficulty notifies registered websocket clients of the stake
+// difficulty required for the block after the provided best chain state along
+// with the expected stake difficulty of the next stake difficulty retarget
+// interval.  Nothing is done when there are no registered clients and the
+// expected stake difficulty is only estimated when the chain is current since
+// it requires loading the headers since the last retarget for every block.
+//
+// This function MUST be called from the block manager goroutine so the best
+// chain can't change while the expected stake difficulty is estimated.
+func (b *blockManager) notifyStakeDifficulty(r *rpcServer, best *blockchain.BestState) {
+	if !r.ntfnMgr.HasStakeDifficultyClients() {
+		return
+	}
+
+	var estimatedStakeDiff *int64
+	if b.current() {
+		estimate, err := estimateExpectedStakeDiff(b.cfg.Chain,
+			b.cfg.ChainParams)
+		if err != nil {
+			bmgrLog.Errorf("Failed to estimate stake difficulty: %v", err)
+		} else {
+			estimatedStakeDiff = &estimate
+		}
+	}
+
+	r.ntfnMgr.NotifyStakeDifficulty(&StakeDifficultyNtfnData{
+		BlockHash:                best.Hash,
+		BlockHeight:              best.Height,
+		StakeDifficulty:          best.NextStakeDiff,
+		EstimatedStakeDifficulty: estimatedStakeDiff,
+	})
+}
+
 // handleBlockMsg handles block messages from all peers.
 func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	// If we didn't ask for this block then the peer is misbehaving.
 	blockHash := bmsg.block.Hash()
-	if _, exists := bmsg.peer.requestedBlocks[*blockHash]; !exists {
+	_, exists := bmsg.peer.requestedBlocks[*blockHash]
+	if !exists && !bmsg.cmpct {
 		bmgrLog.Warnf("Got unrequested block %v from %s -- "+
 			"disconnecting", blockHash, bmsg.peer.Addr())
 		bmsg.peer.Disconnect()
@@ -802,7 +953,13 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 		if firstNodeEl != nil {
 			firstNode := firstNodeEl.Value.(*headerNode)
 			if blockHash.IsEqual(firstNode.hash) {
-				behaviorFlags |= blockchain.BFFastAdd
+				// Blocks leading up to the assumed valid block are
+				// fully validated aside from their scripts, which the
+				// chain skips since their headers are known to lead
+				// to it.
+				if b.nextCheckpoint != cfg.assumeValid {
+					behaviorFlags |= blockchain.BFFastAdd
+				}
 				if firstNode.hash.IsEqual(b.nextCheckpoint.Hash) {
 					isCheckpointBlock = true
 				} else {
@@ -898,12 +1055,7 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 			if r != nil {
 				// Update registered websocket clients on the
 				// current stake difficulty.
-				r.ntfnMgr.NotifyStakeDifficulty(
-					&StakeDifficultyNtfnData{
-						best.Hash,
-						best.Height,
-						best.NextStakeDiff,
-					})
+				b.notifyStakeDifficulty(r, best)
 			}
 			b.cfg.TxMemPool.PruneStakeTx(best.NextStakeDiff, best.Height)
 			b.cfg.TxMemPool.PruneExpiredTx()
@@ -982,6 +1134,27 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	}
 }
 
+// setAssumeValidHeaders provides the chain with the hashes of the headers in the
+// header list, which have been verified to link together and end with the
+// assumed valid block.  The first entry of the list is the block that is
+// already in the database that the headers build on.
+func (b *blockManager) setAssumeValidHeaders() {
+	parentEl := b.headerList.Front()
+	if parentEl == nil {
+		return
+	}
+	parent := parentEl.Value.(*headerNode)
+	hashes := make([]chainhash.Hash, 0, b.headerList.Len()-1)
+	for e := parentEl.Next(); e != nil; e = e.Next() {
+		hashes = append(hashes, *e.Value.(*headerNode).hash)
+	}
+	err := b.cfg.Chain.SetAssumeValidHeaders(parent.hash, hashes)
+	if err != nil {
+		bmgrLog.Warnf("Unable to set headers leading to the assumed valid "+
+			"block: %v", err)
+	}
+}
+
 // fetchHeaderBlocks creates and sends a request to the syncPeer for the next
 // list of blocks to be downloaded based on the current list of headers.
 func (b *blockManager) fetchHeaderBlocks() {
@@ -1031,12 +1204,41 @@ func (b *blockManager) fetchHeaderBlocks() {
 	}
 }
 
+// handleHeaderAnnouncements handles headers messages from peers that were asked
+// to announce new blocks via headers by converting them to the equivalent
+// block inventory announcements.
+func (b *blockManager) handleHeaderAnnouncements(hmsg *headersMsg) {
+	headers := hmsg.headers.Headers
+	inv := wire.NewMsgInvSizeHint(uint(len(headers)))
+	for _, header := range headers {
+		blockHash := header.BlockHash()
+		iv := wire.NewInvVect(wire.InvTypeBlock, &blockHash)
+		if err := inv.AddInvVect(iv); err != nil {
+			bmgrLog.Warnf("Too many headers announced by %s -- "+
+				"disconnecting", hmsg.peer.Addr())
+			hmsg.peer.Disconnect()
+			return
+		}
+	}
+	if len(inv.InvList) > 0 {
+		b.handleInvMsg(&invMsg{inv: inv, peer: hmsg.peer})
+	}
+}
+
 // handleHeadersMsg handles headers messages from all peers.
 func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 	// The remote peer is misbehaving if we didn't request headers.
 	msg := hmsg.headers
 	numHeaders := len(msg.Headers)
 	if !b.headersFirstMode {
+		// Peers that were asked to announce new blocks via headers send
+		// them unrequested, so handle them the same way as block
+		// inventory announcements.
+		if hmsg.peer.requestedBlockAnnounce() == peer.BlockAnnounceHeaders {
+			b.handleHeaderAnnouncements(hmsg)
+			return
+		}
+
 		bmgrLog.Warnf("Got %d unrequested headers from %s -- "+
 			"disconnecting", numHeaders, hmsg.peer.Addr())
 		hmsg.peer.Disconnect()
@@ -1107,6 +1309,12 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 	// When this header is a checkpoint, switch to fetching the blocks for
 	// all of the headers since the last checkpoint.
 	if receivedCheckpoint {
+		// Let the chain know the headers lead to the assumed valid block
+		// so the blocks they describe skip script validation.
+		if b.nextCheckpoint == cfg.assumeValid {
+			b.setAssumeValidHeaders()
+		}
+
 		// Since the first entry of the list is always the final block
 		// that is already in the database and is only used to ensure
 		// the next header links properly, it must be removed before
@@ -1429,12 +1637,7 @@ out:
 					best := b.cfg.Chain.BestSnapshot()
 					r := b.cfg.RpcServer()
 					if r != nil {
-						r.ntfnMgr.NotifyStakeDifficulty(
-							&StakeDifficultyNtfnData{
-								best.Hash,
-								best.Height,
-								best.NextStakeDiff,
-							})
+						b.notifyStakeDifficulty(r, best)
 					}
 					b.cfg.TxMemPool.PruneStakeTx(best.NextStakeDiff,
 						best.Height)
@@ -1471,12 +1674,7 @@ out:
 					// invalidated transactions.
 					best := b.cfg.Chain.BestSnapshot()
 					if r != nil {
-						r.ntfnMgr.NotifyStakeDifficulty(
-							&StakeDifficultyNtfnData{
-								best.Hash,
-								best.Height,
-								best.NextStakeDiff,
-							})
+						b.notifyStakeDifficulty(r, best)
 					}
 					b.cfg.TxMemPool.PruneStakeTx(best.NextStakeDiff,
 						best.Height)
@@ -1577,7 +1775,7 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 
 		// Generate the inventory vector and relay it immediately.
 		iv := wire.NewInvVect(wire.InvTypeBlock, block.Hash())
-		b.cfg.PeerNotifier.RelayInventory(iv, block.MsgBlock().Header, true)
+		b.cfg.PeerNotifier.RelayInventory(iv, block, true)
 		b.announcedBlockMtx.Lock()
 		b.announcedBlock = block.Hash()
 		b.announcedBlockMtx.Unlock()
@@ -1586,14 +1784,6 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 	// (will be ignored if already relayed via NTNewTipBlockChecked) and
 	// possibly notify RPC clients with the winning tickets.
 	case blockchain.NTBlockAccepted:
-		// Don't relay or notify RPC clients with winning tickets if we
-		// are not current. Other peers that are current should already
-		// know about it and clients, such as wallets, shouldn't be voting on
-		// old blocks.
-		if !b.current() {
-			return
-		}
-
 		band, ok := notification.Data.(*blockchain.BlockAcceptedNtfnsData)
 		if !ok {
 			bmgrLog.Warnf("Chain accepted notification is not " +
@@ -1602,6 +1792,20 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 		}
 		block := band.Block
 
+		// Notify registered websocket clients of blocks that were accepted
+		// into a side chain without being connected to the main chain.
+		if r := b.cfg.RpcServer(); r != nil && band.ForkLen != 0 {
+			r.ntfnMgr.NotifySideChainBlockAccepted(band)
+		}
+
+		// Don't relay or notify RPC clients with winning tickets if we
+		// are not current. Other peers that are current should already
+		// know about it and clients, such as wallets, shouldn't be voting on
+		// old blocks.
+		if !b.current() {
+			return
+		}
+
 		// Send a winning tickets notification as needed.  The notification will
 		// only be sent when the following conditions hold:
 		//
@@ -1665,7 +1869,7 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 		b.announcedBlockMtx.Unlock()
 		if !sent {
 			iv := wire.NewInvVect(wire.InvTypeBlock, blockHash)
-			b.cfg.PeerNotifier.RelayInventory(iv, block.MsgBlock().Header, true)
+			b.cfg.PeerNotifier.RelayInventory(iv, block, true)
 		}
 
 		// Inform the background block template generator about the accepted
@@ -1697,6 +1901,12 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 		block := blockSlice[0]
 		parentBlock := blockSlice[1]
 
+		// Track the rate blocks are connected during the initial block
+		// download for estimating the time remaining until synced.
+		if !b.cfg.Chain.IsCurrent() {
+			b.syncRate.addSample(block.Height(), time.Now())
+		}
+
 		// Account for transactions mined in the newly connected block for fee
 		// estimation. This must be done before attempting to remove
 		// transactions from the mempool because the mempool will alert the
@@ -1949,6 +2159,18 @@ func (b *blockManager) QueueBlock(block *dcrutil.Block, sp *serverPeer) {
 	b.msgChan <- &blockMsg{block: block, peer: sp}
 }
 
+// QueueCmpctBlock adds the passed block that was reconstructed from a compact
+// block along with the peer that sent it to the block handling queue.
+func (b *blockManager) QueueCmpctBlock(block *dcrutil.Block, sp *serverPeer) {
+	// Don't accept more blocks if we're shutting down.
+	if atomic.LoadInt32(&b.shutdown) != 0 {
+		sp.blockProcessed <- struct{}{}
+		return
+	}
+
+	b.msgChan <- &blockMsg{block: block, peer: sp, cmpct: true}
+}
+
 // QueueInv adds the passed inv message and peer to the block handling queue.
 func (b *blockManager) QueueInv(inv *wire.MsgInv, sp *serverPeer) {
 	// No channel handling here because peers do not need to block on inv
@@ -2218,20 +2440,21 @@ func newBlockManager(config *blockManagerConfig) (*blockManager, error) {
 		headerList:       list.New(),
 		AggressiveMining: !cfg.NonAggressive,
 		quit:             make(chan struct{}),
+		syncRate:         syncRateTracker{window: syncRateWindow},
 	}
 
 	best := bm.cfg.Chain.BestSnapshot()
 	bm.cfg.Chain.DisableCheckpoints(cfg.DisableCheckpoints)
-	if !cfg.DisableCheckpoints {
-		// Initialize the next checkpoint based on the current height.
-		bm.nextCheckpoint = bm.findNextHeaderCheckpoint(best.Height)
-		if bm.nextCheckpoint != nil {
-			bm.resetHeaderState(&best.Hash, best.Height)
-		}
-	} else {
+	if cfg.DisableCheckpoints {
 		bmgrLog.Info("Checkpoints are disabled")
 	}
 
+	// Initialize the next checkpoint based on the current height.
+	bm.nextCheckpoint = bm.findNextHeaderCheckpoint(best.Height)
+	if bm.nextCheckpoint != nil {
+		bm.resetHeaderState(&best.Hash, best.Height)
+	}
+
 	// Dump the blockchain here if asked for it, and quit.
 	if cfg.DumpBlockchain != "" {
 		err := dumpBlockChain(bm.cfg.Chain, best.Height)
@@ -2349,8 +2572,12 @@ func loadBlockDB() (database.DB, error) {
 	// each run, so remove it now if it already exists.
 	removeRegressionDB(dbPath)
 
+	// The database cache size is specified in MiB.
+	cacheSize := cfg.DbCacheSize * 1024 * 1024
+
 	dcrdLog.Infof("Loading block database from '%s'", dbPath)
-	db, err := database.Open(cfg.DbType, dbPath, activeNetParams.Net)
+	db, err := database.Open(cfg.DbType, dbPath, activeNetParams.Net,
+		cacheSize)
 	if err != nil {
 		// Return the error if it's not because the database doesn't
 		// exist.
@@ -2365,7 +2592,8 @@ func loadBlockDB() (database.DB, error) {
 		if err != nil {
 			return nil, err
 		}
-		db, err = database.Create(cfg.DbType, dbPath, activeNetParams.Net)
+		db, err = database.Create(cfg.DbType, dbPath, activeNetParams.Net,
+			cacheSize)
 		if err != nil {
 			return nil, err
 		}
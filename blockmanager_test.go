@@ -0,0 +1,80 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+)
+
+// TestSyncRateTracker ensures the sync rate tracker calculates the rate of
+// connected blocks over its trailing window and reports an unknown rate when
+// there are not enough samples.
+func TestSyncRateTracker(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name   string
+		height int64
+		offset time.Duration
+		want   float64
+	}{
+		{"single sample", 100, 0, 0},
+		{"second sample", 150, 10 * time.Second, 5},
+		{"third sample", 300, 30 * time.Second, 200.0 / 30},
+		{"no progress", 300, 40 * time.Second, 5},
+		{"first sample expires", 400, 5*time.Minute + 5*time.Second, 250.0 / 295},
+		{"all but one expire", 500, 15 * time.Minute, 0},
+	}
+
+	tracker := syncRateTracker{window: 5 * time.Minute}
+	for _, test := range tests {
+		now := start.Add(test.offset)
+		tracker.addSample(test.height, now)
+		got := tracker.rate(now)
+		if got != test.want {
+			t.Fatalf("%q: unexpected rate -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+
+	// Ensure the rate is unknown once all samples are outside of the window.
+	if got := tracker.rate(start.Add(time.Hour)); got != 0 {
+		t.Fatalf("unexpected rate with expired samples -- got %v, want 0",
+			got)
+	}
+}
+
+// TestFindNextHeaderCheckpointAssumeValid ensures the assumed valid block is
+// treated as the next header checkpoint when it is after the passed height and
+// there are no other checkpoints.
+func TestFindNextHeaderCheckpointAssumeValid(t *testing.T) {
+	assumeValid := &chaincfg.Checkpoint{Height: 100, Hash: &chainhash.Hash{1}}
+	tests := []struct {
+		name        string
+		assumeValid *chaincfg.Checkpoint
+		height      int64
+		want        *chaincfg.Checkpoint
+	}{
+		{"no assumed valid block", nil, 0, nil},
+		{"below assumed valid block", assumeValid, 99, assumeValid},
+		{"at assumed valid block", assumeValid, 100, nil},
+		{"above assumed valid block", assumeValid, 101, nil},
+	}
+
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+
+	var b blockManager
+	for _, test := range tests {
+		cfg = &config{DisableCheckpoints: true, assumeValid: test.assumeValid}
+		if got := b.findNextHeaderCheckpoint(test.height); got != test.want {
+			t.Fatalf("%q: unexpected checkpoint -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
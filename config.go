@@ -7,6 +7,7 @@ package main
 
 import (
 	"crypto/rand"
+	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -14,21 +15,29 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/blockchain/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/decred/dcrd/connmgr/v2"
 	"github.com/decred/dcrd/database/v2"
 	_ "github.com/decred/dcrd/database/v2/ffldb"
 	"github.com/decred/dcrd/dcrutil/v2"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/mempool/v3"
+	"github.com/decred/dcrd/peer/v2"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/sampleconfig"
+	"github.com/decred/dcrd/wire"
 	"github.com/decred/go-socks/socks"
 	"github.com/decred/slog"
 	flags "github.com/jessevdk/go-flags"
@@ -40,14 +49,33 @@ const (
 	defaultLogLevel              = "info"
 	defaultLogDirname            = "logs"
 	defaultLogFilename           = "dcrd.log"
+	defaultRPCAuditLogFilename   = "rpcaudit.log"
 	defaultMaxSameIP             = 5
 	defaultMaxPeers              = 125
+	defaultMinOutboundGroups     = 4
+	defaultFeelerInterval        = time.Minute * 2
+	defaultAddrFailureThreshold  = 10
+	defaultPingInterval          = time.Minute * 2
 	defaultBanDuration           = time.Hour * 24
+	defaultMaxTimeAdjustment     = blockchain.DefaultMaxTimeOffset
 	defaultBanThreshold          = 100
+	defaultBanEscalationWindow   = time.Hour
 	defaultMaxRPCClients         = 10
 	defaultMaxRPCWebsockets      = 25
 	defaultMaxRPCConcurrentReqs  = 20
+	defaultRPCMaxNtfnQueue       = 10000
+	defaultRPCMaxRescans         = 2
+	defaultRPCNtfnOverflow       = ntfnOverflowDisconnect
+	defaultRPCTLSMinVersion      = "1.2"
+	defaultBlockIntervalWindow   = 144
+	defaultFinalityDepth         = 6
 	defaultDbType                = "ffldb"
+	defaultDbCacheSize           = 100
+	minDbCacheSize               = 4
+	maxDbCacheSize               = 1024 * 1024 // 1 TiB
+	minRetainBlocks              = 288
+	minRebroadcastInterval       = time.Minute
+	defaultMaxGetDataInv         = wire.MaxInvPerMsg
 	defaultFreeTxRelayLimit      = 15.0
 	defaultBlockMinSize          = 0
 	defaultBlockMaxSize          = 375000
@@ -59,9 +87,16 @@ const (
 	defaultMaxOrphanTransactions = 1000
 	defaultMaxOrphanTxSize       = 5000
 	defaultSigCacheMaxSize       = 100000
+	defaultUtxoCacheMaxSize      = 150
 	defaultTxIndex               = false
 	defaultNoExistsAddrIndex     = false
 	defaultNoCFilters            = false
+	defaultBlockAnnounce         = "compact"
+	defaultInvSuppressWindow     = time.Second * 5
+
+	// unixListenPrefix is the prefix used to specify a unix domain socket
+	// path for the P2P and RPC listen addresses.
+	unixListenPrefix = "unix:"
 )
 
 var (
@@ -92,95 +127,148 @@ func minUint32(a, b uint32) uint32 {
 //
 // See loadConfig for details on the configuration load process.
 type config struct {
-	HomeDir              string        `short:"A" long:"appdata" description:"Path to application home directory"`
-	ShowVersion          bool          `short:"V" long:"version" description:"Display version information and exit"`
-	ConfigFile           string        `short:"C" long:"configfile" description:"Path to configuration file"`
-	DataDir              string        `short:"b" long:"datadir" description:"Directory to store data"`
-	LogDir               string        `long:"logdir" description:"Directory to log output."`
-	NoFileLogging        bool          `long:"nofilelogging" description:"Disable file logging."`
-	AddPeers             []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
-	ConnectPeers         []string      `long:"connect" description:"Connect only to the specified peers at startup"`
-	DisableListen        bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
-	Listeners            []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9108, testnet: 19108)"`
-	MaxSameIP            int           `long:"maxsameip" description:"Max number of connections with the same IP -- 0 to disable"`
-	MaxPeers             int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
-	DisableBanning       bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
-	BanDuration          time.Duration `long:"banduration" description:"How long to ban misbehaving peers.  Valid time units are {s, m, h}.  Minimum 1 second"`
-	BanThreshold         uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
-	Whitelists           []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned. (eg. 192.168.1.0/24 or ::1)"`
-	RPCUser              string        `short:"u" long:"rpcuser" description:"Username for RPC connections"`
-	RPCPass              string        `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
-	RPCLimitUser         string        `long:"rpclimituser" description:"Username for limited RPC connections"`
-	RPCLimitPass         string        `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
-	RPCListeners         []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109)"`
-	RPCCert              string        `long:"rpccert" description:"File containing the certificate file"`
-	RPCKey               string        `long:"rpckey" description:"File containing the certificate key"`
-	RPCMaxClients        int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
-	RPCMaxWebsockets     int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
-	RPCMaxConcurrentReqs int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
-	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
-	DisableTLS           bool          `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
-	DisableDNSSeed       bool          `long:"nodnsseed" description:"Disable DNS seeding for peers"`
-	ExternalIPs          []string      `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
-	Proxy                string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
-	ProxyUser            string        `long:"proxyuser" description:"Username for proxy server"`
-	ProxyPass            string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
-	OnionProxy           string        `long:"onion" description:"Connect to tor hidden services via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
-	OnionProxyUser       string        `long:"onionuser" description:"Username for onion proxy server"`
-	OnionProxyPass       string        `long:"onionpass" default-mask:"-" description:"Password for onion proxy server"`
-	NoOnion              bool          `long:"noonion" description:"Disable connecting to tor hidden services"`
-	NoDiscoverIP         bool          `long:"nodiscoverip" description:"Disable automatic network address discovery"`
-	TorIsolation         bool          `long:"torisolation" description:"Enable Tor stream isolation by randomizing user credentials for each connection."`
-	TestNet              bool          `long:"testnet" description:"Use the test network"`
-	SimNet               bool          `long:"simnet" description:"Use the simulation test network"`
-	RegNet               bool          `long:"regnet" description:"Use the regression test network"`
-	DisableCheckpoints   bool          `long:"nocheckpoints" description:"Disable built-in checkpoints.  Don't do this unless you know what you're doing."`
-	DbType               string        `long:"dbtype" description:"Database backend to use for the Block Chain"`
-	Profile              string        `long:"profile" description:"Enable HTTP profiling on given [addr:]port -- NOTE port must be between 1024 and 65536"`
-	CPUProfile           string        `long:"cpuprofile" description:"Write CPU profile to the specified file"`
-	MemProfile           string        `long:"memprofile" description:"Write mem profile to the specified file"`
-	DumpBlockchain       string        `long:"dumpblockchain" description:"Write blockchain as a flat file of blocks for use with addblock, to the specified filename"`
-	MiningTimeOffset     int           `long:"miningtimeoffset" description:"Offset the mining timestamp of a block by this many seconds (positive values are in the past)"`
-	DebugLevel           string        `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
-	Upnp                 bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
-	MinRelayTxFee        float64       `long:"minrelaytxfee" description:"The minimum transaction fee in DCR/kB to be considered a non-zero fee."`
-	FreeTxRelayLimit     float64       `long:"limitfreerelay" description:"Limit relay of transactions with no transaction fee to the given amount in thousands of bytes per minute"`
-	NoRelayPriority      bool          `long:"norelaypriority" description:"Do not require free or low-fee transactions to have high priority for relaying"`
-	MaxOrphanTxs         int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
-	Generate             bool          `long:"generate" description:"Generate (mine) coins using the CPU"`
-	MiningAddrs          []string      `long:"miningaddr" description:"Add the specified payment address to the list of addresses to use for generated blocks -- At least one address is required if the generate option is set"`
-	BlockMinSize         uint32        `long:"blockminsize" description:"Minimum block size in bytes to be used when creating a block"`
-	BlockMaxSize         uint32        `long:"blockmaxsize" description:"Maximum block size in bytes to be used when creating a block"`
-	BlockPrioritySize    uint32        `long:"blockprioritysize" description:"Size in bytes for high-priority/low-fee transactions when creating a block"`
-	SigCacheMaxSize      uint          `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
-	NonAggressive        bool          `long:"nonaggressive" description:"Disable mining off of the parent block of the blockchain if there aren't enough voters"`
-	NoMiningStateSync    bool          `long:"nominingstatesync" description:"Disable synchronizing the mining state with other nodes"`
-	AllowOldVotes        bool          `long:"allowoldvotes" description:"Enable the addition of very old votes to the mempool"`
-	BlocksOnly           bool          `long:"blocksonly" description:"Do not accept transactions from remote peers."`
-	AcceptNonStd         bool          `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network."`
-	RejectNonStd         bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
-	TxIndex              bool          `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
-	DropTxIndex          bool          `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits."`
-	AddrIndex            bool          `long:"addrindex" description:"Maintain a full address-based transaction index which makes the searchrawtransactions RPC available"`
-	DropAddrIndex        bool          `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits."`
-	NoExistsAddrIndex    bool          `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used."`
-	DropExistsAddrIndex  bool          `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits."`
-	NoCFilters           bool          `long:"nocfilters" description:"Disable compact filtering (CF) support"`
-	DropCFIndex          bool          `long:"dropcfindex" description:"Deletes the index used for compact filtering (CF) support from the database on start up and then exits."`
-	PipeRx               uint          `long:"piperx" description:"File descriptor of read end pipe to enable parent -> child process communication"`
-	PipeTx               uint          `long:"pipetx" description:"File descriptor of write end pipe to enable parent <- child process communication"`
-	LifetimeEvents       bool          `long:"lifetimeevents" description:"Send lifetime notifications over the TX pipe"`
-	AltDNSNames          []string      `long:"altdnsnames" description:"Specify additional dns names to use when generating the rpc server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
-	onionlookup          func(string) ([]net.IP, error)
-	lookup               func(string) ([]net.IP, error)
-	oniondial            func(string, string) (net.Conn, error)
-	dial                 func(string, string) (net.Conn, error)
-	miningAddrs          []dcrutil.Address
-	minRelayTxFee        dcrutil.Amount
-	whitelists           []*net.IPNet
-	ipv4NetInfo          types.NetworksResult
-	ipv6NetInfo          types.NetworksResult
-	onionNetInfo         types.NetworksResult
+	HomeDir               string        `short:"A" long:"appdata" description:"Path to application home directory"`
+	ShowVersion           bool          `short:"V" long:"version" description:"Display version information and exit"`
+	ConfigFile            string        `short:"C" long:"configfile" description:"Path to configuration file"`
+	DataDir               string        `short:"b" long:"datadir" description:"Directory to store data"`
+	LogDir                string        `long:"logdir" description:"Directory to log output."`
+	NoFileLogging         bool          `long:"nofilelogging" description:"Disable file logging."`
+	AddPeers              []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
+	ConnectPeers          []string      `long:"connect" description:"Connect only to the specified peers at startup"`
+	DisableListen         bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
+	Listeners             []string      `long:"listen" description:"Add an interface/port or unix:/path/to/socket to listen for connections (default all interfaces port: 9108, testnet: 19108)"`
+	MaxSameIP             int           `long:"maxsameip" description:"Max number of connections with the same IP -- 0 to disable"`
+	MaxSameIPInbound      int           `long:"maxsameipinbound" description:"Max number of inbound connections with the same IP, which is enforced in addition to maxsameip -- 0 to disable"`
+	MaxPeers              int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
+	MinOutboundGroups     int           `long:"minoutboundgroups" description:"Min number of distinct network groups among outbound peers -- The final automatic outbound connection slots are reserved for peers in new network groups until it is reached -- 0 to disable"`
+	MaxConcurrentDials    uint32        `long:"maxconcurrentdials" description:"Max number of outbound connection attempts that may be dialing at once -- 0 for unlimited"`
+	PendingConnTimeout    time.Duration `long:"pendingconntimeout" description:"Abandon outbound connection attempts that remain pending for longer than the specified duration and try another address instead.  Valid time units are {s, m, h} -- 0 to disable"`
+	FeelerInterval        time.Duration `long:"feelerinterval" description:"Interval between short-lived feeler connections made to untried addresses to verify they are reachable once the outbound peer slots are full.  Valid time units are {s, m, h} -- 0 to disable"`
+	AddrFailureThreshold  int           `long:"addrfailurethreshold" description:"Number of consecutive failed connections after which an address that has never been successfully connected to is evicted from the address manager -- 0 to disable"`
+	PingInterval          time.Duration `long:"pinginterval" description:"Interval between pings sent to each peer to measure latency and detect unresponsive connections.  Valid time units are {s, m, h}.  Minimum 1 second"`
+	MaxMissedPongs        uint32        `long:"maxmissedpongs" description:"Number of consecutive pings a peer may fail to answer before it is disconnected -- 0 to disable"`
+	MaxTimeAdjustment     time.Duration `long:"maxtimeadjustment" description:"Max amount of time the local clock is adjusted by in either direction based on the median time reported by peers -- No adjustment is made when the median exceeds it.  Valid time units are {s, m, h}.  Maximum 1h10m0s -- 0 to disable"`
+	MinProtocolVersion    uint32        `long:"minprotocolversion" description:"Minimum protocol version to accept from inbound and outbound peers -- 0 to accept all versions supported by the wire protocol"`
+	DisableBanning        bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
+	BanDuration           time.Duration `long:"banduration" description:"How long to ban misbehaving peers.  Valid time units are {s, m, h}.  Minimum 1 second"`
+	BanThreshold          uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
+	BanEscalation         uint32        `long:"banescalation" description:"Ban the entire /24 (IPv4) or /64 (IPv6) subnet of misbehaving peers once this many distinct IPs within it are banned within the ban escalation window -- 0 to disable, otherwise minimum 2"`
+	BanEscalationWindow   time.Duration `long:"banescalationwindow" description:"The window of time in which bans of distinct IPs within the same subnet count toward escalating to a subnet ban.  Valid time units are {s, m, h}.  Minimum 1 second"`
+	Whitelists            []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned and is exempt from the maxsameip, maxsameipinbound, and maxpeers limits as well as outbound network group diversity. (eg. 192.168.1.0/24 or ::1)"`
+	WhitelistUserAgents   []string      `long:"whitelistuseragent" description:"Exempt peers whose user agent contains the specified substring from ban scoring -- They remain subject to connection limits and bans since user agents are reported by peers and can be trivially spoofed -- may be specified multiple times"`
+	RPCUser               string        `short:"u" long:"rpcuser" description:"Username for RPC connections"`
+	RPCPass               string        `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
+	RPCLimitUser          string        `long:"rpclimituser" description:"Username for limited RPC connections"`
+	RPCLimitPass          string        `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
+	RPCListeners          []string      `long:"rpclisten" description:"Add an interface/port or unix:/path/to/socket to listen for RPC connections (default port: 9109, testnet: 19109)"`
+	RPCCert               string        `long:"rpccert" description:"File containing the certificate file"`
+	RPCKey                string        `long:"rpckey" description:"File containing the certificate key"`
+	RPCTLSMinVersion      string        `long:"rpctlsminversion" description:"Minimum TLS version for RPC connections {1.2, 1.3}"`
+	RPCTLSCipherSuites    []string      `long:"rpctlsciphersuite" description:"Add a cipher suite to allow for RPC connections using TLS 1.2, such as TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384 -- All secure cipher suites are allowed if none are specified"`
+	RPCClientCA           string        `long:"rpcclientca" description:"File containing the certificate authorities used to verify RPC client certificates -- Clients that present a valid certificate without a username and password are authenticated with limited access"`
+	RPCAdminClientCNs     []string      `long:"rpcadminclientcn" description:"Add a client certificate common name that grants admin RPC access"`
+	RPCMaxClients         int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
+	RPCMaxWebsockets      int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
+	RPCMaxConcurrentReqs  int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	RPCMaxRescans         int           `long:"rpcmaxrescans" description:"Max number of rescans requested by websocket clients that may run concurrently -- Additional rescans wait for a running one to finish -- 0 for unlimited"`
+	RPCMaxResponseSize    int64         `long:"rpcmaxresponsesize" description:"Max size in bytes of a single marshalled RPC response -- 0 for unlimited"`
+	RPCMaxNtfnQueue       int           `long:"rpcmaxntfnqueue" description:"Max number of notifications that may be queued for a single websocket client before the notification overflow policy is applied -- 0 for unlimited"`
+	RPCNtfnOverflow       string        `long:"rpcntfnoverflow" description:"Action to take when the notification queue of a websocket client is full {dropoldest, disconnect}"`
+	BlockIntervalWindow   uint32        `long:"blockintervalwindow" description:"Number of most recent blocks used to calculate the median block interval reported by getblockchaininfo"`
+	FinalityDepth         uint32        `long:"finalitydepth" description:"Default number of confirmations a block requires to be reported as final by the isblockfinal RPC"`
+	RPCIdleTimeout        time.Duration `long:"rpcidletimeout" description:"Disconnect websocket RPC clients without any requests, notifications, or pings for the specified duration.  Valid time units are {s, m, h} -- 0 to disable"`
+	RPCAuditLog           bool          `long:"rpcauditlog" description:"Log every RPC command along with whether the client is an admin or limited user, the method, a hash of the parameters, and the result to rpcaudit.log in the log directory"`
+	RPCDebugDump          bool          `long:"rpcdebugdump" description:"Enable the admin-only debugdump RPC which returns goroutine stack dumps and writes profiles to the data directory"`
+	DisableRPC            bool          `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
+	DisableTLS            bool          `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
+	DisableDNSSeed        bool          `long:"nodnsseed" description:"Disable DNS seeding for peers"`
+	DNSSeeds              []string      `long:"dnsseed" description:"Add a DNS seed host to query for peers in addition to the built-in seeds for the active network"`
+	ReplaceDNSSeeds       bool          `long:"replacednsseeds" description:"Only query the DNS seeds specified with --dnsseed instead of also querying the built-in seeds for the active network"`
+	DNSSeedServices       []string      `long:"dnsseedservice" description:"Only add peers from DNS seeds that are reported to provide the specified service in addition to being full nodes {bloom, cf, cmpctblock} -- may be specified multiple times"`
+	AdvertiseServices     []string      `long:"advertiseservice" description:"Only advertise the specified service to peers instead of all services the node supports {network, networklimited, cf, cmpctblock} -- may be specified multiple times"`
+	ExternalIPs           []string      `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
+	Proxy                 string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
+	ProxyUser             string        `long:"proxyuser" description:"Username for proxy server"`
+	ProxyPass             string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
+	OnionProxy            string        `long:"onion" description:"Connect to tor hidden services via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
+	OnionProxyUser        string        `long:"onionuser" description:"Username for onion proxy server"`
+	OnionProxyPass        string        `long:"onionpass" default-mask:"-" description:"Password for onion proxy server"`
+	NoOnion               bool          `long:"noonion" description:"Disable connecting to tor hidden services"`
+	OnionOnly             bool          `long:"oniononly" description:"Only connect to tor hidden services and refuse to resolve or connect to any other addresses -- requires --onion or --proxy"`
+	NoDiscoverIP          bool          `long:"nodiscoverip" description:"Disable automatic network address discovery"`
+	TorIsolation          bool          `long:"torisolation" description:"Enable Tor stream isolation by randomizing user credentials for each connection."`
+	TestNet               bool          `long:"testnet" description:"Use the test network"`
+	SimNet                bool          `long:"simnet" description:"Use the simulation test network"`
+	RegNet                bool          `long:"regnet" description:"Use the regression test network"`
+	DisableCheckpoints    bool          `long:"nocheckpoints" description:"Disable built-in checkpoints.  Don't do this unless you know what you're doing."`
+	AssumeValid           string        `long:"assumevalid" description:"Skip transaction script validation for the specified block and its ancestors to speed up the initial sync (format: '<height>:<hash>') -- WARNING: Only specify a block you have independently verified since invalid scripts in those blocks would go unnoticed"`
+	DbType                string        `long:"dbtype" description:"Database backend to use for the Block Chain"`
+	DbCacheSize           uint64        `long:"dbcache" description:"Maximum size in MiB of the database write cache -- Larger values trade memory for fewer disk accesses -- Minimum 4, maximum 1048576 (1 TiB)"`
+	Profile               string        `long:"profile" description:"Enable HTTP profiling on given [addr:]port -- NOTE port must be between 1024 and 65536"`
+	CPUProfile            string        `long:"cpuprofile" description:"Write CPU profile to the specified file"`
+	MemProfile            string        `long:"memprofile" description:"Write mem profile to the specified file"`
+	AutoProfileHeap       uint64        `long:"autoprofileheap" description:"Automatically write a heap profile when the allocated heap exceeds this many MiB -- 0 to disable"`
+	AutoProfileGoroutines uint64        `long:"autoprofilegoroutines" description:"Automatically write a goroutine profile when the number of goroutines exceeds this count -- 0 to disable"`
+	AutoProfileDir        string        `long:"autoprofiledir" description:"Directory to write automatic profiles to (default: data directory)"`
+	DumpBlockchain        string        `long:"dumpblockchain" description:"Write blockchain as a flat file of blocks for use with addblock, to the specified filename"`
+	MiningTimeOffset      int           `long:"miningtimeoffset" description:"Offset the mining timestamp of a block by this many seconds (positive values are in the past)"`
+	DebugLevel            string        `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+	Upnp                  bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
+	MinRelayTxFee         float64       `long:"minrelaytxfee" description:"The minimum transaction fee in DCR/kB to be considered a non-zero fee."`
+	FreeTxRelayLimit      float64       `long:"limitfreerelay" description:"Limit relay of transactions with no transaction fee to the given amount in thousands of bytes per minute"`
+	NoRelayPriority       bool          `long:"norelaypriority" description:"Do not require free or low-fee transactions to have high priority for relaying"`
+	MaxOrphanTxs          int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
+	Generate              bool          `long:"generate" description:"Generate (mine) coins using the CPU"`
+	MiningAddrs           []string      `long:"miningaddr" description:"Add the specified payment address to the list of addresses to use for generated blocks -- At least one address is required if the generate option is set"`
+	BlockMinSize          uint32        `long:"blockminsize" description:"Minimum block size in bytes to be used when creating a block"`
+	BlockMaxSize          uint32        `long:"blockmaxsize" description:"Maximum block size in bytes to be used when creating a block"`
+	BlockPrioritySize     uint32        `long:"blockprioritysize" description:"Size in bytes for high-priority/low-fee transactions when creating a block"`
+	DeterministicTxOrder  bool          `long:"deterministictxorder" description:"Order the transactions in created blocks by fee per kilobyte and then transaction hash so identical mempools produce identical blocks -- the blockprioritysize option is ignored"`
+	SigCacheMaxSize       uint          `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
+	UtxoCacheMaxSize      uint64        `long:"utxocachemaxsize" description:"The maximum size in MiB of the in-memory UTXO cache -- 0 to disable"`
+	NonAggressive         bool          `long:"nonaggressive" description:"Disable mining off of the parent block of the blockchain if there aren't enough voters"`
+	NoMiningStateSync     bool          `long:"nominingstatesync" description:"Disable synchronizing the mining state with other nodes"`
+	AllowOldVotes         bool          `long:"allowoldvotes" description:"Enable the addition of very old votes to the mempool"`
+	BlocksOnly            bool          `long:"blocksonly" description:"Do not accept transactions from remote peers."`
+	RetainBlocks          uint32        `long:"retainblocks" description:"Only serve the specified number of most recent blocks to peers and advertise limited block history instead of the full chain -- 0 to serve all blocks, otherwise minimum 288"`
+	MaxGetDataInv         int           `long:"maxgetdatainv" description:"Max number of inventory items a peer may request in a single getdata message -- Larger requests are rejected and the peer is banned"`
+	MaxGetDataPending     int           `long:"maxgetdatapending" description:"Max number of inventory items requested via getdata that may be queued to a peer without having been sent -- Serving further items is deferred until they are sent -- Whitelisted peers are exempt -- 0 for unlimited"`
+	MaxGetDataPerMinute   int           `long:"maxgetdataperminute" description:"Max number of inventory items requested via getdata to serve to each peer per minute -- Items beyond the budget are answered with notfound -- 0 for unlimited"`
+	NoRelayTxTypes        []string      `long:"norelaytxtype" description:"Do not relay transactions of the specified type to peers even though they are still accepted {regular, ticket, vote, revocation} -- may be specified multiple times"`
+	BlockAnnounce         string        `long:"blockannounce" description:"Preferred method for peers to announce new blocks to this node {inv, headers, compact} -- Peers that do not support the method fall back to the best one they do"`
+	InvSuppressWindow     time.Duration `long:"invsuppresswindow" description:"How long to avoid relaying inventory back to the peer it was received from.  Valid time units are {ms, s, m} -- 0 to disable"`
+	RebroadcastInterval   time.Duration `long:"rebroadcastinterval" description:"How often to rebroadcast transactions submitted via RPC that have not yet been mined.  A small random jitter is added to the interval.  Valid time units are {s, m, h} -- Minimum 1m -- 0 to rebroadcast at a random time up to 30 minutes"`
+	AcceptNonStd          bool          `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network."`
+	RejectNonStd          bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
+	TxIndex               bool          `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
+	DropTxIndex           bool          `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits."`
+	AddrIndex             bool          `long:"addrindex" description:"Maintain a full address-based transaction index which makes the searchrawtransactions RPC available"`
+	DropAddrIndex         bool          `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits."`
+	NoExistsAddrIndex     bool          `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used."`
+	DropExistsAddrIndex   bool          `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits."`
+	NoCFilters            bool          `long:"nocfilters" description:"Disable compact filtering (CF) support"`
+	DropCFIndex           bool          `long:"dropcfindex" description:"Deletes the index used for compact filtering (CF) support from the database on start up and then exits."`
+	PipeRx                uint          `long:"piperx" description:"File descriptor of read end pipe to enable parent -> child process communication"`
+	PipeTx                uint          `long:"pipetx" description:"File descriptor of write end pipe to enable parent <- child process communication"`
+	LifetimeEvents        bool          `long:"lifetimeevents" description:"Send lifetime notifications over the TX pipe"`
+	AltDNSNames           []string      `long:"altdnsnames" description:"Specify additional dns names to use when generating the rpc server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
+	onionlookup           func(string) ([]net.IP, error)
+	lookup                func(string) ([]net.IP, error)
+	oniondial             func(string, string) (net.Conn, error)
+	dial                  func(string, string) (net.Conn, error)
+	assumeValid           *chaincfg.Checkpoint
+	blockAnnounce         peer.BlockAnnounceMode
+	noRelayTxTypes        map[stake.TxType]struct{}
+	dnsSeedServices       wire.ServiceFlag
+	advertiseServices     wire.ServiceFlag
+	rpcTLSMinVersion      uint16
+	rpcTLSCipherSuites    []uint16
+	miningAddrs           []dcrutil.Address
+	minRelayTxFee         dcrutil.Amount
+	whitelists            []*net.IPNet
+	ipv4NetInfo           types.NetworksResult
+	ipv6NetInfo           types.NetworksResult
+	onionNetInfo          types.NetworksResult
 }
 
 // serviceOptions defines the configuration options for the daemon as a service on
@@ -189,6 +277,415 @@ type serviceOptions struct {
 	ServiceCommand string `short:"s" long:"service" description:"Service command {install, remove, start, stop}"`
 }
 
+// parseAssumeValid parses an assumed valid block specified in the form
+// '<height>:<hash>' into a checkpoint.
+func parseAssumeValid(assumeValid string) (*chaincfg.Checkpoint, error) {
+	parts := strings.Split(assumeValid, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("unable to parse %q -- expected format "+
+			"'<height>:<hash>'", assumeValid)
+	}
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || height < 0 {
+		return nil, fmt.Errorf("unable to parse height %q", parts[0])
+	}
+	hash, err := chainhash.NewHashFromStr(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse hash %q: %v", parts[1], err)
+	}
+	return &chaincfg.Checkpoint{Height: height, Hash: hash}, nil
+}
+
+// parseBlockAnnounceMode parses the name of a block announcement method into
+// the corresponding mode.
+func parseBlockAnnounceMode(mode string) (peer.BlockAnnounceMode, error) {
+	for _, m := range []peer.BlockAnnounceMode{peer.BlockAnnounceInv,
+		peer.BlockAnnounceHeaders, peer.BlockAnnounceCmpct} {
+
+		if mode == m.String() {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown block announcement method %q", mode)
+}
+
+// parseTxTypes parses the names of transaction types into the set of
+// corresponding stake transaction types.  Nil is returned when no names are
+// provided.
+func parseTxTypes(names []string) (map[stake.TxType]struct{}, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	txTypes := make(map[stake.TxType]struct{}, len(names))
+	for _, name := range names {
+		var txType stake.TxType
+		switch name {
+		case "regular":
+			txType = stake.TxTypeRegular
+		case "ticket":
+			txType = stake.TxTypeSStx
+		case "vote":
+			txType = stake.TxTypeSSGen
+		case "revocation":
+			txType = stake.TxTypeSSRtx
+		default:
+			return nil, fmt.Errorf("unknown transaction type %q", name)
+		}
+		txTypes[txType] = struct{}{}
+	}
+	return txTypes, nil
+}
+
+// parseTLSVersion parses a TLS version such as 1.2 into the corresponding
+// version constant.  Only versions that are considered secure are accepted.
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %q", version)
+}
+
+// parseTLSCipherSuites parses the names of TLS cipher suites into their IDs.
+// Only cipher suites that are considered secure are accepted.  Nil is returned
+// when no names are provided so the default cipher suites are used.
+func parseTLSCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	secureSuites := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		secureSuites[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := secureSuites[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseDNSSeedServices parses the names of the services that peers returned
+// by the DNS seeds are required to provide into the corresponding service
+// flags.  Peers are always required to provide the default required services.
+func parseDNSSeedServices(names []string) (wire.ServiceFlag, error) {
+	services := defaultRequiredServices
+	for _, name := range names {
+		switch name {
+		case "bloom":
+			services |= wire.SFNodeBloom
+		case "cf":
+			services |= wire.SFNodeCF
+		case "cmpctblock":
+			services |= wire.SFNodeCmpctBlock
+		default:
+			return 0, fmt.Errorf("unknown service %q", name)
+		}
+	}
+	return services, nil
+}
+
+// parseWhitelists parses the provided whitelisted IP addresses and networks.
+// Individual IP addresses are treated as networks that only contain the
+// address.
+func parseWhitelists(addrs []string) ([]*net.IPNet, error) {
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+
+	whitelists := make([]*net.IPNet, 0, len(addrs))
+	for _, addr := range addrs {
+		_, ipnet, err := net.ParseCIDR(addr)
+		if err != nil {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				return nil, fmt.Errorf("the whitelist value of '%s' is "+
+					"invalid", addr)
+			}
+			var bits int
+			if ip.To4() == nil {
+				// IPv6
+				bits = 128
+			} else {
+				bits = 32
+			}
+			ipnet = &net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(bits, bits),
+			}
+		}
+		whitelists = append(whitelists, ipnet)
+	}
+	return whitelists, nil
+}
+
+// reloadConfigFile re-reads the config file and command line options in the
+// same way they are loaded on startup on top of the provided default config and
+// returns the result.  No validation is performed.
+func reloadConfigFile(defaults config) (*config, error) {
+	reloadCfg := defaults
+	parser := newConfigParser(&reloadCfg, &serviceOptions{}, flags.None)
+	if !(cfg.SimNet || cfg.RegNet) || cfg.ConfigFile != defaultConfigFile {
+		err := flags.NewIniParser(parser).ParseFile(cfg.ConfigFile)
+		if err != nil {
+			if _, ok := err.(*os.PathError); !ok {
+				return nil, fmt.Errorf("failed to parse config file: %v",
+					err)
+			}
+		}
+	}
+
+	// Parse command line options again to ensure they take precedence.
+	if _, err := parser.Parse(); err != nil {
+		return nil, err
+	}
+
+	return &reloadCfg, nil
+}
+
+// reloadWhitelists re-reads the whitelisted IP addresses and networks from the
+// config file and command line options in the same way they are loaded on
+// startup and returns the parsed result.
+func reloadWhitelists() ([]*net.IPNet, error) {
+	reloadCfg, err := reloadConfigFile(config{})
+	if err != nil {
+		return nil, err
+	}
+	return parseWhitelists(reloadCfg.Whitelists)
+}
+
+// redactedConfigValue is the value reported for configuration options that
+// contain credentials when they are set.
+const redactedConfigValue = "[redacted]"
+
+// redactedConfigOptions defines the long names of the configuration options
+// that contain credentials and therefore must never have their values reported.
+var redactedConfigOptions = map[string]struct{}{
+	"rpcuser":      {},
+	"rpcpass":      {},
+	"rpclimituser": {},
+	"rpclimitpass": {},
+	"proxyuser":    {},
+	"proxypass":    {},
+	"onionuser":    {},
+	"onionpass":    {},
+}
+
+// effectiveConfig returns the values of all options of the provided config
+// keyed by their long option names.  The values of options that contain
+// credentials are redacted and durations are reported as strings with units.
+func effectiveConfig(c *config) map[string]interface{} {
+	rv := reflect.ValueOf(c).Elem()
+	rt := rv.Type()
+	opts := make(map[string]interface{}, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		name := rt.Field(i).Tag.Get("long")
+		if name == "" {
+			continue
+		}
+
+		value := rv.Field(i).Interface()
+		if _, ok := redactedConfigOptions[name]; ok {
+			if value != "" {
+				value = redactedConfigValue
+			}
+			opts[name] = value
+			continue
+		}
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		opts[name] = value
+	}
+	return opts
+}
+
+// reloadableConfig houses the configuration values that may be changed while
+// the node is running by reloading the configuration.  They must be accessed
+// via the provided methods since they may be changed concurrently.
+type reloadableConfig struct {
+	mtx           sync.RWMutex
+	banThreshold  uint32
+	maxPeers      int
+	minRelayTxFee dcrutil.Amount
+}
+
+// reloadable houses the current values of the configuration options that may
+// be changed while the node is running.  It is populated from the loaded
+// configuration when the server is created.
+var reloadable reloadableConfig
+
+// set replaces all of the reloadable configuration values.
+//
+// This function is safe for concurrent access.
+func (r *reloadableConfig) set(banThreshold uint32, maxPeers int, minRelayTxFee dcrutil.Amount) {
+	r.mtx.Lock()
+	r.banThreshold = banThreshold
+	r.maxPeers = maxPeers
+	r.minRelayTxFee = minRelayTxFee
+	r.mtx.Unlock()
+}
+
+// BanThreshold returns the maximum allowed ban score before disconnecting and
+// banning misbehaving peers.
+//
+// This function is safe for concurrent access.
+func (r *reloadableConfig) BanThreshold() uint32 {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.banThreshold
+}
+
+// MaxPeers returns the maximum number of inbound and outbound peers.
+//
+// This function is safe for concurrent access.
+func (r *reloadableConfig) MaxPeers() int {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.maxPeers
+}
+
+// MinRelayTxFee returns the minimum transaction fee in atoms/kB required for
+// transactions to be accepted into the mempool and relayed.
+//
+// This function is safe for concurrent access.
+func (r *reloadableConfig) MinRelayTxFee() dcrutil.Amount {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.minRelayTxFee
+}
+
+// reloadedConfig houses the validated values of the configuration options that
+// are able to be changed while the node is running.
+type reloadedConfig struct {
+	banThreshold  uint32
+	maxPeers      int
+	minRelayTxFee dcrutil.Amount
+	whitelists    []*net.IPNet
+}
+
+// parseReloadedConfig validates the options of the provided config that are
+// able to be changed while the node is running and returns the parsed values.
+func parseReloadedConfig(c *config) (*reloadedConfig, error) {
+	if c.MaxPeers < 0 {
+		return nil, fmt.Errorf("the maxpeers option may not be less than "+
+			"0 -- parsed [%d]", c.MaxPeers)
+	}
+	minRelayTxFee, err := dcrutil.NewAmount(c.MinRelayTxFee)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minrelaytxfee: %v", err)
+	}
+	if minRelayTxFee < 0 {
+		return nil, fmt.Errorf("the minrelaytxfee option may not be less "+
+			"than 0 -- parsed [%v]", minRelayTxFee)
+	}
+	whitelists, err := parseWhitelists(c.Whitelists)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reloadedConfig{
+		banThreshold:  c.BanThreshold,
+		maxPeers:      c.MaxPeers,
+		minRelayTxFee: minRelayTxFee,
+		whitelists:    whitelists,
+	}, nil
+}
+
+// loadReloadableConfig re-reads the config file and command line options and
+// returns the validated values of the options that are able to be changed while
+// the node is running.  Options that are not specified revert to their
+// defaults.
+func loadReloadableConfig() (*reloadedConfig, error) {
+	reloadCfg, err := reloadConfigFile(config{
+		MaxPeers:      defaultMaxPeers,
+		BanThreshold:  defaultBanThreshold,
+		MinRelayTxFee: mempool.DefaultMinRelayTxFee.ToCoin(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseReloadedConfig(reloadCfg)
+}
+
+// parseAdvertiseServices parses the names of the services to advertise to peers
+// into the corresponding service flags.  No services are returned when no names
+// are provided.
+func parseAdvertiseServices(names []string) (wire.ServiceFlag, error) {
+	var services wire.ServiceFlag
+	for _, name := range names {
+		switch name {
+		case "network":
+			services |= wire.SFNodeNetwork
+		case "networklimited":
+			services |= wire.SFNodeNetworkLimited
+		case "cf":
+			services |= wire.SFNodeCF
+		case "cmpctblock":
+			services |= wire.SFNodeCmpctBlock
+		default:
+			return 0, fmt.Errorf("unknown service %q", name)
+		}
+	}
+	return services, nil
+}
+
+// validateDNSSeedHost ensures the provided DNS seed is a valid hostname.  Seeds
+// are queried on the default port of the active network, so ports and
+// addresses with a scheme are rejected.
+func validateDNSSeedHost(host string) error {
+	if len(host) == 0 || len(host) > 253 {
+		return fmt.Errorf("DNS seed %q must be between 1 and 253 "+
+			"characters", host)
+	}
+	if net.ParseIP(host) != nil {
+		return fmt.Errorf("DNS seed %q must be a hostname instead of an "+
+			"IP address", host)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return fmt.Errorf("DNS seed %q contains an invalid label %q",
+				host, label)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("DNS seed %q contains an invalid label %q",
+				host, label)
+		}
+		for _, r := range label {
+			isAlphaNum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+				(r >= '0' && r <= '9')
+			if !isAlphaNum && r != '-' {
+				return fmt.Errorf("DNS seed %q contains invalid "+
+					"character %q", host, r)
+			}
+		}
+	}
+	return nil
+}
+
+// validDNSSeedHosts returns the provided DNS seeds that are valid hostnames.
+// Malformed seeds are skipped with a warning instead of preventing startup
+// since the remaining seeds are still usable.
+func validDNSSeedHosts(seeds []string) []string {
+	valid := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		if err := validateDNSSeedHost(seed); err != nil {
+			dcrdLog.Warnf("Skipping DNS seed: %v", err)
+			continue
+		}
+		valid = append(valid, seed)
+	}
+	return valid
+}
+
 // cleanAndExpandPath expands environment variables and leading ~ in the
 // passed path, cleans the result, and returns it.
 func cleanAndExpandPath(path string) string {
@@ -339,9 +836,23 @@ func removeDuplicateAddresses(addrs []string) []string {
 	return result
 }
 
+// unixListenPath returns the path of the unix domain socket for the provided
+// listen address along with whether or not it is a unix domain socket address,
+// which are specified with the unixListenPrefix.
+func unixListenPath(addr string) (string, bool) {
+	if !strings.HasPrefix(addr, unixListenPrefix) {
+		return "", false
+	}
+	return addr[len(unixListenPrefix):], true
+}
+
 // normalizeAddress returns addr with the passed default port appended if
-// there is not already a port specified.
+// there is not already a port specified.  Unix domain socket addresses are
+// returned unmodified since they do not have a port.
 func normalizeAddress(addr, defaultPort string) string {
+	if _, ok := unixListenPath(addr); ok {
+		return addr
+	}
 	_, _, err := net.SplitHostPort(addr)
 	if err != nil {
 		return net.JoinHostPort(addr, defaultPort)
@@ -488,14 +999,27 @@ func loadConfig() (*config, []string, error) {
 		DebugLevel:           defaultLogLevel,
 		MaxSameIP:            defaultMaxSameIP,
 		MaxPeers:             defaultMaxPeers,
+		MinOutboundGroups:    defaultMinOutboundGroups,
+		FeelerInterval:       defaultFeelerInterval,
+		AddrFailureThreshold: defaultAddrFailureThreshold,
+		PingInterval:         defaultPingInterval,
 		BanDuration:          defaultBanDuration,
+		MaxTimeAdjustment:    defaultMaxTimeAdjustment,
 		BanThreshold:         defaultBanThreshold,
+		BanEscalationWindow:  defaultBanEscalationWindow,
 		RPCMaxClients:        defaultMaxRPCClients,
 		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
 		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
+		RPCMaxNtfnQueue:      defaultRPCMaxNtfnQueue,
+		RPCMaxRescans:        defaultRPCMaxRescans,
+		RPCNtfnOverflow:      defaultRPCNtfnOverflow,
+		RPCTLSMinVersion:     defaultRPCTLSMinVersion,
+		BlockIntervalWindow:  defaultBlockIntervalWindow,
+		FinalityDepth:        defaultFinalityDepth,
 		DataDir:              defaultDataDir,
 		LogDir:               defaultLogDir,
 		DbType:               defaultDbType,
+		DbCacheSize:          defaultDbCacheSize,
 		RPCKey:               defaultRPCKeyFile,
 		RPCCert:              defaultRPCCertFile,
 		MinRelayTxFee:        mempool.DefaultMinRelayTxFee.ToCoin(),
@@ -505,6 +1029,7 @@ func loadConfig() (*config, []string, error) {
 		BlockPrioritySize:    mempool.DefaultBlockPrioritySize,
 		MaxOrphanTxs:         defaultMaxOrphanTransactions,
 		SigCacheMaxSize:      defaultSigCacheMaxSize,
+		UtxoCacheMaxSize:     defaultUtxoCacheMaxSize,
 		Generate:             defaultGenerate,
 		NoMiningStateSync:    defaultNoMiningStateSync,
 		TxIndex:              defaultTxIndex,
@@ -512,6 +1037,9 @@ func loadConfig() (*config, []string, error) {
 		AllowOldVotes:        defaultAllowOldVotes,
 		NoExistsAddrIndex:    defaultNoExistsAddrIndex,
 		NoCFilters:           defaultNoCFilters,
+		BlockAnnounce:        defaultBlockAnnounce,
+		InvSuppressWindow:    defaultInvSuppressWindow,
+		MaxGetDataInv:        defaultMaxGetDataInv,
 		AltDNSNames:          defaultAltDNSNames,
 		ipv4NetInfo:          types.NetworksResult{Name: "IPV4"},
 		ipv6NetInfo:          types.NetworksResult{Name: "IPV6"},
@@ -666,9 +1194,12 @@ func loadConfig() (*config, []string, error) {
 	}
 	if cfg.SimNet {
 		numNets++
-		// Also disable dns seeding on the simulation test network.
+		// Also disable dns seeding on the simulation test network unless
+		// custom seeds are specified.
 		activeNetParams = &simNetParams
-		cfg.DisableDNSSeed = true
+		if len(cfg.DNSSeeds) == 0 {
+			cfg.DisableDNSSeed = true
+		}
 	}
 	if cfg.RegNet {
 		numNets++
@@ -727,6 +1258,19 @@ func loadConfig() (*config, []string, error) {
 		// Initialize log rotation.  After log rotation has been initialized, the
 		// logger variables may be used.
 		initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename))
+
+		// Initialize the RPC audit log when enabled.
+		if cfg.RPCAuditLog {
+			initRPCAuditLog(filepath.Join(cfg.LogDir,
+				defaultRPCAuditLogFilename))
+		}
+	} else if cfg.RPCAuditLog {
+		str := "%s: the --rpcauditlog and --nofilelogging options may not " +
+			"be used together"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
 	}
 
 	// Special show command to list supported subsystems and exit.
@@ -753,6 +1297,77 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Don't allow a database cache that is too small to be useful.
+	if cfg.DbCacheSize < minDbCacheSize {
+		str := "%s: the dbcache option may not be less than %d -- " +
+			"parsed [%d]"
+		err := fmt.Errorf(str, funcName, minDbCacheSize, cfg.DbCacheSize)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Don't allow a database cache so large that its size in bytes could
+	// overflow.
+	if cfg.DbCacheSize > maxDbCacheSize {
+		str := "%s: the dbcache option may not be more than %d -- " +
+			"parsed [%d]"
+		err := fmt.Errorf(str, funcName, maxDbCacheSize, cfg.DbCacheSize)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Parse the assumed valid block when specified.
+	if cfg.AssumeValid != "" {
+		cfg.assumeValid, err = parseAssumeValid(cfg.AssumeValid)
+		if err != nil {
+			str := "%s: the assumevalid option is invalid: %v"
+			err := fmt.Errorf(str, funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+
+	// Parse the preferred block announcement method.
+	cfg.blockAnnounce, err = parseBlockAnnounceMode(cfg.BlockAnnounce)
+	if err != nil {
+		str := "%s: the blockannounce option is invalid: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Parse the transaction types that are not relayed.
+	cfg.noRelayTxTypes, err = parseTxTypes(cfg.NoRelayTxTypes)
+	if err != nil {
+		str := "%s: the norelaytxtype option is invalid: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Parse the minimum TLS version and cipher suites for the RPC server.
+	cfg.rpcTLSMinVersion, err = parseTLSVersion(cfg.RPCTLSMinVersion)
+	if err != nil {
+		str := "%s: the rpctlsminversion option is invalid: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	cfg.rpcTLSCipherSuites, err = parseTLSCipherSuites(cfg.RPCTLSCipherSuites)
+	if err != nil {
+		str := "%s: the rpctlsciphersuite option is invalid: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Validate format of profile, can be an address:port, or just a port.
 	if cfg.Profile != "" {
 		// if profile is just a number, then add a default host of "127.0.0.1" such that Profile is a valid tcp address
@@ -780,6 +1395,28 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
+	// Default the automatic profile directory to the network-specific data
+	// directory.
+	if cfg.AutoProfileDir == "" {
+		cfg.AutoProfileDir = cfg.DataDir
+	}
+	cfg.AutoProfileDir = cleanAndExpandPath(cfg.AutoProfileDir)
+
+	// Don't allow negative time adjustments or increasing the max time
+	// adjustment beyond the default since that would allow peers to skew
+	// the adjusted time further.
+	if cfg.MaxTimeAdjustment < 0 ||
+		cfg.MaxTimeAdjustment > defaultMaxTimeAdjustment {
+
+		str := "%s: the maxtimeadjustment option must be between 0 and " +
+			"%v -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, defaultMaxTimeAdjustment,
+			cfg.MaxTimeAdjustment)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Don't allow ban durations that are too short.
 	if cfg.BanDuration < time.Second {
 		str := "%s: the banduration option may not be less than 1s -- parsed [%v]"
@@ -789,38 +1426,213 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Ban escalation requires bans of multiple distinct IPs and a sane
+	// window.
+	if cfg.BanEscalation == 1 {
+		str := "%s: the banescalation option must be 0 to disable or at " +
+			"least 2 -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.BanEscalation)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.BanEscalationWindow < time.Second {
+		str := "%s: the banescalationwindow option may not be less than " +
+			"1s -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.BanEscalationWindow)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Don't allow negative inventory suppression windows.
+	if cfg.InvSuppressWindow < 0 {
+		str := "%s: the invsuppresswindow option may not be negative -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.InvSuppressWindow)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The outbound network group diversity target can't exceed the number of
+	// outbound peers.
+	if cfg.MinOutboundGroups < 0 ||
+		cfg.MinOutboundGroups > defaultTargetOutbound {
+
+		str := "%s: the minoutboundgroups option must be between 0 and " +
+			"%d -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, defaultTargetOutbound,
+			cfg.MinOutboundGroups)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The getdata inventory limit must be positive and can't exceed the
+	// maximum number of inventory items a message is able to hold.
+	if cfg.MaxGetDataInv < 1 || cfg.MaxGetDataInv > wire.MaxInvPerMsg {
+		str := "%s: the maxgetdatainv option must be between 1 and %d " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, wire.MaxInvPerMsg,
+			cfg.MaxGetDataInv)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The outstanding getdata limit may not be negative.
+	if cfg.MaxGetDataPending < 0 {
+		str := "%s: the maxgetdatapending option may not be less " +
+			"than 0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.MaxGetDataPending)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The getdata budget may not be negative.
+	if cfg.MaxGetDataPerMinute < 0 {
+		str := "%s: the maxgetdataperminute option may not be less than 0 " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.MaxGetDataPerMinute)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The retained block window must be large enough for peers that are only
+	// slightly behind to catch up.
+	if cfg.RetainBlocks != 0 && cfg.RetainBlocks < minRetainBlocks {
+		str := "%s: the retainblocks option may not be less than %d " +
+			"unless it is 0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, minRetainBlocks, cfg.RetainBlocks)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Don't allow rebroadcast intervals below the minimum other than 0, which
+	// requests a random interval.
+	if cfg.RebroadcastInterval != 0 &&
+		cfg.RebroadcastInterval < minRebroadcastInterval {
+
+		str := "%s: the rebroadcastinterval option may not be less than " +
+			"%v unless it is 0 -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, minRebroadcastInterval,
+			cfg.RebroadcastInterval)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Validate any given whitelisted IP addresses and networks.
-	if len(cfg.Whitelists) > 0 {
-		var ip net.IP
-		cfg.whitelists = make([]*net.IPNet, 0, len(cfg.Whitelists))
+	cfg.whitelists, err = parseWhitelists(cfg.Whitelists)
+	if err != nil {
+		str := "%s: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
 
-		for _, addr := range cfg.Whitelists {
-			_, ipnet, err := net.ParseCIDR(addr)
-			if err != nil {
-				ip = net.ParseIP(addr)
-				if ip == nil {
-					str := "%s: the whitelist value of '%s' is invalid"
-					err = fmt.Errorf(str, funcName, addr)
-					fmt.Fprintln(os.Stderr, err)
-					fmt.Fprintln(os.Stderr, usageMessage)
-					return nil, nil, err
-				}
-				var bits int
-				if ip.To4() == nil {
-					// IPv6
-					bits = 128
-				} else {
-					bits = 32
-				}
-				ipnet = &net.IPNet{
-					IP:   ip,
-					Mask: net.CIDRMask(bits, bits),
-				}
-			}
-			cfg.whitelists = append(cfg.whitelists, ipnet)
+	// Don't allow empty whitelisted user agent substrings since they would
+	// match every peer.
+	for _, userAgent := range cfg.WhitelistUserAgents {
+		if userAgent == "" {
+			str := "%s: the whitelistuseragent option may not be empty"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
 		}
 	}
 
+	// Skip any additional DNS seeds that are malformed and ensure the
+	// built-in seeds are only replaced when there are valid seeds to replace
+	// them with.
+	cfg.DNSSeeds = validDNSSeedHosts(cfg.DNSSeeds)
+	if cfg.ReplaceDNSSeeds && len(cfg.DNSSeeds) == 0 {
+		str := "%s: the --replacednsseeds option requires at least one " +
+			"valid seed to be specified with --dnsseed"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	cfg.DNSSeeds = removeDuplicateAddresses(cfg.DNSSeeds)
+
+	// Parse the services required of peers returned by the DNS seeds.
+	cfg.dnsSeedServices, err = parseDNSSeedServices(cfg.DNSSeedServices)
+	if err != nil {
+		str := "%s: the dnsseedservice option is invalid: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Parse the services to advertise to peers.
+	cfg.advertiseServices, err = parseAdvertiseServices(cfg.AdvertiseServices)
+	if err != nil {
+		str := "%s: the advertiseservice option is invalid: %v"
+		err := fmt.Errorf(str, funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The pending connection timeout must not be negative.
+	if cfg.PendingConnTimeout < 0 {
+		str := "%s: the pendingconntimeout option may not be less " +
+			"than 0 -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.PendingConnTimeout)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The feeler interval must not be negative.
+	if cfg.FeelerInterval < 0 {
+		str := "%s: the feelerinterval option may not be less than 0 " +
+			"-- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.FeelerInterval)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The address failure threshold must not be negative.
+	if cfg.AddrFailureThreshold < 0 {
+		str := "%s: the addrfailurethreshold option may not be less " +
+			"than 0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.AddrFailureThreshold)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The ping interval must be at least one second.
+	if cfg.PingInterval < time.Second {
+		str := "%s: the pinginterval option may not be less than 1s " +
+			"-- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.PingInterval)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The minimum protocol version must not exceed the version of the wire
+	// protocol this software supports since no peers could connect otherwise.
+	if cfg.MinProtocolVersion > wire.ProtocolVersion {
+		str := "%s: the minprotocolversion option may not be more " +
+			"than %d -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, wire.ProtocolVersion,
+			cfg.MinProtocolVersion)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// --addPeer and --connect do not mix.
 	if len(cfg.AddPeers) > 0 && len(cfg.ConnectPeers) > 0 {
 		str := "%s: the --addpeer and --connect options can not be " +
@@ -871,9 +1683,22 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
-	// The RPC server is disabled if no username or password is provided.
+	// Client certificates are only available over TLS.
+	if cfg.RPCClientCA != "" && cfg.DisableTLS {
+		str := "%s: the --rpcclientca and --notls options may not be " +
+			"used together"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	cfg.RPCClientCA = cleanAndExpandPath(cfg.RPCClientCA)
+
+	// The RPC server is disabled if no username or password is provided and
+	// client certificates are not enabled.
 	if (cfg.RPCUser == "" || cfg.RPCPass == "") &&
-		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") {
+		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") &&
+		cfg.RPCClientCA == "" {
 		cfg.DisableRPC = true
 	}
 
@@ -899,6 +1724,74 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	if cfg.RPCMaxRescans < 0 {
+		str := "%s: the rpcmaxrescans option may not be less " +
+			"than 0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RPCMaxRescans)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	if cfg.RPCMaxResponseSize < 0 {
+		str := "%s: the rpcmaxresponsesize option may not be less " +
+			"than 0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RPCMaxResponseSize)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	if cfg.RPCMaxNtfnQueue < 0 {
+		str := "%s: the rpcmaxntfnqueue option may not be less " +
+			"than 0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RPCMaxNtfnQueue)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	switch cfg.RPCNtfnOverflow {
+	case ntfnOverflowDropOldest, ntfnOverflowDisconnect:
+	default:
+		str := "%s: the rpcntfnoverflow option is invalid -- parsed [%s], " +
+			"want one of {%s, %s}"
+		err := fmt.Errorf(str, funcName, cfg.RPCNtfnOverflow,
+			ntfnOverflowDropOldest, ntfnOverflowDisconnect)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// The block interval window must contain at least one interval.
+	if cfg.BlockIntervalWindow < 1 {
+		str := "%s: the blockintervalwindow option may not be less " +
+			"than 1 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.BlockIntervalWindow)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// A block must have at least one confirmation to be final.
+	if cfg.FinalityDepth < 1 {
+		str := "%s: the finalitydepth option may not be less " +
+			"than 1 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.FinalityDepth)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	if cfg.RPCIdleTimeout < 0 {
+		str := "%s: the rpcidletimeout option may not be less " +
+			"than 0 -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.RPCIdleTimeout)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Validate the minrelaytxfee.
 	cfg.minRelayTxFee, err = dcrutil.NewAmount(cfg.MinRelayTxFee)
 	if err != nil {
@@ -1022,8 +1915,27 @@ func loadConfig() (*config, []string, error) {
 	cfg.RPCListeners = normalizeAddresses(cfg.RPCListeners,
 		activeNetParams.rpcPort)
 
+	// Expand the paths of any unix domain socket listeners.
+	for _, listeners := range [][]string{cfg.Listeners, cfg.RPCListeners} {
+		for i, addr := range listeners {
+			path, ok := unixListenPath(addr)
+			if !ok {
+				continue
+			}
+			if path == "" {
+				str := "%s: unix socket listen address '%s' does not " +
+					"specify a path"
+				err := fmt.Errorf(str, funcName, addr)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+			listeners[i] = unixListenPrefix + cleanAndExpandPath(path)
+		}
+	}
+
 	// Only allow TLS to be disabled if the RPC is bound to localhost
-	// addresses.
+	// addresses or unix domain sockets.
 	if !cfg.DisableRPC && cfg.DisableTLS {
 		allowedTLSListeners := map[string]struct{}{
 			"localhost": {},
@@ -1031,6 +1943,9 @@ func loadConfig() (*config, []string, error) {
 			"::1":       {},
 		}
 		for _, addr := range cfg.RPCListeners {
+			if _, ok := unixListenPath(addr); ok {
+				continue
+			}
 			host, _, err := net.SplitHostPort(addr)
 			if err != nil {
 				str := "%s: RPC listen interface '%s' is " +
@@ -1160,6 +2075,25 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
+	// Only connecting to tor hidden services requires a proxy to connect
+	// through and is not possible when they are disabled.
+	if cfg.OnionOnly && cfg.OnionProxy == "" && cfg.Proxy == "" {
+		str := "%s: the --oniononly option requires either --onion or " +
+			"--proxy to be set"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.OnionOnly && cfg.NoOnion {
+		str := "%s: the --oniononly and --noonion options may not be " +
+			"used together"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Warn if old testnet directory is present.
 	for _, oldDir := range oldTestNets {
 		if fileExists(oldDir) {
@@ -1185,15 +2119,24 @@ func loadConfig() (*config, []string, error) {
 	return &cfg, remainingArgs, nil
 }
 
+// errOnionOnly is the error returned when attempting to resolve or connect to
+// an address that is not a tor hidden service when --oniononly was specified.
+var errOnionOnly = errors.New("only tor hidden services are allowed due to " +
+	"--oniononly")
+
 // dcrdDial connects to the address on the named network using the appropriate
 // dial function depending on the address and configuration options.  For
 // example, .onion addresses will be dialed using the onion specific proxy if
 // one was specified, but will otherwise use the normal dial function (which
-// could itself use a proxy or not).
+// could itself use a proxy or not).  All other addresses are refused when
+// --oniononly was specified.
 func dcrdDial(network, addr string) (net.Conn, error) {
 	if strings.Contains(addr, ".onion:") {
 		return cfg.oniondial(network, addr)
 	}
+	if cfg.OnionOnly {
+		return nil, errOnionOnly
+	}
 	return cfg.dial(network, addr)
 }
 
@@ -1203,10 +2146,15 @@ func dcrdDial(network, addr string) (net.Conn, error) {
 // otherwise treat the normal proxy as tor unless --noonion was specified in
 // which case the lookup will fail.  Meanwhile, normal IP addresses will be
 // resolved using tor if a proxy was specified unless --noonion was also
-// specified in which case the normal system DNS resolver will be used.
+// specified in which case the normal system DNS resolver will be used.  All
+// other hosts are refused when --oniononly was specified so they are never
+// resolved.
 func dcrdLookup(host string) ([]net.IP, error) {
 	if strings.HasSuffix(host, ".onion") {
 		return cfg.onionlookup(host)
 	}
+	if cfg.OnionOnly {
+		return nil, errOnionOnly
+	}
 	return cfg.lookup(host)
 }
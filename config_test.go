@@ -5,9 +5,17 @@
 package main
 
 import (
+	"crypto/tls"
+	"net"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/peer/v2"
+	"github.com/decred/dcrd/wire"
 )
 
 // In order to test command line arguments and environment variables, append
@@ -72,6 +80,493 @@ func TestAltDNSNamesWithArg(t *testing.T) {
 	os.Args = old
 }
 
+// TestRebroadcastIntervalMinimum ensures the rebroadcastinterval option is
+// rejected when it is below the minimum unless it is 0.
+func TestRebroadcastIntervalMinimum(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval string
+		wantErr  bool
+	}{
+		{"random", "0", false},
+		{"minimum", "1m", false},
+		{"below minimum", "59s", true},
+		{"negative", "-1m", true},
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+	for _, test := range tests {
+		os.Args = append(origArgs, "--rebroadcastinterval="+test.interval)
+		_, _, err := loadConfig()
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+	}
+}
+
+// TestDbCacheSizeLimits ensures the database cache size is rejected when it is
+// outside of the allowed range.
+func TestDbCacheSizeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    string
+		wantErr bool
+	}{
+		{"minimum", "4", false},
+		{"below minimum", "3", true},
+		{"maximum", "1048576", false},
+		{"above maximum", "1048577", true},
+		{"overflows bytes", "18446744073709551615", true},
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+	for _, test := range tests {
+		os.Args = append(origArgs, "--dbcache="+test.size)
+		_, _, err := loadConfig()
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+	}
+}
+
+// TestParseAssumeValid ensures the assumevalid configuration option is parsed
+// into the expected height and hash and that malformed values are rejected.
+func TestParseAssumeValid(t *testing.T) {
+	const hash = "00000000000000000ef8a5ea6f1dfb0a3e4d4f5a1e6f3c1e2ab2e0e9b0e3a4c5"
+	checkpoint, err := parseAssumeValid("400000:" + hash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checkpoint.Height != 400000 || checkpoint.Hash.String() != hash {
+		t.Fatalf("unexpected checkpoint -- got %d:%v, want 400000:%s",
+			checkpoint.Height, checkpoint.Hash, hash)
+	}
+
+	invalid := []string{"", "400000", hash, "-1:" + hash, "abc:" + hash,
+		"400000:zz", "400000:" + hash + ":1"}
+	for _, assumeValid := range invalid {
+		if _, err := parseAssumeValid(assumeValid); err == nil {
+			t.Fatalf("%q: expected error", assumeValid)
+		}
+	}
+}
+
+// TestParseBlockAnnounceMode ensures the block announcement methods are parsed
+// as expected.
+func TestParseBlockAnnounceMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    peer.BlockAnnounceMode
+		wantErr bool
+	}{
+		{"inv", peer.BlockAnnounceInv, false},
+		{"headers", peer.BlockAnnounceHeaders, false},
+		{"compact", peer.BlockAnnounceCmpct, false},
+		{"", 0, true},
+		{"Compact", 0, true},
+		{"cmpct", 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := parseBlockAnnounceMode(test.mode)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.mode, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected mode -- got %v, want %v", test.mode,
+				got, test.want)
+		}
+	}
+}
+
+// TestValidateDNSSeedHost ensures DNS seed hosts are validated as expected.
+func TestValidateDNSSeedHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{"mainnet-seed.decred.org", false},
+		{"seed-1.example.com", false},
+		{"localhost", false},
+		{"", true},
+		{"seed.example.com:9108", true},
+		{"https://seed.example.com", true},
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"seed..example.com", true},
+		{"-seed.example.com", true},
+		{"seed-.example.com", true},
+		{"seed_1.example.com", true},
+		{strings.Repeat("a", 64) + ".example.com", true},
+		{strings.Repeat("a.", 127) + "a", true},
+	}
+
+	for _, test := range tests {
+		err := validateDNSSeedHost(test.host)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.host, err, test.wantErr)
+		}
+	}
+}
+
+// TestValidDNSSeedHosts ensures malformed DNS seeds are skipped while the
+// remaining seeds are kept in order.
+func TestValidDNSSeedHosts(t *testing.T) {
+	seeds := []string{"seed1.example.org", "seed.example.com:9108",
+		"127.0.0.1", "seed2.example.org", ""}
+	want := []string{"seed1.example.org", "seed2.example.org"}
+	got := validDNSSeedHosts(seeds)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected seeds -- got %v, want %v", got, want)
+	}
+	if got := validDNSSeedHosts(nil); len(got) != 0 {
+		t.Fatalf("unexpected seeds -- got %v, want none", got)
+	}
+}
+
+// TestParseDNSSeedServices ensures the services required of peers returned by
+// the DNS seeds are parsed as expected.
+func TestParseDNSSeedServices(t *testing.T) {
+	tests := []struct {
+		names   []string
+		want    wire.ServiceFlag
+		wantErr bool
+	}{
+		{nil, wire.SFNodeNetwork, false},
+		{[]string{"cf"}, wire.SFNodeNetwork | wire.SFNodeCF, false},
+		{[]string{"bloom", "cmpctblock"}, wire.SFNodeNetwork |
+			wire.SFNodeBloom | wire.SFNodeCmpctBlock, false},
+		{[]string{"cf", "cf"}, wire.SFNodeNetwork | wire.SFNodeCF, false},
+		{[]string{"network"}, 0, true},
+		{[]string{"CF"}, 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := parseDNSSeedServices(test.names)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.names, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected services -- got %v, want %v",
+				test.names, got, test.want)
+		}
+	}
+}
+
+// TestParseWhitelists ensures whitelisted IP addresses and networks are parsed
+// as expected and that the resulting whitelist only contains the expected IPs.
+func TestParseWhitelists(t *testing.T) {
+	tests := []struct {
+		name    string
+		addrs   []string
+		want    []string
+		wantErr bool
+	}{
+		{"none", nil, nil, false},
+		{"ipv4 address", []string{"203.0.113.45"}, []string{"203.0.113.45/32"},
+			false},
+		{"ipv6 address", []string{"2001:db8::1"}, []string{"2001:db8::1/128"},
+			false},
+		{"networks", []string{"192.168.1.0/24", "2001:db8::/32"},
+			[]string{"192.168.1.0/24", "2001:db8::/32"}, false},
+		{"invalid", []string{"192.168.1.0/24", "bogus"}, nil, true},
+	}
+
+	for _, test := range tests {
+		nets, err := parseWhitelists(test.addrs)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if len(nets) != len(test.want) {
+			t.Fatalf("%q: unexpected number of networks -- got %d, want %d",
+				test.name, len(nets), len(test.want))
+		}
+		for i, ipnet := range nets {
+			if ipnet.String() != test.want[i] {
+				t.Fatalf("%q: unexpected network -- got %v, want %v",
+					test.name, ipnet, test.want[i])
+			}
+		}
+	}
+
+	// Ensure replacing the whitelisted networks only affects the IPs that are
+	// considered whitelisted afterwards.
+	var wl ipWhitelist
+	nets, err := parseWhitelists([]string{"192.168.1.0/24"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ip := net.ParseIP("10.0.0.1")
+	if wl.contains(ip) {
+		t.Fatal("empty whitelist contains IP")
+	}
+	wl.replace(nets)
+	if wl.contains(ip) || !wl.contains(net.ParseIP("192.168.1.7")) {
+		t.Fatal("unexpected whitelisted IPs after replacing networks")
+	}
+	nets, err = parseWhitelists([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wl.replace(nets)
+	if !wl.contains(ip) || wl.contains(net.ParseIP("192.168.1.7")) {
+		t.Fatal("unexpected whitelisted IPs after reloading networks")
+	}
+}
+
+// TestParseReloadedConfig ensures the options that may be changed while the
+// node is running are validated and parsed as expected.
+func TestParseReloadedConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config
+		want    reloadedConfig
+		wantErr bool
+	}{{
+		name: "defaults",
+		cfg: config{
+			BanThreshold:  defaultBanThreshold,
+			MaxPeers:      defaultMaxPeers,
+			MinRelayTxFee: 0.0001,
+		},
+		want: reloadedConfig{
+			banThreshold:  defaultBanThreshold,
+			maxPeers:      defaultMaxPeers,
+			minRelayTxFee: 10000,
+		},
+	}, {
+		name: "changed values",
+		cfg: config{
+			BanThreshold:  50,
+			MaxPeers:      8,
+			MinRelayTxFee: 0.001,
+			Whitelists:    []string{"192.168.1.0/24"},
+		},
+		want: reloadedConfig{
+			banThreshold:  50,
+			maxPeers:      8,
+			minRelayTxFee: 100000,
+		},
+	}, {
+		name:    "negative max peers",
+		cfg:     config{MaxPeers: -1},
+		wantErr: true,
+	}, {
+		name:    "negative min relay fee",
+		cfg:     config{MinRelayTxFee: -0.0001},
+		wantErr: true,
+	}, {
+		name:    "invalid whitelist",
+		cfg:     config{Whitelists: []string{"bogus"}},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		got, err := parseReloadedConfig(&test.cfg)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if got.banThreshold != test.want.banThreshold ||
+			got.maxPeers != test.want.maxPeers ||
+			got.minRelayTxFee != test.want.minRelayTxFee {
+
+			t.Fatalf("%q: unexpected values -- got %+v, want %+v", test.name,
+				*got, test.want)
+		}
+		if len(got.whitelists) != len(test.cfg.Whitelists) {
+			t.Fatalf("%q: unexpected number of whitelists -- got %d, want %d",
+				test.name, len(got.whitelists), len(test.cfg.Whitelists))
+		}
+	}
+}
+
+// TestEffectiveConfig ensures the effective configuration is keyed by the long
+// option names and does not disclose the values of options that contain
+// credentials.
+func TestEffectiveConfig(t *testing.T) {
+	c := config{
+		MaxPeers:     8,
+		PingInterval: 2 * time.Minute,
+		Whitelists:   []string{"192.168.1.0/24"},
+		RPCUser:      "user",
+		RPCPass:      "secret",
+		ProxyPass:    "proxysecret",
+	}
+	opts := effectiveConfig(&c)
+
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"maxpeers", 8},
+		{"pinginterval", "2m0s"},
+		{"rpcuser", redactedConfigValue},
+		{"rpcpass", redactedConfigValue},
+		{"proxypass", redactedConfigValue},
+		{"rpclimitpass", ""},
+	}
+	for _, test := range tests {
+		got, ok := opts[test.name]
+		if !ok {
+			t.Fatalf("%q: option not found", test.name)
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected value -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+	if got := opts["whitelist"].([]string); len(got) != 1 ||
+		got[0] != "192.168.1.0/24" {
+
+		t.Fatalf("unexpected whitelist -- got %v", got)
+	}
+
+	// Ensure unexported fields and the values of credentials are not included.
+	if _, ok := opts["whitelists"]; ok {
+		t.Fatal("unexported field included in effective config")
+	}
+	for name, value := range opts {
+		if value == "secret" || value == "proxysecret" {
+			t.Fatalf("%q: credential disclosed in effective config", name)
+		}
+	}
+}
+
+// TestParseAdvertiseServices ensures the services to advertise to peers are
+// parsed as expected.
+func TestParseAdvertiseServices(t *testing.T) {
+	tests := []struct {
+		names   []string
+		want    wire.ServiceFlag
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{[]string{"network", "cf"}, wire.SFNodeNetwork | wire.SFNodeCF, false},
+		{[]string{"networklimited", "cmpctblock"}, wire.SFNodeNetworkLimited |
+			wire.SFNodeCmpctBlock, false},
+		{[]string{"cf", "cf"}, wire.SFNodeCF, false},
+		{[]string{"bloom"}, 0, true},
+		{[]string{"CF"}, 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := parseAdvertiseServices(test.names)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.names, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected services -- got %v, want %v",
+				test.names, got, test.want)
+		}
+	}
+}
+
+// TestParseTxTypes ensures the transaction types that are not relayed are
+// parsed as expected.
+func TestParseTxTypes(t *testing.T) {
+	tests := []struct {
+		names   []string
+		want    map[stake.TxType]struct{}
+		wantErr bool
+	}{
+		{nil, nil, false},
+		{[]string{"revocation"}, map[stake.TxType]struct{}{
+			stake.TxTypeSSRtx: {},
+		}, false},
+		{[]string{"regular", "ticket", "vote", "vote"}, map[stake.TxType]struct{}{
+			stake.TxTypeRegular: {},
+			stake.TxTypeSStx:    {},
+			stake.TxTypeSSGen:   {},
+		}, false},
+		{[]string{"sstx"}, nil, true},
+		{[]string{"Vote"}, nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := parseTxTypes(test.names)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.names, err, test.wantErr)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Fatalf("%q: unexpected tx types -- got %v, want %v",
+				test.names, got, test.want)
+		}
+	}
+}
+
+// TestParseTLSOptions ensures the RPC TLS version and cipher suite options are
+// parsed as expected and that insecure values are rejected.
+func TestParseTLSOptions(t *testing.T) {
+	versionTests := []struct {
+		version string
+		want    uint16
+		wantErr bool
+	}{
+		{"1.2", tls.VersionTLS12, false},
+		{"1.3", tls.VersionTLS13, false},
+		{"1.1", 0, true},
+		{"1.0", 0, true},
+		{"", 0, true},
+	}
+	for _, test := range versionTests {
+		got, err := parseTLSVersion(test.version)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.version, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected version -- got %x, want %x",
+				test.version, got, test.want)
+		}
+	}
+
+	// Ensure no cipher suites results in the defaults being used.
+	suites, err := parseTLSCipherSuites(nil)
+	if err != nil || suites != nil {
+		t.Fatalf("no cipher suites: unexpected result -- got %v, err %v",
+			suites, err)
+	}
+
+	// Ensure secure cipher suites are parsed.
+	names := []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"}
+	want := []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256}
+	suites, err = parseTLSCipherSuites(names)
+	if err != nil {
+		t.Fatalf("unexpected error parsing cipher suites: %v", err)
+	}
+	if !reflect.DeepEqual(suites, want) {
+		t.Fatalf("unexpected cipher suites -- got %v, want %v", suites,
+			want)
+	}
+
+	// Ensure insecure and unknown cipher suites are rejected.
+	for _, name := range []string{"TLS_RSA_WITH_RC4_128_SHA", "bogus"} {
+		if _, err := parseTLSCipherSuites([]string{name}); err == nil {
+			t.Fatalf("%q: cipher suite was not rejected", name)
+		}
+	}
+}
+
 // init parses the -test.* flags from the command line arguments list and then
 // removes them to allow go-flags tests to succeed.
 func init() {
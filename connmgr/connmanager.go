@@ -27,6 +27,11 @@ var (
 	// be specified in the configuration.
 	ErrBothDialsFilled = errors.New("config: cannot specify both Dial and DialAddr")
 
+	// errPendingTimeout is used to indicate that a connection attempt was
+	// abandoned due to exceeding the maximum pending duration.
+	errPendingTimeout = errors.New("connection attempt exceeded the max " +
+		"pending duration")
+
 	// maxRetryDuration is the max duration of time retrying of a persistent
 	// connection is allowed to grow to.  This is necessary since the retry
 	// logic uses a backoff mechanism which increases the interval base times
@@ -126,6 +131,20 @@ type Config struct {
 	// requests. Defaults to 5s.
 	RetryDuration time.Duration
 
+	// MaxConcurrentDials is the maximum number of outbound connection
+	// attempts that may be in the process of dialing at once.  Additional
+	// attempts wait until an in-flight dial completes.  Defaults to 0 which
+	// means unlimited.
+	MaxConcurrentDials uint32
+
+	// MaxPendingDuration is the maximum duration a connection attempt may
+	// remain pending, including any time spent waiting to dial, before it
+	// is abandoned and treated as a failed attempt.  This results in a
+	// fresh candidate address being attempted for automatic connections
+	// and a retry for persistent connections.  Defaults to 0 which means
+	// no limit.
+	MaxPendingDuration time.Duration
+
 	// OnConnection is a callback that is fired when a new outbound
 	// connection is established.
 	OnConnection func(*ConnReq, net.Conn)
@@ -134,6 +153,12 @@ type Config struct {
 	// connection is disconnected.
 	OnDisconnection func(*ConnReq)
 
+	// OnConnectionFailed is a callback that is fired when an outbound
+	// connection attempt fails, including attempts abandoned due to
+	// exceeding MaxPendingDuration.  It may be nil if the caller does not
+	// wish to be notified of failures.
+	OnConnectionFailed func(*ConnReq, error)
+
 	// GetNewAddress is a way to get an address to make a network connection
 	// to.  If nil, no new connections will be made automatically.
 	GetNewAddress func() (net.Addr, error)
@@ -175,16 +200,53 @@ type handleFailed struct {
 	err error
 }
 
+// getStats is used to request the current connection manager statistics.
+type getStats struct {
+	reply chan Stats
+}
+
+// Stats houses statistics about the connection requests tracked by the
+// connection manager.
+type Stats struct {
+	// TargetOutbound is the number of outbound connections the connection
+	// manager attempts to maintain.
+	TargetOutbound uint32
+
+	// Established is the number of established outbound connections,
+	// including persistent connections.
+	Established uint32
+
+	// Pending is the number of connection requests that have yet to
+	// succeed.
+	Pending uint32
+
+	// InFlightDials is the number of connection attempts that are in the
+	// process of dialing.  This includes dials for attempts that were
+	// abandoned due to exceeding the max pending duration which have not
+	// returned yet.
+	InFlightDials uint32
+
+	// FailedAttempts is the number of successive failed attempts to
+	// connect to new addresses since the last successful connection.
+	FailedAttempts uint64
+
+	// TotalFailed is the total number of failed connection attempts.
+	TotalFailed uint64
+}
+
 // ConnManager provides a manager to handle network connections.
 type ConnManager struct {
 	// The following variables must only be used atomically.
-	connReqCount uint64
-	start        int32
-	stop         int32
+	connReqCount  uint64
+	start         int32
+	stop          int32
+	inFlightDials uint32
 
 	cfg            Config
 	wg             sync.WaitGroup
 	failedAttempts uint64
+	totalFailed    uint64
+	dialSem        chan struct{}
 	requests       chan interface{}
 	quit           chan struct{}
 }
@@ -345,7 +407,21 @@ out:
 				connReq.updateState(ConnFailed)
 				log.Debugf("Failed to connect to %v: %v",
 					connReq, msg.err)
+				cm.totalFailed++
+				if cm.cfg.OnConnectionFailed != nil {
+					go cm.cfg.OnConnectionFailed(connReq, msg.err)
+				}
 				cm.handleFailedConn(connReq)
+
+			case getStats:
+				msg.reply <- Stats{
+					TargetOutbound: cm.cfg.TargetOutbound,
+					Established:    uint32(len(conns)),
+					Pending:        uint32(len(pending)),
+					InFlightDials:  atomic.LoadUint32(&cm.inFlightDials),
+					FailedAttempts: cm.failedAttempts,
+					TotalFailed:    cm.totalFailed,
+				}
 			}
 
 		case <-cm.quit:
@@ -440,14 +516,41 @@ func (cm *ConnManager) Connect(c *ConnReq) {
 		}
 	}
 
-	log.Debugf("Attempting to connect to %v", c)
+	// Bound the overall time the attempt remains pending when configured.
+	var timeout <-chan time.Time
+	if cm.cfg.MaxPendingDuration > 0 {
+		timer := time.NewTimer(cm.cfg.MaxPendingDuration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
 
-	var conn net.Conn
+	// Wait for an in-flight dial to complete when the maximum number of
+	// concurrent dials has been reached.
 	var err error
-	if cm.cfg.Dial != nil {
-		conn, err = cm.cfg.Dial(c.Addr.Network(), c.Addr.String())
-	} else {
-		conn, err = cm.cfg.DialAddr(c.Addr)
+	if cm.dialSem != nil {
+		select {
+		case cm.dialSem <- struct{}{}:
+		case <-timeout:
+			err = errPendingTimeout
+		case <-cm.quit:
+			return
+		}
+	}
+
+	var conn net.Conn
+	if err == nil {
+		log.Debugf("Attempting to connect to %v", c)
+
+		// The dial slot is only released once the dial actually returns,
+		// even when the attempt is abandoned beforehand, so abandoned dials
+		// that are still hanging count towards the concurrent dial limit.
+		atomic.AddUint32(&cm.inFlightDials, 1)
+		conn, err = cm.dial(c, timeout, func() {
+			atomic.AddUint32(&cm.inFlightDials, ^uint32(0))
+			if cm.dialSem != nil {
+				<-cm.dialSem
+			}
+		})
 	}
 	if err != nil {
 		select {
@@ -463,6 +566,47 @@ func (cm *ConnManager) Connect(c *ConnReq) {
 	}
 }
 
+// dial connects to the address of the provided connection request.  The dial
+// is abandoned with errPendingTimeout when the provided timeout channel, which
+// may be nil to wait indefinitely, fires first.  Any connection that is
+// established after the dial is abandoned is closed.  The provided done
+// function is invoked once the underlying dial returns, which happens after
+// this function returns when the dial is abandoned.
+func (cm *ConnManager) dial(c *ConnReq, timeout <-chan time.Time, done func()) (net.Conn, error) {
+	dial := func() (net.Conn, error) {
+		defer done()
+		if cm.cfg.Dial != nil {
+			return cm.cfg.Dial(c.Addr.Network(), c.Addr.String())
+		}
+		return cm.cfg.DialAddr(c.Addr)
+	}
+	if timeout == nil {
+		return dial()
+	}
+
+	type dialResult struct {
+		conn net.Conn
+		err  error
+	}
+	result := make(chan dialResult, 1)
+	go func() {
+		conn, err := dial()
+		result <- dialResult{conn, err}
+	}()
+
+	select {
+	case r := <-result:
+		return r.conn, r.err
+	case <-timeout:
+		go func() {
+			if r := <-result; r.conn != nil {
+				r.conn.Close()
+			}
+		}()
+		return nil, errPendingTimeout
+	}
+}
+
 // Disconnect disconnects the connection corresponding to the given connection
 // id. If permanent, the connection will be retried with an increasing backoff
 // duration.
@@ -493,6 +637,29 @@ func (cm *ConnManager) Remove(id uint64) {
 	}
 }
 
+// Stats returns statistics about the connection requests tracked by the
+// connection manager.  The zero value is returned when the connection manager
+// is not running.
+func (cm *ConnManager) Stats() Stats {
+	if atomic.LoadInt32(&cm.start) == 0 || atomic.LoadInt32(&cm.stop) != 0 {
+		return Stats{}
+	}
+
+	reply := make(chan Stats, 1)
+	select {
+	case cm.requests <- getStats{reply}:
+	case <-cm.quit:
+		return Stats{}
+	}
+
+	select {
+	case stats := <-reply:
+		return stats
+	case <-cm.quit:
+		return Stats{}
+	}
+}
+
 // listenHandler accepts incoming connections on a given listener.  It must be
 // run as a goroutine.
 func (cm *ConnManager) listenHandler(listener net.Listener) {
@@ -583,5 +750,8 @@ func New(cfg *Config) (*ConnManager, error) {
 		requests: make(chan interface{}),
 		quit:     make(chan struct{}),
 	}
+	if cfg.MaxConcurrentDials > 0 {
+		cm.dialSem = make(chan struct{}, cfg.MaxConcurrentDials)
+	}
 	return &cm, nil
 }
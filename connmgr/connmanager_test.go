@@ -60,6 +60,18 @@ func (c mockConn) SetDeadline(t time.Time) error      { return nil }
 func (c mockConn) SetReadDeadline(t time.Time) error  { return nil }
 func (c mockConn) SetWriteDeadline(t time.Time) error { return nil }
 
+// mockConnCloser embeds a mock connection and signals when it is closed.
+type mockConnCloser struct {
+	mockConn
+	closed chan struct{}
+}
+
+// Close signals the connection is closed.
+func (c *mockConnCloser) Close() error {
+	close(c.closed)
+	return nil
+}
+
 // mockDialer mocks the net.Dial interface by returning a mock connection to
 // the given address.
 func mockDialer(network, addr string) (net.Conn, error) {
@@ -242,6 +254,232 @@ func TestTargetOutbound(t *testing.T) {
 	cmgr.Stop()
 }
 
+// TestMaxConcurrentDials ensures the number of in-flight dials never exceeds
+// the configured maximum while still establishing all target connections.
+func TestMaxConcurrentDials(t *testing.T) {
+	const targetOutbound = 10
+	const maxConcurrentDials = 3
+	var inFlight, maxInFlight int32
+	connected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		TargetOutbound:     targetOutbound,
+		MaxConcurrentDials: maxConcurrentDials,
+		Dial: func(network, addr string) (net.Conn, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond * 5)
+			atomic.AddInt32(&inFlight, -1)
+			return mockDialer(network, addr)
+		},
+		GetNewAddress: func() (net.Addr, error) {
+			return &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: 18555,
+			}, nil
+		},
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	cmgr.Start()
+	for i := 0; i < targetOutbound; i++ {
+		select {
+		case <-connected:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timeout waiting for connection %d", i)
+		}
+	}
+	cmgr.Stop()
+
+	if max := atomic.LoadInt32(&maxInFlight); max > maxConcurrentDials {
+		t.Fatalf("unexpected max in-flight dials -- got %d, want at most %d",
+			max, maxConcurrentDials)
+	}
+}
+
+// TestStats ensures the connection manager statistics reflect the established
+// and failed connection requests.
+func TestStats(t *testing.T) {
+	const targetOutbound = 3
+	connected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		TargetOutbound: targetOutbound,
+		Dial:           mockDialer,
+		GetNewAddress: func() (net.Addr, error) {
+			return &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: 18555,
+			}, nil
+		},
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	// Ensure the zero value is returned before the connection manager is
+	// started.
+	if stats := cmgr.Stats(); stats != (Stats{}) {
+		t.Fatalf("unexpected stats before start -- got %+v", stats)
+	}
+
+	cmgr.Start()
+	for i := 0; i < targetOutbound; i++ {
+		<-connected
+	}
+	want := Stats{TargetOutbound: targetOutbound, Established: targetOutbound}
+	if stats := cmgr.Stats(); stats != want {
+		t.Fatalf("unexpected stats -- got %+v, want %+v", stats, want)
+	}
+
+	// Ensure failed connection attempts are counted.  The failing request
+	// is permanent so no new requests are made to replace it.
+	failedReq := &ConnReq{
+		Addr:      &mockAddr{"tcp", "127.0.0.1:18556"},
+		Permanent: true,
+	}
+	cmgr.cfg.Dial = func(network, addr string) (net.Conn, error) {
+		return nil, errors.New("dial failed")
+	}
+	cmgr.Connect(failedReq)
+	stats := cmgr.Stats()
+	if stats.TotalFailed != 1 || stats.Pending != 1 {
+		t.Fatalf("unexpected stats after failure -- got %+v", stats)
+	}
+	cmgr.Remove(failedReq.ID())
+	cmgr.Stop()
+	cmgr.Wait()
+
+	// Ensure the zero value is returned after the connection manager is
+	// stopped.
+	if stats := cmgr.Stats(); stats != (Stats{}) {
+		t.Fatalf("unexpected stats after stop -- got %+v", stats)
+	}
+}
+
+// TestMaxPendingDuration ensures connection attempts that remain pending for
+// longer than the configured maximum are abandoned in favor of a fresh
+// candidate address and that connections established after being abandoned are
+// closed.
+func TestMaxPendingDuration(t *testing.T) {
+	var numAddrs uint32
+	hangingDial := make(chan struct{})
+	closed := make(chan struct{})
+	connected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		TargetOutbound:     1,
+		MaxPendingDuration: time.Millisecond * 10,
+		Dial: func(network, addr string) (net.Conn, error) {
+			if addr == "127.0.0.1:18555" {
+				<-hangingDial
+				return &mockConnCloser{mockConn{}, closed}, nil
+			}
+			return mockDialer(network, addr)
+		},
+		GetNewAddress: func() (net.Addr, error) {
+			port := 18555 + int(atomic.AddUint32(&numAddrs, 1)) - 1
+			return &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: port,
+			}, nil
+		},
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	cmgr.Start()
+
+	select {
+	case c := <-connected:
+		if c.Addr.String() != "127.0.0.1:18556" {
+			t.Fatalf("unexpected connection -- got %v, want %v", c.Addr,
+				"127.0.0.1:18556")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout waiting for connection to fresh candidate")
+	}
+	if stats := cmgr.Stats(); stats.TotalFailed != 1 {
+		t.Fatalf("unexpected failed attempts -- got %d, want 1",
+			stats.TotalFailed)
+	}
+
+	// Ensure the connection for the abandoned attempt is closed once the
+	// hanging dial completes.
+	close(hangingDial)
+	select {
+	case <-closed:
+	case <-time.After(time.Second * 5):
+		t.Fatal("connection for abandoned attempt was not closed")
+	}
+	cmgr.Stop()
+}
+
+// TestMaxPendingDurationDialSlot ensures abandoned connection attempts keep
+// their concurrent dial slot until the dial actually returns.
+func TestMaxPendingDurationDialSlot(t *testing.T) {
+	var numAddrs uint32
+	hangingDial := make(chan struct{})
+	connected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		TargetOutbound:     1,
+		MaxConcurrentDials: 1,
+		MaxPendingDuration: time.Millisecond * 10,
+		Dial: func(network, addr string) (net.Conn, error) {
+			if addr == "127.0.0.1:18555" {
+				<-hangingDial
+				return nil, errors.New("dial failed")
+			}
+			return mockDialer(network, addr)
+		},
+		GetNewAddress: func() (net.Addr, error) {
+			port := 18555 + int(atomic.AddUint32(&numAddrs, 1)) - 1
+			return &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: port,
+			}, nil
+		},
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	cmgr.Start()
+
+	// Ensure no other connection is dialed while the abandoned dial is still
+	// hanging since it holds the only dial slot.
+	select {
+	case c := <-connected:
+		t.Fatalf("unexpected connection to %v while dial slot is held",
+			c.Addr)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// Ensure a connection is established once the hanging dial returns and
+	// releases the dial slot.
+	close(hangingDial)
+	select {
+	case <-connected:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout waiting for connection after dial slot released")
+	}
+	cmgr.Stop()
+}
+
 // TestPassAddrAlongDialAddr tests if when using the DialAddr config option,
 // any address object returned by GetNewAddress will be correctly passed along
 // to DialAddr to be used for connecting to a host.
@@ -456,6 +694,55 @@ func TestNetworkFailure(t *testing.T) {
 	}
 }
 
+// TestOnConnectionFailed ensures the connection failed callback is invoked with
+// the connection request and dial error when a connection attempt fails.
+func TestOnConnectionFailed(t *testing.T) {
+	dialErr := errors.New("network down")
+	errDialer := func(network, addr string) (net.Conn, error) {
+		return nil, dialErr
+	}
+	type failure struct {
+		c   *ConnReq
+		err error
+	}
+	failed := make(chan failure, 1)
+	cmgr, err := New(&Config{
+		RetryDuration: time.Hour,
+		Dial:          errDialer,
+		OnConnectionFailed: func(c *ConnReq, err error) {
+			failed <- failure{c, err}
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	cmgr.Start()
+	defer func() {
+		cmgr.Stop()
+		cmgr.Wait()
+	}()
+
+	cr := &ConnReq{
+		Addr: &net.TCPAddr{
+			IP:   net.ParseIP("127.0.0.1"),
+			Port: 18555,
+		},
+		Permanent: true,
+	}
+	go cmgr.Connect(cr)
+	select {
+	case f := <-failed:
+		if f.c != cr {
+			t.Fatalf("unexpected conn req -- got %v, want %v", f.c, cr)
+		}
+		if f.err != dialErr {
+			t.Fatalf("unexpected error -- got %v, want %v", f.err, dialErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("connection failed callback was not invoked")
+	}
+}
+
 // TestStopFailed tests that failed connections are ignored after connmgr is
 // stopped.
 //
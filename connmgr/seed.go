@@ -29,7 +29,15 @@ type OnSeed func(addrs []*wire.NetAddress)
 type LookupFunc func(string) ([]net.IP, error)
 
 // SeedFromDNS uses DNS seeding to populate the address manager with peers.
+//
+// The seeds are asked to only return nodes that provide the required services
+// when they include more than wire.SFNodeNetwork, in which case the returned
+// addresses are marked as providing them.
 func SeedFromDNS(dnsSeeds []string, defaultPort uint16, reqServices wire.ServiceFlag, lookupFn LookupFunc, seedFn OnSeed) {
+	var services wire.ServiceFlag
+	if reqServices != wire.SFNodeNetwork {
+		services = reqServices
+	}
 	for _, seed := range dnsSeeds {
 		host := seed
 		if reqServices != wire.SFNodeNetwork {
@@ -60,7 +68,7 @@ func SeedFromDNS(dnsSeeds []string, defaultPort uint16, reqServices wire.Service
 					// and 7 days ago.
 					time.Now().Add(-1*time.Second*time.Duration(secondsIn3Days+
 						randSource.Int31n(secondsIn4Days))),
-					0, peer, defaultPort)
+					services, peer, defaultPort)
 			}
 
 			seedFn(addresses)
@@ -0,0 +1,67 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package connmgr
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestSeedFromDNS ensures the seeds are queried for the required services and
+// the returned addresses are marked as providing them.
+func TestSeedFromDNS(t *testing.T) {
+	tests := []struct {
+		name         string
+		reqServices  wire.ServiceFlag
+		wantHost     string
+		wantServices wire.ServiceFlag
+	}{{
+		name:         "network only",
+		reqServices:  wire.SFNodeNetwork,
+		wantHost:     "seed.example.com",
+		wantServices: 0,
+	}, {
+		name:         "network and cf",
+		reqServices:  wire.SFNodeNetwork | wire.SFNodeCF,
+		wantHost:     "x5.seed.example.com",
+		wantServices: wire.SFNodeNetwork | wire.SFNodeCF,
+	}}
+
+	for _, test := range tests {
+		hosts := make(chan string, 1)
+		lookup := func(host string) ([]net.IP, error) {
+			hosts <- host
+			return []net.IP{net.ParseIP("127.0.0.1")}, nil
+		}
+		seeded := make(chan []*wire.NetAddress, 1)
+		SeedFromDNS([]string{"seed.example.com"}, 9108, test.reqServices,
+			lookup, func(addrs []*wire.NetAddress) { seeded <- addrs })
+
+		select {
+		case addrs := <-seeded:
+			if host := <-hosts; host != test.wantHost {
+				t.Fatalf("%q: unexpected host -- got %q, want %q",
+					test.name, host, test.wantHost)
+			}
+			if len(addrs) != 1 {
+				t.Fatalf("%q: unexpected number of addresses -- got %d, "+
+					"want 1", test.name, len(addrs))
+			}
+			if addrs[0].Services != test.wantServices {
+				t.Fatalf("%q: unexpected services -- got %v, want %v",
+					test.name, addrs[0].Services, test.wantServices)
+			}
+			if addrs[0].Port != 9108 {
+				t.Fatalf("%q: unexpected port -- got %d, want 9108",
+					test.name, addrs[0].Port)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("%q: timeout waiting for seeded addresses", test.name)
+		}
+	}
+}
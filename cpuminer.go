@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -559,9 +560,11 @@ func (m *CPUMiner) HashesPerSecond() float64 {
 }
 
 // SetNumWorkers sets the number of workers to create which solve blocks.  Any
-// negative values will cause a default number of workers to be used which is
-// based on the number of processor cores in the system.  A value of 0 will
-// cause all CPU mining to be stopped.
+// negative values will cause one worker per processor core in the system to be
+// used.  A value of 0 will cause all CPU mining to be stopped.
+//
+// When the miner is already running, the number of running workers is adjusted
+// to the new value without restarting the miner.
 //
 // This function is safe for concurrent access.
 func (m *CPUMiner) SetNumWorkers(numWorkers int32) {
@@ -569,17 +572,26 @@ func (m *CPUMiner) SetNumWorkers(numWorkers int32) {
 		m.Stop()
 	}
 
-	// Use default if provided value is negative.
+	// Use one worker per processor core if provided value is negative.
 	if numWorkers < 0 {
-		atomic.StoreUint32(&m.numWorkers, defaultNumWorkers)
+		atomic.StoreUint32(&m.numWorkers, uint32(runtime.NumCPU()))
 	} else {
 		atomic.StoreUint32(&m.numWorkers, uint32(numWorkers))
 	}
 
 	// When the miner is already running, notify the controller about the
-	// the change.
-	if m.started {
-		m.updateNumWorkers <- struct{}{}
+	// the change.  Discrete mining only ever uses a single worker, so there
+	// is nothing to notify in that case.  The quit channel is also selected
+	// on to avoid blocking forever should the miner be stopped concurrently.
+	m.Lock()
+	notify := m.started && !m.discreteMining
+	quit := m.quit
+	m.Unlock()
+	if notify {
+		select {
+		case m.updateNumWorkers <- struct{}{}:
+		case <-quit:
+		}
 	}
 }
 
@@ -0,0 +1,101 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestCPUMinerSetNumWorkers ensures changing the number of workers updates the
+// reported number of workers and only notifies the mining controller when the
+// miner is running with multiple workers.
+func TestCPUMinerSetNumWorkers(t *testing.T) {
+	tests := []struct {
+		name           string
+		started        bool
+		discreteMining bool
+		quit           bool
+		numWorkers     int32
+		wantWorkers    int32
+		wantNotify     bool
+	}{{
+		name:        "not started",
+		numWorkers:  2,
+		wantWorkers: 2,
+	}, {
+		name:        "negative uses all cores",
+		numWorkers:  -1,
+		wantWorkers: int32(runtime.NumCPU()),
+	}, {
+		name:        "running notifies controller",
+		started:     true,
+		numWorkers:  3,
+		wantWorkers: 3,
+		wantNotify:  true,
+	}, {
+		name:           "discrete mining does not notify",
+		started:        true,
+		discreteMining: true,
+		numWorkers:     3,
+		wantWorkers:    3,
+	}, {
+		name:        "stopping miner does not block",
+		started:     true,
+		quit:        true,
+		numWorkers:  3,
+		wantWorkers: 3,
+	}}
+
+	for _, test := range tests {
+		m := &CPUMiner{
+			started:          test.started,
+			discreteMining:   test.discreteMining,
+			updateNumWorkers: make(chan struct{}),
+			quit:             make(chan struct{}),
+		}
+		if test.quit {
+			close(m.quit)
+		}
+
+		// Receive the controller notification, if any, while the number of
+		// workers is updated.  There is nothing to receive the notification
+		// when the miner is being stopped to ensure the update does not block.
+		notified := make(chan bool, 1)
+		go func() {
+			if test.quit {
+				notified <- false
+				return
+			}
+			select {
+			case <-m.updateNumWorkers:
+				notified <- true
+			case <-time.After(time.Millisecond * 100):
+				notified <- false
+			}
+		}()
+
+		done := make(chan struct{})
+		go func() {
+			m.SetNumWorkers(test.numWorkers)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("%q: SetNumWorkers did not return", test.name)
+		}
+
+		if got := <-notified; got != test.wantNotify {
+			t.Errorf("%q: unexpected notification -- got %v, want %v",
+				test.name, got, test.wantNotify)
+		}
+		if got := m.NumWorkers(); got != test.wantWorkers {
+			t.Errorf("%q: unexpected number of workers -- got %d, want %d",
+				test.name, got, test.wantWorkers)
+		}
+	}
+}
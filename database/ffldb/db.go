@@ -1792,8 +1792,9 @@ type db struct {
 	cache     *dbCache     // Cache layer which wraps underlying leveldb DB.
 }
 
-// Enforce db implements the database.DB interface.
+// Enforce db implements the database.DB and database.BatchWriter interfaces.
 var _ database.DB = (*db)(nil)
+var _ database.BatchWriter = (*db)(nil)
 
 // Type returns the database driver type the current database instance was
 // created with.
@@ -1956,6 +1957,32 @@ func (db *db) Update(fn func(database.Tx) error) error {
 	return tx.Commit()
 }
 
+// SetBatchMode enables or disables batch mode for the database cache.  While
+// batch mode is enabled, the cache is only flushed to persistent storage once
+// it exceeds its max size or the database is closed as opposed to also being
+// flushed periodically.  Disabling batch mode immediately flushes the cache.
+//
+// This function is part of the database.BatchWriter interface implementation.
+func (db *db) SetBatchMode(enabled bool) error {
+	// The flush related fields of the cache are protected by the write lock,
+	// so acquire it to wait for any outstanding write transaction to finish.
+	// Also, prevent the database from being closed while the cache is being
+	// updated.
+	db.writeLock.Lock()
+	defer db.writeLock.Unlock()
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+	if db.closed {
+		return makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr, nil)
+	}
+
+	db.cache.batchMode = enabled
+	if enabled {
+		return nil
+	}
+	return db.cache.flush()
+}
+
 // Close cleanly shuts down the database and syncs all data.  It will block
 // until all database transactions have been finalized (rolled back or
 // committed).
@@ -2038,9 +2065,11 @@ func initDB(ldb *leveldb.DB) error {
 	return nil
 }
 
-// openDB opens the database at the provided path.  database.ErrDbDoesNotExist
-// is returned if the database doesn't exist and the create flag is not set.
-func openDB(dbPath string, network wire.CurrencyNet, create bool) (database.DB, error) {
+// openDB opens the database at the provided path using a database cache that
+// is allowed to grow to the provided size in bytes before it is flushed.
+// database.ErrDbDoesNotExist is returned if the database doesn't exist and the
+// create flag is not set.
+func openDB(dbPath string, network wire.CurrencyNet, cacheSize uint64, create bool) (database.DB, error) {
 	// Error if the database doesn't exist and the create flag is not set.
 	metadataDbPath := filepath.Join(dbPath, metadataDbName)
 	dbExists := fileExists(metadataDbPath)
@@ -2075,7 +2104,7 @@ func openDB(dbPath string, network wire.CurrencyNet, create bool) (database.DB,
 	// database cache which wraps the underlying leveldb database to provide
 	// write caching.
 	store := newBlockStore(dbPath, network)
-	cache := newDbCache(ldb, store, defaultCacheSize, defaultFlushSecs)
+	cache := newDbCache(ldb, store, cacheSize, defaultFlushSecs)
 	pdb := &db{store: store, cache: cache}
 
 	// Perform any reconciliation needed between the block and metadata as
@@ -362,11 +362,15 @@ type dbCache struct {
 	// lastFlush is the time the cache was last flushed.  It is used in
 	// conjunction with the current time and the flush interval.
 	//
+	// batchMode indicates the flush interval is ignored so the cache is
+	// only flushed once it exceeds the max size.
+	//
 	// NOTE: These flush related fields are protected by the database write
 	// lock.
 	maxSize       uint64
 	flushInterval time.Duration
 	lastFlush     time.Time
+	batchMode     bool
 
 	// The following fields hold the keys that need to be stored or deleted
 	// from the underlying database once the cache is full, enough time has
@@ -520,8 +524,8 @@ func (c *dbCache) flush() error {
 // This function MUST be called with the database write lock held.
 func (c *dbCache) needsFlush(tx *transaction) bool {
 	// A flush is needed when more time has elapsed than the configured
-	// flush interval.
-	if time.Since(c.lastFlush) > c.flushInterval {
+	// flush interval unless the cache is in batch mode.
+	if !c.batchMode && time.Since(c.lastFlush) > c.flushInterval {
 		return true
 	}
 
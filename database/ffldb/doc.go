@@ -15,7 +15,8 @@ Usage
 
 This package is a driver to the database package and provides the database type
 of "ffldb".  The parameters the Open and Create functions take are the
-database path as a string and the block network:
+database path as a string, the block network, and an optional maximum size in
+bytes for the database write cache as a uint64 which defaults to 100 MiB:
 
 	db, err := database.Open("ffldb", "path/to/database", wire.MainNet)
 	if err != nil {
@@ -26,5 +27,11 @@ database path as a string and the block network:
 	if err != nil {
 		// Handle error
 	}
+
+	db, err := database.Open("ffldb", "path/to/database", wire.MainNet,
+		uint64(512*1024*1024))
+	if err != nil {
+		// Handle error
+	}
 */
 package ffldb
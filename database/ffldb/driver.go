@@ -19,49 +19,61 @@ const (
 	dbType = "ffldb"
 )
 
-// parseArgs parses the arguments from the database Open/Create methods.
-func parseArgs(funcName string, args ...interface{}) (string, wire.CurrencyNet, error) {
-	if len(args) != 2 {
-		return "", 0, fmt.Errorf("invalid arguments to %s.%s -- "+
-			"expected database path and block network", dbType,
-			funcName)
+// parseArgs parses the arguments from the database Open/Create methods.  The
+// cache size argument is optional and defaults to defaultCacheSize when it is
+// not provided.
+func parseArgs(funcName string, args ...interface{}) (string, wire.CurrencyNet, uint64, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return "", 0, 0, fmt.Errorf("invalid arguments to %s.%s -- "+
+			"expected database path, block network, and optional "+
+			"cache size", dbType, funcName)
 	}
 
 	dbPath, ok := args[0].(string)
 	if !ok {
-		return "", 0, fmt.Errorf("first argument to %s.%s is invalid -- "+
+		return "", 0, 0, fmt.Errorf("first argument to %s.%s is invalid -- "+
 			"expected database path string", dbType, funcName)
 	}
 
 	network, ok := args[1].(wire.CurrencyNet)
 	if !ok {
-		return "", 0, fmt.Errorf("second argument to %s.%s is invalid -- "+
+		return "", 0, 0, fmt.Errorf("second argument to %s.%s is invalid -- "+
 			"expected block network", dbType, funcName)
 	}
 
-	return dbPath, network, nil
+	cacheSize := uint64(defaultCacheSize)
+	if len(args) == 3 {
+		cacheSize, ok = args[2].(uint64)
+		if !ok {
+			return "", 0, 0, fmt.Errorf("third argument to %s.%s is "+
+				"invalid -- expected cache size in bytes as uint64",
+				dbType, funcName)
+		}
+	}
+
+	return dbPath, network, cacheSize, nil
 }
 
 // openDBDriver is the callback provided during driver registration that opens
 // an existing database for use.
 func openDBDriver(args ...interface{}) (database.DB, error) {
-	dbPath, network, err := parseArgs("Open", args...)
+	dbPath, network, cacheSize, err := parseArgs("Open", args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return openDB(dbPath, network, false)
+	return openDB(dbPath, network, cacheSize, false)
 }
 
 // createDBDriver is the callback provided during driver registration that
 // creates, initializes, and opens a database for use.
 func createDBDriver(args ...interface{}) (database.DB, error) {
-	dbPath, network, err := parseArgs("Create", args...)
+	dbPath, network, cacheSize, err := parseArgs("Create", args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return openDB(dbPath, network, true)
+	return openDB(dbPath, network, cacheSize, true)
 }
 
 // useLogger is the callback provided during driver registration that sets the
@@ -156,7 +156,7 @@ func TestCornerCases(t *testing.T) {
 	// directory is needed.
 	testName := "openDB: fail due to file at target location"
 	wantErrCode := database.ErrDriverSpecific
-	idb, err := openDB(dbPath, blockDataNet, true)
+	idb, err := openDB(dbPath, blockDataNet, defaultCacheSize, true)
 	if !checkDbError(t, testName, err, wantErrCode) {
 		if err == nil {
 			idb.Close()
@@ -168,7 +168,7 @@ func TestCornerCases(t *testing.T) {
 	// Remove the file and create the database to run tests against.  It
 	// should be successful this time.
 	_ = os.RemoveAll(dbPath)
-	idb, err = openDB(dbPath, blockDataNet, true)
+	idb, err = openDB(dbPath, blockDataNet, defaultCacheSize, true)
 	if err != nil {
 		t.Errorf("openDB: unexpected error: %v", err)
 		return
@@ -681,3 +681,57 @@ func TestFailureScenarios(t *testing.T) {
 	// Test various corruption scenarios.
 	testCorruption(tc)
 }
+
+// TestBatchMode ensures the database cache is not flushed due to the flush
+// interval while batch mode is enabled and that disabling batch mode flushes
+// the cache.
+func TestBatchMode(t *testing.T) {
+	// Create a new database to run tests against.
+	dbPath := filepath.Join(os.TempDir(), "ffldb-batchmode-v2")
+	_ = os.RemoveAll(dbPath)
+	idb, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer os.RemoveAll(dbPath)
+	defer idb.Close()
+
+	// Enable batch mode and force the flush interval to have elapsed.
+	if err := idb.(database.BatchWriter).SetBatchMode(true); err != nil {
+		t.Fatalf("SetBatchMode: unexpected error: %v", err)
+	}
+	cache := idb.(*db).cache
+	cache.flushInterval = 0
+
+	// Ensure committed transactions remain in the cache while batch mode is
+	// enabled even though the flush interval has elapsed.
+	for i := 0; i < 2; i++ {
+		err = idb.Update(func(tx database.Tx) error {
+			key := []byte(fmt.Sprintf("batchkey%d", i))
+			return tx.Metadata().Put(key, []byte("batchvalue"))
+		})
+		if err != nil {
+			t.Fatalf("Update: unexpected error: %v", err)
+		}
+	}
+	if cache.cachedKeys.Len() == 0 {
+		t.Fatal("cache was flushed while in batch mode")
+	}
+
+	// Ensure disabling batch mode flushes the cache.
+	if err := idb.(database.BatchWriter).SetBatchMode(false); err != nil {
+		t.Fatalf("SetBatchMode: unexpected error: %v", err)
+	}
+	if n := cache.cachedKeys.Len(); n != 0 {
+		t.Fatalf("cache was not flushed when disabling batch mode -- %d "+
+			"cached keys remain", n)
+	}
+
+	// Ensure setting the batch mode on a closed database returns the
+	// expected error.
+	if err := idb.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+	err = idb.(database.BatchWriter).SetBatchMode(true)
+	checkDbError(t, "SetBatchMode on closed db", err, database.ErrDbNotOpen)
+}
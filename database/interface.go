@@ -476,3 +476,21 @@ type DB interface {
 	// back or committed).
 	Close() error
 }
+
+// BatchWriter is an optional interface that may be implemented by database
+// drivers which buffer committed transactions in memory and write them to
+// persistent storage in batches.  It allows callers to trade durability for
+// throughput during bulk operations such as the initial block download.
+type BatchWriter interface {
+	// SetBatchMode enables or disables batch mode.  While batch mode is
+	// enabled, committed transactions are accumulated in memory for as
+	// long as the implementation allows and are only written to persistent
+	// storage once its buffer is full or the database is closed, rather
+	// than also periodically.  This means an unexpected shutdown while in
+	// batch mode will roll the database back to the state it was in as of
+	// the most recent write to persistent storage.
+	//
+	// Disabling batch mode immediately writes all buffered transactions to
+	// persistent storage.
+	SetBatchMode(enabled bool) error
+}
@@ -31,6 +31,12 @@ var winServiceMain func() (bool, error)
 // service is not running.
 var serviceStartOfDayChan = make(chan *config, 1)
 
+// restartExitCode is the exit code used when the process shuts down due to a
+// restart request from the restart RPC so process managers can distinguish it
+// from a normal shutdown and restart the process.  It is the conventional code
+// for a temporary failure (EX_TEMPFAIL).
+const restartExitCode = 75
+
 // dcrdMain is the real main function for dcrd.  It is necessary to work around
 // the fact that deferred functions do not run when os.Exit() is called.
 func dcrdMain() error {
@@ -45,6 +51,9 @@ func dcrdMain() error {
 		if logRotator != nil {
 			logRotator.Close()
 		}
+		if rpcAuditRotator != nil {
+			rpcAuditRotator.Close()
+		}
 	}()
 
 	// Get a context that will be canceled when a shutdown signal has been
@@ -100,6 +109,19 @@ func dcrdMain() error {
 		defer pprof.WriteHeapProfile(f)
 	}
 
+	// Start the automatic profiling watchdog if either threshold is set.
+	if cfg.AutoProfileHeap != 0 || cfg.AutoProfileGoroutines != 0 {
+		if err := os.MkdirAll(cfg.AutoProfileDir, 0700); err != nil {
+			dcrdLog.Errorf("Unable to create automatic profile "+
+				"directory: %v", err)
+			return err
+		}
+		dcrdLog.Infof("Automatic profiles will be written to %s",
+			cfg.AutoProfileDir)
+		go autoProfileWatchdog(ctx, cfg.AutoProfileDir,
+			cfg.AutoProfileHeap*1024*1024, cfg.AutoProfileGoroutines)
+	}
+
 	var lifetimeNotifier lifetimeEventServer
 	if cfg.LifetimeEvents {
 		lifetimeNotifier = newLifetimeEventServer(outgoingPipeMessages)
@@ -199,6 +221,10 @@ func dcrdMain() error {
 		return nil
 	}
 
+	// Reload the subset of the configuration that may be changed while
+	// running when requested via an OS signal.
+	go reloadListener(ctx, server.reloadConfig)
+
 	lifetimeNotifier.notifyStartupComplete()
 
 	// Signal the Windows service (if running) that startup has completed.
@@ -245,4 +271,10 @@ func main() {
 	if err := dcrdMain(); err != nil {
 		os.Exit(1)
 	}
+
+	// Exit with a distinct code when the shutdown was the result of a restart
+	// request so process managers are able to restart the process.
+	if restartRequested {
+		os.Exit(restartExitCode)
+	}
 }
@@ -18,6 +18,16 @@ parsed when dcrd starts up.  By default, the configuration file is located at
 on Windows.  The -C (--configfile) flag, as shown below, can be used to override
 this location.
 
+A subset of the options may be changed while dcrd is running by updating the
+configuration file (or command line) and sending the process a SIGHUP signal on
+POSIX-style operating systems.  The reloadable options are --banthreshold,
+--maxpeers, --minrelaytxfee, and --whitelist.  Reloaded values that are invalid
+are rejected and logged while the current values are retained, and reloadable
+options that are no longer specified revert to their defaults.  All other
+options, including the listening interfaces, indexes, network selection, RPC
+settings, the target number of outbound peers, and the fee estimator buckets,
+only take effect on startup and therefore require a restart.
+
 Usage:
   dcrd [OPTIONS]
 
@@ -33,35 +43,146 @@ Application Options:
                             Listening is automatically disabled if the --connect
                             or --proxy options are used without also specifying
                             listen interfaces via --listen
-      --listen=             Add an interface/port to listen for connections
-                            (default all interfaces port: 9108, testnet: 19108)
+      --listen=             Add an interface/port or unix:/path/to/socket to
+                            listen for connections (default all interfaces
+                            port: 9108, testnet: 19108)
       --maxsameip=          Max number of connections with the same IP -- 0 to
                             disable (default: 5)
+      --maxsameipinbound=   Max number of inbound connections with the same IP,
+                            which is enforced in addition to maxsameip -- 0 to
+                            disable
       --maxpeers=           Max number of inbound and outbound peers (125)
+      --minoutboundgroups=  Min number of distinct network groups among
+                            outbound peers -- The final automatic outbound
+                            connection slots are reserved for peers in new
+                            network groups until it is reached -- 0 to disable
+                            (4)
+      --maxconcurrentdials= Max number of outbound connection attempts that may
+                            be dialing at once -- 0 for unlimited (default: 0)
+      --pendingconntimeout= Abandon outbound connection attempts that remain
+                            pending for longer than the specified duration and
+                            try another address instead.  Valid time units are
+                            {s, m, h} -- 0 to disable
+      --feelerinterval=     Interval between short-lived feeler connections
+                            made to untried addresses to verify they are
+                            reachable once the outbound peer slots are full.
+                            Valid time units are {s, m, h} -- 0 to disable
+                            (2m0s)
+      --addrfailurethreshold= Number of consecutive failed connections after
+                            which an address that has never been successfully
+                            connected to is evicted from the address manager
+                            -- 0 to disable (10)
+      --pinginterval=       Interval between pings sent to each peer to
+                            measure latency and detect unresponsive
+                            connections.  Valid time units are {s, m, h}.
+                            Minimum 1 second (2m0s)
+      --maxmissedpongs=     Number of consecutive pings a peer may fail to
+                            answer before it is disconnected -- 0 to disable
+      --maxtimeadjustment=  Max amount of time the local clock is adjusted by in
+                            either direction based on the median time reported
+                            by peers -- No adjustment is made when the median
+                            exceeds it.  Valid time units are {s, m, h}.
+                            Maximum 1h10m0s -- 0 to disable (1h10m0s)
+      --minprotocolversion= Minimum protocol version to accept from inbound
+                            and outbound peers -- 0 to accept all versions
+                            supported by the wire protocol
       --nobanning           Disable banning of misbehaving peers
       --banduration=        How long to ban misbehaving peers.  Valid time units
                             are {s, m, h}.  Minimum 1 second (24h0m0s)
       --banthreshold=       Maximum allowed ban score before disconnecting and
                             banning misbehaving peers.
-      --whitelist=          Add an IP network or IP that will not be banned.
-                            (eg. 192.168.1.0/24 or ::1)
+      --banescalation=      Ban the entire /24 (IPv4) or /64 (IPv6) subnet of
+                            misbehaving peers once this many distinct IPs within
+                            it are banned within the ban escalation window -- 0
+                            to disable, otherwise minimum 2
+      --banescalationwindow= The window of time in which bans of distinct IPs
+                            within the same subnet count toward escalating to a
+                            subnet ban.  Valid time units are {s, m, h}.
+                            Minimum 1 second (1h0m0s)
+      --whitelist=          Add an IP network or IP that will not be banned and
+                            is exempt from the maxsameip, maxsameipinbound, and
+                            maxpeers limits as well as outbound network group
+                            diversity. (eg. 192.168.1.0/24 or ::1)
+      --whitelistuseragent= Exempt peers whose user agent contains the
+                            specified substring from ban scoring -- They
+                            remain subject to connection limits and bans since
+                            user agents are reported by peers and can be
+                            trivially spoofed -- may be specified multiple
+                            times
   -u, --rpcuser=            Username for RPC connections
   -P, --rpcpass=            Password for RPC connections
       --rpclimituser=       Username for limited RPC connections
       --rpclimitpass=       Password for limited RPC connections
-      --rpclisten=          Add an interface/port to listen for RPC connections
-                            (default port: 9109, testnet: 19109)
+      --rpclisten=          Add an interface/port or unix:/path/to/socket to
+                            listen for RPC connections (default port: 9109,
+                            testnet: 19109)
       --rpccert=            File containing the certificate file
       --rpckey=             File containing the certificate key
+      --rpctlsminversion=   Minimum TLS version for RPC connections {1.2, 1.3}
+                            (1.2)
+      --rpctlsciphersuite=  Add a cipher suite to allow for RPC connections
+                            using TLS 1.2, such as
+                            TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384 -- All
+                            secure cipher suites are allowed if none are
+                            specified
+      --rpcclientca=        File containing the certificate authorities used to
+                            verify RPC client certificates -- Clients that
+                            present a valid certificate without a username and
+                            password are authenticated with limited access
+      --rpcadminclientcn=   Add a client certificate common name that grants
+                            admin RPC access
       --rpcmaxclients=      Max number of RPC clients for standard connections
                             (10)
       --rpcmaxwebsockets=   Max number of RPC websocket connections (25)
+      --rpcmaxrescans=      Max number of rescans requested by websocket
+                            clients that may run concurrently -- Additional
+                            rescans wait for a running one to finish -- 0 for
+                            unlimited (2)
+      --rpcmaxresponsesize= Max size in bytes of a single marshalled RPC
+                            response -- 0 for unlimited
+      --rpcmaxntfnqueue=    Max number of notifications that may be queued for
+                            a single websocket client before the notification
+                            overflow policy is applied -- 0 for unlimited
+                            (10000)
+      --rpcntfnoverflow=    Action to take when the notification queue of a
+                            websocket client is full {dropoldest, disconnect}
+                            (disconnect)
+      --blockintervalwindow= Number of most recent blocks used to calculate the
+                            median block interval reported by getblockchaininfo
+                            (144)
+      --finalitydepth=      Default number of confirmations a block requires
+                            to be reported as final by the isblockfinal RPC
+                            (6)
+      --rpcidletimeout=     Disconnect websocket RPC clients without any
+                            requests, notifications, or pings for the
+                            specified duration.  Valid time units are {s, m,
+                            h} -- 0 to disable
+      --rpcauditlog         Log every RPC command along with whether the client
+                            is an admin or limited user, the method, a hash of
+                            the parameters, and the result to rpcaudit.log in
+                            the log directory
+      --rpcdebugdump        Enable the admin-only debugdump RPC which returns
+                            goroutine stack dumps and writes profiles to the
+                            data directory
       --norpc               Disable built-in RPC server -- NOTE: The RPC server
                             is disabled by default if no rpcuser/rpcpass or
                             rpclimituser/rpclimitpass is specified
       --notls               Disable TLS for the RPC server -- NOTE: This is only
                             allowed if the RPC server is bound to localhost
       --nodnsseed           Disable DNS seeding for peers
+      --dnsseed=            Add a DNS seed host to query for peers in addition to
+                            the built-in seeds for the active network
+      --replacednsseeds     Only query the DNS seeds specified with --dnsseed
+                            instead of also querying the built-in seeds for the
+                            active network
+      --dnsseedservice=     Only add peers from DNS seeds that are reported to
+                            provide the specified service in addition to being
+                            full nodes {bloom, cf, cmpctblock} -- may be
+                            specified multiple times
+      --advertiseservice=   Only advertise the specified service to peers
+                            instead of all services the node supports {network,
+                            networklimited, cf, cmpctblock} -- may be specified
+                            multiple times
       --externalip=         Add an ip to the list of local addresses we claim to
                             listen on to peers
       --proxy=              Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)
@@ -72,6 +193,9 @@ Application Options:
       --onionuser=          Username for onion proxy server
       --onionpass=          Password for onion proxy server
       --noonion             Disable connecting to tor hidden services
+      --oniononly           Only connect to tor hidden services and refuse to
+                            resolve or connect to any other addresses --
+                            requires --onion or --proxy
       --torisolation        Enable Tor stream isolation by randomizing user
                             credentials for each connection.
       --testnet             Use the test network
@@ -79,11 +203,28 @@ Application Options:
       --regnet              Use the regression test network
       --nocheckpoints       Disable built-in checkpoints.  Don't do this unless
                             you know what you're doing.
+      --assumevalid=        Skip transaction script validation for the specified
+                            block and its ancestors to speed up the initial
+                            sync (format: '<height>:<hash>') -- WARNING: Only
+                            specify a block you have independently verified
+                            since invalid scripts in those blocks would go
+                            unnoticed
       --dbtype=             Database backend to use for the Block Chain (ffldb)
+      --dbcache=            Maximum size in MiB of the database write cache --
+                            Larger values trade memory for fewer disk accesses
+                            -- Minimum 4, maximum 1048576 (1 TiB) (100)
       --profile=            Enable HTTP profiling on given [addr:]port -- NOTE: port
                             must be between 1024 and 65536
       --cpuprofile=         Write CPU profile to the specified file
       --memprofile=         Write mem profile to the specified file
+      --autoprofileheap=    Automatically write a heap profile when the
+                            allocated heap exceeds this many MiB -- 0 to
+                            disable
+      --autoprofilegoroutines= Automatically write a goroutine profile when
+                            the number of goroutines exceeds this count -- 0
+                            to disable
+      --autoprofiledir=     Directory to write automatic profiles to
+                            (default: data directory)
       --dumpblockchain=     Write blockchain as a gob-encoded map to the
                             specified file
       --miningtimeoffset=   Offset the mining timestamp of a block by this many
@@ -114,6 +255,10 @@ Application Options:
                             a block (375000)
       --blockprioritysize=  Size in bytes for high-priority/low-fee transactions
                             when creating a block (20000)
+      --deterministictxorder Order the transactions in created blocks by fee per
+                            kilobyte and then transaction hash so identical
+                            mempools produce identical blocks -- the
+                            blockprioritysize option is ignored
       --nonaggressive       Disable mining off of the parent block of the blockchain
                             if there aren't enough voters
       --nominingstatesync   Disable synchronizing the mining state with other nodes
@@ -121,7 +266,41 @@ Application Options:
 
       --sigcachemaxsize=    The maximum number of entries in the signature
                             verification cache.
+      --utxocachemaxsize=   The maximum size in MiB of the in-memory UTXO cache
+                            -- 0 to disable (150)
       --blocksonly          Do not accept transactions from remote peers.
+      --retainblocks=       Only serve the specified number of most recent
+                            blocks to peers and advertise limited block
+                            history instead of the full chain -- 0 to serve
+                            all blocks, otherwise minimum 288
+      --maxgetdatainv=      Max number of inventory items a peer may request in
+                            a single getdata message -- Larger requests are
+                            rejected and the peer is banned (50000)
+      --maxgetdatapending=  Max number of inventory items requested via
+                            getdata that may be queued to a peer without
+                            having been sent -- Serving further items is
+                            deferred until they are sent -- Whitelisted peers
+                            are exempt -- 0 for unlimited
+      --maxgetdataperminute= Max number of inventory items requested via
+                            getdata to serve to each peer per minute -- Items
+                            beyond the budget are answered with notfound -- 0
+                            for unlimited
+      --norelaytxtype=      Do not relay transactions of the specified type to
+                            peers even though they are still accepted {regular,
+                            ticket, vote, revocation} -- may be specified
+                            multiple times
+      --blockannounce=      Preferred method for peers to announce new blocks
+                            to this node {inv, headers, compact} -- Peers that
+                            do not support the method fall back to the best
+                            one they do (compact)
+      --invsuppresswindow=  How long to avoid relaying inventory back to the
+                            peer it was received from.  Valid time units are
+                            {ms, s, m} -- 0 to disable (5s)
+      --rebroadcastinterval= How often to rebroadcast transactions submitted
+                            via RPC that have not yet been mined.  A small
+                            random jitter is added to the interval.  Valid
+                            time units are {s, m, h} -- Minimum 1m -- 0 to
+                            rebroadcast at a random time up to 30 minutes
       --acceptnonstd        Accept and relay non-standard transactions to
                             the network regardless of the default settings
                             for the active network.
@@ -37,6 +37,22 @@ const (
 	// estimator.
 	defaultDecay float64 = 0.998
 
+	// shortDecay is the value used to decay old transactions from the short
+	// horizon statistics used by the economical estimation mode.  It halves
+	// the weight of transactions roughly every 18 blocks so that only recent
+	// blocks meaningfully contribute to the estimates.
+	shortDecay float64 = 0.962
+
+	// conservativeSuccessPct is the minimum percentage of transactions that
+	// must have been mined within the target confirmation range for a fee
+	// rate to be selected by the conservative estimation mode.
+	conservativeSuccessPct = 0.95
+
+	// economicalSuccessPct is the minimum percentage of transactions that
+	// must have been mined within the target confirmation range for a fee
+	// rate to be selected by the economical estimation mode.
+	economicalSuccessPct = 0.85
+
 	// maxAllowedBucketFees is an upper bound of how many bucket fees can be
 	// used in the estimator. This is verified during estimator initialization
 	// and database loading.
@@ -68,6 +84,35 @@ var (
 	dbKeyBucketPrefix = []byte{0x01, 0x70, 0x1d, 0x00}
 )
 
+// EstimateMode defines the mode used when estimating fees.
+type EstimateMode int
+
+const (
+	// EstimateConservative estimates fees using the statistics gathered over
+	// a long horizon of blocks along with a high required success rate.  It
+	// is less responsive to short-term drops in fee rates, but is less
+	// likely to result in transactions that take longer than the target to
+	// confirm.
+	EstimateConservative EstimateMode = iota
+
+	// EstimateEconomical estimates fees using only the statistics gathered
+	// over recent blocks along with a lower required success rate.  It
+	// generally results in lower fee rates which respond quickly to
+	// short-term changes in demand.
+	EstimateEconomical
+)
+
+// String returns the EstimateMode as a human-readable name.
+func (m EstimateMode) String() string {
+	switch m {
+	case EstimateConservative:
+		return "conservative"
+	case EstimateEconomical:
+		return "economical"
+	}
+	return fmt.Sprintf("unknown estimate mode (%d)", int(m))
+}
+
 // ErrTargetConfTooLarge is the type of error returned when an user of the
 // estimator requested a confirmation range higher than tracked by the estimator.
 type ErrTargetConfTooLarge struct {
@@ -94,6 +139,44 @@ type txConfirmStatBucket struct {
 	feeSum       float64
 }
 
+// newConfirmStatBuckets returns the provided number of empty buckets which
+// track the provided number of confirmation ranges.
+func newConfirmStatBuckets(nbBuckets int, maxConfirms int32) []txConfirmStatBucket {
+	buckets := make([]txConfirmStatBucket, nbBuckets)
+	for i := range buckets {
+		buckets[i].confirmed = make([]txConfirmStatBucketCount, maxConfirms)
+	}
+	return buckets
+}
+
+// applyDecay discounts the existing statistics of the bucket by the provided
+// decay factor.
+func (bucket *txConfirmStatBucket) applyDecay(decay float64) {
+	bucket.feeSum *= decay
+	bucket.confirmCount *= decay
+	for c := 0; c < len(bucket.confirmed); c++ {
+		conf := &bucket.confirmed[c]
+		conf.feeSum *= decay
+		conf.txCount *= decay
+	}
+}
+
+// addMinedTx records a transaction mined with the provided fee rate in the
+// bucket.  The counts are increased for all confirmation ranges starting at the
+// provided one because it took at least that long for the transaction to be
+// mined.  This is used to simplify the bucket selection during estimation, so
+// that only a single confirmation range needs to be checked (instead of
+// iterating to sum all confirmations with <= `minConfs`).
+func (bucket *txConfirmStatBucket) addMinedTx(confirmIdx int32, rate feeRate) {
+	for c := int(confirmIdx); c < len(bucket.confirmed); c++ {
+		conf := &bucket.confirmed[c]
+		conf.feeSum += float64(rate)
+		conf.txCount++
+	}
+	bucket.confirmCount++
+	bucket.feeSum += float64(rate)
+}
+
 // EstimatorConfig stores the configuration parameters for a given fee
 // estimator. It is used to initialize an empty fee estimator.
 type EstimatorConfig struct {
@@ -154,6 +237,12 @@ type Estimator struct {
 	// buckets are the confirmed tx count and fee sum by bucket fee.
 	buckets []txConfirmStatBucket
 
+	// shortBuckets are the confirmed tx count and fee sum by bucket fee
+	// decayed at a faster rate such that they reflect only recent blocks.
+	// They are used by the economical estimation mode and are not stored in
+	// the database since they are quickly rebuilt.
+	shortBuckets []txConfirmStatBucket
+
 	// memPool are the mempool transaction count and fee sum by bucket fee.
 	memPool []txConfirmStatBucket
 
@@ -162,6 +251,7 @@ type Estimator struct {
 
 	maxConfirms int32
 	decay       float64
+	shortDecay  float64
 	bestHeight  int64
 	db          *leveldb.DB
 	lock        sync.RWMutex
@@ -210,9 +300,11 @@ func NewEstimator(cfg *EstimatorConfig) (*Estimator, error) {
 	res := &Estimator{
 		bucketFeeBounds: bucketFees,
 		buckets:         make([]txConfirmStatBucket, nbBuckets),
+		shortBuckets:    newConfirmStatBuckets(nbBuckets, int32(maxConfirms)),
 		memPool:         make([]txConfirmStatBucket, nbBuckets),
 		maxConfirms:     int32(maxConfirms),
 		decay:           decay,
+		shortDecay:      shortDecay,
 		memPoolTxs:      make(map[chainhash.Hash]memPoolTxDesc),
 		bestHeight:      -1,
 	}
@@ -456,6 +548,8 @@ func (stats *Estimator) loadFromDatabase(replaceBuckets bool) error {
 
 	stats.bucketFeeBounds = fileBucketFees
 	stats.buckets = fileBuckets
+	stats.shortBuckets = newConfirmStatBuckets(fileNbBucketFees,
+		fileMaxConfirms)
 	stats.maxConfirms = fileMaxConfirms
 	log.Debug("Loaded fee estimator database")
 
@@ -541,14 +635,8 @@ func (stats *Estimator) updateMovingAverages(newHeight int64) {
 	// decay the existing stats so that, over time, we rely on more up to date
 	// information regarding fees.
 	for b := 0; b < len(stats.buckets); b++ {
-		bucket := &stats.buckets[b]
-		bucket.feeSum *= stats.decay
-		bucket.confirmCount *= stats.decay
-		for c := 0; c < len(bucket.confirmed); c++ {
-			conf := &bucket.confirmed[c]
-			conf.feeSum *= stats.decay
-			conf.txCount *= stats.decay
-		}
+		stats.buckets[b].applyDecay(stats.decay)
+		stats.shortBuckets[b].applyDecay(stats.shortDecay)
 	}
 
 	// For unconfirmed (mempool) transactions, every transaction will now take
@@ -596,20 +684,8 @@ func (stats *Estimator) newMemPoolTx(bucketIdx int32, fees feeRate) {
 func (stats *Estimator) newMinedTx(blocksToConfirm int32, rate feeRate) {
 	bucketIdx := stats.lowerBucket(rate)
 	confirmIdx := stats.confirmRange(blocksToConfirm)
-	bucket := &stats.buckets[bucketIdx]
-
-	// increase the counts for all confirmation ranges starting at the first
-	// confirmIdx because it took at least `blocksToConfirm` for this tx to be
-	// mined. This is used to simplify the bucket selection during estimation,
-	// so that we only need to check a single confirmation range (instead of
-	// iterating to sum all confirmations with <= `minConfs`).
-	for c := int(confirmIdx); c < len(bucket.confirmed); c++ {
-		conf := &bucket.confirmed[c]
-		conf.feeSum += float64(rate)
-		conf.txCount++
-	}
-	bucket.confirmCount++
-	bucket.feeSum += float64(rate)
+	stats.buckets[bucketIdx].addMinedTx(confirmIdx, rate)
+	stats.shortBuckets[bucketIdx].addMinedTx(confirmIdx, rate)
 }
 
 func (stats *Estimator) removeFromMemPool(blocksInMemPool int32, rate feeRate) {
@@ -634,7 +710,7 @@ func (stats *Estimator) removeFromMemPool(blocksInMemPool int32, rate feeRate) {
 	}
 }
 
-// estimateMedianFee estimates the median fee rate for the current recorded
+// estimateMedianFee estimates the median fee rate for the provided recorded
 // statistics such that at least successPct transactions have been mined on all
 // tracked fee rate buckets with fee >= to the median.
 // In other words, this is the median fee of the lowest bucket such that it and
@@ -645,7 +721,7 @@ func (stats *Estimator) removeFromMemPool(blocksInMemPool int32, rate feeRate) {
 // or there are not enough recorded statistics to derive a successful estimate
 // (eg: confirmation tracking has only started or there was a period of very few
 // transactions). In those situations, the appropriate error is returned.
-func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64) (feeRate, error) {
+func (stats *Estimator) estimateMedianFee(buckets []txConfirmStatBucket, targetConfs int32, successPct float64) (feeRate, error) {
 	if targetConfs <= 0 {
 		return 0, errors.New("target confirmation range cannot be <= 0")
 	}
@@ -660,7 +736,7 @@ func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64)
 			ReqConfirms: targetConfs}
 	}
 
-	startIdx := len(stats.buckets) - 1
+	startIdx := len(buckets) - 1
 	confirmRangeIdx := stats.confirmRange(targetConfs)
 
 	var totalTxs, confirmedTxs float64
@@ -669,8 +745,8 @@ func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64)
 	curBucketsEnd := startIdx
 
 	for b := startIdx; b >= 0; b-- {
-		totalTxs += stats.buckets[b].confirmCount
-		confirmedTxs += stats.buckets[b].confirmed[confirmRangeIdx].txCount
+		totalTxs += buckets[b].confirmCount
+		confirmedTxs += buckets[b].confirmed[confirmRangeIdx].txCount
 
 		// Add the mempool (unconfirmed) transactions to the total tx count
 		// since a very large mempool for the given bucket might mean that
@@ -695,17 +771,17 @@ func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64)
 
 	txCount := float64(0)
 	for b := bestBucketsStt; b <= bestBucketsEnd; b++ {
-		txCount += stats.buckets[b].confirmCount
+		txCount += buckets[b].confirmCount
 	}
 	if txCount <= 0 {
 		return 0, ErrNotEnoughTxsForEstimate
 	}
 	txCount /= 2
 	for b := bestBucketsStt; b <= bestBucketsEnd; b++ {
-		if stats.buckets[b].confirmCount < txCount {
-			txCount -= stats.buckets[b].confirmCount
+		if buckets[b].confirmCount < txCount {
+			txCount -= buckets[b].confirmCount
 		} else {
-			median := stats.buckets[b].feeSum / stats.buckets[b].confirmCount
+			median := buckets[b].feeSum / buckets[b].confirmCount
 			return feeRate(median), nil
 		}
 	}
@@ -717,11 +793,34 @@ func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64)
 // suggested fee for a transaction to be confirmed in at most `targetConf`
 // blocks after publishing with a high degree of certainty.
 //
+// It is equivalent to EstimateSmartFee with the conservative mode.
+//
 // This function is safe to be called from multiple goroutines but might block
 // until concurrent modifications to the internal database state are complete.
 func (stats *Estimator) EstimateFee(targetConfs int32) (dcrutil.Amount, error) {
+	return stats.EstimateSmartFee(targetConfs, EstimateConservative)
+}
+
+// EstimateSmartFee calculates the suggested fee for a transaction to be
+// confirmed in at most `targetConf` blocks after publishing using the provided
+// estimation mode.  See EstimateMode for details on the available modes.
+//
+// This function is safe to be called from multiple goroutines but might block
+// until concurrent modifications to the internal database state are complete.
+func (stats *Estimator) EstimateSmartFee(targetConfs int32, mode EstimateMode) (dcrutil.Amount, error) {
+	var rate feeRate
+	var err error
 	stats.lock.RLock()
-	rate, err := stats.estimateMedianFee(targetConfs, 0.95)
+	switch mode {
+	case EstimateConservative:
+		rate, err = stats.estimateMedianFee(stats.buckets, targetConfs,
+			conservativeSuccessPct)
+	case EstimateEconomical:
+		rate, err = stats.estimateMedianFee(stats.shortBuckets, targetConfs,
+			economicalSuccessPct)
+	default:
+		err = fmt.Errorf("unknown estimate mode %v", mode)
+	}
 	stats.lock.RUnlock()
 
 	if err != nil {
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package fees
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/dcrutil/v2"
+)
+
+// TestEstimateSmartFeeModes ensures the economical estimation mode only
+// considers recent blocks and thus produces a lower estimate than the
+// conservative mode when fee rates have recently dropped.
+func TestEstimateSmartFeeModes(t *testing.T) {
+	stats, err := NewEstimator(&EstimatorConfig{
+		MaxConfirms:  DefaultMaxConfirmations,
+		MinBucketFee: 10000,
+		MaxBucketFee: 1000000,
+		FeeRateStep:  DefaultFeeRateStep,
+	})
+	if err != nil {
+		t.Fatalf("NewEstimator: unexpected error: %v", err)
+	}
+
+	// Create a synthetic fee history where low fee transactions historically
+	// took several blocks to confirm while high fee transactions confirmed in
+	// the next block followed by a period of recent blocks where the low fee
+	// transactions also confirmed in the next block.
+	const lowRate, highRate = 50000, 200000
+	height := int64(0)
+	for i := 0; i < 200; i++ {
+		height++
+		stats.updateMovingAverages(height)
+		for j := 0; j < 10; j++ {
+			stats.newMinedTx(6, lowRate)
+			stats.newMinedTx(1, highRate)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		height++
+		stats.updateMovingAverages(height)
+		for j := 0; j < 20; j++ {
+			stats.newMinedTx(1, lowRate)
+		}
+	}
+
+	// Ensure the conservative mode still requires the high fee rate while the
+	// economical mode reflects the recent drop.
+	const targetConfs = 2
+	tests := []struct {
+		name string
+		mode EstimateMode
+		want dcrutil.Amount
+	}{
+		{"conservative", EstimateConservative, highRate},
+		{"economical", EstimateEconomical, lowRate},
+	}
+	for _, test := range tests {
+		got, err := stats.EstimateSmartFee(targetConfs, test.mode)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected estimate -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+
+	// Ensure the default estimate uses the conservative mode.
+	got, err := stats.EstimateFee(targetConfs)
+	if err != nil {
+		t.Fatalf("EstimateFee: unexpected error: %v", err)
+	}
+	if got != highRate {
+		t.Fatalf("EstimateFee: unexpected estimate -- got %v, want %v", got,
+			dcrutil.Amount(highRate))
+	}
+
+	// Ensure unknown modes are rejected.
+	if _, err := stats.EstimateSmartFee(targetConfs, EstimateMode(-1)); err == nil {
+		t.Fatal("EstimateSmartFee: did not fail for unknown mode")
+	}
+}
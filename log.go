@@ -71,6 +71,16 @@ var (
 	srvrLog = backendLog.Logger("SRVR")
 	stkeLog = backendLog.Logger("STKE")
 	txmpLog = backendLog.Logger("TXMP")
+
+	// rpcAuditRotator is the output of the RPC audit log.  It is only
+	// initialized when the audit log is enabled and should be closed on
+	// application shutdown.
+	rpcAuditRotator *rotator.Rotator
+
+	// rpcAuditLog is the logger for the RPC audit log.  It is disabled unless
+	// the audit log is enabled, in which case it writes to a dedicated log
+	// file instead of the main log.
+	rpcAuditLog = slog.Disabled
 )
 
 // Initialize package-global logger variables.
@@ -127,6 +137,27 @@ func initLogRotator(logFile string) {
 	logRotator = r
 }
 
+// initRPCAuditLog initializes the RPC audit logger to write to logFile and
+// create roll files in the same directory.  It must be called after the log
+// directory has been determined.
+func initRPCAuditLog(logFile string) {
+	logDir, _ := filepath.Split(logFile)
+	err := os.MkdirAll(logDir, 0700)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create log directory: %v\n", err)
+		os.Exit(1)
+	}
+	r, err := rotator.New(logFile, 10*1024, false, 3)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create file rotator: %v\n", err)
+		os.Exit(1)
+	}
+
+	rpcAuditRotator = r
+	rpcAuditLog = slog.NewBackend(r).Logger("AUDT")
+	rpcAuditLog.SetLevel(slog.LevelInfo)
+}
+
 // setLogLevel sets the logging level for provided subsystem.  Invalid
 // subsystems are ignored.  Uninitialized subsystems are dynamically created as
 // needed.
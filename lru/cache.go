@@ -93,6 +93,17 @@ func (m *Cache) Delete(item interface{}) {
 	m.mtx.Unlock()
 }
 
+// Len returns the number of items currently in the cache.
+//
+// This function is safe for concurrent access.
+func (m *Cache) Len() int {
+	m.mtx.Lock()
+	numItems := len(m.cache)
+	m.mtx.Unlock()
+
+	return numItems
+}
+
 // Cache returns an initialized and empty LRU cache.  See the documentation for
 // Cache for more details.
 func NewCache(limit uint) Cache {
@@ -41,6 +41,13 @@ testLoop:
 			cache.Add(nonces[j])
 		}
 
+		// Ensure the number of items in the cache is limited.
+		if cache.Len() != test.limit {
+			t.Errorf("Len #%d (%s) unexpected number of items -- got %d, "+
+				"want %d", i, test.name, cache.Len(), test.limit)
+			continue testLoop
+		}
+
 		// Ensure the limited number of most recent entries in the list exist.
 		for j := numNonces - test.limit; j < numNonces; j++ {
 			if !cache.Contains(nonces[j]) {
@@ -65,6 +65,7 @@ be an exhaustive list.
   - Max signature operations per transaction
   - Max orphan transaction size
   - Max number of orphan transactions allowed
+  - Optional custom acceptance filter for experimental policies
 - Additional metadata tracking for each transaction
   - Timestamp when the transaction was added to the pool
   - Most recent block height when the transaction was added to the pool
@@ -52,6 +52,7 @@ const (
 	ErrInsufficientPriority
 	ErrFeeTooHigh
 	ErrOrphan
+	ErrRejectedByFilter
 )
 
 // TxRuleError identifies a rule violation.  It is used to indicate that
@@ -20,4 +20,5 @@ require (
 replace (
 	github.com/decred/dcrd/chaincfg/v2 => ../chaincfg
 	github.com/decred/dcrd/txscript/v2 => ../txscript
+	github.com/decred/dcrd/wire => ../wire
 )
@@ -6,8 +6,10 @@
 package mempool
 
 import (
+	"bytes"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -56,6 +58,11 @@ const (
 	// maxNullDataOutputs is the maximum number of OP_RETURN null data
 	// pushes in a transaction, after which it is considered non-standard.
 	maxNullDataOutputs = 4
+
+	// maxRelativesDepth is the maximum number of generations of unconfirmed
+	// ancestors or descendants of a transaction that are traversed when
+	// collecting them.
+	maxRelativesDepth = 100
 )
 
 // Config is a descriptor containing the memory pool configuration.
@@ -129,6 +136,17 @@ type Config struct {
 	// OnVoteReceived defines the function used to signal receiving a new
 	// vote in the mempool.
 	OnVoteReceived func(voteTx *dcrutil.Tx)
+
+	// AcceptFilter defines an optional function to be called for every
+	// transaction that has passed all other validation and policy checks
+	// immediately prior to it being added to the pool.  Returning a non-nil
+	// error rejects the transaction with the ErrRejectedByFilter error code
+	// and the error is included in its description.  This allows custom
+	// acceptance policies to be implemented without modifying the package.
+	//
+	// The function is invoked while the mempool lock is held, so it must
+	// return quickly and must NOT call back into the mempool.
+	AcceptFilter func(tx *dcrutil.Tx, txType stake.TxType, fee int64) error
 }
 
 // Policy houses the policy (configuration parameters) which is used to
@@ -217,6 +235,29 @@ type VerboseTxDesc struct {
 	Depends []*TxDesc
 }
 
+// orphanTx houses an orphan transaction along with the time it was added to
+// the orphan pool.
+type orphanTx struct {
+	tx    *dcrutil.Tx
+	added time.Time
+}
+
+// OrphanTxDesc is a descriptor containing a transaction in the orphan pool
+// along with the time it was added and the previous outpoints referenced by its
+// inputs that are not yet available.
+type OrphanTxDesc struct {
+	// Tx is the orphan transaction.
+	Tx *dcrutil.Tx
+
+	// Added is the time when the transaction was added to the orphan pool.
+	Added time.Time
+
+	// MissingParents enumerates the previous outpoints referenced by inputs
+	// of the transaction that are neither in the main chain nor in the main
+	// pool.
+	MissingParents []wire.OutPoint
+}
+
 // TxPool is used as a source of transactions that need to be mined into blocks
 // and relayed to other peers.  It is safe for concurrent access from multiple
 // peers.
@@ -227,7 +268,7 @@ type TxPool struct {
 	mtx           sync.RWMutex
 	cfg           Config
 	pool          map[chainhash.Hash]*TxDesc
-	orphans       map[chainhash.Hash]*dcrutil.Tx
+	orphans       map[chainhash.Hash]*orphanTx
 	orphansByPrev map[wire.OutPoint]map[chainhash.Hash]*dcrutil.Tx
 	outpoints     map[wire.OutPoint]*dcrutil.Tx
 
@@ -332,10 +373,11 @@ func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) {
 	txHash := tx.Hash()
 
 	// Nothing to do if passed tx is not an orphan.
-	tx, exists := mp.orphans[*txHash]
+	otx, exists := mp.orphans[*txHash]
 	if !exists {
 		return
 	}
+	tx = otx.tx
 
 	log.Tracef("Removing orphan transaction %v", txHash)
 
@@ -399,8 +441,8 @@ func (mp *TxPool) limitNumOrphans() {
 	// is not important here because an adversary would have to be
 	// able to pull off preimage attacks on the hashing function in
 	// order to target eviction of specific entries anyways.
-	for _, tx := range mp.orphans {
-		mp.removeOrphan(tx, false)
+	for _, otx := range mp.orphans {
+		mp.removeOrphan(otx.tx, false)
 		break
 	}
 }
@@ -418,7 +460,7 @@ func (mp *TxPool) addOrphan(tx *dcrutil.Tx) {
 	// random orphan is evicted to make room if needed.
 	mp.limitNumOrphans()
 
-	mp.orphans[*tx.Hash()] = tx
+	mp.orphans[*tx.Hash()] = &orphanTx{tx: tx, added: time.Now()}
 	for _, txIn := range tx.MsgTx().TxIn {
 		if _, exists := mp.orphansByPrev[txIn.PreviousOutPoint]; !exists {
 			mp.orphansByPrev[txIn.PreviousOutPoint] =
@@ -854,6 +896,36 @@ func (mp *TxPool) FetchTransaction(txHash *chainhash.Hash) (*dcrutil.Tx, error)
 	return nil, fmt.Errorf("transaction is not in the pool")
 }
 
+// TxsByShortID returns the transactions in the pool, including orphans, keyed
+// by their compact block short transaction IDs derived from the provided short
+// ID key.  It is used to reconstruct blocks received as compact blocks.
+//
+// Transactions with colliding short IDs are mapped to nil since it is not
+// possible to determine which of them a short ID refers to.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) TxsByShortID(key *chainhash.Hash) map[uint64]*dcrutil.Tx {
+	mp.mtx.RLock()
+	txns := make(map[uint64]*dcrutil.Tx, len(mp.pool)+len(mp.orphans))
+	addTx := func(tx *dcrutil.Tx) {
+		shortID := wire.CmpctBlockShortID(key, tx.Hash())
+		if _, exists := txns[shortID]; exists {
+			txns[shortID] = nil
+			return
+		}
+		txns[shortID] = tx
+	}
+	for _, desc := range mp.pool {
+		addTx(desc.Tx)
+	}
+	for _, otx := range mp.orphans {
+		addTx(otx.tx)
+	}
+	mp.mtx.RUnlock()
+
+	return txns
+}
+
 // maybeAcceptTransaction is the internal function which implements the public
 // MaybeAcceptTransaction.  See the comment for MaybeAcceptTransaction for
 // more details.
@@ -1307,6 +1379,16 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 		return nil, err
 	}
 
+	// Allow the optional custom acceptance filter to reject the transaction.
+	if mp.cfg.AcceptFilter != nil {
+		if err := mp.cfg.AcceptFilter(tx, txType, txFee); err != nil {
+			str := fmt.Sprintf("transaction %v rejected by the acceptance "+
+				"filter: %v", txHash, err)
+			return nil, txRuleError(wire.RejectNonstandard,
+				ErrRejectedByFilter, str)
+		}
+	}
+
 	// Add to transaction pool.
 	mp.addTransaction(utxoView, tx, txType, bestHeight, txFee)
 
@@ -1574,6 +1656,17 @@ func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, rateLimit, all
 	return nil, err
 }
 
+// SetMinRelayTxFee sets the minimum transaction fee in atoms/kB required for
+// transactions to be accepted into the pool.  It only applies to transactions
+// that are processed after it is changed.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) SetMinRelayTxFee(fee dcrutil.Amount) {
+	mp.mtx.Lock()
+	mp.cfg.Policy.MinRelayTxFee = fee
+	mp.mtx.Unlock()
+}
+
 // Count returns the number of transactions in the main pool.  It does not
 // include the orphan pool.
 //
@@ -1604,6 +1697,56 @@ func (mp *TxPool) TxHashes() []*chainhash.Hash {
 	return hashes
 }
 
+// OrphanTxDescs returns a slice of descriptors for all of the transactions in
+// the orphan pool ordered by the time they were added.  The transactions in the
+// descriptors must be treated as read only.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) OrphanTxDescs() ([]*OrphanTxDesc, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	descs := make([]*OrphanTxDesc, 0, len(mp.orphans))
+	for _, otx := range mp.orphans {
+		// Determine which of the outputs referenced by the inputs are not
+		// available yet.  The stakebase input of votes does not reference
+		// a previous output.
+		utxoView, err := mp.fetchInputUtxos(otx.tx)
+		if err != nil {
+			return nil, err
+		}
+		msgTx := otx.tx.MsgTx()
+		isVote := stake.IsSSGen(msgTx)
+		var missingParents []wire.OutPoint
+		for i, txIn := range msgTx.TxIn {
+			if i == 0 && isVote {
+				continue
+			}
+
+			prevOut := &txIn.PreviousOutPoint
+			entry := utxoView.LookupEntry(&prevOut.Hash)
+			if entry == nil || entry.IsOutputSpent(prevOut.Index) {
+				missingParents = append(missingParents, *prevOut)
+			}
+		}
+
+		descs = append(descs, &OrphanTxDesc{
+			Tx:             otx.tx,
+			Added:          otx.added,
+			MissingParents: missingParents,
+		})
+	}
+
+	sort.Slice(descs, func(i, j int) bool {
+		if descs[i].Added.Equal(descs[j].Added) {
+			return bytes.Compare(descs[i].Tx.Hash()[:],
+				descs[j].Tx.Hash()[:]) < 0
+		}
+		return descs[i].Added.Before(descs[j].Added)
+	})
+	return descs, nil
+}
+
 // TxDescs returns a slice of descriptors for all the transactions in the pool.
 // The descriptors must be treated as read only.
 //
@@ -1621,6 +1764,37 @@ func (mp *TxPool) TxDescs() []*TxDesc {
 	return descs
 }
 
+// verboseTxDesc returns a verbose descriptor for the passed transaction
+// descriptor which includes its current priority and in-pool dependencies.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) verboseTxDesc(desc *TxDesc, bestHeight int64) *VerboseTxDesc {
+	// Calculate the current priority based on inputs to the transaction.
+	// Use zero if one or more of the input transactions can't be found for
+	// some reason.
+	tx := desc.Tx
+	var currentPriority float64
+	utxos, err := mp.fetchInputUtxos(tx)
+	if err == nil {
+		currentPriority = mining.CalcPriority(tx.MsgTx(), utxos,
+			bestHeight+1)
+	}
+
+	// Create the descriptor and add dependencies as needed.
+	vtxd := &VerboseTxDesc{
+		TxDesc:          *desc,
+		CurrentPriority: currentPriority,
+	}
+	for _, txIn := range tx.MsgTx().TxIn {
+		hash := &txIn.PreviousOutPoint.Hash
+		if depDesc, ok := mp.pool[*hash]; ok {
+			vtxd.Depends = append(vtxd.Depends, depDesc)
+		}
+	}
+
+	return vtxd
+}
+
 // VerboseTxDescs returns a slice of verbose descriptors for all the
 // transactions in the pool.  The descriptors must be treated as read only.
 //
@@ -1634,35 +1808,132 @@ func (mp *TxPool) VerboseTxDescs() []*VerboseTxDesc {
 
 	result := make([]*VerboseTxDesc, 0, len(mp.pool))
 	bestHeight := mp.cfg.BestHeight()
-
 	for _, desc := range mp.pool {
-		// Calculate the current priority based on inputs to the transaction.
-		// Use zero if one or more of the input transactions can't be found for
-		// some reason.
-		tx := desc.Tx
-		var currentPriority float64
-		utxos, err := mp.fetchInputUtxos(tx)
-		if err == nil {
-			currentPriority = mining.CalcPriority(tx.MsgTx(), utxos,
-				bestHeight+1)
+		result = append(result, mp.verboseTxDesc(desc, bestHeight))
+	}
+
+	return result
+}
+
+// VerboseTxDesc returns a verbose descriptor for the requested transaction in
+// the main transaction pool.  It does not include orphans.  The descriptor
+// must be treated as read only.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) VerboseTxDesc(txHash *chainhash.Hash) (*VerboseTxDesc, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	desc, exists := mp.pool[*txHash]
+	if !exists {
+		return nil, fmt.Errorf("transaction is not in the pool")
+	}
+
+	return mp.verboseTxDesc(desc, mp.cfg.BestHeight()), nil
+}
+
+// txParents returns the descriptors of the transactions in the main pool that
+// the transaction described by the passed descriptor spends outputs of.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) txParents(desc *TxDesc) []*TxDesc {
+	var parents []*TxDesc
+	for _, txIn := range desc.Tx.MsgTx().TxIn {
+		if parent, ok := mp.pool[txIn.PreviousOutPoint.Hash]; ok {
+			parents = append(parents, parent)
 		}
+	}
+	return parents
+}
 
-		// Create the descriptor and add dependencies as needed.
-		vtxd := &VerboseTxDesc{
-			TxDesc:          *desc,
-			CurrentPriority: currentPriority,
+// txChildren returns the descriptors of the transactions in the main pool that
+// spend outputs of the transaction described by the passed descriptor.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) txChildren(desc *TxDesc) []*TxDesc {
+	tree := wire.TxTreeRegular
+	if desc.Type != stake.TxTypeRegular {
+		tree = wire.TxTreeStake
+	}
+
+	var children []*TxDesc
+	prevOut := wire.OutPoint{Hash: *desc.Tx.Hash(), Tree: tree}
+	for i := range desc.Tx.MsgTx().TxOut {
+		prevOut.Index = uint32(i)
+		redeemer, ok := mp.outpoints[prevOut]
+		if !ok {
+			continue
 		}
-		for _, txIn := range tx.MsgTx().TxIn {
-			hash := &txIn.PreviousOutPoint.Hash
-			if depDesc, ok := mp.pool[*hash]; ok {
-				vtxd.Depends = append(vtxd.Depends, depDesc)
-			}
+		if child, ok := mp.pool[*redeemer.Hash()]; ok {
+			children = append(children, child)
 		}
+	}
+	return children
+}
 
-		result = append(result, vtxd)
+// txRelatives returns verbose descriptors for all transactions in the main pool
+// that are reachable from the passed transaction by repeatedly following the
+// provided relation, up to maxRelativesDepth generations away.  Each relative
+// is only included once and the transaction itself is never included, so
+// cycles are not an issue.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) txRelatives(txHash *chainhash.Hash, related func(*TxDesc) []*TxDesc) ([]*VerboseTxDesc, error) {
+	desc, exists := mp.pool[*txHash]
+	if !exists {
+		return nil, fmt.Errorf("transaction is not in the pool")
 	}
 
-	return result
+	bestHeight := mp.cfg.BestHeight()
+	seen := map[chainhash.Hash]struct{}{*txHash: {}}
+	var relatives []*VerboseTxDesc
+	generation := []*TxDesc{desc}
+	for depth := 0; depth < maxRelativesDepth && len(generation) > 0; depth++ {
+		var next []*TxDesc
+		for _, desc := range generation {
+			for _, relative := range related(desc) {
+				hash := *relative.Tx.Hash()
+				if _, ok := seen[hash]; ok {
+					continue
+				}
+				seen[hash] = struct{}{}
+				relatives = append(relatives,
+					mp.verboseTxDesc(relative, bestHeight))
+				next = append(next, relative)
+			}
+		}
+		generation = next
+	}
+
+	return relatives, nil
+}
+
+// Ancestors returns verbose descriptors for the unconfirmed ancestors of the
+// requested transaction in the main pool.  That is, the transactions in the
+// pool it spends outputs of, the transactions those spend outputs of, and so
+// on, up to a maximum depth.  It does not include orphans.  The descriptors
+// must be treated as read only.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Ancestors(txHash *chainhash.Hash) ([]*VerboseTxDesc, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.txRelatives(txHash, mp.txParents)
+}
+
+// Descendants returns verbose descriptors for the descendants of the requested
+// transaction in the main pool.  That is, the transactions in the pool that
+// spend its outputs, the transactions that spend outputs of those, and so on,
+// up to a maximum depth.  It does not include orphans.  The descriptors must be
+// treated as read only.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Descendants(txHash *chainhash.Hash) ([]*VerboseTxDesc, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.txRelatives(txHash, mp.txChildren)
 }
 
 // MiningDescs returns a slice of mining descriptors for all the transactions
@@ -1697,7 +1968,7 @@ func New(cfg *Config) *TxPool {
 	return &TxPool{
 		cfg:           *cfg,
 		pool:          make(map[chainhash.Hash]*TxDesc),
-		orphans:       make(map[chainhash.Hash]*dcrutil.Tx),
+		orphans:       make(map[chainhash.Hash]*orphanTx),
 		orphansByPrev: make(map[wire.OutPoint]map[chainhash.Hash]*dcrutil.Tx),
 		outpoints:     make(map[wire.OutPoint]*dcrutil.Tx),
 		votes:         make(map[chainhash.Hash][]mining.VoteDesc),
@@ -2124,3 +2124,378 @@ func TestMempoolDoubleSpend(t *testing.T) {
 	testPoolMembership(tc, tx, false, true)
 	testPoolMembership(tc, doubleSpendTx, false, false)
 }
+
+// TestVerboseTxDesc ensures that the verbose descriptor for a single
+// transaction in the pool is returned with its in-pool dependencies and that
+// requesting a transaction which is not in the main pool fails.
+func TestVerboseTxDesc(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Create a chain of three transactions rooted with the first spendable
+	// output provided by the harness and ensure the first two are accepted.
+	chainedTxns, err := harness.CreateTxChain(spendableOuts[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns[:2] {
+		_, err := harness.txPool.ProcessTransaction(tx, false, false, true)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid tx: %v",
+				err)
+		}
+		testPoolMembership(tc, tx, false, true)
+	}
+
+	// Ensure the first transaction has no dependencies and the second one
+	// depends on the first.
+	desc, err := harness.txPool.VerboseTxDesc(chainedTxns[0].Hash())
+	if err != nil {
+		t.Fatalf("VerboseTxDesc: unexpected error: %v", err)
+	}
+	if desc.Tx.Hash() != chainedTxns[0].Hash() {
+		t.Fatalf("VerboseTxDesc: unexpected tx -- got %v, want %v",
+			desc.Tx.Hash(), chainedTxns[0].Hash())
+	}
+	if len(desc.Depends) != 0 {
+		t.Fatalf("VerboseTxDesc: unexpected number of dependencies -- "+
+			"got %d, want 0", len(desc.Depends))
+	}
+	desc, err = harness.txPool.VerboseTxDesc(chainedTxns[1].Hash())
+	if err != nil {
+		t.Fatalf("VerboseTxDesc: unexpected error: %v", err)
+	}
+	if len(desc.Depends) != 1 ||
+		desc.Depends[0].Tx.Hash() != chainedTxns[0].Hash() {
+
+		t.Fatalf("VerboseTxDesc: unexpected dependencies %v",
+			desc.Depends)
+	}
+
+	// Ensure a transaction that is not in the pool is not found.
+	_, err = harness.txPool.VerboseTxDesc(chainedTxns[2].Hash())
+	if err == nil {
+		t.Fatal("VerboseTxDesc: did not fail for tx not in the pool")
+	}
+}
+
+// TestTxRelatives ensures the unconfirmed ancestors and descendants of
+// transactions in the pool are reported as expected.
+func TestTxRelatives(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Create a chain of four transactions rooted with the first spendable
+	// output provided by the harness and ensure the first three are
+	// accepted.
+	chainedTxns, err := harness.CreateTxChain(spendableOuts[0], 4)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns[:3] {
+		_, err := harness.txPool.ProcessTransaction(tx, false, false, true)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid tx: %v",
+				err)
+		}
+		testPoolMembership(tc, tx, false, true)
+	}
+
+	hashes := func(descs []*VerboseTxDesc) map[chainhash.Hash]struct{} {
+		result := make(map[chainhash.Hash]struct{}, len(descs))
+		for _, desc := range descs {
+			result[*desc.Tx.Hash()] = struct{}{}
+		}
+		return result
+	}
+	txHashes := func(txns ...*dcrutil.Tx) map[chainhash.Hash]struct{} {
+		result := make(map[chainhash.Hash]struct{}, len(txns))
+		for _, tx := range txns {
+			result[*tx.Hash()] = struct{}{}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name            string
+		tx              *dcrutil.Tx
+		wantAncestors   map[chainhash.Hash]struct{}
+		wantDescendants map[chainhash.Hash]struct{}
+	}{{
+		name:            "first in chain",
+		tx:              chainedTxns[0],
+		wantAncestors:   txHashes(),
+		wantDescendants: txHashes(chainedTxns[1], chainedTxns[2]),
+	}, {
+		name:            "middle of chain",
+		tx:              chainedTxns[1],
+		wantAncestors:   txHashes(chainedTxns[0]),
+		wantDescendants: txHashes(chainedTxns[2]),
+	}, {
+		name:            "last in chain",
+		tx:              chainedTxns[2],
+		wantAncestors:   txHashes(chainedTxns[0], chainedTxns[1]),
+		wantDescendants: txHashes(),
+	}}
+
+	for _, test := range tests {
+		ancestors, err := harness.txPool.Ancestors(test.tx.Hash())
+		if err != nil {
+			t.Fatalf("%q: unexpected error from Ancestors: %v", test.name,
+				err)
+		}
+		if got := hashes(ancestors); !reflect.DeepEqual(got,
+			test.wantAncestors) {
+
+			t.Fatalf("%q: unexpected ancestors -- got %v, want %v",
+				test.name, got, test.wantAncestors)
+		}
+
+		descendants, err := harness.txPool.Descendants(test.tx.Hash())
+		if err != nil {
+			t.Fatalf("%q: unexpected error from Descendants: %v",
+				test.name, err)
+		}
+		if got := hashes(descendants); !reflect.DeepEqual(got,
+			test.wantDescendants) {
+
+			t.Fatalf("%q: unexpected descendants -- got %v, want %v",
+				test.name, got, test.wantDescendants)
+		}
+	}
+
+	// Ensure a transaction that is not in the pool is not found.
+	if _, err := harness.txPool.Ancestors(chainedTxns[3].Hash()); err == nil {
+		t.Fatal("Ancestors: did not fail for tx not in the pool")
+	}
+	if _, err := harness.txPool.Descendants(chainedTxns[3].Hash()); err == nil {
+		t.Fatal("Descendants: did not fail for tx not in the pool")
+	}
+}
+
+// TestTxsByShortID ensures the transactions in the pool and orphan pool are
+// returned keyed by their compact block short transaction IDs.
+func TestTxsByShortID(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Create a chain of three transactions rooted with the first spendable
+	// output provided by the harness and ensure the first one is accepted
+	// to the pool and the third one is accepted as an orphan.
+	chainedTxns, err := harness.CreateTxChain(spendableOuts[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range []*dcrutil.Tx{chainedTxns[0], chainedTxns[2]} {
+		_, err := harness.txPool.ProcessTransaction(tx, true, false, true)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid tx: %v",
+				err)
+		}
+	}
+	testPoolMembership(tc, chainedTxns[0], false, true)
+	testPoolMembership(tc, chainedTxns[2], true, false)
+
+	// Ensure both transactions are found by their short ids and the
+	// transaction that was never added is not.
+	key := chainhash.Hash{0x01}
+	txns := harness.txPool.TxsByShortID(&key)
+	if len(txns) != 2 {
+		t.Fatalf("TxsByShortID: unexpected number of txns -- got %d, "+
+			"want 2", len(txns))
+	}
+	for i, tx := range chainedTxns {
+		shortID := wire.CmpctBlockShortID(&key, tx.Hash())
+		found := txns[shortID]
+		if i == 1 {
+			if found != nil {
+				t.Fatalf("TxsByShortID: found tx %v not in the pool",
+					tx.Hash())
+			}
+			continue
+		}
+		if found == nil || *found.Hash() != *tx.Hash() {
+			t.Fatalf("TxsByShortID: did not find tx %v", tx.Hash())
+		}
+	}
+}
+
+// TestAcceptFilter ensures the optional acceptance filter is able to reject
+// transactions that otherwise pass all checks and that transactions it allows
+// are accepted to the pool.
+func TestAcceptFilter(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Configure a filter that rejects transactions with more than a single
+	// output.
+	harness.txPool.cfg.AcceptFilter = func(tx *dcrutil.Tx, txType stake.TxType, fee int64) error {
+		if len(tx.MsgTx().TxOut) > 1 {
+			return fmt.Errorf("too many outputs")
+		}
+		return nil
+	}
+
+	// Ensure a transaction rejected by the filter is not added to the pool
+	// and fails with the expected error code.
+	rejectedTx, err := harness.CreateSignedTx(spendableOuts, 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(rejectedTx, false, false, true)
+	if !IsErrorCode(err, ErrRejectedByFilter) {
+		t.Fatalf("ProcessTransaction: did not get expected "+
+			"ErrRejectedByFilter -- got %v", err)
+	}
+	testPoolMembership(tc, rejectedTx, false, false)
+
+	// Ensure a transaction spending the same output that is allowed by the
+	// filter is accepted to the pool.
+	tx, err := harness.CreateSignedTx(spendableOuts, 1)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(tx, false, false, true)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+	}
+	testPoolMembership(tc, tx, false, true)
+}
+
+// TestOrphanTxDescs ensures the orphan pool descriptors report the orphans in
+// the order they were added along with the previous outpoints they are missing
+// and that orphans which are no longer orphans are no longer reported.
+func TestOrphanTxDescs(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Create a chain of transactions rooted with the first spendable output
+	// provided by the harness and add all but the first one as orphans.
+	chainedTxns, err := harness.CreateTxChain(spendableOuts[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns[1:] {
+		_, err := harness.txPool.ProcessTransaction(tx, true, false, true)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid "+
+				"orphan %v", err)
+		}
+		testPoolMembership(tc, tx, true, false)
+	}
+
+	// Ensure both orphans are reported in the order they were added and
+	// that each of them is missing the output of its parent.
+	descs, err := harness.txPool.OrphanTxDescs()
+	if err != nil {
+		t.Fatalf("OrphanTxDescs: unexpected error: %v", err)
+	}
+	if len(descs) != 2 {
+		t.Fatalf("OrphanTxDescs: unexpected number of orphans -- got %d, "+
+			"want 2", len(descs))
+	}
+	if descs[1].Added.Before(descs[0].Added) {
+		t.Fatal("OrphanTxDescs: orphans are not ordered by added time")
+	}
+	parents := map[chainhash.Hash]chainhash.Hash{
+		*chainedTxns[1].Hash(): *chainedTxns[0].Hash(),
+		*chainedTxns[2].Hash(): *chainedTxns[1].Hash(),
+	}
+	for _, desc := range descs {
+		txHash := desc.Tx.Hash()
+		parent, ok := parents[*txHash]
+		if !ok {
+			t.Fatalf("OrphanTxDescs: unexpected orphan %v", txHash)
+		}
+		if desc.Added.IsZero() {
+			t.Fatalf("OrphanTxDescs: orphan %v has no added time", txHash)
+		}
+		if len(desc.MissingParents) != 1 {
+			t.Fatalf("OrphanTxDescs: unexpected number of missing parents "+
+				"for %v -- got %d, want 1", txHash,
+				len(desc.MissingParents))
+		}
+		if missing := desc.MissingParents[0].Hash; missing != parent {
+			t.Fatalf("OrphanTxDescs: unexpected missing parent for %v -- "+
+				"got %v, want %v", txHash, missing, parent)
+		}
+	}
+
+	// Ensure there are no orphans reported once the missing parent is
+	// accepted since it causes the orphans to be accepted as well.
+	_, err = harness.txPool.ProcessTransaction(chainedTxns[0], false, false,
+		true)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	descs, err = harness.txPool.OrphanTxDescs()
+	if err != nil {
+		t.Fatalf("OrphanTxDescs: unexpected error: %v", err)
+	}
+	if len(descs) != 0 {
+		t.Fatalf("OrphanTxDescs: unexpected number of orphans -- got %d, "+
+			"want 0", len(descs))
+	}
+}
+
+// TestSetMinRelayTxFee ensures changing the minimum relay fee of the pool
+// applies to transactions processed afterwards.
+func TestSetMinRelayTxFee(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Create a transaction that pays a fee which is considered too high for
+	// the minimum relay fee the pool was created with.
+	const fee = 1e7
+	tx, err := harness.CreateSignedTx(spendableOuts, 1, func(tx *wire.MsgTx) {
+		tx.TxOut[0].Value -= fee
+	})
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(tx, false, false, false)
+	if !IsErrorCode(err, ErrFeeTooHigh) {
+		t.Fatalf("ProcessTransaction: did not get expected ErrFeeTooHigh "+
+			"-- got %v", err)
+	}
+	testPoolMembership(tc, tx, false, false)
+
+	// Ensure the same transaction is accepted once the minimum relay fee is
+	// increased such that the fee is no longer considered too high.
+	harness.txPool.SetMinRelayTxFee(1e5)
+	_, err = harness.txPool.ProcessTransaction(tx, false, false, false)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+	}
+	testPoolMembership(tc, tx, false, true)
+}
@@ -6,6 +6,7 @@
 package main
 
 import (
+	"bytes"
 	"container/heap"
 	"context"
 	"encoding/binary"
@@ -69,6 +70,20 @@ const (
 	// merkleRootPairSize is the size in bytes of the merkle root + stake root
 	// of a block.
 	merkleRootPairSize = 64
+
+	// coinbaseExtraNonceOutput is the index of the output of a standard
+	// coinbase transaction that houses the extra nonce.
+	coinbaseExtraNonceOutput = 1
+
+	// coinbaseExtraNonceOffset is the offset of the extra nonce within the
+	// public key script of the standard coinbase extra nonce output.  It
+	// skips the OP_RETURN and data push opcodes as well as the serialized
+	// block height that precedes the extra nonce.
+	coinbaseExtraNonceOffset = 6
+
+	// coinbaseExtraNonceSize is the size in bytes of the extra nonce in a
+	// standard coinbase transaction.
+	coinbaseExtraNonceSize = 8
 )
 
 // txPrioItem houses a transaction along with extra information that allows the
@@ -239,6 +254,31 @@ func txPQByStakeAndFeeAndThenPriority(pq *txPriorityQueue, i, j int) bool {
 	return pq.items[i].priority > pq.items[j].priority
 }
 
+// txPQByStakeFeeAndHash sorts a txPriorityQueue by stake priority, followed by
+// fees per kilobyte, and then transaction hash.  Since transaction hashes are
+// unique, this results in a total ordering that does not depend on the order
+// the transactions were added to the queue.
+func txPQByStakeFeeAndHash(pq *txPriorityQueue, i, j int) bool {
+	// Sort by stake priority, continue if they're the same stake priority.
+	cmp := compareStakePriority(pq.items[i], pq.items[j])
+	if cmp == 1 {
+		return true
+	}
+	if cmp == -1 {
+		return false
+	}
+
+	// Using > here so that pop gives the highest fee item as opposed to the
+	// lowest.
+	if pq.items[i].feePerKB != pq.items[j].feePerKB {
+		return pq.items[i].feePerKB > pq.items[j].feePerKB
+	}
+
+	// Fall back to the lowest transaction hash when the fees are equal.
+	iHash, jHash := pq.items[i].tx.Hash(), pq.items[j].tx.Hash()
+	return bytes.Compare(iHash[:], jHash[:]) < 0
+}
+
 // newTxPriorityQueue returns a new transaction priority queue that reserves the
 // passed amount of space for the elements.  The new priority queue uses the
 // less than function lessFunc to sort the items in the min heap. The priority
@@ -457,14 +497,15 @@ func standardCoinbaseOpReturn(height uint32, extraNonce uint64) ([]byte, error)
 // not have the relevant output or the script is not large enough to perform the
 // extraction.
 func extractCoinbaseTxExtraNonce(coinbaseTx *wire.MsgTx) uint64 {
-	if len(coinbaseTx.TxOut) < 2 {
+	if len(coinbaseTx.TxOut) <= coinbaseExtraNonceOutput {
 		return 0
 	}
-	script := coinbaseTx.TxOut[1].PkScript
-	if len(script) < 14 {
+	script := coinbaseTx.TxOut[coinbaseExtraNonceOutput].PkScript
+	const extraNonceEnd = coinbaseExtraNonceOffset + coinbaseExtraNonceSize
+	if len(script) < extraNonceEnd {
 		return 0
 	}
-	return binary.LittleEndian.Uint64(script[6:14])
+	return binary.LittleEndian.Uint64(script[coinbaseExtraNonceOffset:extraNonceEnd])
 }
 
 // extractCoinbaseExtraNonce extracts the extra nonce from a block template's
@@ -835,6 +876,10 @@ func handleTooFewVoters(subsidyCache *standalone.SubsidyCache, nextHeight int64,
 // See the NewBlockTemplate method for a detailed description of how the block
 // template is generated.
 type BlkTmplGenerator struct {
+	// txMinFreeFeeMtx protects the TxMinFreeFee field of the policy since
+	// it may be changed while the generator is in use.
+	txMinFreeFeeMtx sync.Mutex
+
 	policy       *mining.Policy
 	txSource     mining.TxSource
 	sigCache     *txscript.SigCache
@@ -865,6 +910,27 @@ func newBlkTmplGenerator(policy *mining.Policy, txSource mining.TxSource,
 	}
 }
 
+// SetTxMinFreeFee sets the minimum fee in atoms/kB required for a transaction to
+// be treated as free when generating block templates.  It only applies to
+// templates that are generated after it is changed.
+//
+// This function is safe for concurrent access.
+func (g *BlkTmplGenerator) SetTxMinFreeFee(fee dcrutil.Amount) {
+	g.txMinFreeFeeMtx.Lock()
+	g.policy.TxMinFreeFee = fee
+	g.txMinFreeFeeMtx.Unlock()
+}
+
+// txMinFreeFee returns the minimum fee in atoms/kB required for a transaction
+// to be treated as free when generating block templates.
+//
+// This function is safe for concurrent access.
+func (g *BlkTmplGenerator) txMinFreeFee() dcrutil.Amount {
+	g.txMinFreeFeeMtx.Lock()
+	defer g.txMinFreeFeeMtx.Unlock()
+	return g.policy.TxMinFreeFee
+}
+
 // NewBlockTemplate returns a new block template that is ready to be solved
 // using the transactions from the passed transaction source pool and a coinbase
 // that either pays to the passed address if it is not nil, or a coinbase that
@@ -896,6 +962,11 @@ func newBlkTmplGenerator(policy *mining.Policy, txSource mining.TxSource,
 // the priority queue is updated to prioritize by fees per kilobyte (then
 // priority).
 //
+// When the DeterministicTxOrder policy setting is enabled, the high-priority
+// area is not used and the priority queue instead prioritizes by fees per
+// kilobyte and then transaction hash so that the selected transactions and
+// their order only depend on the contents of the source pool.
+//
 // When the fees per kilobyte drop below the TxMinFreeFee policy setting, the
 // transaction will be skipped unless the BlockMinSize policy setting is
 // nonzero, in which case the block will be filled with the low-fee/free
@@ -1040,9 +1111,14 @@ func (g *BlkTmplGenerator) NewBlockTemplate(payToAddress dcrutil.Address) (*Bloc
 	// choose the initial sort order for the priority queue based on whether
 	// or not there is an area allocated for high-priority transactions.
 	sourceTxns := g.txSource.MiningDescs()
-	sortedByFee := g.policy.BlockPrioritySize == 0
+	txMinFreeFee := g.txMinFreeFee()
+	sortedByFee := g.policy.BlockPrioritySize == 0 ||
+		g.policy.DeterministicTxOrder
 	lessFunc := txPQByStakeAndFeeAndThenPriority
-	if sortedByFee {
+	switch {
+	case g.policy.DeterministicTxOrder:
+		lessFunc = txPQByStakeFeeAndHash
+	case sortedByFee:
 		lessFunc = txPQByStakeAndFee
 	}
 	priorityQueue := newTxPriorityQueue(len(sourceTxns), lessFunc)
@@ -1323,14 +1399,14 @@ mempoolLoop:
 		// Skip free transactions once the block is larger than the
 		// minimum block size, except for stake transactions.
 		if sortedByFee &&
-			(prioItem.feePerKB < float64(g.policy.TxMinFreeFee)) &&
+			(prioItem.feePerKB < float64(txMinFreeFee)) &&
 			(tx.Tree() != wire.TxTreeStake) &&
 			(blockPlusTxSize >= g.policy.BlockMinSize) {
 
 			minrLog.Tracef("Skipping tx %s with feePerKB %.2f "+
 				"< TxMinFreeFee %d and block size %d >= "+
 				"minBlockSize %d", tx.Hash(), prioItem.feePerKB,
-				g.policy.TxMinFreeFee, blockPlusTxSize,
+				txMinFreeFee, blockPlusTxSize,
 				g.policy.BlockMinSize)
 			logSkippedDeps(tx, deps)
 			continue
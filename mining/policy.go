@@ -38,6 +38,13 @@ type Policy struct {
 	// required for a transaction to be treated as free for mining purposes
 	// (block template generation).
 	TxMinFreeFee dcrutil.Amount
+
+	// DeterministicTxOrder specifies that transactions are selected strictly
+	// by stake priority, then fee per kilobyte, and then transaction hash
+	// when generating a block template.  The high-priority area is not used
+	// in this mode, so nodes with identical memory pools produce identical
+	// templates.
+	DeterministicTxOrder bool
 }
 
 // minInt is a helper function to return the minimum of two ints.  This avoids
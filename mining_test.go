@@ -6,11 +6,15 @@
 package main
 
 import (
+	"bytes"
 	"container/heap"
 	"math/rand"
 	"testing"
 
 	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrd/mining/v2"
+	"github.com/decred/dcrd/wire"
 )
 
 // TestStakeTxFeePrioHeap tests the priority heaps including the stake types for
@@ -126,3 +130,90 @@ func TestStakeTxFeePrioHeap(t *testing.T) {
 		}
 	}
 }
+
+// TestTxPQByStakeFeeAndHash ensures the deterministic priority queue ordering
+// sorts by stake priority, then fee per KB, and then transaction hash and that
+// the resulting order does not depend on the order the items were added.
+func TestTxPQByStakeFeeAndHash(t *testing.T) {
+	numElements := 500
+	testItems := make([]*txPrioItem, 0, numElements)
+	for i := 0; i < numElements; i++ {
+		// Limit the fees to a small set of values to ensure there are many
+		// items with equal fees that must be ordered by their hash.
+		tx := dcrutil.NewTx(&wire.MsgTx{LockTime: uint32(i)})
+		testItems = append(testItems, &txPrioItem{
+			tx:       tx,
+			txType:   stake.TxType(rand.Intn(4)),
+			feePerKB: float64(rand.Intn(5)),
+			priority: rand.Float64() * 100,
+		})
+	}
+
+	// popAll adds the provided items to a new priority queue in the given
+	// order and returns them in the order they are removed.
+	popAll := func(items []*txPrioItem) []*txPrioItem {
+		pq := newTxPriorityQueue(len(items), txPQByStakeFeeAndHash)
+		for _, item := range items {
+			heap.Push(pq, item)
+		}
+		popped := make([]*txPrioItem, 0, len(items))
+		for pq.Len() > 0 {
+			popped = append(popped, heap.Pop(pq).(*txPrioItem))
+		}
+		return popped
+	}
+
+	popped := popAll(testItems)
+	for i := 1; i < len(popped); i++ {
+		prev, cur := popped[i-1], popped[i]
+		cmp := compareStakePriority(prev, cur)
+		if cmp == 1 {
+			continue
+		}
+		if cmp == -1 {
+			t.Fatalf("bad pop %d: stake priority of %v is higher than "+
+				"previous %v", i, cur.txType, prev.txType)
+		}
+		if cur.feePerKB > prev.feePerKB {
+			t.Fatalf("bad pop %d: fee per KB %v is higher than previous %v",
+				i, cur.feePerKB, prev.feePerKB)
+		}
+		if cur.feePerKB == prev.feePerKB &&
+			bytes.Compare(cur.tx.Hash()[:], prev.tx.Hash()[:]) < 0 {
+
+			t.Fatalf("bad pop %d: hash %v sorts before previous %v", i,
+				cur.tx.Hash(), prev.tx.Hash())
+		}
+	}
+
+	// Ensure adding the same items in a different order results in the
+	// same ordering.
+	shuffled := make([]*txPrioItem, len(testItems))
+	copy(shuffled, testItems)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	reordered := popAll(shuffled)
+	for i := range popped {
+		if popped[i] != reordered[i] {
+			t.Fatalf("mismatched order at index %d: got %v, want %v", i,
+				reordered[i].tx.Hash(), popped[i].tx.Hash())
+		}
+	}
+}
+
+// TestSetTxMinFreeFee ensures changing the minimum fee for free transactions of
+// the block template generator updates the policy used for new templates.
+func TestSetTxMinFreeFee(t *testing.T) {
+	policy := mining.Policy{TxMinFreeFee: 1e4}
+	g := newBlkTmplGenerator(&policy, nil, nil, nil, nil, nil, nil, nil)
+	if fee := g.txMinFreeFee(); fee != 1e4 {
+		t.Fatalf("unexpected initial fee -- got %v, want %v", fee,
+			dcrutil.Amount(1e4))
+	}
+	g.SetTxMinFreeFee(2e4)
+	if fee := g.txMinFreeFee(); fee != 2e4 {
+		t.Fatalf("unexpected fee after update -- got %v, want %v", fee,
+			dcrutil.Amount(2e4))
+	}
+}
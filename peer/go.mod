@@ -12,3 +12,5 @@ require (
 	github.com/decred/go-socks v1.0.0
 	github.com/decred/slog v1.0.0
 )
+
+replace github.com/decred/dcrd/wire => ../wire
@@ -26,7 +26,7 @@ import (
 
 const (
 	// MaxProtocolVersion is the max protocol version the peer supports.
-	MaxProtocolVersion = wire.NodeCFVersion
+	MaxProtocolVersion = wire.CmpctBlockVersion
 
 	// outputBufferSize is the number of elements the output channels use.
 	outputBufferSize = 5000
@@ -84,6 +84,40 @@ var (
 	allowSelfConns bool
 )
 
+// BlockAnnounceMode identifies the method used to announce new blocks to a
+// peer.
+type BlockAnnounceMode uint8
+
+const (
+	// BlockAnnounceInv indicates new blocks are announced via inventory
+	// vectors.  All peers support this mode.
+	BlockAnnounceInv BlockAnnounceMode = iota
+
+	// BlockAnnounceHeaders indicates new blocks are announced via headers
+	// messages.  Peers request this mode with a sendheaders message.
+	BlockAnnounceHeaders
+
+	// BlockAnnounceCmpct indicates new blocks are announced via cmpctblock
+	// messages.  Peers request this mode with a sendcmpct message.
+	BlockAnnounceCmpct
+)
+
+// blockAnnounceModeStrings is a map of block announcement modes back to their
+// human-readable names.
+var blockAnnounceModeStrings = map[BlockAnnounceMode]string{
+	BlockAnnounceInv:     "inv",
+	BlockAnnounceHeaders: "headers",
+	BlockAnnounceCmpct:   "compact",
+}
+
+// String returns the BlockAnnounceMode in human-readable form.
+func (m BlockAnnounceMode) String() string {
+	if s, ok := blockAnnounceModeStrings[m]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown BlockAnnounceMode (%d)", uint8(m))
+}
+
 // MessageListeners defines callback function pointers to invoke with message
 // listeners for a peer. Any listener which is not set to a concrete callback
 // during peer initialization is ignored. Execution of multiple message
@@ -182,6 +216,20 @@ type MessageListeners struct {
 	// message.
 	OnSendHeaders func(p *Peer, msg *wire.MsgSendHeaders)
 
+	// OnSendCmpct is invoked when a peer receives a sendcmpct wire message.
+	OnSendCmpct func(p *Peer, msg *wire.MsgSendCmpct)
+
+	// OnCmpctBlock is invoked when a peer receives a cmpctblock wire
+	// message.
+	OnCmpctBlock func(p *Peer, msg *wire.MsgCmpctBlock)
+
+	// OnGetBlockTxns is invoked when a peer receives a getblocktxns wire
+	// message.
+	OnGetBlockTxns func(p *Peer, msg *wire.MsgGetBlockTxns)
+
+	// OnBlockTxns is invoked when a peer receives a blocktxns wire message.
+	OnBlockTxns func(p *Peer, msg *wire.MsgBlockTxns)
+
 	// OnRead is invoked when a peer receives a wire message.  It consists
 	// of the number of bytes read, the message, and whether or not an error
 	// in the read occurred.  Typically, callers will opt to use the
@@ -250,6 +298,17 @@ type Config struct {
 	// not send inv messages for transactions.
 	DisableRelayTx bool
 
+	// PingInterval specifies the interval of time to wait in between
+	// sending ping messages.  This field can be omitted in which case a
+	// default of 2 minutes will be used.
+	PingInterval time.Duration
+
+	// MaxMissedPongs specifies the number of consecutive pings the remote
+	// peer may fail to answer with a pong before it is disconnected.  This
+	// field can be omitted in which case unanswered pings never result in a
+	// disconnect.
+	MaxMissedPongs uint32
+
 	// Listeners houses callback functions to be invoked on receiving peer
 	// messages.
 	Listeners MessageListeners
@@ -275,6 +334,14 @@ func newNetAddress(addr net.Addr, services wire.ServiceFlag) (*wire.NetAddress,
 		return na, nil
 	}
 
+	// addr will be a net.UnixAddr for peers connected over a unix domain
+	// socket.  Such peers are necessarily on the local host, so they are
+	// treated as the IPv4 loopback address without a port.
+	if _, ok := addr.(*net.UnixAddr); ok {
+		na := wire.NewNetAddressIPPort(net.IPv4(127, 0, 0, 1), 0, services)
+		return na, nil
+	}
+
 	// addr will be a socks.ProxiedAddr when using a proxy.
 	if proxiedAddr, ok := addr.(*socks.ProxiedAddr); ok {
 		ip := net.ParseIP(proxiedAddr.Host)
@@ -421,6 +488,7 @@ type Peer struct {
 	advertisedProtoVer   uint32 // protocol version advertised by remote
 	protocolVersion      uint32 // negotiated protocol version
 	sendHeadersPreferred bool   // peer sent a sendheaders message
+	sendCmpctPreferred   bool   // peer sent a sendcmpct message
 	versionSent          bool
 	verAckReceived       bool
 
@@ -443,6 +511,7 @@ type Peer struct {
 	lastPingNonce      uint64    // Set to nonce if we have a pending ping.
 	lastPingTime       time.Time // Time we sent last ping.
 	lastPingMicros     int64     // Time for last ping to return.
+	missedPongs        uint32    // Consecutive pings without a pong.
 
 	stallControl  chan stallControlMsg
 	outputQueue   chan outMsg
@@ -498,6 +567,14 @@ func (p *Peer) AddKnownInventory(invVect *wire.InvVect) {
 	p.knownInventory.Add(invVect)
 }
 
+// IsKnownInventory returns whether or not the passed inventory is already known
+// to the peer.
+//
+// This function is safe for concurrent access.
+func (p *Peer) IsKnownInventory(invVect *wire.InvVect) bool {
+	return p.knownInventory.Contains(invVect)
+}
+
 // StatsSnapshot returns a snapshot of the current peer flags and statistics.
 //
 // This function is safe for concurrent access.
@@ -774,6 +851,36 @@ func (p *Peer) WantsHeaders() bool {
 	return sendHeadersPreferred
 }
 
+// WantsCmpctBlocks returns if the peer wants new blocks to be announced via
+// cmpctblock messages instead of header messages or inventory vectors.
+//
+// This function is safe for concurrent access.
+func (p *Peer) WantsCmpctBlocks() bool {
+	p.flagsMtx.Lock()
+	sendCmpctPreferred := p.sendCmpctPreferred
+	p.flagsMtx.Unlock()
+
+	return sendCmpctPreferred
+}
+
+// BlockAnnounceMode returns the method the peer prefers new blocks to be
+// announced with.  Compact blocks take precedence over headers when the peer
+// has requested both.
+//
+// This function is safe for concurrent access.
+func (p *Peer) BlockAnnounceMode() BlockAnnounceMode {
+	p.flagsMtx.Lock()
+	defer p.flagsMtx.Unlock()
+
+	switch {
+	case p.sendCmpctPreferred:
+		return BlockAnnounceCmpct
+	case p.sendHeadersPreferred:
+		return BlockAnnounceHeaders
+	}
+	return BlockAnnounceInv
+}
+
 // PushAddrMsg sends an addr message to the connected peer using the provided
 // addresses.  This function is useful over manually sending the message via
 // QueueMessage since it automatically limits the addresses to the maximum
@@ -953,10 +1060,25 @@ func (p *Peer) handlePongMsg(msg *wire.MsgPong) {
 		p.lastPingMicros = time.Since(p.lastPingTime).Nanoseconds()
 		p.lastPingMicros /= 1000 // convert to usec.
 		p.lastPingNonce = 0
+		p.missedPongs = 0
 	}
 	p.statsMtx.Unlock()
 }
 
+// missedPong records that the previous ping, if any, has not been answered by
+// the time the next ping is due and reports whether the remote peer has now
+// exceeded the configured maximum number of missed pongs.
+func (p *Peer) missedPong() bool {
+	p.statsMtx.Lock()
+	defer p.statsMtx.Unlock()
+
+	if p.lastPingNonce == 0 {
+		return false
+	}
+	p.missedPongs++
+	return p.cfg.MaxMissedPongs != 0 && p.missedPongs >= p.cfg.MaxMissedPongs
+}
+
 // readMessage reads the next wire message from the peer with logging.
 func (p *Peer) readMessage() (wire.Message, []byte, error) {
 	n, msg, buf, err := wire.ReadMessageN(p.conn, p.ProtocolVersion(),
@@ -1091,6 +1213,10 @@ func (p *Peer) maybeAddDeadline(pendingResponses map[string]time.Time, msgCmd st
 
 	case wire.CmdGetMiningState:
 		pendingResponses[wire.CmdMiningState] = deadline
+
+	case wire.CmdGetBlockTxns:
+		// Expects a blocktxns message.
+		pendingResponses[wire.CmdBlockTxns] = deadline
 	}
 }
 
@@ -1436,6 +1562,30 @@ out:
 				p.cfg.Listeners.OnSendHeaders(p, msg)
 			}
 
+		case *wire.MsgSendCmpct:
+			p.flagsMtx.Lock()
+			p.sendCmpctPreferred = true
+			p.flagsMtx.Unlock()
+
+			if p.cfg.Listeners.OnSendCmpct != nil {
+				p.cfg.Listeners.OnSendCmpct(p, msg)
+			}
+
+		case *wire.MsgCmpctBlock:
+			if p.cfg.Listeners.OnCmpctBlock != nil {
+				p.cfg.Listeners.OnCmpctBlock(p, msg)
+			}
+
+		case *wire.MsgGetBlockTxns:
+			if p.cfg.Listeners.OnGetBlockTxns != nil {
+				p.cfg.Listeners.OnGetBlockTxns(p, msg)
+			}
+
+		case *wire.MsgBlockTxns:
+			if p.cfg.Listeners.OnBlockTxns != nil {
+				p.cfg.Listeners.OnBlockTxns(p, msg)
+			}
+
 		default:
 			log.Debugf("Received unhandled message of type %v "+
 				"from %v", rmsg.Command(), p)
@@ -1606,7 +1756,7 @@ func (p *Peer) shouldLogWriteError(err error) bool {
 // allowing the sender to continue running asynchronously.
 func (p *Peer) outHandler() {
 	// pingTicker is used to periodically send pings to the remote peer.
-	pingTicker := time.NewTicker(pingInterval)
+	pingTicker := time.NewTicker(p.cfg.PingInterval)
 	defer pingTicker.Stop()
 
 out:
@@ -1647,6 +1797,16 @@ out:
 			p.sendDoneQueue <- struct{}{}
 
 		case <-pingTicker.C:
+			// Disconnect the peer when it has failed to answer the
+			// configured number of consecutive pings.
+			if p.missedPong() {
+				log.Warnf("Peer %s failed to respond to %d "+
+					"consecutive pings -- disconnecting", p,
+					p.cfg.MaxMissedPongs)
+				p.Disconnect()
+				continue
+			}
+
 			nonce, err := wire.RandomUint64()
 			if err != nil {
 				log.Errorf("Not sending ping to %s: %v", p, err)
@@ -2018,6 +2178,13 @@ func (p *Peer) AssociateConnection(conn net.Conn) {
 			return
 		}
 		p.na = na
+
+		// Peers connected over a unix domain socket do not have a remote
+		// address with a host and port, so use the address they are
+		// treated as instead.
+		if _, ok := p.conn.RemoteAddr().(*net.UnixAddr); ok {
+			p.addr = net.JoinHostPort(na.IP.String(), "0")
+		}
 	}
 
 	go func(peer *Peer) {
@@ -2056,6 +2223,11 @@ func newPeerBase(cfgOrig *Config, inbound bool) *Peer {
 		cfg.Net = wire.TestNet3
 	}
 
+	// Use the default ping interval if the caller did not specify one.
+	if cfg.PingInterval == 0 {
+		cfg.PingInterval = pingInterval
+	}
+
 	p := Peer{
 		inbound:         inbound,
 		knownInventory:  lru.NewCache(maxKnownInventory),
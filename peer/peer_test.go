@@ -397,6 +397,18 @@ func TestPeerListeners(t *testing.T) {
 			OnSendHeaders: func(p *Peer, msg *wire.MsgSendHeaders) {
 				ok <- msg
 			},
+			OnSendCmpct: func(p *Peer, msg *wire.MsgSendCmpct) {
+				ok <- msg
+			},
+			OnCmpctBlock: func(p *Peer, msg *wire.MsgCmpctBlock) {
+				ok <- msg
+			},
+			OnGetBlockTxns: func(p *Peer, msg *wire.MsgGetBlockTxns) {
+				ok <- msg
+			},
+			OnBlockTxns: func(p *Peer, msg *wire.MsgBlockTxns) {
+				ok <- msg
+			},
 		},
 		UserAgentName:    "peer",
 		UserAgentVersion: "1.0",
@@ -538,6 +550,22 @@ func TestPeerListeners(t *testing.T) {
 			"OnSendHeaders",
 			wire.NewMsgSendHeaders(),
 		},
+		{
+			"OnSendCmpct",
+			wire.NewMsgSendCmpct(),
+		},
+		{
+			"OnCmpctBlock",
+			wire.NewMsgCmpctBlock(&wire.BlockHeader{}, 0),
+		},
+		{
+			"OnGetBlockTxns",
+			wire.NewMsgGetBlockTxns(&chainhash.Hash{}, []uint32{1}, nil),
+		},
+		{
+			"OnBlockTxns",
+			wire.NewMsgBlockTxns(&chainhash.Hash{}),
+		},
 	}
 	t.Logf("Running %d tests", len(tests))
 	for _, test := range tests {
@@ -550,6 +578,13 @@ func TestPeerListeners(t *testing.T) {
 			return
 		}
 	}
+
+	// Ensure the block announcement preference reflects the sendcmpct
+	// message taking precedence over the sendheaders message.
+	if mode := inPeer.BlockAnnounceMode(); mode != BlockAnnounceCmpct {
+		t.Errorf("TestPeerListeners: unexpected block announce mode -- "+
+			"got %v, want %v", mode, BlockAnnounceCmpct)
+	}
 	inPeer.Disconnect()
 	outPeer.Disconnect()
 }
@@ -757,6 +792,96 @@ func TestDuplicateVersionMsg(t *testing.T) {
 	}
 }
 
+// TestMissedPongs ensures outbound peers are only disconnected for failing to
+// answer pings when a maximum number of missed pongs is configured.
+func TestMissedPongs(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxMissedPongs uint32
+		wantDisconnect bool
+	}{
+		{"disabled", 0, false},
+		{"two missed pongs", 2, true},
+	}
+
+	for _, test := range tests {
+		// Create an inbound peer that answers the first ping and then
+		// stalls while writing that pong so no further pongs are sent.
+		verack := make(chan struct{}, 2)
+		release := make(chan struct{})
+		inCfg := &Config{
+			Listeners: MessageListeners{
+				OnVerAck: func(p *Peer, msg *wire.MsgVerAck) {
+					verack <- struct{}{}
+				},
+				OnWrite: func(p *Peer, bytesWritten int, msg wire.Message, err error) {
+					if _, ok := msg.(*wire.MsgPong); ok {
+						<-release
+					}
+				},
+			},
+			UserAgentName:    "peer",
+			UserAgentVersion: "1.0",
+			Net:              wire.MainNet,
+		}
+		outCfg := &Config{
+			Listeners: MessageListeners{
+				OnVerAck: func(p *Peer, msg *wire.MsgVerAck) {
+					verack <- struct{}{}
+				},
+			},
+			UserAgentName:    "peer",
+			UserAgentVersion: "1.0",
+			Net:              wire.MainNet,
+			PingInterval:     10 * time.Millisecond,
+			MaxMissedPongs:   test.maxMissedPongs,
+		}
+		inConn, outConn := pipe(
+			&conn{laddr: "10.0.0.1:9108", raddr: "10.0.0.2:9108"},
+			&conn{laddr: "10.0.0.2:9108", raddr: "10.0.0.1:9108"},
+		)
+		outPeer, err := NewOutboundPeer(outCfg, inConn.laddr)
+		if err != nil {
+			t.Fatalf("%q: NewOutboundPeer: unexpected err: %v", test.name,
+				err)
+		}
+		outPeer.AssociateConnection(outConn)
+		inPeer := NewInboundPeer(inCfg)
+		inPeer.AssociateConnection(inConn)
+
+		// Wait for the veracks from the initial protocol version
+		// negotiation.
+		for i := 0; i < 2; i++ {
+			select {
+			case <-verack:
+			case <-time.After(time.Second):
+				t.Fatalf("%q: verack timeout", test.name)
+			}
+		}
+
+		// Ensure the outbound peer is only disconnected when expected.
+		disconnected := make(chan struct{})
+		go func() {
+			outPeer.WaitForDisconnect()
+			close(disconnected)
+		}()
+		select {
+		case <-disconnected:
+			if !test.wantDisconnect {
+				t.Fatalf("%q: peer unexpectedly disconnected", test.name)
+			}
+		case <-time.After(500 * time.Millisecond):
+			if test.wantDisconnect {
+				t.Fatalf("%q: peer did not disconnect", test.name)
+			}
+		}
+
+		close(release)
+		outPeer.Disconnect()
+		inPeer.Disconnect()
+	}
+}
+
 // TestNetFallback ensures the network is set to the expected value in
 // accordance with the parameters.
 func TestNetFallback(t *testing.T) {
@@ -853,3 +978,25 @@ func init() {
 	// Allow self connection when running the tests.
 	allowSelfConns = true
 }
+
+// TestBlockAnnounceModeStringer tests the stringized output for the
+// BlockAnnounceMode type.
+func TestBlockAnnounceModeStringer(t *testing.T) {
+	tests := []struct {
+		in   BlockAnnounceMode
+		want string
+	}{
+		{BlockAnnounceInv, "inv"},
+		{BlockAnnounceHeaders, "headers"},
+		{BlockAnnounceCmpct, "compact"},
+		{0xff, "Unknown BlockAnnounceMode (255)"},
+	}
+
+	for i, test := range tests {
+		result := test.in.String()
+		if result != test.want {
+			t.Errorf("String #%d\n got: %s want: %s", i, result,
+				test.want)
+		}
+	}
+}
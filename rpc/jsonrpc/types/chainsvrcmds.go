@@ -45,6 +45,18 @@ const (
 	NDisconnect NodeSubCmd = "disconnect"
 )
 
+// SetBanSubCmd defines the type used in the setban JSON-RPC command for the
+// sub command field.
+type SetBanSubCmd string
+
+const (
+	// SBAdd indicates the specified address should be banned.
+	SBAdd SetBanSubCmd = "add"
+
+	// SBRemove indicates the ban of the specified address should be lifted.
+	SBRemove SetBanSubCmd = "remove"
+)
+
 // AddNodeCmd defines the addnode JSON-RPC command.
 type AddNodeCmd struct {
 	Addr   string
@@ -60,6 +72,46 @@ func NewAddNodeCmd(addr string, subCmd AddNodeSubCmd) *AddNodeCmd {
 	}
 }
 
+// PeerAddress represents a peer address to import into the address manager via
+// the addpeeraddresses JSON-RPC command.  The services and time are optional
+// and default to a full node and the current time, respectively, when zero.
+type PeerAddress struct {
+	Address  string `json:"address"`
+	Services uint64 `json:"services,omitempty"`
+	Time     int64  `json:"time,omitempty"`
+}
+
+// AddPeerAddressesCmd defines the addpeeraddresses JSON-RPC command.
+type AddPeerAddressesCmd struct {
+	Addresses []PeerAddress
+}
+
+// NewAddPeerAddressesCmd returns a new instance which can be used to issue an
+// addpeeraddresses JSON-RPC command.
+func NewAddPeerAddressesCmd(addresses []PeerAddress) *AddPeerAddressesCmd {
+	return &AddPeerAddressesCmd{
+		Addresses: addresses,
+	}
+}
+
+// BanSubnetCmd defines the bansubnet JSON-RPC command.
+type BanSubnetCmd struct {
+	Subnet   string
+	Duration *int64
+}
+
+// NewBanSubnetCmd returns a new instance which can be used to issue a
+// bansubnet JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewBanSubnetCmd(subnet string, duration *int64) *BanSubnetCmd {
+	return &BanSubnetCmd{
+		Subnet:   subnet,
+		Duration: duration,
+	}
+}
+
 // SStxInput represents the inputs to an SStx transaction. Specifically a
 // transactionsha and output number pair, along with the output amounts.
 type SStxInput struct {
@@ -143,6 +195,24 @@ func NewCreateRawTransactionCmd(inputs []TransactionInput, amounts map[string]fl
 	}
 }
 
+// DebugDumpCmd defines the debugdump JSON-RPC command.
+type DebugDumpCmd struct {
+	Profile *string
+	Seconds *int `jsonrpcdefault:"30"`
+}
+
+// NewDebugDumpCmd returns a new instance which can be used to issue a
+// debugdump JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewDebugDumpCmd(profile *string, seconds *int) *DebugDumpCmd {
+	return &DebugDumpCmd{
+		Profile: profile,
+		Seconds: seconds,
+	}
+}
+
 // DebugLevelCmd defines the debuglevel JSON-RPC command.  This command is not a
 // standard Bitcoin command.  It is an extension for btcd.
 type DebugLevelCmd struct {
@@ -185,6 +255,35 @@ func NewDecodeScriptCmd(hexScript string) *DecodeScriptCmd {
 	}
 }
 
+// DecodeTicketCmd defines the decodeticket JSON-RPC command.
+type DecodeTicketCmd struct {
+	Ticket string
+}
+
+// NewDecodeTicketCmd returns a new instance which can be used to issue a
+// decodeticket JSON-RPC command.
+func NewDecodeTicketCmd(ticket string) *DecodeTicketCmd {
+	return &DecodeTicketCmd{
+		Ticket: ticket,
+	}
+}
+
+// DropAllPeersCmd defines the dropallpeers JSON-RPC command.
+type DropAllPeersCmd struct {
+	ExcludePersistent *bool `jsonrpcdefault:"false"`
+}
+
+// NewDropAllPeersCmd returns a new instance which can be used to issue a
+// dropallpeers JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewDropAllPeersCmd(excludePersistent *bool) *DropAllPeersCmd {
+	return &DropAllPeersCmd{
+		ExcludePersistent: excludePersistent,
+	}
+}
+
 // EstimateFeeCmd defines the estimatefee JSON-RPC command.
 type EstimateFeeCmd struct {
 	NumBlocks int64
@@ -361,6 +460,24 @@ func NewGetAddedNodeInfoCmd(dns bool, node *string) *GetAddedNodeInfoCmd {
 	}
 }
 
+// GetAddrManInfoCmd defines the getaddrmaninfo JSON-RPC command.
+type GetAddrManInfoCmd struct{}
+
+// NewGetAddrManInfoCmd returns a new instance which can be used to issue a
+// getaddrmaninfo JSON-RPC command.
+func NewGetAddrManInfoCmd() *GetAddrManInfoCmd {
+	return &GetAddrManInfoCmd{}
+}
+
+// GetBannedPeersCmd defines the getbannedpeers JSON-RPC command.
+type GetBannedPeersCmd struct{}
+
+// NewGetBannedPeersCmd returns a new instance which can be used to issue a
+// getbannedpeers JSON-RPC command.
+func NewGetBannedPeersCmd() *GetBannedPeersCmd {
+	return &GetBannedPeersCmd{}
+}
+
 // GetBestBlockCmd defines the getbestblock JSON-RPC command.
 type GetBestBlockCmd struct{}
 
@@ -381,9 +498,10 @@ func NewGetBestBlockHashCmd() *GetBestBlockHashCmd {
 
 // GetBlockCmd defines the getblock JSON-RPC command.
 type GetBlockCmd struct {
-	Hash      string
-	Verbose   *bool `jsonrpcdefault:"true"`
-	VerboseTx *bool `jsonrpcdefault:"false"`
+	Hash           string
+	Verbose        *bool `jsonrpcdefault:"true"`
+	VerboseTx      *bool `jsonrpcdefault:"false"`
+	VerbosePrevOut *bool `jsonrpcdefault:"false"`
 }
 
 // NewGetBlockCmd returns a new instance which can be used to issue a getblock
@@ -430,6 +548,21 @@ func NewGetBlockHashCmd(index int64) *GetBlockHashCmd {
 	}
 }
 
+// GetBlockHashesCmd defines the getblockhashes JSON-RPC command.
+type GetBlockHashesCmd struct {
+	StartHeight int64
+	EndHeight   int64
+}
+
+// NewGetBlockHashesCmd returns a new instance which can be used to issue a
+// getblockhashes JSON-RPC command.
+func NewGetBlockHashesCmd(startHeight, endHeight int64) *GetBlockHashesCmd {
+	return &GetBlockHashesCmd{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+}
+
 // GetBlockHeaderCmd defines the getblockheader JSON-RPC command.
 type GetBlockHeaderCmd struct {
 	Hash    string
@@ -445,6 +578,20 @@ func NewGetBlockHeaderCmd(hash string, verbose *bool) *GetBlockHeaderCmd {
 	}
 }
 
+// GetBlockHeaderByHeightCmd defines the getblockheaderbyheight JSON-RPC
+// command.
+type GetBlockHeaderByHeightCmd struct {
+	Height int64
+}
+
+// NewGetBlockHeaderByHeightCmd returns a new instance which can be used to
+// issue a getblockheaderbyheight JSON-RPC command.
+func NewGetBlockHeaderByHeightCmd(height int64) *GetBlockHeaderByHeightCmd {
+	return &GetBlockHeaderByHeightCmd{
+		Height: height,
+	}
+}
+
 // GetBlockSubsidyCmd defines the getblocksubsidy JSON-RPC command.
 type GetBlockSubsidyCmd struct {
 	Height int64
@@ -499,8 +646,19 @@ func NewGetChainTipsCmd() *GetChainTipsCmd {
 	return &GetChainTipsCmd{}
 }
 
+// GetCoinbaseTemplateCmd defines the getcoinbasetemplate JSON-RPC command.
+type GetCoinbaseTemplateCmd struct{}
+
+// NewGetCoinbaseTemplateCmd returns a new instance which can be used to issue
+// a getcoinbasetemplate JSON-RPC command.
+func NewGetCoinbaseTemplateCmd() *GetCoinbaseTemplateCmd {
+	return &GetCoinbaseTemplateCmd{}
+}
+
 // GetCoinSupplyCmd defines the getcoinsupply JSON-RPC command.
-type GetCoinSupplyCmd struct{}
+type GetCoinSupplyCmd struct {
+	Verbose *bool `jsonrpcdefault:"false"`
+}
 
 // NewGetCoinSupplyCmd returns a new instance which can be used to issue a
 // getcoinsupply JSON-RPC command.
@@ -508,6 +666,47 @@ func NewGetCoinSupplyCmd() *GetCoinSupplyCmd {
 	return &GetCoinSupplyCmd{}
 }
 
+// NewGetCoinSupplyVerboseCmd returns a new instance which can be used to issue
+// a getcoinsupply JSON-RPC command that optionally returns a breakdown of the
+// coin supply.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetCoinSupplyVerboseCmd(verbose *bool) *GetCoinSupplyCmd {
+	return &GetCoinSupplyCmd{
+		Verbose: verbose,
+	}
+}
+
+// GetCompactBlockCmd defines the getcompactblock JSON-RPC command.
+type GetCompactBlockCmd struct {
+	Hash    string
+	Verbose *bool `jsonrpcdefault:"true"`
+	Nonce   *uint64
+}
+
+// NewGetCompactBlockCmd returns a new instance which can be used to issue a
+// getcompactblock JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetCompactBlockCmd(hash string, verbose *bool, nonce *uint64) *GetCompactBlockCmd {
+	return &GetCompactBlockCmd{
+		Hash:    hash,
+		Verbose: verbose,
+		Nonce:   nonce,
+	}
+}
+
+// GetConfigCmd defines the getconfig JSON-RPC command.
+type GetConfigCmd struct{}
+
+// NewGetConfigCmd returns a new instance which can be used to issue a getconfig
+// JSON-RPC command.
+func NewGetConfigCmd() *GetConfigCmd {
+	return &GetConfigCmd{}
+}
+
 // GetConnectionCountCmd defines the getconnectioncount JSON-RPC command.
 type GetConnectionCountCmd struct{}
 
@@ -517,6 +716,15 @@ func NewGetConnectionCountCmd() *GetConnectionCountCmd {
 	return &GetConnectionCountCmd{}
 }
 
+// GetConnMgrStatsCmd defines the getconnmgrstats JSON-RPC command.
+type GetConnMgrStatsCmd struct{}
+
+// NewGetConnMgrStatsCmd returns a new instance which can be used to issue a
+// getconnmgrstats JSON-RPC command.
+func NewGetConnMgrStatsCmd() *GetConnMgrStatsCmd {
+	return &GetConnMgrStatsCmd{}
+}
+
 // GetCurrentNetCmd defines the getcurrentnet JSON-RPC command.
 type GetCurrentNetCmd struct{}
 
@@ -535,6 +743,15 @@ func NewGetDifficultyCmd() *GetDifficultyCmd {
 	return &GetDifficultyCmd{}
 }
 
+// GetDifficultyInfoCmd defines the getdifficultyinfo JSON-RPC command.
+type GetDifficultyInfoCmd struct{}
+
+// NewGetDifficultyInfoCmd returns a new instance which can be used to issue a
+// getdifficultyinfo JSON-RPC command.
+func NewGetDifficultyInfoCmd() *GetDifficultyInfoCmd {
+	return &GetDifficultyInfoCmd{}
+}
+
 // GetGenerateCmd defines the getgenerate JSON-RPC command.
 type GetGenerateCmd struct{}
 
@@ -562,6 +779,22 @@ func NewGetInfoCmd() *GetInfoCmd {
 	return &GetInfoCmd{}
 }
 
+// GetKnownAddressesCmd defines the getknownaddresses JSON-RPC command.
+type GetKnownAddressesCmd struct {
+	Count *int `jsonrpcdefault:"0"`
+}
+
+// NewGetKnownAddressesCmd returns a new instance which can be used to issue a
+// getknownaddresses JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetKnownAddressesCmd(count *int) *GetKnownAddressesCmd {
+	return &GetKnownAddressesCmd{
+		Count: count,
+	}
+}
+
 // GetHeadersCmd defines the getheaders JSON-RPC command.
 type GetHeadersCmd struct {
 	BlockLocators []string `json:"blocklocators"`
@@ -577,6 +810,73 @@ func NewGetHeadersCmd(blockLocators []string, hashStop string) *GetHeadersCmd {
 	}
 }
 
+// GetLocalAddressesCmd defines the getlocaladdresses JSON-RPC command.
+type GetLocalAddressesCmd struct{}
+
+// NewGetLocalAddressesCmd returns a new instance which can be used to issue a
+// getlocaladdresses JSON-RPC command.
+func NewGetLocalAddressesCmd() *GetLocalAddressesCmd {
+	return &GetLocalAddressesCmd{}
+}
+
+// GetMemoryInfoCmd defines the getmemoryinfo JSON-RPC command.
+type GetMemoryInfoCmd struct{}
+
+// NewGetMemoryInfoCmd returns a new instance which can be used to issue a
+// getmemoryinfo JSON-RPC command.
+func NewGetMemoryInfoCmd() *GetMemoryInfoCmd {
+	return &GetMemoryInfoCmd{}
+}
+
+// GetMempoolAncestorsCmd defines the getmempoolancestors JSON-RPC command.
+type GetMempoolAncestorsCmd struct {
+	Txid    string
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetMempoolAncestorsCmd returns a new instance which can be used to issue a
+// getmempoolancestors JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMempoolAncestorsCmd(txHash string, verbose *bool) *GetMempoolAncestorsCmd {
+	return &GetMempoolAncestorsCmd{
+		Txid:    txHash,
+		Verbose: verbose,
+	}
+}
+
+// GetMempoolDescendantsCmd defines the getmempooldescendants JSON-RPC command.
+type GetMempoolDescendantsCmd struct {
+	Txid    string
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetMempoolDescendantsCmd returns a new instance which can be used to issue
+// a getmempooldescendants JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMempoolDescendantsCmd(txHash string, verbose *bool) *GetMempoolDescendantsCmd {
+	return &GetMempoolDescendantsCmd{
+		Txid:    txHash,
+		Verbose: verbose,
+	}
+}
+
+// GetMempoolEntryCmd defines the getmempoolentry JSON-RPC command.
+type GetMempoolEntryCmd struct {
+	Txid string
+}
+
+// NewGetMempoolEntryCmd returns a new instance which can be used to issue a
+// getmempoolentry JSON-RPC command.
+func NewGetMempoolEntryCmd(txHash string) *GetMempoolEntryCmd {
+	return &GetMempoolEntryCmd{
+		Txid: txHash,
+	}
+}
+
 // GetMempoolInfoCmd defines the getmempoolinfo JSON-RPC command.
 type GetMempoolInfoCmd struct{}
 
@@ -587,7 +887,9 @@ func NewGetMempoolInfoCmd() *GetMempoolInfoCmd {
 }
 
 // GetMiningInfoCmd defines the getmininginfo JSON-RPC command.
-type GetMiningInfoCmd struct{}
+type GetMiningInfoCmd struct {
+	Blocks *int `jsonrpcdefault:"120"`
+}
 
 // NewGetMiningInfoCmd returns a new instance which can be used to issue a
 // getmininginfo JSON-RPC command.
@@ -595,6 +897,18 @@ func NewGetMiningInfoCmd() *GetMiningInfoCmd {
 	return &GetMiningInfoCmd{}
 }
 
+// NewGetMiningInfoWindowCmd returns a new instance which can be used to issue a
+// getmininginfo JSON-RPC command that estimates the network hashes per second
+// over the specified number of blocks.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMiningInfoWindowCmd(numBlocks *int) *GetMiningInfoCmd {
+	return &GetMiningInfoCmd{
+		Blocks: numBlocks,
+	}
+}
+
 // GetNetworkInfoCmd defines the getnetworkinfo JSON-RPC command.
 type GetNetworkInfoCmd struct{}
 
@@ -631,8 +945,57 @@ func NewGetNetworkHashPSCmd(numBlocks, height *int) *GetNetworkHashPSCmd {
 	}
 }
 
+// GetNodeAddressesCmd defines the getnodeaddresses JSON-RPC command.
+type GetNodeAddressesCmd struct {
+	Count *int `jsonrpcdefault:"1"`
+}
+
+// NewGetNodeAddressesCmd returns a new instance which can be used to issue a
+// getnodeaddresses JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetNodeAddressesCmd(count *int) *GetNodeAddressesCmd {
+	return &GetNodeAddressesCmd{
+		Count: count,
+	}
+}
+
+// GetOrphanTxsCmd defines the getorphantxs JSON-RPC command.
+type GetOrphanTxsCmd struct {
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetOrphanTxsCmd returns a new instance which can be used to issue a
+// getorphantxs JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetOrphanTxsCmd(verbose *bool) *GetOrphanTxsCmd {
+	return &GetOrphanTxsCmd{
+		Verbose: verbose,
+	}
+}
+
+// GetPeerInfoDirection defines the connection direction used to filter the
+// peers returned by the getpeerinfo command.
+type GetPeerInfoDirection string
+
+const (
+	// GetPeerInfoAll returns both inbound and outbound peers.
+	GetPeerInfoAll GetPeerInfoDirection = "all"
+
+	// GetPeerInfoInbound returns only inbound peers.
+	GetPeerInfoInbound GetPeerInfoDirection = "inbound"
+
+	// GetPeerInfoOutbound returns only outbound peers.
+	GetPeerInfoOutbound GetPeerInfoDirection = "outbound"
+)
+
 // GetPeerInfoCmd defines the getpeerinfo JSON-RPC command.
-type GetPeerInfoCmd struct{}
+type GetPeerInfoCmd struct {
+	Direction *GetPeerInfoDirection `jsonrpcdefault:"\"all\""`
+}
 
 // NewGetPeerInfoCmd returns a new instance which can be used to issue a getpeer
 // JSON-RPC command.
@@ -640,6 +1003,18 @@ func NewGetPeerInfoCmd() *GetPeerInfoCmd {
 	return &GetPeerInfoCmd{}
 }
 
+// NewGetPeerInfoDirectionCmd returns a new instance which can be used to issue
+// a getpeer JSON-RPC command that only returns peers with the specified
+// connection direction.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetPeerInfoDirectionCmd(direction *GetPeerInfoDirection) *GetPeerInfoCmd {
+	return &GetPeerInfoCmd{
+		Direction: direction,
+	}
+}
+
 // GetRawMempoolTxTypeCmd defines the type used in the getrawmempool JSON-RPC
 // command for the TxType command field.
 type GetRawMempoolTxTypeCmd string
@@ -740,8 +1115,43 @@ func NewGetStakeVersionsCmd(hash string, count int32) *GetStakeVersionsCmd {
 	}
 }
 
+// GetSubsidyScheduleCmd defines the getsubsidyschedule JSON-RPC command.
+type GetSubsidyScheduleCmd struct {
+	StartHeight int64
+	EndHeight   int64
+	Voters      *uint16
+}
+
+// NewGetSubsidyScheduleCmd returns a new instance which can be used to issue a
+// getsubsidyschedule JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetSubsidyScheduleCmd(startHeight, endHeight int64, voters *uint16) *GetSubsidyScheduleCmd {
+	return &GetSubsidyScheduleCmd{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+		Voters:      voters,
+	}
+}
+
+// GetTicketInfoCmd defines the getticketinfo JSON-RPC command.
+type GetTicketInfoCmd struct {
+	Hash string
+}
+
+// NewGetTicketInfoCmd returns a new instance which can be used to issue a
+// getticketinfo JSON-RPC command.
+func NewGetTicketInfoCmd(hash string) *GetTicketInfoCmd {
+	return &GetTicketInfoCmd{
+		Hash: hash,
+	}
+}
+
 // GetTicketPoolValueCmd defines the getticketpoolvalue JSON-RPC command.
-type GetTicketPoolValueCmd struct{}
+type GetTicketPoolValueCmd struct {
+	Block *string
+}
 
 // NewGetTicketPoolValueCmd returns a new instance which can be used to issue a
 // getticketpoolvalue JSON-RPC command.
@@ -749,6 +1159,18 @@ func NewGetTicketPoolValueCmd() *GetTicketPoolValueCmd {
 	return &GetTicketPoolValueCmd{}
 }
 
+// NewGetTicketPoolValueBlockCmd returns a new instance which can be used to
+// issue a getticketpoolvalue JSON-RPC command for the ticket pool as of the
+// specified block.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetTicketPoolValueBlockCmd(block *string) *GetTicketPoolValueCmd {
+	return &GetTicketPoolValueCmd{
+		Block: block,
+	}
+}
+
 // GetTxOutCmd defines the gettxout JSON-RPC command.
 type GetTxOutCmd struct {
 	Txid           string
@@ -824,6 +1246,24 @@ func NewHelpCmd(command *string) *HelpCmd {
 	}
 }
 
+// IsBlockFinalCmd defines the isblockfinal JSON-RPC command.
+type IsBlockFinalCmd struct {
+	Block string
+	Depth *uint32
+}
+
+// NewIsBlockFinalCmd returns a new instance which can be used to issue an
+// isblockfinal JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewIsBlockFinalCmd(block string, depth *uint32) *IsBlockFinalCmd {
+	return &IsBlockFinalCmd{
+		Block: block,
+		Depth: depth,
+	}
+}
+
 // LiveTicketsCmd is a type handling custom marshaling and
 // unmarshaling of livetickets JSON RPC commands.
 type LiveTicketsCmd struct{}
@@ -893,15 +1333,47 @@ func NewRebroadcastWinnersCmd() *RebroadcastWinnersCmd {
 	return &RebroadcastWinnersCmd{}
 }
 
+// RelayBlockCmd defines the relayblock JSON-RPC command.
+type RelayBlockCmd struct {
+	Hash string
+}
+
+// NewRelayBlockCmd returns a new instance which can be used to issue a
+// relayblock JSON-RPC command.
+func NewRelayBlockCmd(hash string) *RelayBlockCmd {
+	return &RelayBlockCmd{
+		Hash: hash,
+	}
+}
+
+// ReloadWhitelistsCmd defines the reloadwhitelists JSON-RPC command.
+type ReloadWhitelistsCmd struct{}
+
+// NewReloadWhitelistsCmd returns a new instance which can be used to issue a
+// reloadwhitelists JSON-RPC command.
+func NewReloadWhitelistsCmd() *ReloadWhitelistsCmd {
+	return &ReloadWhitelistsCmd{}
+}
+
+// RestartCmd defines the restart JSON-RPC command.
+type RestartCmd struct{}
+
+// NewRestartCmd returns a new instance which can be used to issue a restart
+// JSON-RPC command.
+func NewRestartCmd() *RestartCmd {
+	return &RestartCmd{}
+}
+
 // SearchRawTransactionsCmd defines the searchrawtransactions JSON-RPC command.
 type SearchRawTransactionsCmd struct {
-	Address     string
-	Verbose     *int  `jsonrpcdefault:"1"`
-	Skip        *int  `jsonrpcdefault:"0"`
-	Count       *int  `jsonrpcdefault:"100"`
-	VinExtra    *int  `jsonrpcdefault:"0"`
-	Reverse     *bool `jsonrpcdefault:"false"`
-	FilterAddrs *[]string
+	Address      string
+	Verbose      *int  `jsonrpcdefault:"1"`
+	Skip         *int  `jsonrpcdefault:"0"`
+	Count        *int  `jsonrpcdefault:"100"`
+	VinExtra     *int  `jsonrpcdefault:"0"`
+	Reverse      *bool `jsonrpcdefault:"false"`
+	FilterAddrs  *[]string
+	IncludeTotal *bool `jsonrpcdefault:"false"`
 }
 
 // NewSearchRawTransactionsCmd returns a new instance which can be used to issue a
@@ -921,6 +1393,25 @@ func NewSearchRawTransactionsCmd(address string, verbose, skip, count *int, vinE
 	}
 }
 
+// NewSearchRawTransactionsTotalCmd returns a new instance which can be used to
+// issue a searchrawtransactions JSON-RPC command that optionally includes the
+// total number of matching transactions in the result.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSearchRawTransactionsTotalCmd(address string, verbose, skip, count *int, vinExtra *int, reverse *bool, filterAddrs *[]string, includeTotal *bool) *SearchRawTransactionsCmd {
+	return &SearchRawTransactionsCmd{
+		Address:      address,
+		Verbose:      verbose,
+		Skip:         skip,
+		Count:        count,
+		VinExtra:     vinExtra,
+		Reverse:      reverse,
+		FilterAddrs:  filterAddrs,
+		IncludeTotal: includeTotal,
+	}
+}
+
 // SendRawTransactionCmd defines the sendrawtransaction JSON-RPC command.
 type SendRawTransactionCmd struct {
 	HexTx         string
@@ -939,6 +1430,26 @@ func NewSendRawTransactionCmd(hexTx string, allowHighFees *bool) *SendRawTransac
 	}
 }
 
+// SetBanCmd defines the setban JSON-RPC command.
+type SetBanCmd struct {
+	Address string
+	Command SetBanSubCmd `jsonrpcusage:"\"add|remove\""`
+	BanTime *int64
+}
+
+// NewSetBanCmd returns a new instance which can be used to issue a setban
+// JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSetBanCmd(address string, command SetBanSubCmd, banTime *int64) *SetBanCmd {
+	return &SetBanCmd{
+		Address: address,
+		Command: command,
+		BanTime: banTime,
+	}
+}
+
 // SetGenerateCmd defines the setgenerate JSON-RPC command.
 type SetGenerateCmd struct {
 	Generate     bool
@@ -1017,6 +1528,19 @@ func NewTicketsForAddressCmd(addr string) *TicketsForAddressCmd {
 	return &TicketsForAddressCmd{addr}
 }
 
+// TicketsForAddressesCmd defines the ticketsforaddresses JSON-RPC command.
+type TicketsForAddressesCmd struct {
+	Addresses []string
+}
+
+// NewTicketsForAddressesCmd returns a new instance which can be used to issue
+// a ticketsforaddresses JSON-RPC command.
+func NewTicketsForAddressesCmd(addrs []string) *TicketsForAddressesCmd {
+	return &TicketsForAddressesCmd{
+		Addresses: addrs,
+	}
+}
+
 // TicketVWAPCmd defines the ticketvwap JSON-RPC command.
 type TicketVWAPCmd struct {
 	Start *uint32
@@ -1034,13 +1558,18 @@ func NewTicketVWAPCmd(start *uint32, end *uint32) *TicketVWAPCmd {
 
 // TxFeeInfoCmd defines the ticketsfeeinfo JSON-RPC command.
 type TxFeeInfoCmd struct {
-	Blocks     *uint32
-	RangeStart *uint32
-	RangeEnd   *uint32
+	Blocks      *uint32
+	RangeStart  *uint32
+	RangeEnd    *uint32
+	Percentiles *bool `jsonrpcdefault:"false"`
+	MempoolOnly *bool `jsonrpcdefault:"false"`
 }
 
 // NewTxFeeInfoCmd returns a new instance which can be used to issue a
 // JSON-RPC ticket fee info command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
 func NewTxFeeInfoCmd(blocks *uint32, start *uint32, end *uint32) *TxFeeInfoCmd {
 	return &TxFeeInfoCmd{
 		Blocks:     blocks,
@@ -1049,6 +1578,22 @@ func NewTxFeeInfoCmd(blocks *uint32, start *uint32, end *uint32) *TxFeeInfoCmd {
 	}
 }
 
+// NewTxFeeInfoPercentilesCmd returns a new instance which can be used to issue
+// a JSON-RPC ticket fee info command that optionally includes fee percentiles
+// and only considers the memory pool.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewTxFeeInfoPercentilesCmd(blocks *uint32, start *uint32, end *uint32, percentiles *bool, mempoolOnly *bool) *TxFeeInfoCmd {
+	return &TxFeeInfoCmd{
+		Blocks:      blocks,
+		RangeStart:  start,
+		RangeEnd:    end,
+		Percentiles: percentiles,
+		MempoolOnly: mempoolOnly,
+	}
+}
+
 // ValidateAddressCmd defines the validateaddress JSON-RPC command.
 type ValidateAddressCmd struct {
 	Address string
@@ -1109,12 +1654,17 @@ func init() {
 	flags := dcrjson.UsageFlag(0)
 
 	dcrjson.MustRegister(Method("addnode"), (*AddNodeCmd)(nil), flags)
+	dcrjson.MustRegister(Method("addpeeraddresses"), (*AddPeerAddressesCmd)(nil), flags)
+	dcrjson.MustRegister(Method("bansubnet"), (*BanSubnetCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawssrtx"), (*CreateRawSSRtxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawsstx"), (*CreateRawSStxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawtransaction"), (*CreateRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("debugdump"), (*DebugDumpCmd)(nil), flags)
 	dcrjson.MustRegister(Method("debuglevel"), (*DebugLevelCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decoderawtransaction"), (*DecodeRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decodescript"), (*DecodeScriptCmd)(nil), flags)
+	dcrjson.MustRegister(Method("decodeticket"), (*DecodeTicketCmd)(nil), flags)
+	dcrjson.MustRegister(Method("dropallpeers"), (*DropAllPeersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatefee"), (*EstimateFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatesmartfee"), (*EstimateSmartFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatestakediff"), (*EstimateStakeDiffCmd)(nil), flags)
@@ -1127,55 +1677,80 @@ func init() {
 	dcrjson.MustRegister(Method("existsmempooltxs"), (*ExistsMempoolTxsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("generate"), (*GenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getaddednodeinfo"), (*GetAddedNodeInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getaddrmaninfo"), (*GetAddrManInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getbannedpeers"), (*GetBannedPeersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getbestblock"), (*GetBestBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getbestblockhash"), (*GetBestBlockHashCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblock"), (*GetBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockchaininfo"), (*GetBlockChainInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockcount"), (*GetBlockCountCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockhash"), (*GetBlockHashCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblockhashes"), (*GetBlockHashesCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockheader"), (*GetBlockHeaderCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblockheaderbyheight"), (*GetBlockHeaderByHeightCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblocksubsidy"), (*GetBlockSubsidyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilter"), (*GetCFilterCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilterheader"), (*GetCFilterHeaderCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getchaintips"), (*GetChainTipsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getcoinbasetemplate"), (*GetCoinbaseTemplateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcoinsupply"), (*GetCoinSupplyCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getcompactblock"), (*GetCompactBlockCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getconfig"), (*GetConfigCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getconnectioncount"), (*GetConnectionCountCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getconnmgrstats"), (*GetConnMgrStatsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcurrentnet"), (*GetCurrentNetCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getdifficulty"), (*GetDifficultyCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getdifficultyinfo"), (*GetDifficultyInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getgenerate"), (*GetGenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gethashespersec"), (*GetHashesPerSecCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getheaders"), (*GetHeadersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getinfo"), (*GetInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getknownaddresses"), (*GetKnownAddressesCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getlocaladdresses"), (*GetLocalAddressesCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmemoryinfo"), (*GetMemoryInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempoolancestors"), (*GetMempoolAncestorsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempooldescendants"), (*GetMempoolDescendantsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempoolentry"), (*GetMempoolEntryCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmempoolinfo"), (*GetMempoolInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmininginfo"), (*GetMiningInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnetworkinfo"), (*GetNetworkInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnettotals"), (*GetNetTotalsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnetworkhashps"), (*GetNetworkHashPSCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getnodeaddresses"), (*GetNodeAddressesCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getorphantxs"), (*GetOrphanTxsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getpeerinfo"), (*GetPeerInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawmempool"), (*GetRawMempoolCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawtransaction"), (*GetRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakedifficulty"), (*GetStakeDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversioninfo"), (*GetStakeVersionInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversions"), (*GetStakeVersionsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getsubsidyschedule"), (*GetSubsidyScheduleCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getticketinfo"), (*GetTicketInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getticketpoolvalue"), (*GetTicketPoolValueCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gettxout"), (*GetTxOutCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gettxoutsetinfo"), (*GetTxOutSetInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getvoteinfo"), (*GetVoteInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getwork"), (*GetWorkCmd)(nil), flags)
 	dcrjson.MustRegister(Method("help"), (*HelpCmd)(nil), flags)
+	dcrjson.MustRegister(Method("isblockfinal"), (*IsBlockFinalCmd)(nil), flags)
 	dcrjson.MustRegister(Method("livetickets"), (*LiveTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("missedtickets"), (*MissedTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("node"), (*NodeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ping"), (*PingCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rebroadcastmissed"), (*RebroadcastMissedCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rebroadcastwinners"), (*RebroadcastWinnersCmd)(nil), flags)
+	dcrjson.MustRegister(Method("relayblock"), (*RelayBlockCmd)(nil), flags)
+	dcrjson.MustRegister(Method("reloadwhitelists"), (*ReloadWhitelistsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("restart"), (*RestartCmd)(nil), flags)
 	dcrjson.MustRegister(Method("searchrawtransactions"), (*SearchRawTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("sendrawtransaction"), (*SendRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("setban"), (*SetBanCmd)(nil), flags)
 	dcrjson.MustRegister(Method("setgenerate"), (*SetGenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stop"), (*StopCmd)(nil), flags)
 	dcrjson.MustRegister(Method("submitblock"), (*SubmitBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketfeeinfo"), (*TicketFeeInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketsforaddress"), (*TicketsForAddressCmd)(nil), flags)
+	dcrjson.MustRegister(Method("ticketsforaddresses"), (*TicketsForAddressesCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketvwap"), (*TicketVWAPCmd)(nil), flags)
 	dcrjson.MustRegister(Method("txfeeinfo"), (*TxFeeInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("validateaddress"), (*ValidateAddressCmd)(nil), flags)
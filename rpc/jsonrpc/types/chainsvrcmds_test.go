@@ -41,6 +41,49 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"addnode","params":["127.0.0.1","remove"],"id":1}`,
 			unmarshalled: &AddNodeCmd{Addr: "127.0.0.1", SubCmd: ANRemove},
 		},
+		{
+			name: "addpeeraddresses",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("addpeeraddresses"),
+					`[{"address":"127.0.0.1:9108","services":5,"time":1589482431},{"address":"::1"}]`)
+			},
+			staticCmd: func() interface{} {
+				addrs := []PeerAddress{
+					{Address: "127.0.0.1:9108", Services: 5, Time: 1589482431},
+					{Address: "::1"},
+				}
+				return NewAddPeerAddressesCmd(addrs)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"addpeeraddresses","params":[[{"address":"127.0.0.1:9108","services":5,"time":1589482431},{"address":"::1"}]],"id":1}`,
+			unmarshalled: &AddPeerAddressesCmd{
+				Addresses: []PeerAddress{
+					{Address: "127.0.0.1:9108", Services: 5, Time: 1589482431},
+					{Address: "::1"},
+				},
+			},
+		},
+		{
+			name: "bansubnet",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("bansubnet"), "192.168.0.0/16")
+			},
+			staticCmd: func() interface{} {
+				return NewBanSubnetCmd("192.168.0.0/16", nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"bansubnet","params":["192.168.0.0/16"],"id":1}`,
+			unmarshalled: &BanSubnetCmd{Subnet: "192.168.0.0/16"},
+		},
+		{
+			name: "bansubnet optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("bansubnet"), "192.168.0.0/16", 3600)
+			},
+			staticCmd: func() interface{} {
+				return NewBanSubnetCmd("192.168.0.0/16", dcrjson.Int64(3600))
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"bansubnet","params":["192.168.0.0/16",3600],"id":1}`,
+			unmarshalled: &BanSubnetCmd{Subnet: "192.168.0.0/16", Duration: dcrjson.Int64(3600)},
+		},
 		{
 			name: "createrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -83,6 +126,34 @@ func TestChainSvrCmds(t *testing.T) {
 				Expiry:   dcrjson.Int64(12312333333),
 			},
 		},
+		{
+			name: "debugdump",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("debugdump"))
+			},
+			staticCmd: func() interface{} {
+				return NewDebugDumpCmd(nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"debugdump","params":[],"id":1}`,
+			unmarshalled: &DebugDumpCmd{
+				Profile: nil,
+				Seconds: dcrjson.Int(30),
+			},
+		},
+		{
+			name: "debugdump optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("debugdump"), "cpu", 10)
+			},
+			staticCmd: func() interface{} {
+				return NewDebugDumpCmd(dcrjson.String("cpu"), dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"debugdump","params":["cpu",10],"id":1}`,
+			unmarshalled: &DebugDumpCmd{
+				Profile: dcrjson.String("cpu"),
+				Seconds: dcrjson.Int(10),
+			},
+		},
 		{
 			name: "debuglevel",
 			newCmd: func() (interface{}, error) {
@@ -131,6 +202,43 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"decodescript","params":["00",1],"id":1}`,
 			unmarshalled: &DecodeScriptCmd{HexScript: "00", Version: dcrjson.Uint16(1)},
 		},
+		{
+			name: "decodeticket",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("decodeticket"), "00")
+			},
+			staticCmd: func() interface{} {
+				return NewDecodeTicketCmd("00")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"decodeticket","params":["00"],"id":1}`,
+			unmarshalled: &DecodeTicketCmd{Ticket: "00"},
+		},
+		{
+			name: "dropallpeers",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("dropallpeers"))
+			},
+			staticCmd: func() interface{} {
+				return NewDropAllPeersCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"dropallpeers","params":[],"id":1}`,
+			unmarshalled: &DropAllPeersCmd{
+				ExcludePersistent: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "dropallpeers optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("dropallpeers"), true)
+			},
+			staticCmd: func() interface{} {
+				return NewDropAllPeersCmd(dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"dropallpeers","params":[true],"id":1}`,
+			unmarshalled: &DropAllPeersCmd{
+				ExcludePersistent: dcrjson.Bool(true),
+			},
+		},
 		{
 			name: "estimatefee",
 			newCmd: func() (interface{}, error) {
@@ -211,6 +319,28 @@ func TestChainSvrCmds(t *testing.T) {
 				Node: dcrjson.String("127.0.0.1"),
 			},
 		},
+		{
+			name: "getaddrmaninfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getaddrmaninfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddrManInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getaddrmaninfo","params":[],"id":1}`,
+			unmarshalled: &GetAddrManInfoCmd{},
+		},
+		{
+			name: "getbannedpeers",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getbannedpeers"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetBannedPeersCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getbannedpeers","params":[],"id":1}`,
+			unmarshalled: &GetBannedPeersCmd{},
+		},
 		{
 			name: "getbestblock",
 			newCmd: func() (interface{}, error) {
@@ -243,9 +373,10 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123"],"id":1}`,
 			unmarshalled: &GetBlockCmd{
-				Hash:      "123",
-				Verbose:   dcrjson.Bool(true),
-				VerboseTx: dcrjson.Bool(false),
+				Hash:           "123",
+				Verbose:        dcrjson.Bool(true),
+				VerboseTx:      dcrjson.Bool(false),
+				VerbosePrevOut: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -262,9 +393,10 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true],"id":1}`,
 			unmarshalled: &GetBlockCmd{
-				Hash:      "123",
-				Verbose:   dcrjson.Bool(true),
-				VerboseTx: dcrjson.Bool(false),
+				Hash:           "123",
+				Verbose:        dcrjson.Bool(true),
+				VerboseTx:      dcrjson.Bool(false),
+				VerbosePrevOut: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -277,9 +409,28 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true,true],"id":1}`,
 			unmarshalled: &GetBlockCmd{
-				Hash:      "123",
-				Verbose:   dcrjson.Bool(true),
-				VerboseTx: dcrjson.Bool(true),
+				Hash:           "123",
+				Verbose:        dcrjson.Bool(true),
+				VerboseTx:      dcrjson.Bool(true),
+				VerbosePrevOut: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getblock required optional3",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblock"), "123", true, true, true)
+			},
+			staticCmd: func() interface{} {
+				cmd := NewGetBlockCmd("123", dcrjson.Bool(true), dcrjson.Bool(true))
+				cmd.VerbosePrevOut = dcrjson.Bool(true)
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true,true,true],"id":1}`,
+			unmarshalled: &GetBlockCmd{
+				Hash:           "123",
+				Verbose:        dcrjson.Bool(true),
+				VerboseTx:      dcrjson.Bool(true),
+				VerbosePrevOut: dcrjson.Bool(true),
 			},
 		},
 		{
@@ -315,6 +466,20 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getblockhash","params":[123],"id":1}`,
 			unmarshalled: &GetBlockHashCmd{Index: 123},
 		},
+		{
+			name: "getblockhashes",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblockhashes"), 100, 200)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockHashesCmd(100, 200)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockhashes","params":[100,200],"id":1}`,
+			unmarshalled: &GetBlockHashesCmd{
+				StartHeight: 100,
+				EndHeight:   200,
+			},
+		},
 		{
 			name: "getblockheader",
 			newCmd: func() (interface{}, error) {
@@ -329,6 +494,17 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: dcrjson.Bool(true),
 			},
 		},
+		{
+			name: "getblockheaderbyheight",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblockheaderbyheight"), 123)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockHeaderByHeightCmd(123)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getblockheaderbyheight","params":[123],"id":1}`,
+			unmarshalled: &GetBlockHeaderByHeightCmd{Height: 123},
+		},
 		{
 			name: "getblocksubsidy",
 			newCmd: func() (interface{}, error) {
@@ -382,6 +558,83 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getchaintips","params":[],"id":1}`,
 			unmarshalled: &GetChainTipsCmd{},
 		},
+		{
+			name: "getcoinbasetemplate",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcoinbasetemplate"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetCoinbaseTemplateCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getcoinbasetemplate","params":[],"id":1}`,
+			unmarshalled: &GetCoinbaseTemplateCmd{},
+		},
+		{
+			name: "getcoinsupply",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcoinsupply"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetCoinSupplyCmd()
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcoinsupply","params":[],"id":1}`,
+			unmarshalled: &GetCoinSupplyCmd{
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getcoinsupply optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcoinsupply"), true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetCoinSupplyVerboseCmd(dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcoinsupply","params":[true],"id":1}`,
+			unmarshalled: &GetCoinSupplyCmd{
+				Verbose: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getcompactblock",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcompactblock"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetCompactBlockCmd("123", nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcompactblock","params":["123"],"id":1}`,
+			unmarshalled: &GetCompactBlockCmd{
+				Hash:    "123",
+				Verbose: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getcompactblock optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcompactblock"), "123", false, 5)
+			},
+			staticCmd: func() interface{} {
+				return NewGetCompactBlockCmd("123", dcrjson.Bool(false), dcrjson.Uint64(5))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcompactblock","params":["123",false,5],"id":1}`,
+			unmarshalled: &GetCompactBlockCmd{
+				Hash:    "123",
+				Verbose: dcrjson.Bool(false),
+				Nonce:   dcrjson.Uint64(5),
+			},
+		},
+		{
+			name: "getconfig",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getconfig"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetConfigCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getconfig","params":[],"id":1}`,
+			unmarshalled: &GetConfigCmd{},
+		},
 		{
 			name: "getconnectioncount",
 			newCmd: func() (interface{}, error) {
@@ -393,6 +646,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getconnectioncount","params":[],"id":1}`,
 			unmarshalled: &GetConnectionCountCmd{},
 		},
+		{
+			name: "getconnmgrstats",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getconnmgrstats"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetConnMgrStatsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getconnmgrstats","params":[],"id":1}`,
+			unmarshalled: &GetConnMgrStatsCmd{},
+		},
 		{
 			name: "getcurrentnet",
 			newCmd: func() (interface{}, error) {
@@ -415,6 +679,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getdifficulty","params":[],"id":1}`,
 			unmarshalled: &GetDifficultyCmd{},
 		},
+		{
+			name: "getdifficultyinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getdifficultyinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetDifficultyInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getdifficultyinfo","params":[],"id":1}`,
+			unmarshalled: &GetDifficultyInfoCmd{},
+		},
 		{
 			name: "getgenerate",
 			newCmd: func() (interface{}, error) {
@@ -448,6 +723,123 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getinfo","params":[],"id":1}`,
 			unmarshalled: &GetInfoCmd{},
 		},
+		{
+			name: "getknownaddresses",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getknownaddresses"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetKnownAddressesCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getknownaddresses","params":[],"id":1}`,
+			unmarshalled: &GetKnownAddressesCmd{
+				Count: dcrjson.Int(0),
+			},
+		},
+		{
+			name: "getknownaddresses optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getknownaddresses"), 10)
+			},
+			staticCmd: func() interface{} {
+				return NewGetKnownAddressesCmd(dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getknownaddresses","params":[10],"id":1}`,
+			unmarshalled: &GetKnownAddressesCmd{
+				Count: dcrjson.Int(10),
+			},
+		},
+		{
+			name: "getlocaladdresses",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getlocaladdresses"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetLocalAddressesCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getlocaladdresses","params":[],"id":1}`,
+			unmarshalled: &GetLocalAddressesCmd{},
+		},
+		{
+			name: "getmemoryinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmemoryinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetMemoryInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getmemoryinfo","params":[],"id":1}`,
+			unmarshalled: &GetMemoryInfoCmd{},
+		},
+		{
+			name: "getmempoolancestors",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolancestors"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolAncestorsCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolancestors","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolAncestorsCmd{
+				Txid:    "123",
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getmempoolancestors optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolancestors"), "123", true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolAncestorsCmd("123", dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolancestors","params":["123",true],"id":1}`,
+			unmarshalled: &GetMempoolAncestorsCmd{
+				Txid:    "123",
+				Verbose: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getmempooldescendants",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempooldescendants"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolDescendantsCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempooldescendants","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolDescendantsCmd{
+				Txid:    "123",
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getmempooldescendants optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempooldescendants"), "123", true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolDescendantsCmd("123", dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempooldescendants","params":["123",true],"id":1}`,
+			unmarshalled: &GetMempoolDescendantsCmd{
+				Txid:    "123",
+				Verbose: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getmempoolentry",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolentry"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolEntryCmd("123")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolentry","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolEntryCmd{
+				Txid: "123",
+			},
+		},
 		{
 			name: "getmempoolinfo",
 			newCmd: func() (interface{}, error) {
@@ -467,8 +859,23 @@ func TestChainSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewGetMiningInfoCmd()
 			},
-			marshalled:   `{"jsonrpc":"1.0","method":"getmininginfo","params":[],"id":1}`,
-			unmarshalled: &GetMiningInfoCmd{},
+			marshalled: `{"jsonrpc":"1.0","method":"getmininginfo","params":[],"id":1}`,
+			unmarshalled: &GetMiningInfoCmd{
+				Blocks: dcrjson.Int(120),
+			},
+		},
+		{
+			name: "getmininginfo optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmininginfo"), -1)
+			},
+			staticCmd: func() interface{} {
+				return NewGetMiningInfoWindowCmd(dcrjson.Int(-1))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmininginfo","params":[-1],"id":1}`,
+			unmarshalled: &GetMiningInfoCmd{
+				Blocks: dcrjson.Int(-1),
+			},
 		},
 		{
 			name: "getnetworkinfo",
@@ -534,6 +941,58 @@ func TestChainSvrCmds(t *testing.T) {
 				Height: dcrjson.Int(123),
 			},
 		},
+		{
+			name: "getnodeaddresses",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getnodeaddresses"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetNodeAddressesCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getnodeaddresses","params":[],"id":1}`,
+			unmarshalled: &GetNodeAddressesCmd{
+				Count: dcrjson.Int(1),
+			},
+		},
+		{
+			name: "getnodeaddresses optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getnodeaddresses"), 10)
+			},
+			staticCmd: func() interface{} {
+				return NewGetNodeAddressesCmd(dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getnodeaddresses","params":[10],"id":1}`,
+			unmarshalled: &GetNodeAddressesCmd{
+				Count: dcrjson.Int(10),
+			},
+		},
+		{
+			name: "getorphantxs",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getorphantxs"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetOrphanTxsCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getorphantxs","params":[],"id":1}`,
+			unmarshalled: &GetOrphanTxsCmd{
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getorphantxs optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getorphantxs"), true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetOrphanTxsCmd(dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getorphantxs","params":[true],"id":1}`,
+			unmarshalled: &GetOrphanTxsCmd{
+				Verbose: dcrjson.Bool(true),
+			},
+		},
 		{
 			name: "getpeerinfo",
 			newCmd: func() (interface{}, error) {
@@ -542,8 +1001,24 @@ func TestChainSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewGetPeerInfoCmd()
 			},
-			marshalled:   `{"jsonrpc":"1.0","method":"getpeerinfo","params":[],"id":1}`,
-			unmarshalled: &GetPeerInfoCmd{},
+			marshalled: `{"jsonrpc":"1.0","method":"getpeerinfo","params":[],"id":1}`,
+			unmarshalled: &GetPeerInfoCmd{
+				Direction: GetPeerInfoDirectionAddr(GetPeerInfoAll),
+			},
+		},
+		{
+			name: "getpeerinfo optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getpeerinfo"), GetPeerInfoInbound)
+			},
+			staticCmd: func() interface{} {
+				direction := GetPeerInfoInbound
+				return NewGetPeerInfoDirectionCmd(&direction)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getpeerinfo","params":["inbound"],"id":1}`,
+			unmarshalled: &GetPeerInfoCmd{
+				Direction: GetPeerInfoDirectionAddr(GetPeerInfoInbound),
+			},
 		},
 		{
 			name: "getrawmempool",
@@ -627,6 +1102,73 @@ func TestChainSvrCmds(t *testing.T) {
 				Count: 1,
 			},
 		},
+		{
+			name: "getsubsidyschedule",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getsubsidyschedule"), 0, 100000)
+			},
+			staticCmd: func() interface{} {
+				return NewGetSubsidyScheduleCmd(0, 100000, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getsubsidyschedule","params":[0,100000],"id":1}`,
+			unmarshalled: &GetSubsidyScheduleCmd{
+				StartHeight: 0,
+				EndHeight:   100000,
+				Voters:      nil,
+			},
+		},
+		{
+			name: "getsubsidyschedule optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getsubsidyschedule"), 0, 100000, 3)
+			},
+			staticCmd: func() interface{} {
+				return NewGetSubsidyScheduleCmd(0, 100000, dcrjson.Uint16(3))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getsubsidyschedule","params":[0,100000,3],"id":1}`,
+			unmarshalled: &GetSubsidyScheduleCmd{
+				StartHeight: 0,
+				EndHeight:   100000,
+				Voters:      dcrjson.Uint16(3),
+			},
+		},
+		{
+			name: "getticketinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getticketinfo"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetTicketInfoCmd("123")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getticketinfo","params":["123"],"id":1}`,
+			unmarshalled: &GetTicketInfoCmd{
+				Hash: "123",
+			},
+		},
+		{
+			name: "getticketpoolvalue",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getticketpoolvalue"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetTicketPoolValueCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getticketpoolvalue","params":[],"id":1}`,
+			unmarshalled: &GetTicketPoolValueCmd{},
+		},
+		{
+			name: "getticketpoolvalue optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getticketpoolvalue"), "150")
+			},
+			staticCmd: func() interface{} {
+				return NewGetTicketPoolValueBlockCmd(dcrjson.String("150"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getticketpoolvalue","params":["150"],"id":1}`,
+			unmarshalled: &GetTicketPoolValueCmd{
+				Block: dcrjson.String("150"),
+			},
+		},
 		{
 			name: "gettxout",
 			newCmd: func() (interface{}, error) {
@@ -733,6 +1275,33 @@ func TestChainSvrCmds(t *testing.T) {
 				Command: dcrjson.String("getblock"),
 			},
 		},
+		{
+			name: "isblockfinal",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("isblockfinal"), "150")
+			},
+			staticCmd: func() interface{} {
+				return NewIsBlockFinalCmd("150", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"isblockfinal","params":["150"],"id":1}`,
+			unmarshalled: &IsBlockFinalCmd{
+				Block: "150",
+			},
+		},
+		{
+			name: "isblockfinal optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("isblockfinal"), "150", 12)
+			},
+			staticCmd: func() interface{} {
+				return NewIsBlockFinalCmd("150", dcrjson.Uint32(12))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"isblockfinal","params":["150",12],"id":1}`,
+			unmarshalled: &IsBlockFinalCmd{
+				Block: "150",
+				Depth: dcrjson.Uint32(12),
+			},
+		},
 		{
 			name: "node option remove",
 			newCmd: func() (interface{}, error) {
@@ -787,6 +1356,41 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"ping","params":[],"id":1}`,
 			unmarshalled: &PingCmd{},
 		},
+		{
+			name: "relayblock",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("relayblock"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewRelayBlockCmd("123")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"relayblock","params":["123"],"id":1}`,
+			unmarshalled: &RelayBlockCmd{
+				Hash: "123",
+			},
+		},
+		{
+			name: "reloadwhitelists",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("reloadwhitelists"))
+			},
+			staticCmd: func() interface{} {
+				return NewReloadWhitelistsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"reloadwhitelists","params":[],"id":1}`,
+			unmarshalled: &ReloadWhitelistsCmd{},
+		},
+		{
+			name: "restart",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("restart"))
+			},
+			staticCmd: func() interface{} {
+				return NewRestartCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"restart","params":[],"id":1}`,
+			unmarshalled: &RestartCmd{},
+		},
 		{
 			name: "searchrawtransactions",
 			newCmd: func() (interface{}, error) {
@@ -797,13 +1401,14 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address"],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
-				Address:     "1Address",
-				Verbose:     dcrjson.Int(1),
-				Skip:        dcrjson.Int(0),
-				Count:       dcrjson.Int(100),
-				VinExtra:    dcrjson.Int(0),
-				Reverse:     dcrjson.Bool(false),
-				FilterAddrs: nil,
+				Address:      "1Address",
+				Verbose:      dcrjson.Int(1),
+				Skip:         dcrjson.Int(0),
+				Count:        dcrjson.Int(100),
+				VinExtra:     dcrjson.Int(0),
+				Reverse:      dcrjson.Bool(false),
+				FilterAddrs:  nil,
+				IncludeTotal: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -817,13 +1422,14 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
-				Address:     "1Address",
-				Verbose:     dcrjson.Int(0),
-				Skip:        dcrjson.Int(0),
-				Count:       dcrjson.Int(100),
-				VinExtra:    dcrjson.Int(0),
-				Reverse:     dcrjson.Bool(false),
-				FilterAddrs: nil,
+				Address:      "1Address",
+				Verbose:      dcrjson.Int(0),
+				Skip:         dcrjson.Int(0),
+				Count:        dcrjson.Int(100),
+				VinExtra:     dcrjson.Int(0),
+				Reverse:      dcrjson.Bool(false),
+				FilterAddrs:  nil,
+				IncludeTotal: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -837,13 +1443,14 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
-				Address:     "1Address",
-				Verbose:     dcrjson.Int(0),
-				Skip:        dcrjson.Int(5),
-				Count:       dcrjson.Int(100),
-				VinExtra:    dcrjson.Int(0),
-				Reverse:     dcrjson.Bool(false),
-				FilterAddrs: nil,
+				Address:      "1Address",
+				Verbose:      dcrjson.Int(0),
+				Skip:         dcrjson.Int(5),
+				Count:        dcrjson.Int(100),
+				VinExtra:     dcrjson.Int(0),
+				Reverse:      dcrjson.Bool(false),
+				FilterAddrs:  nil,
+				IncludeTotal: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -857,13 +1464,14 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
-				Address:     "1Address",
-				Verbose:     dcrjson.Int(0),
-				Skip:        dcrjson.Int(5),
-				Count:       dcrjson.Int(10),
-				VinExtra:    dcrjson.Int(0),
-				Reverse:     dcrjson.Bool(false),
-				FilterAddrs: nil,
+				Address:      "1Address",
+				Verbose:      dcrjson.Int(0),
+				Skip:         dcrjson.Int(5),
+				Count:        dcrjson.Int(10),
+				VinExtra:     dcrjson.Int(0),
+				Reverse:      dcrjson.Bool(false),
+				FilterAddrs:  nil,
+				IncludeTotal: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -877,13 +1485,14 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
-				Address:     "1Address",
-				Verbose:     dcrjson.Int(0),
-				Skip:        dcrjson.Int(5),
-				Count:       dcrjson.Int(10),
-				VinExtra:    dcrjson.Int(1),
-				Reverse:     dcrjson.Bool(false),
-				FilterAddrs: nil,
+				Address:      "1Address",
+				Verbose:      dcrjson.Int(0),
+				Skip:         dcrjson.Int(5),
+				Count:        dcrjson.Int(10),
+				VinExtra:     dcrjson.Int(1),
+				Reverse:      dcrjson.Bool(false),
+				FilterAddrs:  nil,
+				IncludeTotal: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -898,13 +1507,14 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1,true],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
-				Address:     "1Address",
-				Verbose:     dcrjson.Int(0),
-				Skip:        dcrjson.Int(5),
-				Count:       dcrjson.Int(10),
-				VinExtra:    dcrjson.Int(1),
-				Reverse:     dcrjson.Bool(true),
-				FilterAddrs: nil,
+				Address:      "1Address",
+				Verbose:      dcrjson.Int(0),
+				Skip:         dcrjson.Int(5),
+				Count:        dcrjson.Int(10),
+				VinExtra:     dcrjson.Int(1),
+				Reverse:      dcrjson.Bool(true),
+				FilterAddrs:  nil,
+				IncludeTotal: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -919,13 +1529,37 @@ func TestChainSvrCmds(t *testing.T) {
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1,true,["1Address"]],"id":1}`,
 			unmarshalled: &SearchRawTransactionsCmd{
-				Address:     "1Address",
-				Verbose:     dcrjson.Int(0),
-				Skip:        dcrjson.Int(5),
-				Count:       dcrjson.Int(10),
-				VinExtra:    dcrjson.Int(1),
-				Reverse:     dcrjson.Bool(true),
-				FilterAddrs: &[]string{"1Address"},
+				Address:      "1Address",
+				Verbose:      dcrjson.Int(0),
+				Skip:         dcrjson.Int(5),
+				Count:        dcrjson.Int(10),
+				VinExtra:     dcrjson.Int(1),
+				Reverse:      dcrjson.Bool(true),
+				FilterAddrs:  &[]string{"1Address"},
+				IncludeTotal: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "searchrawtransactions",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("searchrawtransactions"), "1Address", 0, 5, 10, 1, true, []string{"1Address"}, true)
+			},
+			staticCmd: func() interface{} {
+				return NewSearchRawTransactionsTotalCmd("1Address",
+					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10),
+					dcrjson.Int(1), dcrjson.Bool(true), &[]string{"1Address"},
+					dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,1,true,["1Address"],true],"id":1}`,
+			unmarshalled: &SearchRawTransactionsCmd{
+				Address:      "1Address",
+				Verbose:      dcrjson.Int(0),
+				Skip:         dcrjson.Int(5),
+				Count:        dcrjson.Int(10),
+				VinExtra:     dcrjson.Int(1),
+				Reverse:      dcrjson.Bool(true),
+				FilterAddrs:  &[]string{"1Address"},
+				IncludeTotal: dcrjson.Bool(true),
 			},
 		},
 		{
@@ -956,6 +1590,49 @@ func TestChainSvrCmds(t *testing.T) {
 				AllowHighFees: dcrjson.Bool(false),
 			},
 		},
+		{
+			name: "setban",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setban"), "1.2.3.4", "add")
+			},
+			staticCmd: func() interface{} {
+				return NewSetBanCmd("1.2.3.4", SBAdd, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setban","params":["1.2.3.4","add"],"id":1}`,
+			unmarshalled: &SetBanCmd{
+				Address: "1.2.3.4",
+				Command: SBAdd,
+			},
+		},
+		{
+			name: "setban optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setban"), "1.2.3.0/24", "add", 3600)
+			},
+			staticCmd: func() interface{} {
+				return NewSetBanCmd("1.2.3.0/24", SBAdd, dcrjson.Int64(3600))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setban","params":["1.2.3.0/24","add",3600],"id":1}`,
+			unmarshalled: &SetBanCmd{
+				Address: "1.2.3.0/24",
+				Command: SBAdd,
+				BanTime: dcrjson.Int64(3600),
+			},
+		},
+		{
+			name: "setban remove",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setban"), "1.2.3.4", "remove")
+			},
+			staticCmd: func() interface{} {
+				return NewSetBanCmd("1.2.3.4", SBRemove, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setban","params":["1.2.3.4","remove"],"id":1}`,
+			unmarshalled: &SetBanCmd{
+				Address: "1.2.3.4",
+				Command: SBRemove,
+			},
+		},
 		{
 			name: "setgenerate",
 			newCmd: func() (interface{}, error) {
@@ -1028,6 +1705,52 @@ func TestChainSvrCmds(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ticketsforaddresses",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("ticketsforaddresses"), []string{"a", "b"})
+			},
+			staticCmd: func() interface{} {
+				return NewTicketsForAddressesCmd([]string{"a", "b"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"ticketsforaddresses","params":[["a","b"]],"id":1}`,
+			unmarshalled: &TicketsForAddressesCmd{
+				Addresses: []string{"a", "b"},
+			},
+		},
+		{
+			name: "txfeeinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("txfeeinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewTxFeeInfoCmd(nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"txfeeinfo","params":[],"id":1}`,
+			unmarshalled: &TxFeeInfoCmd{
+				Percentiles: dcrjson.Bool(false),
+				MempoolOnly: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "txfeeinfo optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("txfeeinfo"), 5, 10, 20, true, true)
+			},
+			staticCmd: func() interface{} {
+				return NewTxFeeInfoPercentilesCmd(dcrjson.Uint32(5),
+					dcrjson.Uint32(10), dcrjson.Uint32(20), dcrjson.Bool(true),
+					dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"txfeeinfo","params":[5,10,20,true,true],"id":1}`,
+			unmarshalled: &TxFeeInfoCmd{
+				Blocks:      dcrjson.Uint32(5),
+				RangeStart:  dcrjson.Uint32(10),
+				RangeEnd:    dcrjson.Uint32(20),
+				Percentiles: dcrjson.Bool(true),
+				MempoolOnly: dcrjson.Bool(true),
+			},
+		},
 		{
 			name: "validateaddress",
 			newCmd: func() (interface{}, error) {
@@ -17,6 +17,12 @@ type TxRawDecodeResult struct {
 	Vout     []Vout `json:"vout"`
 }
 
+// DebugDumpResult models the data returned from the debugdump command.
+type DebugDumpResult struct {
+	Goroutines  string `json:"goroutines"`
+	ProfileFile string `json:"profilefile,omitempty"`
+}
+
 // DecodeScriptResult models the data returned from the decodescript command.
 type DecodeScriptResult struct {
 	Asm       string   `json:"asm"`
@@ -26,6 +32,25 @@ type DecodeScriptResult struct {
 	P2sh      string   `json:"p2sh,omitempty"`
 }
 
+// TicketCommitmentResult models a ticket commitment output and its associated
+// change output as returned by the decodeticket command.
+type TicketCommitmentResult struct {
+	Address            string   `json:"address"`
+	Amount             float64  `json:"amount"`
+	ChangeAddress      string   `json:"changeaddress,omitempty"`
+	ChangeAmount       float64  `json:"changeamount"`
+	VoteFeeLimit       *float64 `json:"votefeelimit,omitempty"`
+	RevocationFeeLimit *float64 `json:"revocationfeelimit,omitempty"`
+}
+
+// DecodeTicketResult models the data returned from the decodeticket command.
+type DecodeTicketResult struct {
+	Txid          string                   `json:"txid"`
+	TicketPrice   float64                  `json:"ticketprice"`
+	VotingAddress string                   `json:"votingaddress"`
+	Commitments   []TicketCommitmentResult `json:"commitments"`
+}
+
 // EstimateSmartFeeResult models the data returned from the estimatesmartfee
 // command.
 type EstimateSmartFeeResult struct {
@@ -57,6 +82,25 @@ type GetAddedNodeInfoResult struct {
 	Addresses *[]GetAddedNodeInfoResultAddr `json:"addresses,omitempty"`
 }
 
+// GetAddrManInfoResult models the data returned from the getaddrmaninfo
+// command.
+type GetAddrManInfoResult struct {
+	New   int `json:"new"`
+	Tried int `json:"tried"`
+	Total int `json:"total"`
+	IPv4  int `json:"ipv4"`
+	IPv6  int `json:"ipv6"`
+	Onion int `json:"onion"`
+}
+
+// GetBannedPeersResult models the data of a banned host or subnet from the
+// getbannedpeers command.
+type GetBannedPeersResult struct {
+	Host                string `json:"host"`
+	BanUntil            int64  `json:"banuntil"`
+	BanSecondsRemaining int64  `json:"bansecondsremaining"`
+}
+
 // GetBlockVerboseResult models the data from the getblock command when the
 // verbose flag is set.  When the verbose flag is not set, getblock returns a
 // hex-encoded string.  Contains Decred additions.
@@ -115,9 +159,11 @@ type GetBlockChainInfoResult struct {
 	Difficulty           uint32                `json:"difficulty"`
 	DifficultyRatio      float64               `json:"difficultyratio"`
 	VerificationProgress float64               `json:"verificationprogress"`
+	SyncETASeconds       int64                 `json:"synceta_seconds,omitempty"`
 	ChainWork            string                `json:"chainwork"`
 	InitialBlockDownload bool                  `json:"initialblockdownload"`
 	MaxBlockSize         int64                 `json:"maxblocksize"`
+	MedianBlockInterval  float64               `json:"medianblockinterval"`
 	Deployments          map[string]AgendaInfo `json:"deployments"`
 }
 
@@ -167,12 +213,120 @@ type GetChainTipsResult struct {
 	Status    string `json:"status"`
 }
 
+// PrefilledTxResult models the data of a transaction that is included in full
+// in a compact block.
+type PrefilledTxResult struct {
+	Index uint32 `json:"index"`
+	Txid  string `json:"txid"`
+}
+
+// CoinbaseExtraNonceResult models the location of the extra nonce within the
+// coinbase transaction returned from the getcoinbasetemplate command.
+type CoinbaseExtraNonceResult struct {
+	Output uint32 `json:"output"`
+	Offset uint32 `json:"offset"`
+	Size   uint32 `json:"size"`
+	Value  uint64 `json:"value"`
+}
+
+// GetCoinbaseTemplateResult models the data returned from the
+// getcoinbasetemplate command.
+type GetCoinbaseTemplateResult struct {
+	Height            int64                     `json:"height"`
+	PreviousBlockHash string                    `json:"previousblockhash"`
+	Voters            uint16                    `json:"voters"`
+	PoW               int64                     `json:"pow"`
+	PoS               int64                     `json:"pos"`
+	Developer         int64                     `json:"developer"`
+	Fees              int64                     `json:"fees"`
+	Txid              string                    `json:"txid"`
+	Hex               string                    `json:"hex"`
+	Vout              []Vout                    `json:"vout"`
+	ExtraNonce        *CoinbaseExtraNonceResult `json:"extranonce,omitempty"`
+}
+
+// GetCoinSupplyVerboseResult models the data returned from the getcoinsupply
+// command when the verbose flag is set.  When the verbose flag is not set,
+// getcoinsupply returns the total coin supply in atoms.
+type GetCoinSupplyVerboseResult struct {
+	Total           int64 `json:"total"`
+	Mined           int64 `json:"mined"`
+	Treasury        int64 `json:"treasury"`
+	BlockOne        int64 `json:"blockone"`
+	LockedInTickets int64 `json:"lockedintickets"`
+	Circulating     int64 `json:"circulating"`
+}
+
+// GetCompactBlockVerboseResult models the data from the getcompactblock
+// command when the verbose flag is set.  When the verbose flag is not set,
+// getcompactblock returns a hex-encoded string.
+type GetCompactBlockVerboseResult struct {
+	Hash         string              `json:"hash"`
+	Nonce        uint64              `json:"nonce"`
+	ShortIDs     []string            `json:"shortids"`
+	PrefilledTx  []PrefilledTxResult `json:"prefilledtx"`
+	STxShortIDs  []string            `json:"stxshortids"`
+	PrefilledSTx []PrefilledTxResult `json:"prefilledstx"`
+	Size         int                 `json:"size"`
+	BlockSize    int                 `json:"blocksize"`
+}
+
+// GetConnMgrStatsResult models the data returned from the getconnmgrstats
+// command.
+type GetConnMgrStatsResult struct {
+	TargetOutbound      uint32 `json:"targetoutbound"`
+	Outbound            uint32 `json:"outbound"`
+	Pending             uint32 `json:"pending"`
+	InFlightDials       uint32 `json:"inflightdials"`
+	ConsecutiveFailures uint64 `json:"consecutivefailures"`
+	TotalFailures       uint64 `json:"totalfailures"`
+	KnownAddresses      int    `json:"knownaddresses"`
+	TriedAddresses      int    `json:"triedaddresses"`
+	NewAddresses        int    `json:"newaddresses"`
+}
+
+// GetDifficultyInfoResult models the data returned from the getdifficultyinfo
+// command.
+type GetDifficultyInfoResult struct {
+	Difficulty               float64 `json:"difficulty"`
+	Bits                     string  `json:"bits"`
+	NextRetargetHeight       int64   `json:"nextretargetheight"`
+	BlocksUntilRetarget      int64   `json:"blocksuntilretarget"`
+	EstimatedDifficulty      float64 `json:"estimateddifficulty"`
+	EstimatedBits            string  `json:"estimatedbits"`
+	StakeDifficulty          float64 `json:"stakedifficulty"`
+	NextStakeDifficulty      float64 `json:"nextstakedifficulty"`
+	NextStakeRetargetHeight  int64   `json:"nextstakeretargetheight"`
+	BlocksUntilStakeRetarget int64   `json:"blocksuntilstakeretarget"`
+	EstimatedStakeDifficulty float64 `json:"estimatedstakedifficulty"`
+}
+
 // GetHeadersResult models the data returned by the chain server getheaders
 // command.
 type GetHeadersResult struct {
 	Headers []string `json:"headers"`
 }
 
+// GetKnownAddressesResult models the data of a single address returned from
+// the getknownaddresses command.
+type GetKnownAddressesResult struct {
+	Address     string `json:"address"`
+	Services    string `json:"services"`
+	LastSeen    int64  `json:"lastseen"`
+	LastAttempt int64  `json:"lastattempt"`
+	Source      string `json:"source"`
+	Tried       bool   `json:"tried"`
+}
+
+// GetLocalAddressesResult models the data of a single local address returned
+// from the getlocaladdresses command.
+type GetLocalAddressesResult struct {
+	Address  string `json:"address"`
+	Port     uint16 `json:"port"`
+	Priority string `json:"priority"`
+	Score    int32  `json:"score"`
+}
+
 // InfoChainResult models the data returned by the chain server getinfo command.
 type InfoChainResult struct {
 	Version         int32   `json:"version"`
@@ -187,6 +341,25 @@ type InfoChainResult struct {
 	Errors          string  `json:"errors"`
 }
 
+// GetMemoryInfoResult models the data returned from the getmemoryinfo
+// command.
+type GetMemoryInfoResult struct {
+	HeapAlloc             uint64 `json:"heapalloc"`
+	HeapInUse             uint64 `json:"heapinuse"`
+	HeapIdle              uint64 `json:"heapidle"`
+	HeapReleased          uint64 `json:"heapreleased"`
+	HeapObjects           uint64 `json:"heapobjects"`
+	TotalAlloc            uint64 `json:"totalalloc"`
+	Sys                   uint64 `json:"sys"`
+	NumGC                 uint32 `json:"numgc"`
+	GCPauseTotal          uint64 `json:"gcpausetotal"`
+	LastGCPause           uint64 `json:"lastgcpause"`
+	Goroutines            int    `json:"goroutines"`
+	SigCacheEntries       int    `json:"sigcacheentries"`
+	SigCacheMaxEntries    uint   `json:"sigcachemaxentries"`
+	KnownAddrCacheEntries int    `json:"knownaddrcacheentries"`
+}
+
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
@@ -236,6 +409,8 @@ type GetNetworkInfoResult struct {
 	ProtocolVersion int32                  `json:"protocolversion"`
 	TimeOffset      int64                  `json:"timeoffset"`
 	Connections     int32                  `json:"connections"`
+	ConnectionsIn   int32                  `json:"connections_in"`
+	ConnectionsOut  int32                  `json:"connections_out"`
 	Networks        []NetworksResult       `json:"networks"`
 	RelayFee        float64                `json:"relayfee"`
 	LocalAddresses  []LocalAddressesResult `json:"localaddresses"`
@@ -249,6 +424,25 @@ type GetNetTotalsResult struct {
 	TimeMillis     int64  `json:"timemillis"`
 }
 
+// GetNodeAddressesResult models the data returned from the getnodeaddresses
+// command.
+type GetNodeAddressesResult struct {
+	Time     int64  `json:"time"`
+	Services string `json:"services"`
+	Address  string `json:"address"`
+	Port     uint16 `json:"port"`
+}
+
+// GetOrphanTxsResult models the data returned from the getorphantxs command
+// when the verbose flag is set.
+type GetOrphanTxsResult struct {
+	TxID           string            `json:"txid"`
+	Size           int32             `json:"size"`
+	Time           int64             `json:"time"`
+	MissingParents []OutPoint        `json:"missingparents"`
+	Tx             TxRawDecodeResult `json:"tx"`
+}
+
 // GetPeerInfoResult models the data returned from the getpeerinfo command.
 type GetPeerInfoResult struct {
 	ID             int32   `json:"id"`
@@ -260,6 +454,8 @@ type GetPeerInfoResult struct {
 	LastRecv       int64   `json:"lastrecv"`
 	BytesSent      uint64  `json:"bytessent"`
 	BytesRecv      uint64  `json:"bytesrecv"`
+	SendRate       float64 `json:"sendrate"`
+	RecvRate       float64 `json:"recvrate"`
 	ConnTime       int64   `json:"conntime"`
 	TimeOffset     int64   `json:"timeoffset"`
 	PingTime       float64 `json:"pingtime"`
@@ -271,6 +467,8 @@ type GetPeerInfoResult struct {
 	CurrentHeight  int64   `json:"currentheight,omitempty"`
 	BanScore       int32   `json:"banscore"`
 	SyncNode       bool    `json:"syncnode"`
+	BlocksReceived uint64  `json:"blocksreceived"`
+	TxsReceived    uint64  `json:"txsreceived"`
 }
 
 // GetRawMempoolVerboseResult models the data returned from the getrawmempool
@@ -353,14 +551,36 @@ type GetStakeVersionsResult struct {
 	StakeVersions []StakeVersions `json:"stakeversions"`
 }
 
+// GetSubsidyScheduleResult models a single entry of the data returned from the
+// getsubsidyschedule command.
+type GetSubsidyScheduleResult struct {
+	Height    int64 `json:"height"`
+	Developer int64 `json:"developer"`
+	PoS       int64 `json:"pos"`
+	PoW       int64 `json:"pow"`
+	Total     int64 `json:"total"`
+}
+
+// GetTicketInfoResult models the data returned from the getticketinfo command.
+type GetTicketInfoResult struct {
+	Hash           string `json:"hash"`
+	Status         string `json:"status"`
+	BlockHash      string `json:"blockhash"`
+	BlockHeight    int64  `json:"blockheight"`
+	MaturityHeight int64  `json:"maturityheight"`
+	ExpiryHeight   int64  `json:"expiryheight"`
+}
+
 // GetTxOutResult models the data from the gettxout command.
 type GetTxOutResult struct {
-	BestBlock     string             `json:"bestblock"`
-	Confirmations int64              `json:"confirmations"`
-	Value         float64            `json:"value"`
-	ScriptPubKey  ScriptPubKeyResult `json:"scriptPubKey"`
-	Version       int32              `json:"version"`
-	Coinbase      bool               `json:"coinbase"`
+	BestBlock      string             `json:"bestblock"`
+	Confirmations  int64              `json:"confirmations"`
+	Value          float64            `json:"value"`
+	ScriptPubKey   ScriptPubKeyResult `json:"scriptPubKey"`
+	Version        int32              `json:"version"`
+	Coinbase       bool               `json:"coinbase"`
+	Spendable      bool               `json:"spendable"`
+	MaturityHeight int64              `json:"maturityheight,omitempty"`
 }
 
 // Choice models an individual choice inside an Agenda.
@@ -399,9 +619,26 @@ type GetVoteInfoResult struct {
 }
 
 // GetWorkResult models the data from the getwork command.
+//
+// The PreviousHash field is the hash of the block the work builds on which
+// allows miners to cheaply detect when in-progress work has become stale by
+// comparing it between requests.
 type GetWorkResult struct {
-	Data   string `json:"data"`
-	Target string `json:"target"`
+	Data         string `json:"data"`
+	Target       string `json:"target"`
+	PreviousHash string `json:"previousblockhash"`
+}
+
+// IsBlockFinalResult models the data returned from the isblockfinal command.
+//
+// The Confirmations field is -1 when the block is not in the main chain, in
+// which case it is never final.
+type IsBlockFinalResult struct {
+	Hash          string `json:"hash"`
+	Height        int64  `json:"height"`
+	Confirmations int64  `json:"confirmations"`
+	FinalityDepth uint32 `json:"finalitydepth"`
+	Final         bool   `json:"final"`
 }
 
 // Ticket is the structure representing a ticket.
@@ -424,45 +661,58 @@ type MissedTicketsResult struct {
 
 // FeeInfoBlock is ticket fee information about a block.
 type FeeInfoBlock struct {
-	Height uint32  `json:"height"`
-	Number uint32  `json:"number"`
-	Min    float64 `json:"min"`
-	Max    float64 `json:"max"`
-	Mean   float64 `json:"mean"`
-	Median float64 `json:"median"`
-	StdDev float64 `json:"stddev"`
+	Height      uint32              `json:"height"`
+	Number      uint32              `json:"number"`
+	Min         float64             `json:"min"`
+	Max         float64             `json:"max"`
+	Mean        float64             `json:"mean"`
+	Median      float64             `json:"median"`
+	StdDev      float64             `json:"stddev"`
+	Percentiles *FeeInfoPercentiles `json:"percentiles,omitempty"`
 }
 
 // FeeInfoMempool is ticket fee information about the mempool.
 type FeeInfoMempool struct {
-	Number uint32  `json:"number"`
-	Min    float64 `json:"min"`
-	Max    float64 `json:"max"`
-	Mean   float64 `json:"mean"`
-	Median float64 `json:"median"`
-	StdDev float64 `json:"stddev"`
+	Number      uint32              `json:"number"`
+	Min         float64             `json:"min"`
+	Max         float64             `json:"max"`
+	Mean        float64             `json:"mean"`
+	Median      float64             `json:"median"`
+	StdDev      float64             `json:"stddev"`
+	Percentiles *FeeInfoPercentiles `json:"percentiles,omitempty"`
+}
+
+// FeeInfoPercentiles is a percentile breakdown of the fees in a fee
+// information set.
+type FeeInfoPercentiles struct {
+	P10 float64 `json:"p10"`
+	P25 float64 `json:"p25"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
 }
 
 // FeeInfoRange is ticket fee information about a range.
 type FeeInfoRange struct {
-	Number uint32  `json:"number"`
-	Min    float64 `json:"min"`
-	Max    float64 `json:"max"`
-	Mean   float64 `json:"mean"`
-	Median float64 `json:"median"`
-	StdDev float64 `json:"stddev"`
+	Number      uint32              `json:"number"`
+	Min         float64             `json:"min"`
+	Max         float64             `json:"max"`
+	Mean        float64             `json:"mean"`
+	Median      float64             `json:"median"`
+	StdDev      float64             `json:"stddev"`
+	Percentiles *FeeInfoPercentiles `json:"percentiles,omitempty"`
 }
 
 // FeeInfoWindow is ticket fee information about an adjustment window.
 type FeeInfoWindow struct {
-	StartHeight uint32  `json:"startheight"`
-	EndHeight   uint32  `json:"endheight"`
-	Number      uint32  `json:"number"`
-	Min         float64 `json:"min"`
-	Max         float64 `json:"max"`
-	Mean        float64 `json:"mean"`
-	Median      float64 `json:"median"`
-	StdDev      float64 `json:"stddev"`
+	StartHeight uint32              `json:"startheight"`
+	EndHeight   uint32              `json:"endheight"`
+	Number      uint32              `json:"number"`
+	Min         float64             `json:"min"`
+	Max         float64             `json:"max"`
+	Mean        float64             `json:"mean"`
+	Median      float64             `json:"median"`
+	StdDev      float64             `json:"stddev"`
+	Percentiles *FeeInfoPercentiles `json:"percentiles,omitempty"`
 }
 
 // TicketFeeInfoResult models the data returned from the ticketfeeinfo command.
@@ -491,6 +741,15 @@ type SearchRawTransactionsResult struct {
 	Blocktime     int64        `json:"blocktime,omitempty"`
 }
 
+// SearchRawTransactionsTotalResult models the data from the
+// searchrawtransactions command when the includetotal flag is set.  Only one
+// of the Hex and Transactions fields is set depending on the verbose flag.
+type SearchRawTransactionsTotalResult struct {
+	Total        uint32                        `json:"total"`
+	Hex          []string                      `json:"hex,omitempty"`
+	Transactions []SearchRawTransactionsResult `json:"transactions,omitempty"`
+}
+
 // TxFeeInfoResult models the data returned from the ticketfeeinfo command.
 // command.
 type TxFeeInfoResult struct {
@@ -505,6 +764,12 @@ type TicketsForAddressResult struct {
 	Tickets []string `json:"tickets"`
 }
 
+// TicketsForAddressesResult models the data returned from the
+// ticketsforaddresses command.  The tickets are keyed by address.
+type TicketsForAddressesResult struct {
+	Tickets map[string][]string `json:"tickets"`
+}
+
 // ValidateAddressChainResult models the data returned by the chain server
 // validateaddress command.
 type ValidateAddressChainResult struct {
@@ -556,6 +821,7 @@ type Vin struct {
 	BlockHeight uint32     `json:"blockheight"`
 	BlockIndex  uint32     `json:"blockindex"`
 	ScriptSig   *ScriptSig `json:"scriptSig"`
+	PrevOut     *PrevOut   `json:"prevOut,omitempty"`
 }
 
 // IsCoinBase returns a bool to show if a Vin is a Coinbase one or not.
@@ -613,6 +879,7 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 		BlockHeight uint32     `json:"blockheight"`
 		BlockIndex  uint32     `json:"blockindex"`
 		ScriptSig   *ScriptSig `json:"scriptSig"`
+		PrevOut     *PrevOut   `json:"prevOut,omitempty"`
 	}{
 		Txid:        v.Txid,
 		Vout:        v.Vout,
@@ -622,6 +889,7 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 		BlockHeight: v.BlockHeight,
 		BlockIndex:  v.BlockIndex,
 		ScriptSig:   v.ScriptSig,
+		PrevOut:     v.PrevOut,
 	}
 	return json.Marshal(txStruct)
 }
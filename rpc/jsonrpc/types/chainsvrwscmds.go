@@ -35,10 +35,14 @@ type OutPoint struct {
 
 // LoadTxFilterCmd defines the loadtxfilter request parameters to load or
 // reload a transaction filter.
+//
+// The optional Tree field limits matches to transactions in the "regular" or
+// "stake" transaction tree, or matches both when it is "all".
 type LoadTxFilterCmd struct {
 	Reload    bool
 	Addresses []string
 	OutPoints []OutPoint
+	Tree      *string
 }
 
 // NewLoadTxFilterCmd returns a new instance which can be used to issue a
@@ -51,8 +55,25 @@ func NewLoadTxFilterCmd(reload bool, addresses []string, outPoints []OutPoint) *
 	}
 }
 
+// NewLoadTxFilterTreeCmd returns a new instance which can be used to issue a
+// loadtxfilter JSON-RPC command that only matches transactions in the
+// specified transaction tree.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewLoadTxFilterTreeCmd(reload bool, addresses []string, outPoints []OutPoint, tree *string) *LoadTxFilterCmd {
+	return &LoadTxFilterCmd{
+		Reload:    reload,
+		Addresses: addresses,
+		OutPoints: outPoints,
+		Tree:      tree,
+	}
+}
+
 // NotifyBlocksCmd defines the notifyblocks JSON-RPC command.
-type NotifyBlocksCmd struct{}
+type NotifyBlocksCmd struct {
+	SideChain *bool `jsonrpcdefault:"false"`
+}
 
 // NewNotifyBlocksCmd returns a new instance which can be used to issue a
 // notifyblocks JSON-RPC command.
@@ -60,6 +81,18 @@ func NewNotifyBlocksCmd() *NotifyBlocksCmd {
 	return &NotifyBlocksCmd{}
 }
 
+// NewNotifyBlocksSideChainCmd returns a new instance which can be used to
+// issue a notifyblocks JSON-RPC command that optionally also requests
+// notifications for blocks that are not added to the main chain.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewNotifyBlocksSideChainCmd(sideChain *bool) *NotifyBlocksCmd {
+	return &NotifyBlocksCmd{
+		SideChain: sideChain,
+	}
+}
+
 // NotifyWinningTicketsCmd is a type handling custom marshaling and
 // unmarshaling of notifywinningtickets JSON websocket extension
 // commands.
@@ -161,6 +194,15 @@ func NewRescanCmd(blockHashes []string) *RescanCmd {
 	return &RescanCmd{BlockHashes: blockHashes}
 }
 
+// CancelRescanCmd defines the cancelrescan JSON-RPC command.
+type CancelRescanCmd struct{}
+
+// NewCancelRescanCmd returns a new instance which can be used to issue a
+// cancelrescan JSON-RPC command.
+func NewCancelRescanCmd() *CancelRescanCmd {
+	return &CancelRescanCmd{}
+}
+
 func init() {
 	// The commands in this file are only usable by websockets.
 	flags := dcrjson.UFWebsocketOnly
@@ -180,4 +222,5 @@ func init() {
 	dcrjson.MustRegister(Method("stopnotifyblocks"), (*StopNotifyBlocksCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifynewtransactions"), (*StopNotifyNewTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rescan"), (*RescanCmd)(nil), flags)
+	dcrjson.MustRegister(Method("cancelrescan"), (*CancelRescanCmd)(nil), flags)
 }
@@ -93,8 +93,23 @@ func TestChainSvrWsCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewNotifyBlocksCmd()
 			},
-			marshalled:   `{"jsonrpc":"1.0","method":"notifyblocks","params":[],"id":1}`,
-			unmarshalled: &NotifyBlocksCmd{},
+			marshalled: `{"jsonrpc":"1.0","method":"notifyblocks","params":[],"id":1}`,
+			unmarshalled: &NotifyBlocksCmd{
+				SideChain: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "notifyblocks optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifyblocks"), true)
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyBlocksSideChainCmd(dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"notifyblocks","params":[true],"id":1}`,
+			unmarshalled: &NotifyBlocksCmd{
+				SideChain: dcrjson.Bool(true),
+			},
 		},
 		{
 			name: "stopnotifyblocks",
@@ -144,6 +159,45 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifynewtransactions","params":[],"id":1}`,
 			unmarshalled: &StopNotifyNewTransactionsCmd{},
 		},
+		{
+			name: "loadtxfilter",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("loadtxfilter"), false,
+					[]string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+					[]OutPoint{{Hash: "123", Tree: 0, Index: 1}})
+			},
+			staticCmd: func() interface{} {
+				return NewLoadTxFilterCmd(false,
+					[]string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+					[]OutPoint{{Hash: "123", Tree: 0, Index: 1}})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"loadtxfilter","params":[false,["DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"],[{"hash":"123","tree":0,"index":1}]],"id":1}`,
+			unmarshalled: &LoadTxFilterCmd{
+				Reload:    false,
+				Addresses: []string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+				OutPoints: []OutPoint{{Hash: "123", Tree: 0, Index: 1}},
+			},
+		},
+		{
+			name: "loadtxfilter optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("loadtxfilter"), true,
+					[]string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+					[]OutPoint{}, "stake")
+			},
+			staticCmd: func() interface{} {
+				return NewLoadTxFilterTreeCmd(true,
+					[]string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+					[]OutPoint{}, dcrjson.String("stake"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"loadtxfilter","params":[true,["DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"],[],"stake"],"id":1}`,
+			unmarshalled: &LoadTxFilterCmd{
+				Reload:    true,
+				Addresses: []string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+				OutPoints: []OutPoint{},
+				Tree:      dcrjson.String("stake"),
+			},
+		},
 		{
 			name: "rescan",
 			newCmd: func() (interface{}, error) {
@@ -157,6 +211,17 @@ func TestChainSvrWsCmds(t *testing.T) {
 				BlockHashes: []string{"0000000000000000000000000000000000000000000000000000000000000123"},
 			},
 		},
+		{
+			name: "cancelrescan",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("cancelrescan"))
+			},
+			staticCmd: func() interface{} {
+				return NewCancelRescanCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"cancelrescan","params":[],"id":1}`,
+			unmarshalled: &CancelRescanCmd{},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
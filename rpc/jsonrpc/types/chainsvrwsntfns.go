@@ -36,6 +36,11 @@ const (
 	// more details in the notification.
 	TxAcceptedVerboseNtfnMethod Method = "txacceptedverbose"
 
+	// SideChainBlockAcceptedNtfnMethod is the method used for notifications
+	// from the chain server that a block has been accepted into a side
+	// chain without being connected to the main chain.
+	SideChainBlockAcceptedNtfnMethod Method = "sidechainblockaccepted"
+
 	// RelevantTxAcceptedNtfnMethod is the method used for notifications
 	// from the chain server that inform a client that a relevant
 	// transaction was accepted by the mempool.
@@ -121,6 +126,23 @@ func NewReorganizationNtfn(oldHash string, oldHeight int32, newHash string,
 	}
 }
 
+// SideChainBlockAcceptedNtfn defines the sidechainblockaccepted JSON-RPC
+// notification.  The ForkLen field is the length of the side chain the block
+// created or extended.
+type SideChainBlockAcceptedNtfn struct {
+	Header  string `json:"header"`
+	ForkLen int64  `json:"forklen"`
+}
+
+// NewSideChainBlockAcceptedNtfn returns a new instance which can be used to
+// issue a sidechainblockaccepted JSON-RPC notification.
+func NewSideChainBlockAcceptedNtfn(header string, forkLen int64) *SideChainBlockAcceptedNtfn {
+	return &SideChainBlockAcceptedNtfn{
+		Header:  header,
+		ForkLen: forkLen,
+	}
+}
+
 // SpentAndMissedTicketsNtfn is a type handling custom marshaling and
 // unmarshaling of spentandmissedtickets JSON websocket notifications.
 type SpentAndMissedTicketsNtfn struct {
@@ -142,10 +164,16 @@ func NewSpentAndMissedTicketsNtfn(hash string, height int32, stakeDiff int64, ti
 
 // StakeDifficultyNtfn is a type handling custom marshaling and
 // unmarshaling of stakedifficulty JSON websocket notifications.
+//
+// The StakeDiff field is the stake difficulty required for the block after the
+// notified block while the EstimatedStakeDiff field is the expected stake
+// difficulty of the next stake difficulty retarget interval.  The estimate is
+// omitted when the chain is not current.
 type StakeDifficultyNtfn struct {
-	BlockHash   string
-	BlockHeight int32
-	StakeDiff   int64
+	BlockHash          string
+	BlockHeight        int32
+	StakeDiff          int64
+	EstimatedStakeDiff *int64
 }
 
 // NewStakeDifficultyNtfn creates a new StakeDifficultyNtfn.
@@ -157,6 +185,17 @@ func NewStakeDifficultyNtfn(hash string, height int32, stakeDiff int64) *StakeDi
 	}
 }
 
+// NewStakeDifficultyEstimateNtfn creates a new StakeDifficultyNtfn that
+// includes the estimated stake difficulty of the next retarget interval.
+func NewStakeDifficultyEstimateNtfn(hash string, height int32, stakeDiff, estimatedStakeDiff int64) *StakeDifficultyNtfn {
+	return &StakeDifficultyNtfn{
+		BlockHash:          hash,
+		BlockHeight:        height,
+		StakeDiff:          stakeDiff,
+		EstimatedStakeDiff: &estimatedStakeDiff,
+	}
+}
+
 // TxAcceptedNtfn defines the txaccepted JSON-RPC notification.
 type TxAcceptedNtfn struct {
 	TxID   string  `json:"txid"`
@@ -226,6 +265,7 @@ func init() {
 	dcrjson.MustRegister(TxAcceptedNtfnMethod, (*TxAcceptedNtfn)(nil), flags)
 	dcrjson.MustRegister(TxAcceptedVerboseNtfnMethod, (*TxAcceptedVerboseNtfn)(nil), flags)
 	dcrjson.MustRegister(RelevantTxAcceptedNtfnMethod, (*RelevantTxAcceptedNtfn)(nil), flags)
+	dcrjson.MustRegister(SideChainBlockAcceptedNtfnMethod, (*SideChainBlockAcceptedNtfn)(nil), flags)
 	dcrjson.MustRegister(SpentAndMissedTicketsNtfnMethod, (*SpentAndMissedTicketsNtfn)(nil), flags)
 	dcrjson.MustRegister(StakeDifficultyNtfnMethod, (*StakeDifficultyNtfn)(nil), flags)
 	dcrjson.MustRegister(WinningTicketsNtfnMethod, (*WinningTicketsNtfn)(nil), flags)
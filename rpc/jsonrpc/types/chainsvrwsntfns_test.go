@@ -85,6 +85,20 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Transaction: "001122",
 			},
 		},
+		{
+			name: "sidechainblockaccepted",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("sidechainblockaccepted"), "header", 2)
+			},
+			staticNtfn: func() interface{} {
+				return NewSideChainBlockAcceptedNtfn("header", 2)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"sidechainblockaccepted","params":["header",2],"id":null}`,
+			unmarshalled: &SideChainBlockAcceptedNtfn{
+				Header:  "header",
+				ForkLen: 2,
+			},
+		},
 		{
 			name: "spentandmissedtickets",
 			newNtfn: func() (interface{}, error) {
@@ -101,6 +115,37 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Tickets:   map[string]string{"a": "b"},
 			},
 		},
+		{
+			name: "stakedifficulty",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stakedifficulty"), "123", 100, 3)
+			},
+			staticNtfn: func() interface{} {
+				return NewStakeDifficultyNtfn("123", 100, 3)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"stakedifficulty","params":["123",100,3],"id":null}`,
+			unmarshalled: &StakeDifficultyNtfn{
+				BlockHash:   "123",
+				BlockHeight: 100,
+				StakeDiff:   3,
+			},
+		},
+		{
+			name: "stakedifficulty estimated",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stakedifficulty"), "123", 100, 3, 4)
+			},
+			staticNtfn: func() interface{} {
+				return NewStakeDifficultyEstimateNtfn("123", 100, 3, 4)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"stakedifficulty","params":["123",100,3,4],"id":null}`,
+			unmarshalled: &StakeDifficultyNtfn{
+				BlockHash:          "123",
+				BlockHeight:        100,
+				StakeDiff:          3,
+				EstimatedStakeDiff: dcrjson.Int64(4),
+			},
+		},
 		{
 			name: "txaccepted",
 			newNtfn: func() (interface{}, error) {
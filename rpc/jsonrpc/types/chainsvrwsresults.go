@@ -11,8 +11,13 @@ type SessionResult struct {
 }
 
 // RescanResult models the result object returned by the rescan RPC.
+//
+// The Cancelled field is set when the rescan was stopped early by the
+// cancelrescan RPC, in which case the discovered data only covers the blocks
+// that were rescanned before it was cancelled.
 type RescanResult struct {
 	DiscoveredData []RescannedBlock `json:"discovereddata"`
+	Cancelled      bool             `json:"cancelled,omitempty"`
 }
 
 // RescannedBlock contains the hash and all discovered transactions of a single
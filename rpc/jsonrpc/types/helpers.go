@@ -12,3 +12,12 @@ func EstimateSmartFeeModeAddr(v EstimateSmartFeeMode) *EstimateSmartFeeMode {
 	*p = v
 	return p
 }
+
+// GetPeerInfoDirectionAddr is a helper routine that allocates a new
+// GetPeerInfoDirection value to store v and returns a pointer to it.  This is
+// useful when assigning optional parameters.
+func GetPeerInfoDirectionAddr(v GetPeerInfoDirection) *GetPeerInfoDirection {
+	p := new(GetPeerInfoDirection)
+	*p = v
+	return p
+}
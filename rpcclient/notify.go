@@ -838,7 +838,9 @@ func parseStakeDifficultyNtfnParams(params []json.RawMessage) (
 	int64,
 	error) {
 
-	if len(params) != 3 {
+	// The fourth parameter containing the estimated stake difficulty of the
+	// next retarget interval is optional and not currently surfaced.
+	if len(params) != 3 && len(params) != 4 {
 		return nil, 0, 0, wrongNumParams(len(params))
 	}
 
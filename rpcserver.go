@@ -11,6 +11,7 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
@@ -24,8 +25,11 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httputil"
 	"os"
+	"path/filepath"
 	"runtime"
+	"runtime/pprof"
 	"sort"
 	"strconv"
 	"strings"
@@ -35,6 +39,7 @@ import (
 
 	"github.com/gorilla/websocket"
 
+	"github.com/decred/dcrd/addrmgr"
 	"github.com/decred/dcrd/blockchain/stake/v2"
 	"github.com/decred/dcrd/blockchain/standalone"
 	"github.com/decred/dcrd/blockchain/v2"
@@ -45,6 +50,7 @@ import (
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrd/fees/v2"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/mempool/v3"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
@@ -90,6 +96,32 @@ const (
 	// the template pool.
 	getworkExpirationDiff = 3
 
+	// maxGetBlockHashesRange is the maximum number of block hashes that may
+	// be requested by a single getblockhashes command.
+	maxGetBlockHashesRange = 2000
+
+	// maxSubsidyScheduleEntries is the maximum number of subsidy reduction
+	// boundaries that may be returned by a single getsubsidyschedule
+	// command.
+	maxSubsidyScheduleEntries = 1000
+
+	// maxSubsidyScheduleHeight is the maximum end height that may be
+	// requested by a getsubsidyschedule command.  It is the maximum height
+	// that can be represented by a block header.
+	maxSubsidyScheduleHeight = math.MaxUint32
+
+	// maxTicketPoolValueCacheEntries is the maximum number of blocks to
+	// cache the ticket pool values of for the getticketpoolvalue command.
+	maxTicketPoolValueCacheEntries = 100
+
+	// maxTicketsForAddresses is the maximum number of addresses that may be
+	// requested by a single ticketsforaddresses command.
+	maxTicketsForAddresses = 100
+
+	// maxDebugDumpCPUProfileSecs is the maximum number of seconds a CPU
+	// profile requested by the debugdump command may run for.
+	maxDebugDumpCPUProfileSecs = 300
+
 	// sstxCommitmentString is the string to insert when a verbose
 	// transaction output's pkscript type is a ticket commitment.
 	sstxCommitmentString = "sstxcommitment"
@@ -130,77 +162,107 @@ type commandHandler func(*rpcServer, interface{}, <-chan struct{}) (interface{},
 // a dependency loop.
 var rpcHandlers map[types.Method]commandHandler
 var rpcHandlersBeforeInit = map[types.Method]commandHandler{
-	"addnode":               handleAddNode,
-	"createrawsstx":         handleCreateRawSStx,
-	"createrawssrtx":        handleCreateRawSSRtx,
-	"createrawtransaction":  handleCreateRawTransaction,
-	"debuglevel":            handleDebugLevel,
-	"decoderawtransaction":  handleDecodeRawTransaction,
-	"decodescript":          handleDecodeScript,
-	"estimatefee":           handleEstimateFee,
-	"estimatesmartfee":      handleEstimateSmartFee,
-	"estimatestakediff":     handleEstimateStakeDiff,
-	"existsaddress":         handleExistsAddress,
-	"existsaddresses":       handleExistsAddresses,
-	"existsexpiredtickets":  handleExistsExpiredTickets,
-	"existsliveticket":      handleExistsLiveTicket,
-	"existslivetickets":     handleExistsLiveTickets,
-	"existsmempooltxs":      handleExistsMempoolTxs,
-	"existsmissedtickets":   handleExistsMissedTickets,
-	"generate":              handleGenerate,
-	"getaddednodeinfo":      handleGetAddedNodeInfo,
-	"getbestblock":          handleGetBestBlock,
-	"getbestblockhash":      handleGetBestBlockHash,
-	"getblock":              handleGetBlock,
-	"getblockchaininfo":     handleGetBlockchainInfo,
-	"getblockcount":         handleGetBlockCount,
-	"getblockhash":          handleGetBlockHash,
-	"getblockheader":        handleGetBlockHeader,
-	"getblocksubsidy":       handleGetBlockSubsidy,
-	"getcfilter":            handleGetCFilter,
-	"getcfilterheader":      handleGetCFilterHeader,
-	"getchaintips":          handleGetChainTips,
-	"getcoinsupply":         handleGetCoinSupply,
-	"getconnectioncount":    handleGetConnectionCount,
-	"getcurrentnet":         handleGetCurrentNet,
-	"getdifficulty":         handleGetDifficulty,
-	"getgenerate":           handleGetGenerate,
-	"gethashespersec":       handleGetHashesPerSec,
-	"getheaders":            handleGetHeaders,
-	"getinfo":               handleGetInfo,
-	"getmempoolinfo":        handleGetMempoolInfo,
-	"getmininginfo":         handleGetMiningInfo,
-	"getnettotals":          handleGetNetTotals,
-	"getnetworkhashps":      handleGetNetworkHashPS,
-	"getnetworkinfo":        handleGetNetworkInfo,
-	"getpeerinfo":           handleGetPeerInfo,
-	"getrawmempool":         handleGetRawMempool,
-	"getrawtransaction":     handleGetRawTransaction,
-	"getstakedifficulty":    handleGetStakeDifficulty,
-	"getstakeversioninfo":   handleGetStakeVersionInfo,
-	"getstakeversions":      handleGetStakeVersions,
-	"getticketpoolvalue":    handleGetTicketPoolValue,
-	"getvoteinfo":           handleGetVoteInfo,
-	"gettxout":              handleGetTxOut,
-	"getwork":               handleGetWork,
-	"help":                  handleHelp,
-	"livetickets":           handleLiveTickets,
-	"missedtickets":         handleMissedTickets,
-	"node":                  handleNode,
-	"ping":                  handlePing,
-	"searchrawtransactions": handleSearchRawTransactions,
-	"sendrawtransaction":    handleSendRawTransaction,
-	"setgenerate":           handleSetGenerate,
-	"stop":                  handleStop,
-	"submitblock":           handleSubmitBlock,
-	"ticketfeeinfo":         handleTicketFeeInfo,
-	"ticketsforaddress":     handleTicketsForAddress,
-	"ticketvwap":            handleTicketVWAP,
-	"txfeeinfo":             handleTxFeeInfo,
-	"validateaddress":       handleValidateAddress,
-	"verifychain":           handleVerifyChain,
-	"verifymessage":         handleVerifyMessage,
-	"version":               handleVersion,
+	"addnode":                handleAddNode,
+	"addpeeraddresses":       handleAddPeerAddresses,
+	"bansubnet":              handleBanSubnet,
+	"createrawsstx":          handleCreateRawSStx,
+	"createrawssrtx":         handleCreateRawSSRtx,
+	"createrawtransaction":   handleCreateRawTransaction,
+	"debugdump":              handleDebugDump,
+	"debuglevel":             handleDebugLevel,
+	"decoderawtransaction":   handleDecodeRawTransaction,
+	"decodescript":           handleDecodeScript,
+	"decodeticket":           handleDecodeTicket,
+	"dropallpeers":           handleDropAllPeers,
+	"estimatefee":            handleEstimateFee,
+	"estimatesmartfee":       handleEstimateSmartFee,
+	"estimatestakediff":      handleEstimateStakeDiff,
+	"existsaddress":          handleExistsAddress,
+	"existsaddresses":        handleExistsAddresses,
+	"existsexpiredtickets":   handleExistsExpiredTickets,
+	"existsliveticket":       handleExistsLiveTicket,
+	"existslivetickets":      handleExistsLiveTickets,
+	"existsmempooltxs":       handleExistsMempoolTxs,
+	"existsmissedtickets":    handleExistsMissedTickets,
+	"generate":               handleGenerate,
+	"getaddednodeinfo":       handleGetAddedNodeInfo,
+	"getaddrmaninfo":         handleGetAddrManInfo,
+	"getbannedpeers":         handleGetBannedPeers,
+	"getbestblock":           handleGetBestBlock,
+	"getbestblockhash":       handleGetBestBlockHash,
+	"getblock":               handleGetBlock,
+	"getblockchaininfo":      handleGetBlockchainInfo,
+	"getblockcount":          handleGetBlockCount,
+	"getblockhash":           handleGetBlockHash,
+	"getblockhashes":         handleGetBlockHashes,
+	"getblockheader":         handleGetBlockHeader,
+	"getblockheaderbyheight": handleGetBlockHeaderByHeight,
+	"getblocksubsidy":        handleGetBlockSubsidy,
+	"getcfilter":             handleGetCFilter,
+	"getcfilterheader":       handleGetCFilterHeader,
+	"getchaintips":           handleGetChainTips,
+	"getcoinbasetemplate":    handleGetCoinbaseTemplate,
+	"getcoinsupply":          handleGetCoinSupply,
+	"getcompactblock":        handleGetCompactBlock,
+	"getconfig":              handleGetConfig,
+	"getconnectioncount":     handleGetConnectionCount,
+	"getconnmgrstats":        handleGetConnMgrStats,
+	"getcurrentnet":          handleGetCurrentNet,
+	"getdifficulty":          handleGetDifficulty,
+	"getdifficultyinfo":      handleGetDifficultyInfo,
+	"getgenerate":            handleGetGenerate,
+	"gethashespersec":        handleGetHashesPerSec,
+	"getheaders":             handleGetHeaders,
+	"getinfo":                handleGetInfo,
+	"getknownaddresses":      handleGetKnownAddresses,
+	"getlocaladdresses":      handleGetLocalAddresses,
+	"getmemoryinfo":          handleGetMemoryInfo,
+	"getmempoolancestors":    handleGetMempoolAncestors,
+	"getmempooldescendants":  handleGetMempoolDescendants,
+	"getmempoolentry":        handleGetMempoolEntry,
+	"getmempoolinfo":         handleGetMempoolInfo,
+	"getmininginfo":          handleGetMiningInfo,
+	"getnettotals":           handleGetNetTotals,
+	"getnetworkhashps":       handleGetNetworkHashPS,
+	"getnetworkinfo":         handleGetNetworkInfo,
+	"getnodeaddresses":       handleGetNodeAddresses,
+	"getorphantxs":           handleGetOrphanTxs,
+	"getpeerinfo":            handleGetPeerInfo,
+	"getrawmempool":          handleGetRawMempool,
+	"getrawtransaction":      handleGetRawTransaction,
+	"getstakedifficulty":     handleGetStakeDifficulty,
+	"getstakeversioninfo":    handleGetStakeVersionInfo,
+	"getstakeversions":       handleGetStakeVersions,
+	"getsubsidyschedule":     handleGetSubsidySchedule,
+	"getticketinfo":          handleGetTicketInfo,
+	"getticketpoolvalue":     handleGetTicketPoolValue,
+	"getvoteinfo":            handleGetVoteInfo,
+	"gettxout":               handleGetTxOut,
+	"getwork":                handleGetWork,
+	"help":                   handleHelp,
+	"isblockfinal":           handleIsBlockFinal,
+	"livetickets":            handleLiveTickets,
+	"missedtickets":          handleMissedTickets,
+	"node":                   handleNode,
+	"ping":                   handlePing,
+	"relayblock":             handleRelayBlock,
+	"reloadwhitelists":       handleReloadWhitelists,
+	"restart":                handleRestart,
+	"searchrawtransactions":  handleSearchRawTransactions,
+	"sendrawtransaction":     handleSendRawTransaction,
+	"setban":                 handleSetBan,
+	"setgenerate":            handleSetGenerate,
+	"stop":                   handleStop,
+	"submitblock":            handleSubmitBlock,
+	"ticketfeeinfo":          handleTicketFeeInfo,
+	"ticketsforaddress":      handleTicketsForAddress,
+	"ticketsforaddresses":    handleTicketsForAddresses,
+	"ticketvwap":             handleTicketVWAP,
+	"txfeeinfo":              handleTxFeeInfo,
+	"validateaddress":        handleValidateAddress,
+	"verifychain":            handleVerifyChain,
+	"verifymessage":          handleVerifyMessage,
+	"version":                handleVersion,
 }
 
 // list of commands that we recognize, but for which dcrd has no support because
@@ -287,6 +349,7 @@ var rpcLimited = map[string]struct{}{
 	"notifyreceived":        {},
 	"notifyspent":           {},
 	"rescan":                {},
+	"cancelrescan":          {},
 	"session":               {},
 	"rebroadcastmissed":     {},
 	"rebroadcastwinners":    {},
@@ -295,58 +358,72 @@ var rpcLimited = map[string]struct{}{
 	"help": {},
 
 	// HTTP/S-only commands
-	"createrawsstx":         {},
-	"createrawssrtx":        {},
-	"createrawtransaction":  {},
-	"decoderawtransaction":  {},
-	"decodescript":          {},
-	"estimatefee":           {},
-	"estimatesmartfee":      {},
-	"estimatestakediff":     {},
-	"existsaddress":         {},
-	"existsaddresses":       {},
-	"existsexpiredtickets":  {},
-	"existsliveticket":      {},
-	"existslivetickets":     {},
-	"existsmempooltxs":      {},
-	"existsmissedtickets":   {},
-	"getbestblock":          {},
-	"getbestblockhash":      {},
-	"getblock":              {},
-	"getblockchaininfo":     {},
-	"getblockcount":         {},
-	"getblockhash":          {},
-	"getblockheader":        {},
-	"getblocksubsidy":       {},
-	"getcfilter":            {},
-	"getchaintips":          {},
-	"getcoinsupply":         {},
-	"getcurrentnet":         {},
-	"getdifficulty":         {},
-	"getheaders":            {},
-	"getinfo":               {},
-	"getnettotals":          {},
-	"getnetworkhashps":      {},
-	"getnetworkinfo":        {},
-	"getrawmempool":         {},
-	"getstakedifficulty":    {},
-	"getstakeversioninfo":   {},
-	"getstakeversions":      {},
-	"getrawtransaction":     {},
-	"gettxout":              {},
-	"getvoteinfo":           {},
-	"livetickets":           {},
-	"missedtickets":         {},
-	"searchrawtransactions": {},
-	"sendrawtransaction":    {},
-	"submitblock":           {},
-	"ticketfeeinfo":         {},
-	"ticketsforaddress":     {},
-	"ticketvwap":            {},
-	"txfeeinfo":             {},
-	"validateaddress":       {},
-	"verifymessage":         {},
-	"version":               {},
+	"createrawsstx":          {},
+	"createrawssrtx":         {},
+	"createrawtransaction":   {},
+	"decoderawtransaction":   {},
+	"decodescript":           {},
+	"decodeticket":           {},
+	"estimatefee":            {},
+	"estimatesmartfee":       {},
+	"estimatestakediff":      {},
+	"existsaddress":          {},
+	"existsaddresses":        {},
+	"existsexpiredtickets":   {},
+	"existsliveticket":       {},
+	"existslivetickets":      {},
+	"existsmempooltxs":       {},
+	"existsmissedtickets":    {},
+	"getbestblock":           {},
+	"getbestblockhash":       {},
+	"getblock":               {},
+	"getblockchaininfo":      {},
+	"getblockcount":          {},
+	"getblockhash":           {},
+	"getblockhashes":         {},
+	"getblockheader":         {},
+	"getblockheaderbyheight": {},
+	"getblocksubsidy":        {},
+	"getcfilter":             {},
+	"getchaintips":           {},
+	"getcoinsupply":          {},
+	"getcompactblock":        {},
+	"getcurrentnet":          {},
+	"getdifficulty":          {},
+	"getdifficultyinfo":      {},
+	"getheaders":             {},
+	"getinfo":                {},
+	"getlocaladdresses":      {},
+	"getmempoolancestors":    {},
+	"getmempooldescendants":  {},
+	"getmempoolentry":        {},
+	"getnettotals":           {},
+	"getnetworkhashps":       {},
+	"getnetworkinfo":         {},
+	"getorphantxs":           {},
+	"getrawmempool":          {},
+	"getstakedifficulty":     {},
+	"getstakeversioninfo":    {},
+	"getstakeversions":       {},
+	"getsubsidyschedule":     {},
+	"getticketinfo":          {},
+	"getrawtransaction":      {},
+	"gettxout":               {},
+	"getvoteinfo":            {},
+	"isblockfinal":           {},
+	"livetickets":            {},
+	"missedtickets":          {},
+	"searchrawtransactions":  {},
+	"sendrawtransaction":     {},
+	"submitblock":            {},
+	"ticketfeeinfo":          {},
+	"ticketsforaddress":      {},
+	"ticketsforaddresses":    {},
+	"ticketvwap":             {},
+	"txfeeinfo":              {},
+	"validateaddress":        {},
+	"verifymessage":          {},
+	"version":                {},
 }
 
 // rpcInternalError is a convenience function to convert an internal error to
@@ -487,6 +564,112 @@ func handleAddNode(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (in
 	return nil, nil
 }
 
+// handleAddPeerAddresses handles addpeeraddresses commands.
+func handleAddPeerAddresses(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.AddPeerAddressesCmd)
+	if len(c.Addresses) == 0 {
+		return nil, rpcInvalidError("No addresses specified")
+	}
+
+	// Validate all of the addresses before adding any of them so that an
+	// invalid entry does not result in only some of them being added.
+	maxTimestamp := time.Now().Add(10 * time.Minute)
+	netAddrs := make([]*wire.NetAddress, 0, len(c.Addresses))
+	for _, peerAddr := range c.Addresses {
+		addr := normalizeAddress(peerAddr.Address,
+			s.server.chainParams.DefaultPort)
+		host, portStr, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, rpcInvalidError("Invalid address %q: %v",
+				peerAddr.Address, err)
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || port == 0 {
+			return nil, rpcInvalidError("Invalid port for address %q",
+				peerAddr.Address)
+		}
+
+		// Assume the address is a full node when no services are
+		// specified.
+		services := wire.ServiceFlag(peerAddr.Services)
+		if services == 0 {
+			services = wire.SFNodeNetwork
+		}
+		na, err := s.server.addrManager.HostToNetAddress(host,
+			uint16(port), services)
+		if err != nil {
+			return nil, rpcInvalidError("Unable to resolve address %q: %v",
+				peerAddr.Address, err)
+		}
+		if !addrmgr.IsRoutable(na) {
+			return nil, rpcInvalidError("Address %q is not routable",
+				peerAddr.Address)
+		}
+
+		// Use the provided timestamp when specified while rejecting
+		// those from the future since the address manager considers
+		// them bad.
+		if peerAddr.Time != 0 {
+			timestamp := time.Unix(peerAddr.Time, 0)
+			if timestamp.After(maxTimestamp) {
+				return nil, rpcInvalidError("Timestamp for address %q "+
+					"is in the future", peerAddr.Address)
+			}
+			na.Timestamp = timestamp
+		}
+		netAddrs = append(netAddrs, na)
+	}
+
+	// Add the addresses using themselves as the source since they were not
+	// learned from another peer.
+	for _, na := range netAddrs {
+		s.server.addrManager.AddAddress(na, na)
+	}
+
+	return nil, nil
+}
+
+// parseSubnet parses the provided subnet in CIDR notation into an IP network.
+// A single IP address is treated as a subnet that only contains that address.
+func parseSubnet(subnet string) (*net.IPNet, error) {
+	if ip := net.ParseIP(subnet); ip != nil {
+		bits := net.IPv6len * 8
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+			bits = net.IPv4len * 8
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, err
+	}
+	return ipNet, nil
+}
+
+// handleBanSubnet handles bansubnet commands.
+func handleBanSubnet(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.BanSubnetCmd)
+
+	ipNet, err := parseSubnet(c.Subnet)
+	if err != nil {
+		return nil, rpcInvalidError("Invalid subnet %q: %v", c.Subnet, err)
+	}
+
+	// Use the configured ban duration when not specified.
+	duration := cfg.BanDuration
+	if c.Duration != nil && *c.Duration != 0 {
+		if *c.Duration < 0 {
+			return nil, rpcInvalidError("Ban duration may not be negative")
+		}
+		duration = time.Duration(*c.Duration) * time.Second
+	}
+
+	s.server.BanSubnet(ipNet, duration)
+	return nil, nil
+}
+
 // handleNode handles node commands.
 func handleNode(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*types.NodeCmd)
@@ -1022,6 +1205,92 @@ func handleCreateRawSSRtx(s *rpcServer, cmd interface{}, closeChan <-chan struct
 	return mtxHex, nil
 }
 
+// handleDebugDump handles debugdump commands.
+func handleDebugDump(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.DebugDumpCmd)
+
+	if !cfg.RPCDebugDump {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCMisc,
+			Message: "The debugdump RPC is disabled -- restart with " +
+				"--rpcdebugdump to enable it",
+		}
+	}
+
+	var result types.DebugDumpResult
+	if c.Profile != nil {
+		seconds := 30
+		if c.Seconds != nil {
+			seconds = *c.Seconds
+		}
+		if seconds < 1 || seconds > maxDebugDumpCPUProfileSecs {
+			return nil, rpcInvalidError("Seconds must be between 1 and "+
+				"%d -- got %d", maxDebugDumpCPUProfileSecs, seconds)
+		}
+
+		profile := *c.Profile
+		fileName := filepath.Join(cfg.DataDir, fmt.Sprintf("%s-%s.pprof",
+			profile, time.Now().UTC().Format("20060102T150405Z")))
+		switch profile {
+		case "cpu":
+			f, err := os.Create(fileName)
+			if err != nil {
+				return nil, rpcInternalError(err.Error(),
+					"Unable to create profile file")
+			}
+			if err := pprof.StartCPUProfile(f); err != nil {
+				f.Close()
+				os.Remove(fileName)
+				return nil, rpcInternalError(err.Error(),
+					"Unable to start CPU profile")
+			}
+			select {
+			case <-time.After(time.Duration(seconds) * time.Second):
+			case <-closeChan:
+			}
+			pprof.StopCPUProfile()
+			if err := f.Close(); err != nil {
+				return nil, rpcInternalError(err.Error(),
+					"Unable to write CPU profile")
+			}
+
+		case "heap":
+			f, err := os.Create(fileName)
+			if err != nil {
+				return nil, rpcInternalError(err.Error(),
+					"Unable to create profile file")
+			}
+
+			// Run a collection first so the profile reflects the current
+			// live heap rather than the state as of the last collection.
+			runtime.GC()
+			err = pprof.WriteHeapProfile(f)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return nil, rpcInternalError(err.Error(),
+					"Unable to write heap profile")
+			}
+
+		default:
+			return nil, rpcInvalidError("Invalid profile %q -- must be "+
+				"cpu or heap", profile)
+		}
+		rpcsLog.Infof("Wrote %s profile to %s", profile, fileName)
+		result.ProfileFile = fileName
+	}
+
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Unable to dump goroutines")
+	}
+	result.Goroutines = buf.String()
+
+	return &result, nil
+}
+
 // handleDebugLevel handles debuglevel commands.
 func handleDebugLevel(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*types.DebugLevelCmd)
@@ -1307,11 +1576,138 @@ func handleDecodeScript(s *rpcServer, cmd interface{}, closeChan <-chan struct{}
 	return reply, nil
 }
 
+// ticketFeeLimit converts the provided encoded fee limit of a ticket
+// commitment to the maximum fee in coins a vote or revocation is permitted to
+// pay from the associated output.  Nil is returned when the entire output may
+// be used as a fee.
+func ticketFeeLimit(hasLimit bool, limitLog2 uint16) *float64 {
+	// Outputs without a fee limit must pay exactly the calculated amount.
+	if !hasLimit {
+		limit := 0.0
+		return &limit
+	}
+
+	// The limit is a log2 value, so anything greater than or equal to 63
+	// allows the entire amount to be spent as a fee.
+	if limitLog2 >= 63 {
+		return nil
+	}
+	limit := dcrutil.Amount(1 << limitLog2).ToCoin()
+	return &limit
+}
+
+// handleDecodeTicket implements the decodeticket command.
+func handleDecodeTicket(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.DecodeTicketCmd)
+
+	// The ticket may either be specified by its hash, in which case it is
+	// loaded from the mempool or the transaction index, or directly as a
+	// serialized transaction.
+	var mtx *wire.MsgTx
+	if len(c.Ticket) == chainhash.MaxHashStringSize {
+		txHash, err := chainhash.NewHashFromStr(c.Ticket)
+		if err != nil {
+			return nil, rpcDecodeHexError(c.Ticket)
+		}
+		tx, err := s.server.txMemPool.FetchTransaction(txHash)
+		if err == nil {
+			mtx = tx.MsgTx()
+		} else {
+			mtx, _, err = fetchIndexedTx(s, txHash)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		hexStr := c.Ticket
+		if len(hexStr)%2 != 0 {
+			hexStr = "0" + hexStr
+		}
+		serializedTx, err := hex.DecodeString(hexStr)
+		if err != nil {
+			return nil, rpcDecodeHexError(hexStr)
+		}
+		mtx = new(wire.MsgTx)
+		err = mtx.Deserialize(bytes.NewReader(serializedTx))
+		if err != nil {
+			return nil, rpcDeserializationError("Could not decode Tx: %v",
+				err)
+		}
+	}
+	if err := stake.CheckSStx(mtx); err != nil {
+		return nil, rpcInvalidError("Transaction %v is not a ticket: %v",
+			mtx.TxHash(), err)
+	}
+
+	// The voting rights of the ticket are given by the stake submission
+	// output.
+	params := s.server.chainParams
+	submission := mtx.TxOut[0]
+	_, addrs, _, _ := txscript.ExtractPkScriptAddrs(submission.Version,
+		submission.PkScript, params)
+	var votingAddr string
+	if len(addrs) > 0 {
+		votingAddr = addrs[0].Address()
+	}
+
+	// Decode the commitment outputs along with their associated change
+	// outputs.  The ticket was already checked for sanity above, so the
+	// commitment scripts are well formed.
+	_, _, _, _, spendRules, spendLimits := stake.TxSStxStakeOutputInfo(mtx)
+	commitments := make([]types.TicketCommitmentResult, 0, len(mtx.TxOut)/2)
+	for i := 1; i < len(mtx.TxOut); i += 2 {
+		commitScript := mtx.TxOut[i].PkScript
+		addr, err := stake.AddrFromSStxPkScrCommitment(commitScript, params)
+		if err != nil {
+			context := "Failed to decode commitment address"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		amount, err := stake.AmountFromSStxPkScrCommitment(commitScript)
+		if err != nil {
+			context := "Failed to decode commitment amount"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+
+		change := mtx.TxOut[i+1]
+		var changeAddr string
+		_, addrs, _, _ := txscript.ExtractPkScriptAddrs(change.Version,
+			change.PkScript, params)
+		if len(addrs) > 0 {
+			changeAddr = addrs[0].Address()
+		}
+
+		idx := i / 2
+		commitments = append(commitments, types.TicketCommitmentResult{
+			Address:       addr.Address(),
+			Amount:        amount.ToCoin(),
+			ChangeAddress: changeAddr,
+			ChangeAmount:  dcrutil.Amount(change.Value).ToCoin(),
+			VoteFeeLimit: ticketFeeLimit(spendRules[idx][0],
+				spendLimits[idx][0]),
+			RevocationFeeLimit: ticketFeeLimit(spendRules[idx][1],
+				spendLimits[idx][1]),
+		})
+	}
+
+	return &types.DecodeTicketResult{
+		Txid:          mtx.TxHash().String(),
+		TicketPrice:   dcrutil.Amount(submission.Value).ToCoin(),
+		VotingAddress: votingAddr,
+		Commitments:   commitments,
+	}, nil
+}
+
+// handleDropAllPeers implements the dropallpeers command.
+func handleDropAllPeers(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.DropAllPeersCmd)
+	return s.server.DropAllPeers(*c.ExcludePersistent), nil
+}
+
 // handleEstimateFee implements the estimatefee command.
 // TODO this is a very basic implementation.  It should be
 // modified to match the bitcoin-core one.
 func handleEstimateFee(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	return cfg.minRelayTxFee.ToCoin(), nil
+	return reloadable.MinRelayTxFee().ToCoin(), nil
 }
 
 // handleEstimateSmartFee implements the estimatesmartfee command.
@@ -1326,12 +1722,21 @@ func handleEstimateSmartFee(s *rpcServer, cmd interface{}, closeChan <-chan stru
 		mode = *c.Mode
 	}
 
-	if mode != types.EstimateSmartFeeConservative {
-		return nil, rpcInvalidError("Only the default and conservative modes " +
-			"are supported for smart fee estimation at the moment")
+	var estimateMode fees.EstimateMode
+	switch mode {
+	case types.EstimateSmartFeeConservative:
+		estimateMode = fees.EstimateConservative
+	case types.EstimateSmartFeeEconomical:
+		estimateMode = fees.EstimateEconomical
+	default:
+		return nil, rpcInvalidError("Unsupported smart fee estimation "+
+			"mode %q -- supported modes: %q, %q", mode,
+			types.EstimateSmartFeeConservative,
+			types.EstimateSmartFeeEconomical)
 	}
 
-	fee, err := s.server.feeEstimator.EstimateFee(int32(c.Confirmations))
+	fee, err := s.server.feeEstimator.EstimateSmartFee(
+		int32(c.Confirmations), estimateMode)
 	if err != nil {
 		return nil, rpcInternalError(err.Error(), "Could not estimate fee")
 	}
@@ -1339,29 +1744,11 @@ func handleEstimateSmartFee(s *rpcServer, cmd interface{}, closeChan <-chan stru
 	return fee.ToCoin(), nil
 }
 
-// handleEstimateStakeDiff implements the estimatestakediff command.
-func handleEstimateStakeDiff(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	c := cmd.(*types.EstimateStakeDiffCmd)
-
-	// Minimum possible stake difficulty.
-	chain := s.chain
-	min, err := chain.EstimateNextStakeDifficulty(0, false)
-	if err != nil {
-		return nil, rpcInternalError(err.Error(), "Could not "+
-			"estimate next minimum stake difficulty")
-	}
-
-	// Maximum possible stake difficulty.
-	max, err := chain.EstimateNextStakeDifficulty(0, true)
-	if err != nil {
-		return nil, rpcInternalError(err.Error(), "Could not "+
-			"estimate next maximum stake difficulty")
-	}
-
-	// The expected stake difficulty. Average the number of fresh stake
-	// since the last retarget to get the number of tickets per block,
-	// then use that to estimate the next stake difficulty.
-	params := s.server.chainParams
+// estimateExpectedStakeDiff estimates the stake difficulty of the next stake
+// difficulty retarget interval by averaging the number of fresh stake since
+// the last retarget to get the number of tickets per block and pretending the
+// remainder of the interval continues at that rate.
+func estimateExpectedStakeDiff(chain *blockchain.BlockChain, params *chaincfg.Params) (int64, error) {
 	bestHeight := chain.BestSnapshot().Height
 	lastAdjustment := (bestHeight / params.StakeDiffWindowSize) *
 		params.StakeDiffWindowSize
@@ -1371,7 +1758,7 @@ func handleEstimateStakeDiff(s *rpcServer, cmd interface{}, closeChan <-chan str
 	for i := lastAdjustment; i <= bestHeight; i++ {
 		bh, err := chain.HeaderByHeight(i)
 		if err != nil {
-			return nil, rpcInternalError(err.Error(), "Could not "+
+			return 0, rpcInternalError(err.Error(), "Could not "+
 				"estimate next stake difficulty")
 		}
 		totalTickets += int(bh.FreshStake)
@@ -1383,9 +1770,36 @@ func handleEstimateStakeDiff(s *rpcServer, cmd interface{}, closeChan <-chan str
 	expected, err := chain.EstimateNextStakeDifficulty(expectedTickets,
 		false)
 	if err != nil {
-		return nil, rpcInternalError(err.Error(), "Could not "+
+		return 0, rpcInternalError(err.Error(), "Could not "+
 			"estimate next stake difficulty")
 	}
+	return expected, nil
+}
+
+// handleEstimateStakeDiff implements the estimatestakediff command.
+func handleEstimateStakeDiff(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.EstimateStakeDiffCmd)
+
+	// Minimum possible stake difficulty.
+	chain := s.chain
+	min, err := chain.EstimateNextStakeDifficulty(0, false)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not "+
+			"estimate next minimum stake difficulty")
+	}
+
+	// Maximum possible stake difficulty.
+	max, err := chain.EstimateNextStakeDifficulty(0, true)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not "+
+			"estimate next maximum stake difficulty")
+	}
+
+	// The expected stake difficulty.
+	expected, err := estimateExpectedStakeDiff(chain, s.server.chainParams)
+	if err != nil {
+		return nil, err
+	}
 
 	// User-specified stake difficulty, if they asked for one.
 	var userEstFltPtr *float64
@@ -1728,6 +2142,41 @@ func handleGetAddedNodeInfo(s *rpcServer, cmd interface{}, closeChan <-chan stru
 	return results, nil
 }
 
+// handleGetAddrManInfo implements the getaddrmaninfo command.
+func handleGetAddrManInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	stats := s.server.addrManager.Stats()
+	return &types.GetAddrManInfoResult{
+		New:   stats.New,
+		Tried: stats.Tried,
+		Total: stats.Total,
+		IPv4:  stats.IPv4,
+		IPv6:  stats.IPv6,
+		Onion: stats.Onion,
+	}, nil
+}
+
+// handleGetBannedPeers implements the getbannedpeers command.
+func handleGetBannedPeers(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	banned := s.server.BannedPeers()
+	hosts := make([]string, 0, len(banned))
+	for host := range banned {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	now := time.Now()
+	results := make([]types.GetBannedPeersResult, 0, len(hosts))
+	for _, host := range hosts {
+		banEnd := banned[host]
+		results = append(results, types.GetBannedPeersResult{
+			Host:                host,
+			BanUntil:            banEnd.Unix(),
+			BanSecondsRemaining: int64(banEnd.Sub(now).Seconds()),
+		})
+	}
+	return results, nil
+}
+
 // handleGetBestBlock implements the getbestblock command.
 func handleGetBestBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	// All other "get block" commands give either the height, the hash, or
@@ -1846,6 +2295,15 @@ func handleGetBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		NextHash:      nextHashString,
 	}
 
+	// Resolving the previous outputs referenced by the transaction inputs
+	// requires the transaction index.
+	verbosePrevOut := c.VerboseTx != nil && *c.VerboseTx &&
+		c.VerbosePrevOut != nil && *c.VerbosePrevOut
+	if verbosePrevOut && s.server.txIndex == nil {
+		return nil, rpcInternalError("Transaction index must be "+
+			"enabled (--txindex)", "Configuration")
+	}
+
 	if c.VerboseTx == nil || !*c.VerboseTx {
 		transactions := blk.Transactions()
 		txNames := make([]string, len(transactions))
@@ -1874,6 +2332,12 @@ func handleGetBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 				return nil, rpcInternalError(err.Error(),
 					"Could not create transaction")
 			}
+			if verbosePrevOut {
+				err := addVinPrevOuts(s, rawTxn.Vin, tx.MsgTx())
+				if err != nil {
+					return nil, err
+				}
+			}
 			rawTxns[i] = *rawTxn
 		}
 		blockReply.RawTx = rawTxns
@@ -1889,6 +2353,12 @@ func handleGetBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 				return nil, rpcInternalError(err.Error(),
 					"Could not create stake transaction")
 			}
+			if verbosePrevOut {
+				err := addVinPrevOuts(s, rawSTxn.Vin, tx.MsgTx())
+				if err != nil {
+					return nil, err
+				}
+			}
 			rawSTxns[i] = *rawSTxn
 		}
 		blockReply.RawSTx = rawSTxns
@@ -1897,6 +2367,48 @@ func handleGetBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 	return blockReply, nil
 }
 
+// medianBlockInterval returns the median number of seconds between the
+// timestamps of consecutive blocks over the provided number of most recent
+// blocks in the main chain ending at the given height.  Fewer blocks are used
+// when the chain is not long enough and zero is returned when there are no
+// intervals at all.
+func medianBlockInterval(chain *blockchain.BlockChain, height int64, window uint32) (float64, error) {
+	numIntervals := int64(window)
+	if numIntervals > height {
+		numIntervals = height
+	}
+	if numIntervals <= 0 {
+		return 0, nil
+	}
+
+	intervals := make([]int64, 0, numIntervals)
+	header, err := chain.HeaderByHeight(height - numIntervals)
+	if err != nil {
+		return 0, err
+	}
+	prevTimestamp := header.Timestamp.Unix()
+	for h := height - numIntervals + 1; h <= height; h++ {
+		header, err := chain.HeaderByHeight(h)
+		if err != nil {
+			return 0, err
+		}
+		timestamp := header.Timestamp.Unix()
+		intervals = append(intervals, timestamp-prevTimestamp)
+		prevTimestamp = timestamp
+	}
+
+	// Block timestamps are not required to be in order, so the intervals
+	// may be negative, however, the median is still meaningful.
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i] < intervals[j]
+	})
+	mid := len(intervals) / 2
+	if len(intervals)%2 == 0 {
+		return float64(intervals[mid-1]+intervals[mid]) / 2, nil
+	}
+	return float64(intervals[mid]), nil
+}
+
 // handleGetBlockchainInfo implements the getblockchaininfo command.
 func handleGetBlockchainInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	best := s.chain.BestSnapshot()
@@ -1914,6 +2426,19 @@ func handleGetBlockchainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 		verifyProgress = math.Min(float64(best.Height)/float64(syncHeight), 1.0)
 	}
 
+	// Estimate the number of seconds until the chain is synced from the
+	// rate blocks have recently been connected during the initial block
+	// download.  It is left as zero when the rate is unknown.
+	isCurrent := s.chain.IsCurrent()
+	var syncETA int64
+	if !isCurrent && syncHeight > best.Height {
+		rate := s.server.blockManager.SyncRate()
+		if rate > 0 {
+			remaining := float64(syncHeight - best.Height)
+			syncETA = int64(math.Ceil(remaining / rate))
+		}
+	}
+
 	// Fetch the maximum allowed block size.
 	maxBlockSize, err := s.chain.MaxBlockSize()
 	if err != nil {
@@ -1921,6 +2446,14 @@ func handleGetBlockchainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 			"Could not fetch max block size.")
 	}
 
+	// Calculate the median time between the most recent blocks.
+	medianInterval, err := medianBlockInterval(s.chain, best.Height,
+		cfg.BlockIntervalWindow)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not calculate median block interval.")
+	}
+
 	// Fetch the agendas of the consensus deployments as well as their
 	// threshold states and state activation heights.
 	dInfo := make(map[string]types.AgendaInfo)
@@ -1961,12 +2494,14 @@ func handleGetBlockchainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 		Headers:              best.Height,
 		SyncHeight:           syncHeight,
 		ChainWork:            fmt.Sprintf("%064x", chainWork),
-		InitialBlockDownload: !s.chain.IsCurrent(),
+		InitialBlockDownload: !isCurrent,
 		VerificationProgress: verifyProgress,
+		SyncETASeconds:       syncETA,
 		BestBlockHash:        best.Hash.String(),
 		Difficulty:           best.Bits,
 		DifficultyRatio:      getDifficultyRatio(best.Bits, s.server.chainParams),
 		MaxBlockSize:         maxBlockSize,
+		MedianBlockInterval:  medianInterval,
 		Deployments:          dInfo,
 	}
 
@@ -1994,6 +2529,55 @@ func handleGetBlockHash(s *rpcServer, cmd interface{}, closeChan <-chan struct{}
 	return hash.String(), nil
 }
 
+// handleGetBlockHashes implements the getblockhashes command.
+func handleGetBlockHashes(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetBlockHashesCmd)
+
+	if c.StartHeight < 0 {
+		return nil, rpcInvalidError("Start height %d must not be "+
+			"negative", c.StartHeight)
+	}
+	if c.EndHeight < c.StartHeight {
+		return nil, rpcInvalidError("Start height %d is beyond end "+
+			"height %d", c.StartHeight, c.EndHeight)
+	}
+	if c.EndHeight-c.StartHeight >= maxGetBlockHashesRange {
+		return nil, rpcInvalidError("Range of %d blocks exceeds the "+
+			"maximum of %d", c.EndHeight-c.StartHeight+1,
+			maxGetBlockHashesRange)
+	}
+	bestHeight := s.chain.BestSnapshot().Height
+	if c.EndHeight > bestHeight {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Block number out of range: %v",
+				c.EndHeight),
+		}
+	}
+
+	hashes, err := s.chain.HeightRange(c.StartHeight, c.EndHeight+1)
+	if err != nil {
+		context := "Failed to fetch block hashes"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	// Ensure the range was not truncated by a reorganization that happened
+	// after the best height was obtained above.
+	if int64(len(hashes)) != c.EndHeight-c.StartHeight+1 {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Block number out of range: %v",
+				c.EndHeight),
+		}
+	}
+
+	result := make([]string, 0, len(hashes))
+	for i := range hashes {
+		result = append(result, hashes[i].String())
+	}
+	return result, nil
+}
+
 // handleGetBlockHeader implements the getblockheader command.
 func handleGetBlockHeader(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*types.GetBlockHeaderCmd)
@@ -2079,26 +2663,52 @@ func handleGetBlockHeader(s *rpcServer, cmd interface{}, closeChan <-chan struct
 
 }
 
-// handleGetBlockSubsidy implements the getblocksubsidy command.
-func handleGetBlockSubsidy(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	c := cmd.(*types.GetBlockSubsidyCmd)
+// handleGetBlockHeaderByHeight implements the getblockheaderbyheight command.
+func handleGetBlockHeaderByHeight(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetBlockHeaderByHeightCmd)
+	hash, err := s.chain.BlockHashByHeight(c.Height)
+	if err != nil {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Block number out of range: %v",
+				c.Height),
+		}
+	}
+	blockHeader, err := s.chain.HeaderByHash(hash)
+	if err != nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCBlockNotFound,
+			Message: fmt.Sprintf("Block not found: %v", hash),
+		}
+	}
 
-	height := c.Height
-	voters := c.Voters
+	var headerBuf bytes.Buffer
+	err = blockHeader.Serialize(&headerBuf)
+	if err != nil {
+		context := "Failed to serialize block header"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	return hex.EncodeToString(headerBuf.Bytes()), nil
+}
 
+// calcBlockSubsidy returns the subsidy breakdown for a block at the provided
+// height with the given number of voters.
+func calcBlockSubsidy(s *rpcServer, height int64, voters uint16) types.GetBlockSubsidyResult {
 	dev := s.subsidyCache.CalcTreasurySubsidy(height, voters)
 	pos := s.subsidyCache.CalcStakeVoteSubsidy(height-1) * int64(voters)
 	pow := s.subsidyCache.CalcWorkSubsidy(height, voters)
-	total := dev + pos + pow
-
-	rep := types.GetBlockSubsidyResult{
+	return types.GetBlockSubsidyResult{
 		Developer: dev,
 		PoS:       pos,
 		PoW:       pow,
-		Total:     total,
+		Total:     dev + pos + pow,
 	}
-
-	return rep, nil
+}
+
+// handleGetBlockSubsidy implements the getblocksubsidy command.
+func handleGetBlockSubsidy(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetBlockSubsidyCmd)
+	return calcBlockSubsidy(s, c.Height, c.Voters), nil
 }
 
 // handleGetChainTips implements the getchaintips command.
@@ -2116,9 +2726,238 @@ func handleGetChainTips(s *rpcServer, cmd interface{}, closeChan <-chan struct{}
 	return result, nil
 }
 
+// handleGetCoinbaseTemplate implements the getcoinbasetemplate command.
+func handleGetCoinbaseTemplate(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	// Respond with an error if there are no addresses to pay the created
+	// blocks to since templates are not generated in that case.
+	if len(cfg.miningAddrs) == 0 || s.server.bg == nil {
+		return nil, rpcInternalError("No payment addresses specified "+
+			"via --miningaddr", "Configuration")
+	}
+
+	template, err := s.server.bg.CurrentTemplate()
+	if err != nil {
+		context := "Failed to obtain current block template"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	if template == nil {
+		return nil, rpcMiscError("No block template is currently available")
+	}
+
+	// NOTE: The template and the coinbase it contains are shared by all
+	// callers, so they must not be modified.
+	msgBlock := template.Block
+	coinbaseTx := msgBlock.Transactions[0]
+	coinbaseHex, err := messageToHex(coinbaseTx)
+	if err != nil {
+		return nil, err
+	}
+
+	height := template.Height
+	voters := msgBlock.Header.Voters
+	result := &types.GetCoinbaseTemplateResult{
+		Height:            height,
+		PreviousBlockHash: msgBlock.Header.PrevBlock.String(),
+		Voters:            voters,
+		PoW:               s.subsidyCache.CalcWorkSubsidy(height, voters),
+		PoS:               s.subsidyCache.CalcStakeVoteSubsidy(height-1) * int64(voters),
+		Developer:         s.subsidyCache.CalcTreasurySubsidy(height, voters),
+		Fees:              -template.Fees[0],
+		Txid:              coinbaseTx.TxHash().String(),
+		Hex:               coinbaseHex,
+		Vout:              createVoutList(coinbaseTx, s.server.chainParams, nil),
+	}
+
+	// The block one ledger payouts do not include an extra nonce.
+	if height != 1 || len(s.server.chainParams.BlockOneLedger) == 0 {
+		result.ExtraNonce = &types.CoinbaseExtraNonceResult{
+			Output: coinbaseExtraNonceOutput,
+			Offset: coinbaseExtraNonceOffset,
+			Size:   coinbaseExtraNonceSize,
+			Value:  extractCoinbaseTxExtraNonce(coinbaseTx),
+		}
+	}
+
+	return result, nil
+}
+
+// coinSupplyCache houses the portions of the coin supply that were created by
+// the treasury subsidy and the block one ledger as of a given block in the main
+// chain.  It allows the verbose getcoinsupply results to only account for the
+// blocks connected since the previous request.
+type coinSupplyCache struct {
+	sync.Mutex
+	hash     chainhash.Hash
+	height   int64
+	voters   uint16
+	treasury int64
+	blockOne int64
+}
+
+// coinSupplyBreakdown returns the total treasury subsidy and block one ledger
+// payout that have been added to the coin supply as of the block at the
+// provided height in the main chain.
+//
+// Since the subsidy in the coinbase of a block is only added to the coin supply
+// once the following block approves it, this walks the headers of the main
+// chain the first time it is called, which can take a while, and then reuses
+// the totals from the previous call so long as the block they were calculated
+// as of is still in the main chain.
+//
+// This function is safe for concurrent access.
+func (s *rpcServer) coinSupplyBreakdown(height int64) (int64, int64, error) {
+	params := s.server.chainParams
+	c := &s.coinSupply
+	c.Lock()
+	defer c.Unlock()
+
+	// Start over from the genesis block when the cached totals are no longer
+	// for a block in the main chain.
+	if c.height > height || !s.chain.MainChainHasBlock(&c.hash) {
+		c.hash = params.GenesisHash
+		c.height, c.voters, c.treasury, c.blockOne = 0, 0, 0, 0
+	}
+
+	for c.height < height {
+		header, err := s.chain.HeaderByHeight(c.height + 1)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		// Add the subsidy paid by the parent block when it is approved.
+		if dcrutil.IsFlagSet16(header.VoteBits, dcrutil.BlockValid) {
+			switch {
+			case c.height == 1 && len(params.BlockOneLedger) != 0:
+				c.blockOne = params.BlockOneSubsidy()
+			case c.height > 1:
+				c.treasury += s.subsidyCache.CalcTreasurySubsidy(c.height,
+					c.voters)
+			}
+		}
+
+		c.hash = header.BlockHash()
+		c.height++
+		c.voters = header.Voters
+	}
+
+	return c.treasury, c.blockOne, nil
+}
+
 // handleGetCoinSupply implements the getcoinsupply command.
 func handleGetCoinSupply(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	return s.chain.BestSnapshot().TotalSubsidy, nil
+	c := cmd.(*types.GetCoinSupplyCmd)
+
+	best := s.chain.BestSnapshot()
+	if c.Verbose == nil || !*c.Verbose {
+		return best.TotalSubsidy, nil
+	}
+
+	treasury, blockOne, err := s.coinSupplyBreakdown(best.Height)
+	if err != nil {
+		context := "Failed to calculate coin supply breakdown"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	locked, err := s.server.blockManager.TicketPoolValue()
+	if err != nil {
+		context := "Could not obtain ticket pool value"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	return &types.GetCoinSupplyVerboseResult{
+		Total:           best.TotalSubsidy,
+		Mined:           best.TotalSubsidy - treasury - blockOne,
+		Treasury:        treasury,
+		BlockOne:        blockOne,
+		LockedInTickets: int64(locked),
+		Circulating:     best.TotalSubsidy - int64(locked),
+	}, nil
+}
+
+// handleGetCompactBlock implements the getcompactblock command.
+func handleGetCompactBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetCompactBlockCmd)
+
+	hash, err := chainhash.NewHashFromStr(c.Hash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Hash)
+	}
+	blk, err := s.server.chain.BlockByHash(hash)
+	if err != nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCBlockNotFound,
+			Message: fmt.Sprintf("Block not found: %v", hash),
+		}
+	}
+
+	// Use a random nonce unless one is specified.
+	var nonce uint64
+	if c.Nonce != nil {
+		nonce = *c.Nonce
+	} else {
+		nonce, err = wire.RandomUint64()
+		if err != nil {
+			return nil, rpcInternalError(err.Error(),
+				"Failed to generate nonce")
+		}
+	}
+
+	msgBlock := blk.MsgBlock()
+	cmpctBlock := wire.NewMsgCmpctBlockFromBlock(msgBlock, nonce)
+	cmpctBlockBytes, err := cmpctBlock.Bytes()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not serialize compact block")
+	}
+
+	// When the verbose flag isn't set, simply return the serialized compact
+	// block as a hex-encoded string.
+	if c.Verbose != nil && !*c.Verbose {
+		return hex.EncodeToString(cmpctBlockBytes), nil
+	}
+
+	encodeShortIDs := func(shortIDs []uint64) []string {
+		encoded := make([]string, 0, len(shortIDs))
+		for _, shortID := range shortIDs {
+			encoded = append(encoded, fmt.Sprintf("%0*x",
+				wire.CmpctBlockShortIDSize*2, shortID))
+		}
+		return encoded
+	}
+	prefilledResults := func(prefilled []wire.PrefilledTx) []types.PrefilledTxResult {
+		results := make([]types.PrefilledTxResult, 0, len(prefilled))
+		for _, ptx := range prefilled {
+			results = append(results, types.PrefilledTxResult{
+				Index: ptx.Index,
+				Txid:  ptx.Tx.TxHash().String(),
+			})
+		}
+		return results
+	}
+	return &types.GetCompactBlockVerboseResult{
+		Hash:         c.Hash,
+		Nonce:        nonce,
+		ShortIDs:     encodeShortIDs(cmpctBlock.ShortIDs),
+		PrefilledTx:  prefilledResults(cmpctBlock.PrefilledTxns),
+		STxShortIDs:  encodeShortIDs(cmpctBlock.STxShortIDs),
+		PrefilledSTx: prefilledResults(cmpctBlock.PrefilledSTxns),
+		Size:         len(cmpctBlockBytes),
+		BlockSize:    msgBlock.SerializeSize(),
+	}, nil
+}
+
+// handleGetConfig implements the getconfig command.
+func handleGetConfig(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	opts := effectiveConfig(cfg)
+
+	// Report the values currently in effect for the options that may be
+	// changed while running since they may differ from the values loaded on
+	// startup.
+	opts["banthreshold"] = reloadable.BanThreshold()
+	opts["maxpeers"] = reloadable.MaxPeers()
+	opts["minrelaytxfee"] = reloadable.MinRelayTxFee().ToCoin()
+	opts["whitelist"] = s.server.whitelist.strings()
+
+	return opts, nil
 }
 
 // handleGetConnectionCount implements the getconnectioncount command.
@@ -2126,6 +2965,23 @@ func handleGetConnectionCount(s *rpcServer, cmd interface{}, closeChan <-chan st
 	return s.server.ConnectedCount(), nil
 }
 
+// handleGetConnMgrStats implements the getconnmgrstats command.
+func handleGetConnMgrStats(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	stats := s.server.connManager.Stats()
+	numTried, numNew := s.server.addrManager.AddressCounts()
+	return &types.GetConnMgrStatsResult{
+		TargetOutbound:      stats.TargetOutbound,
+		Outbound:            stats.Established,
+		Pending:             stats.Pending,
+		InFlightDials:       stats.InFlightDials,
+		ConsecutiveFailures: stats.FailedAttempts,
+		TotalFailures:       stats.TotalFailed,
+		KnownAddresses:      numTried + numNew,
+		TriedAddresses:      numTried,
+		NewAddresses:        numNew,
+	}, nil
+}
+
 // handleGetCurrentNet implements the getcurrentnet command.
 func handleGetCurrentNet(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	return s.server.chainParams.Net, nil
@@ -2137,6 +2993,54 @@ func handleGetDifficulty(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 	return getDifficultyRatio(best.Bits, s.server.chainParams), nil
 }
 
+// handleGetDifficultyInfo implements the getdifficultyinfo command.
+func handleGetDifficultyInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	chain := s.chain
+	params := s.server.chainParams
+	best := chain.BestSnapshot()
+
+	// Estimate the proof-of-work difficulty for the next retarget interval
+	// based on the recent block timing.
+	estimatedBits, nextRetargetHeight, err := chain.EstimateNextWorkDifficulty()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not "+
+			"estimate next difficulty")
+	}
+
+	// Determine the current stake difficulty along with the required and
+	// expected stake difficulties for the upcoming blocks.
+	header, err := chain.HeaderByHeight(best.Height)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not "+
+			"fetch best block header")
+	}
+	nextStakeDiff, err := chain.CalcNextRequiredStakeDifficulty()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not "+
+			"calculate next stake difficulty")
+	}
+	estimatedStakeDiff, err := estimateExpectedStakeDiff(chain, params)
+	if err != nil {
+		return nil, err
+	}
+	nextStakeRetargetHeight := (best.Height/params.StakeDiffWindowSize + 1) *
+		params.StakeDiffWindowSize
+
+	return &types.GetDifficultyInfoResult{
+		Difficulty:               getDifficultyRatio(best.Bits, params),
+		Bits:                     strconv.FormatInt(int64(best.Bits), 16),
+		NextRetargetHeight:       nextRetargetHeight,
+		BlocksUntilRetarget:      nextRetargetHeight - best.Height,
+		EstimatedDifficulty:      getDifficultyRatio(estimatedBits, params),
+		EstimatedBits:            strconv.FormatInt(int64(estimatedBits), 16),
+		StakeDifficulty:          dcrutil.Amount(header.SBits).ToCoin(),
+		NextStakeDifficulty:      dcrutil.Amount(nextStakeDiff).ToCoin(),
+		NextStakeRetargetHeight:  nextStakeRetargetHeight,
+		BlocksUntilStakeRetarget: nextStakeRetargetHeight - best.Height,
+		EstimatedStakeDifficulty: dcrutil.Amount(estimatedStakeDiff).ToCoin(),
+	}, nil
+}
+
 // handleGetGenerate implements the getgenerate command.
 func handleGetGenerate(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	return s.server.cpuMiner.IsMining(), nil
@@ -2294,12 +3198,120 @@ func handleGetInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (in
 		Proxy:           cfg.Proxy,
 		Difficulty:      getDifficultyRatio(best.Bits, s.server.chainParams),
 		TestNet:         cfg.TestNet,
-		RelayFee:        cfg.minRelayTxFee.ToCoin(),
+		RelayFee:        reloadable.MinRelayTxFee().ToCoin(),
 	}
 
 	return ret, nil
 }
 
+// handleGetMemoryInfo implements the getmemoryinfo command.
+func handleGetMemoryInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	// The most recent pause is stored in a circular buffer indexed by the
+	// number of completed collections.
+	var lastGCPause uint64
+	if memStats.NumGC > 0 {
+		lastGCPause = memStats.PauseNs[(memStats.NumGC+255)%256]
+	}
+
+	// Tally the entries in the per-peer known address caches.
+	var knownAddrs int
+	for _, sp := range s.server.Peers() {
+		knownAddrs += sp.knownAddresses.Len()
+	}
+
+	return &types.GetMemoryInfoResult{
+		HeapAlloc:             memStats.HeapAlloc,
+		HeapInUse:             memStats.HeapInuse,
+		HeapIdle:              memStats.HeapIdle,
+		HeapReleased:          memStats.HeapReleased,
+		HeapObjects:           memStats.HeapObjects,
+		TotalAlloc:            memStats.TotalAlloc,
+		Sys:                   memStats.Sys,
+		NumGC:                 memStats.NumGC,
+		GCPauseTotal:          memStats.PauseTotalNs,
+		LastGCPause:           lastGCPause,
+		Goroutines:            runtime.NumGoroutine(),
+		SigCacheEntries:       s.server.sigCache.Len(),
+		SigCacheMaxEntries:    cfg.SigCacheMaxSize,
+		KnownAddrCacheEntries: knownAddrs,
+	}, nil
+}
+
+// mempoolRelativesResult returns the result for the getmempoolancestors and
+// getmempooldescendants commands for the provided related transactions.  It is
+// either an array of their hashes or, when verbose is set, a map of their
+// verbose descriptors keyed by hash.
+func mempoolRelativesResult(descs []*mempool.VerboseTxDesc, verbose bool) interface{} {
+	if verbose {
+		result := make(map[string]*types.GetRawMempoolVerboseResult,
+			len(descs))
+		for _, desc := range descs {
+			result[desc.Tx.Hash().String()] = mempoolVerboseResult(desc)
+		}
+		return result
+	}
+
+	hashStrings := make([]string, 0, len(descs))
+	for _, desc := range descs {
+		hashStrings = append(hashStrings, desc.Tx.Hash().String())
+	}
+	return hashStrings
+}
+
+// handleGetMempoolAncestors implements the getmempoolancestors command.
+func handleGetMempoolAncestors(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolAncestorsCmd)
+
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	descs, err := s.server.txMemPool.Ancestors(txHash)
+	if err != nil {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+
+	return mempoolRelativesResult(descs, *c.Verbose), nil
+}
+
+// handleGetMempoolDescendants implements the getmempooldescendants command.
+func handleGetMempoolDescendants(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolDescendantsCmd)
+
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	descs, err := s.server.txMemPool.Descendants(txHash)
+	if err != nil {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+
+	return mempoolRelativesResult(descs, *c.Verbose), nil
+}
+
+// handleGetMempoolEntry implements the getmempoolentry command.
+func handleGetMempoolEntry(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolEntryCmd)
+
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	desc, err := s.server.txMemPool.VerboseTxDesc(txHash)
+	if err != nil {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+
+	return mempoolVerboseResult(desc), nil
+}
+
 // handleGetMempoolInfo implements the getmempoolinfo command.
 func handleGetMempoolInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	mempoolTxns := s.server.txMemPool.TxDescs()
@@ -2320,9 +3332,11 @@ func handleGetMempoolInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct
 // handleGetMiningInfo implements the getmininginfo command. We only return the
 // fields that are not related to wallet functionality.
 func handleGetMiningInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	// Create a default getnetworkhashps command to use defaults and make
-	// use of the existing getnetworkhashps handler.
-	gnhpsCmd := types.NewGetNetworkHashPSCmd(nil, nil)
+	c := cmd.(*types.GetMiningInfoCmd)
+
+	// Create a getnetworkhashps command with the requested averaging window
+	// and make use of the existing getnetworkhashps handler.
+	gnhpsCmd := types.NewGetNetworkHashPSCmd(c.Blocks, nil)
 	networkHashesPerSecIface, err := handleGetNetworkHashPS(s, gnhpsCmd,
 		closeChan)
 	if err != nil {
@@ -2478,6 +3492,7 @@ func handleGetNetworkInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct
 		localAddrs[idx] = addr
 	}
 
+	connsIn, connsOut := s.server.ConnectedCounts()
 	info := types.GetNetworkInfoResult{
 		Version: int32(1000000*version.Major + 10000*version.Minor +
 			100*version.Patch),
@@ -2485,7 +3500,9 @@ func handleGetNetworkInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct
 		ProtocolVersion: int32(maxProtocolVersion),
 		TimeOffset:      int64(s.server.timeSource.Offset().Seconds()),
 		Connections:     s.server.ConnectedCount(),
-		RelayFee:        cfg.minRelayTxFee.ToCoin(),
+		ConnectionsIn:   connsIn,
+		ConnectionsOut:  connsOut,
+		RelayFee:        reloadable.MinRelayTxFee().ToCoin(),
 		Networks:        networks,
 		LocalAddresses:  localAddrs,
 		LocalServices:   fmt.Sprintf("%016x", uint64(s.server.services)),
@@ -2494,12 +3511,158 @@ func handleGetNetworkInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct
 	return info, nil
 }
 
+// handleGetKnownAddresses implements the getknownaddresses command.
+func handleGetKnownAddresses(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetKnownAddressesCmd)
+	count := *c.Count
+	if count < 0 {
+		return nil, rpcInvalidError("Address count may not be negative")
+	}
+
+	infos := s.server.addrManager.KnownAddresses(count)
+	results := make([]types.GetKnownAddressesResult, 0, len(infos))
+	for i := range infos {
+		info := &infos[i]
+		var lastAttempt int64
+		if !info.LastAttempt.IsZero() {
+			lastAttempt = info.LastAttempt.Unix()
+		}
+		results = append(results, types.GetKnownAddressesResult{
+			Address:     addrmgr.NetAddressKey(&info.Addr),
+			Services:    fmt.Sprintf("%08d", uint64(info.Addr.Services)),
+			LastSeen:    info.Addr.Timestamp.Unix(),
+			LastAttempt: lastAttempt,
+			Source:      addrmgr.NetAddressKey(&info.Source),
+			Tried:       info.Tried,
+		})
+	}
+	return results, nil
+}
+
+// handleGetLocalAddresses implements the getlocaladdresses command.
+func handleGetLocalAddresses(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	infos := s.server.addrManager.LocalAddresses()
+	results := make([]types.GetLocalAddressesResult, 0, len(infos))
+	for i := range infos {
+		info := &infos[i]
+		results = append(results, types.GetLocalAddressesResult{
+			Address:  info.Addr.IP.String(),
+			Port:     info.Addr.Port,
+			Priority: info.Priority.String(),
+			Score:    info.Score,
+		})
+	}
+	return results, nil
+}
+
+// handleGetNodeAddresses implements the getnodeaddresses command.
+func handleGetNodeAddresses(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	// Don't disclose any addresses when running on the simulation test
+	// network for the same reasons they are not provided to peers.
+	if cfg.SimNet {
+		return nil, rpcMiscError("Addresses are not disclosed on the " +
+			"simulation test network")
+	}
+
+	c := cmd.(*types.GetNodeAddressesCmd)
+	count := *c.Count
+	if count < 0 {
+		return nil, rpcInvalidError("Address count may not be negative")
+	}
+
+	addrs := s.server.addrManager.GoodAddresses(count)
+	results := make([]types.GetNodeAddressesResult, 0, len(addrs))
+	for _, na := range addrs {
+		results = append(results, types.GetNodeAddressesResult{
+			Time:     na.Timestamp.Unix(),
+			Services: fmt.Sprintf("%08d", uint64(na.Services)),
+			Address:  na.IP.String(),
+			Port:     na.Port,
+		})
+	}
+	return results, nil
+}
+
+// handleGetOrphanTxs implements the getorphantxs command.
+func handleGetOrphanTxs(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetOrphanTxsCmd)
+	descs, err := s.server.txMemPool.OrphanTxDescs()
+	if err != nil {
+		context := "Failed to fetch orphan transactions"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	if !*c.Verbose {
+		hashStrings := make([]string, 0, len(descs))
+		for _, desc := range descs {
+			hashStrings = append(hashStrings, desc.Tx.Hash().String())
+		}
+		return hashStrings, nil
+	}
+
+	results := make([]types.GetOrphanTxsResult, 0, len(descs))
+	for _, desc := range descs {
+		mtx := desc.Tx.MsgTx()
+		missingParents := make([]types.OutPoint, 0, len(desc.MissingParents))
+		for _, op := range desc.MissingParents {
+			missingParents = append(missingParents, types.OutPoint{
+				Hash:  op.Hash.String(),
+				Tree:  op.Tree,
+				Index: op.Index,
+			})
+		}
+		results = append(results, types.GetOrphanTxsResult{
+			TxID:           desc.Tx.Hash().String(),
+			Size:           int32(mtx.SerializeSize()),
+			Time:           desc.Added.Unix(),
+			MissingParents: missingParents,
+			Tx: types.TxRawDecodeResult{
+				Txid:     desc.Tx.Hash().String(),
+				Version:  int32(mtx.Version),
+				Locktime: mtx.LockTime,
+				Expiry:   mtx.Expiry,
+				Vin:      createVinList(mtx),
+				Vout:     createVoutList(mtx, s.server.chainParams, nil),
+			},
+		})
+	}
+	return results, nil
+}
+
 // handleGetPeerInfo implements the getpeerinfo command.
 func handleGetPeerInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetPeerInfoCmd)
+
+	// Determine which peers to include based on the requested connection
+	// direction.
+	includeInbound, includeOutbound := true, true
+	if c.Direction != nil {
+		switch *c.Direction {
+		case types.GetPeerInfoAll:
+			// Nothing to do.
+		case types.GetPeerInfoInbound:
+			includeOutbound = false
+		case types.GetPeerInfoOutbound:
+			includeInbound = false
+		default:
+			supported := []types.GetPeerInfoDirection{types.GetPeerInfoAll,
+				types.GetPeerInfoInbound, types.GetPeerInfoOutbound}
+			return nil, rpcInvalidError("Invalid direction: %s -- "+
+				"supported directions: %v", *c.Direction, supported)
+		}
+	}
+
 	peers := s.server.Peers()
 	syncPeer := s.server.blockManager.SyncPeer()
+	now := time.Now()
 	infos := make([]*types.GetPeerInfoResult, 0, len(peers))
 	for _, p := range peers {
+		if (p.Inbound() && !includeInbound) ||
+			(!p.Inbound() && !includeOutbound) {
+
+			continue
+		}
+
 		statsSnap := p.StatsSnapshot()
 		info := &types.GetPeerInfoResult{
 			ID:             statsSnap.ID,
@@ -2511,6 +3674,8 @@ func handleGetPeerInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 			LastRecv:       statsSnap.LastRecv.Unix(),
 			BytesSent:      statsSnap.BytesSent,
 			BytesRecv:      statsSnap.BytesRecv,
+			SendRate:       p.sendRate.rate(now),
+			RecvRate:       p.recvRate.rate(now),
 			ConnTime:       statsSnap.ConnTime.Unix(),
 			PingTime:       float64(statsSnap.LastPingMicros),
 			TimeOffset:     statsSnap.TimeOffset,
@@ -2521,6 +3686,8 @@ func handleGetPeerInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 			CurrentHeight:  statsSnap.LastBlock,
 			BanScore:       int32(p.banScore.Int()),
 			SyncNode:       p == syncPeer,
+			BlocksReceived: p.usefulness.blocks(),
+			TxsReceived:    atomic.LoadUint64(&p.txsReceived),
 		}
 		if p.LastPingNonce() != 0 {
 			wait := float64(time.Since(statsSnap.LastPingTime).Nanoseconds())
@@ -2532,6 +3699,26 @@ func handleGetPeerInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 	return infos, nil
 }
 
+// mempoolVerboseResult returns the verbose mempool result for the passed
+// verbose mempool transaction descriptor.
+func mempoolVerboseResult(desc *mempool.VerboseTxDesc) *types.GetRawMempoolVerboseResult {
+	tx := desc.Tx
+	mpd := &types.GetRawMempoolVerboseResult{
+		Size:             int32(tx.MsgTx().SerializeSize()),
+		Fee:              dcrutil.Amount(desc.Fee).ToCoin(),
+		Time:             desc.Added.Unix(),
+		Height:           desc.Height,
+		StartingPriority: desc.StartingPriority,
+		CurrentPriority:  desc.CurrentPriority,
+		Depends:          make([]string, len(desc.Depends)),
+	}
+	for j, depDesc := range desc.Depends {
+		mpd.Depends[j] = depDesc.Tx.Hash().String()
+	}
+
+	return mpd
+}
+
 // handleGetRawMempool implements the getrawmempool command.
 func handleGetRawMempool(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*types.GetRawMempoolCmd)
@@ -2575,21 +3762,7 @@ func handleGetRawMempool(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 				continue
 			}
 
-			tx := desc.Tx
-			mpd := &types.GetRawMempoolVerboseResult{
-				Size:             int32(tx.MsgTx().SerializeSize()),
-				Fee:              dcrutil.Amount(desc.Fee).ToCoin(),
-				Time:             desc.Added.Unix(),
-				Height:           desc.Height,
-				StartingPriority: desc.StartingPriority,
-				CurrentPriority:  desc.CurrentPriority,
-				Depends:          make([]string, len(desc.Depends)),
-			}
-			for j, depDesc := range desc.Depends {
-				mpd.Depends[j] = depDesc.Tx.Hash().String()
-			}
-
-			result[tx.Hash().String()] = mpd
+			result[desc.Tx.Hash().String()] = mempoolVerboseResult(desc)
 		}
 
 		return result, nil
@@ -2849,6 +4022,63 @@ func handleGetStakeVersionInfo(s *rpcServer, cmd interface{}, closeChan <-chan s
 	return result, nil
 }
 
+// handleGetSubsidySchedule implements the getsubsidyschedule command.
+func handleGetSubsidySchedule(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetSubsidyScheduleCmd)
+
+	if c.StartHeight < 0 {
+		return nil, rpcInvalidError("Start height %d must not be negative",
+			c.StartHeight)
+	}
+	if c.EndHeight < c.StartHeight {
+		return nil, rpcInvalidError("End height %d is less than start "+
+			"height %d", c.EndHeight, c.StartHeight)
+	}
+	if c.EndHeight > maxSubsidyScheduleHeight {
+		return nil, rpcInvalidError("End height %d exceeds the max of %d",
+			c.EndHeight, int64(maxSubsidyScheduleHeight))
+	}
+
+	// Default to the maximum number of voters per block.
+	voters := s.server.chainParams.TicketsPerBlock
+	if c.Voters != nil {
+		voters = *c.Voters
+	}
+
+	// Limit the number of subsidy reduction boundaries that are returned.
+	interval := s.server.chainParams.SubsidyReductionInterval
+	numEntries := c.EndHeight/interval - c.StartHeight/interval + 1
+	if numEntries > maxSubsidyScheduleEntries {
+		return nil, rpcInvalidError("Height range [%d, %d] contains %d "+
+			"subsidy reductions which exceeds the max of %d", c.StartHeight,
+			c.EndHeight, numEntries, maxSubsidyScheduleEntries)
+	}
+
+	// Include the subsidy at the start of the range followed by the subsidy
+	// at each subsidy reduction boundary within it.
+	result := make([]types.GetSubsidyScheduleResult, 0, numEntries)
+	height := c.StartHeight
+	for height <= c.EndHeight {
+		subsidy := calcBlockSubsidy(s, height, voters)
+		result = append(result, types.GetSubsidyScheduleResult{
+			Height:    height,
+			Developer: subsidy.Developer,
+			PoS:       subsidy.PoS,
+			PoW:       subsidy.PoW,
+			Total:     subsidy.Total,
+		})
+
+		// Guard against overflow of the next boundary.
+		next := (height/interval + 1) * interval
+		if next <= height {
+			break
+		}
+		height = next
+	}
+
+	return result, nil
+}
+
 // handleGetStakeVersions implements the getstakeversions command.
 func handleGetStakeVersions(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*types.GetStakeVersionsCmd)
@@ -2892,9 +4122,238 @@ func handleGetStakeVersions(s *rpcServer, cmd interface{}, closeChan <-chan stru
 	return result, nil
 }
 
+// ticketMinedHeight returns the height of the main chain block the provided
+// ticket was mined in.  The utxo set is consulted first since it is always
+// available and contains every ticket that has not yet been fully spent, while
+// the transaction index, when enabled, is used as a fallback for tickets that
+// have been.  An error is returned when the transaction is not a ticket.
+func ticketMinedHeight(s *rpcServer, hash *chainhash.Hash) (int64, error) {
+	entry, err := s.chain.FetchUtxoEntry(hash)
+	if err != nil {
+		context := "Failed to retrieve utxo entry"
+		return 0, rpcInternalError(err.Error(), context)
+	}
+	if entry != nil {
+		if entry.TransactionType() != stake.TxTypeSStx {
+			return 0, rpcInvalidError("Transaction %v is not a ticket",
+				hash)
+		}
+		return entry.BlockHeight(), nil
+	}
+
+	msgTx, blockHash, err := fetchIndexedTx(s, hash)
+	if err != nil {
+		return 0, err
+	}
+	if !stake.IsSStx(msgTx) {
+		return 0, rpcInvalidError("Transaction %v is not a ticket", hash)
+	}
+
+	height, err := s.chain.BlockHeightByHash(blockHash)
+	if err != nil {
+		context := "Failed to retrieve block height"
+		return 0, rpcInternalError(err.Error(), context)
+	}
+	return height, nil
+}
+
+// fetchIndexedTx loads the transaction with the provided hash from the
+// database by way of the transaction index and returns it along with the hash
+// of the block that contains it.  An error is returned when the transaction
+// index is not enabled.
+func fetchIndexedTx(s *rpcServer, hash *chainhash.Hash) (*wire.MsgTx, *chainhash.Hash, error) {
+	txIndex := s.server.txIndex
+	if txIndex == nil {
+		return nil, nil, rpcInternalError("The transaction index must be "+
+			"enabled to query mined transactions (specify --txindex)",
+			"Configuration")
+	}
+
+	// Look up the location of the transaction and load it from the
+	// database.
+	idxEntry, err := txIndex.Entry(hash)
+	if err != nil {
+		context := "Failed to retrieve transaction location"
+		return nil, nil, rpcInternalError(err.Error(), context)
+	}
+	if idxEntry == nil {
+		return nil, nil, rpcNoTxInfoError(hash)
+	}
+	var txBytes []byte
+	err = s.server.db.View(func(dbTx database.Tx) error {
+		var err error
+		txBytes, err = dbTx.FetchBlockRegion(&idxEntry.BlockRegion)
+		return err
+	})
+	if err != nil {
+		return nil, nil, rpcNoTxInfoError(hash)
+	}
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		context := "Failed to deserialize transaction"
+		return nil, nil, rpcInternalError(err.Error(), context)
+	}
+	return &msgTx, idxEntry.BlockRegion.Hash, nil
+}
+
+// handleGetTicketInfo implements the getticketinfo command.
+func handleGetTicketInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.GetTicketInfoCmd)
+
+	hash, err := chainhash.NewHashFromStr(c.Hash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Hash)
+	}
+
+	height, err := ticketMinedHeight(s, hash)
+	if err != nil {
+		return nil, err
+	}
+	blockHash, err := s.chain.BlockHashByHeight(height)
+	if err != nil {
+		context := "Failed to retrieve block hash"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	// Tickets become live once they reach maturity and expire when they are
+	// not selected to vote before the expiry height.
+	params := s.server.chainParams
+	maturityHeight := height + int64(params.TicketMaturity)
+	expiryHeight := height + int64(params.TicketExpiry)
+
+	// Determine the status of the ticket from the stake state of the best
+	// chain.  Tickets that vote are removed from the stake state entirely,
+	// so a mature ticket that is not otherwise tracked must have voted.
+	var status string
+	switch {
+	case s.chain.CheckLiveTicket(*hash):
+		status = "live"
+	case s.chain.CheckRevokedTicket(*hash):
+		status = "revoked"
+	case s.chain.CheckExpiredTicket(*hash):
+		status = "expired"
+	case s.chain.CheckMissedTickets([]chainhash.Hash{*hash})[0]:
+		status = "missed"
+	case s.chain.BestSnapshot().Height < maturityHeight:
+		status = "immature"
+	default:
+		status = "voted"
+	}
+
+	return &types.GetTicketInfoResult{
+		Hash:           hash.String(),
+		Status:         status,
+		BlockHash:      blockHash.String(),
+		BlockHeight:    height,
+		MaturityHeight: maturityHeight,
+		ExpiryHeight:   expiryHeight,
+	}, nil
+}
+
+// ticketPoolValueCache houses the ticket pool values as of recently requested
+// blocks since they can be expensive to calculate for blocks deep in history.
+type ticketPoolValueCache struct {
+	sync.Mutex
+	values map[chainhash.Hash]dcrutil.Amount
+}
+
+// ticketPoolValue returns the value of all locked funds in the ticket pool as
+// of the block with the provided hash.  Results are cached up to a maximum
+// number of blocks, at which point random entries are evicted.
+//
+// This function is safe for concurrent access.
+func (s *rpcServer) ticketPoolValue(hash *chainhash.Hash) (dcrutil.Amount, error) {
+	c := &s.ticketPoolValues
+	c.Lock()
+	amt, ok := c.values[*hash]
+	c.Unlock()
+	if ok {
+		return amt, nil
+	}
+
+	amt, err := s.chain.TicketPoolValueByHash(hash)
+	if err != nil {
+		return 0, err
+	}
+
+	c.Lock()
+	if c.values == nil {
+		c.values = make(map[chainhash.Hash]dcrutil.Amount)
+	}
+	for k := range c.values {
+		if len(c.values) < maxTicketPoolValueCacheEntries {
+			break
+		}
+		delete(c.values, k)
+	}
+	c.values[*hash] = amt
+	c.Unlock()
+	return amt, nil
+}
+
+// blockHashFromHashOrHeight returns the hash of the block identified by the
+// provided string, which is either the hash of a known block or the height of
+// a block in the main chain.  An appropriate RPC error is returned when the
+// string is invalid or the block does not exist.
+func (s *rpcServer) blockHashFromHashOrHeight(block string) (*chainhash.Hash, error) {
+	var hash *chainhash.Hash
+	if len(block) == chainhash.MaxHashStringSize {
+		var err error
+		hash, err = chainhash.NewHashFromStr(block)
+		if err != nil {
+			return nil, rpcDecodeHexError(block)
+		}
+	} else {
+		height, err := strconv.ParseInt(block, 10, 64)
+		if err != nil {
+			return nil, rpcInvalidError("Block must be a hash or height: "+
+				"%v", block)
+		}
+		hash, err = s.chain.BlockHashByHeight(height)
+		if err != nil {
+			return nil, &dcrjson.RPCError{
+				Code: dcrjson.ErrRPCOutOfRange,
+				Message: fmt.Sprintf("Block number out of range: %v",
+					height),
+			}
+		}
+	}
+	if _, err := s.chain.HeaderByHash(hash); err != nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCBlockNotFound,
+			Message: fmt.Sprintf("Block not found: %v", hash),
+		}
+	}
+
+	return hash, nil
+}
+
 // handleGetTicketPoolValue implements the getticketpoolvalue command.
 func handleGetTicketPoolValue(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	amt, err := s.server.blockManager.TicketPoolValue()
+	c, ok := cmd.(*types.GetTicketPoolValueCmd)
+	if !ok {
+		return nil, rpcInvalidError("Invalid type: %T", c)
+	}
+
+	// Return the current value when no block is specified.
+	if c.Block == nil {
+		amt, err := s.server.blockManager.TicketPoolValue()
+		if err != nil {
+			return nil, rpcInternalError(err.Error(),
+				"Could not obtain ticket pool value")
+		}
+
+		return amt.ToCoin(), nil
+	}
+
+	// Determine the block to report the value as of from either its hash or
+	// its height in the main chain.
+	hash, err := s.blockHashFromHashOrHeight(*c.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	amt, err := s.ticketPoolValue(hash)
 	if err != nil {
 		return nil, rpcInternalError(err.Error(),
 			"Could not obtain ticket pool value")
@@ -3051,6 +4510,8 @@ func handleGetTxOut(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 	var scriptVersion uint16
 	var pkScript []byte
 	var isCoinbase bool
+	var hasExpiry bool
+	var originHeight, bestHeight int64
 	includeMempool := true
 	if c.IncludeMempool != nil {
 		includeMempool = *c.IncludeMempool
@@ -3084,6 +4545,7 @@ func handleGetTxOut(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		scriptVersion = txOut.Version
 		pkScript = txOut.PkScript
 		isCoinbase = standalone.IsCoinBaseTx(mtx)
+		hasExpiry = mtx.Expiry != wire.NoExpiryValue
 	} else {
 		entry, err := s.chain.FetchUtxoEntry(txHash)
 		if err != nil {
@@ -3107,6 +4569,9 @@ func handleGetTxOut(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		scriptVersion = entry.ScriptVersionByIndex(c.Vout)
 		pkScript = entry.PkScriptByIndex(c.Vout)
 		isCoinbase = entry.IsCoinBase()
+		hasExpiry = entry.HasExpiry()
+		originHeight = entry.BlockHeight()
+		bestHeight = best.Height
 	}
 
 	// Disassemble script into single line printable format.  The
@@ -3125,6 +4590,26 @@ func handleGetTxOut(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		addresses[i] = addr.Address()
 	}
 
+	// Determine whether or not the output may be spent by a regular
+	// transaction in the next block given the maturity rules.  Outputs of
+	// unmined transactions that are subject to a maturity requirement can't
+	// be spent until the transaction is mined and matures.
+	var spendable bool
+	var maturityHeight int64
+	maturity, spendableByRegular := txOutMaturity(s.server.chainParams,
+		isCoinbase, hasExpiry, scriptClass)
+	if spendableByRegular {
+		switch {
+		case confirmations == 0:
+			spendable = maturity == 0
+		case maturity == 0:
+			spendable = true
+		default:
+			maturityHeight = originHeight + maturity
+			spendable = bestHeight+1 >= maturityHeight
+		}
+	}
+
 	txOutReply := &types.GetTxOutResult{
 		BestBlock:     bestBlockHash,
 		Confirmations: confirmations,
@@ -3137,11 +4622,38 @@ func handleGetTxOut(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 			Type:      scriptClass.String(),
 			Addresses: addresses,
 		},
-		Coinbase: isCoinbase,
+		Coinbase:       isCoinbase,
+		Spendable:      spendable,
+		MaturityHeight: maturityHeight,
 	}
 	return txOutReply, nil
 }
 
+// txOutMaturity returns the number of blocks after the block that contains a
+// transaction that must elapse before an output of the transaction with the
+// provided characteristics may be spent.  It also returns whether or not the
+// output may be spent by regular transactions at all, which is not the case for
+// ticket submission outputs since only votes and revocations may spend them.
+func txOutMaturity(params *chaincfg.Params, isCoinbase, hasExpiry bool, scriptClass txscript.ScriptClass) (int64, bool) {
+	if scriptClass == txscript.StakeSubmissionTy {
+		return 0, false
+	}
+
+	var maturity int64
+	if isCoinbase || hasExpiry {
+		maturity = int64(params.CoinbaseMaturity)
+	}
+	switch scriptClass {
+	case txscript.StakeGenTy, txscript.StakeRevocationTy,
+		txscript.StakeSubChangeTy:
+
+		if int64(params.SStxChangeMaturity) > maturity {
+			maturity = int64(params.SStxChangeMaturity)
+		}
+	}
+	return maturity, true
+}
+
 // pruneOldBlockTemplates prunes all old block templates from the templatePool
 // map. Must be called with the RPC workstate locked to avoid races to the map.
 func pruneOldBlockTemplates(s *rpcServer, bestHeight int64) {
@@ -3310,8 +4822,9 @@ func handleGetWorkRequest(s *rpcServer) (interface{}, error) {
 	// implementation, but it is required for compatibility.
 	target := bigToLEUint256(standalone.CompactToBig(msgBlock.Header.Bits))
 	reply := &types.GetWorkResult{
-		Data:   hex.EncodeToString(data),
-		Target: hex.EncodeToString(target[:]),
+		Data:         hex.EncodeToString(data),
+		Target:       hex.EncodeToString(target[:]),
+		PreviousHash: msgBlock.Header.PrevBlock.String(),
 	}
 	return reply, nil
 }
@@ -3513,6 +5026,48 @@ func handleHelp(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (inter
 	return help, nil
 }
 
+// handleIsBlockFinal implements the isblockfinal command.
+func handleIsBlockFinal(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c, ok := cmd.(*types.IsBlockFinalCmd)
+	if !ok {
+		return nil, rpcInvalidError("Invalid type: %T", c)
+	}
+
+	depth := cfg.FinalityDepth
+	if c.Depth != nil {
+		depth = *c.Depth
+	}
+	if depth < 1 {
+		return nil, rpcInvalidError("Finality depth must be at least 1")
+	}
+
+	hash, err := s.blockHashFromHashOrHeight(c.Block)
+	if err != nil {
+		return nil, err
+	}
+	header, err := s.chain.HeaderByHash(hash)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Failed to fetch header")
+	}
+
+	// Blocks that are not in the main chain have no confirmations and are
+	// never final.
+	height := int64(header.Height)
+	confirmations := int64(-1)
+	if s.chain.MainChainHasBlock(hash) {
+		best := s.chain.BestSnapshot()
+		confirmations = 1 + best.Height - height
+	}
+
+	return &types.IsBlockFinalResult{
+		Hash:          hash.String(),
+		Height:        height,
+		Confirmations: confirmations,
+		FinalityDepth: depth,
+		Final:         confirmations >= int64(depth),
+	}, nil
+}
+
 // handleLiveTickets implements the livetickets command.
 func handleLiveTickets(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	lt, err := s.server.chain.LiveTickets()
@@ -3558,6 +5113,39 @@ func handlePing(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (inter
 	return nil, nil
 }
 
+// handleRelayBlock implements the relayblock command.
+func handleRelayBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	// Only allow forcing block announcements on the test networks that are
+	// intended for local testing to prevent abusing it to spam the network.
+	if !cfg.SimNet && !cfg.RegNet {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCMisc,
+			Message: "The relayblock RPC is only available on the " +
+				"simulation and regression test networks",
+		}
+	}
+
+	c := cmd.(*types.RelayBlockCmd)
+	hash, err := chainhash.NewHashFromStr(c.Hash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Hash)
+	}
+	block, err := s.server.chain.BlockByHash(hash)
+	if err != nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCBlockNotFound,
+			Message: fmt.Sprintf("Block not found: %v", hash),
+		}
+	}
+
+	// Relay the block to all peers that are not already known to have it in
+	// the same way as newly connected blocks.
+	iv := wire.NewInvVect(wire.InvTypeBlock, hash)
+	s.server.RelayInventory(iv, block, true)
+
+	return nil, nil
+}
+
 // retrievedTx represents a transaction that was either loaded from the
 // transaction memory pool or from the database.  When a transaction is loaded
 // from the database, it is loaded with the raw serialized bytes while the
@@ -3645,6 +5233,44 @@ func fetchInputTxos(s *rpcServer, tx *wire.MsgTx) (map[wire.OutPoint]wire.TxOut,
 	return originOutputs, nil
 }
 
+// addVinPrevOuts populates the previous output details of the passed JSON
+// objects for the inputs of the passed transaction.  Coinbase and stakebase
+// inputs are left unmodified since they do not reference a previous output.
+func addVinPrevOuts(s *rpcServer, vinList []types.Vin, mtx *wire.MsgTx) error {
+	if standalone.IsCoinBaseTx(mtx) {
+		return nil
+	}
+
+	originOutputs, err := fetchInputTxos(s, mtx)
+	if err != nil {
+		return err
+	}
+	for i := range vinList {
+		vin := &vinList[i]
+		if vin.IsStakeBase() {
+			continue
+		}
+		originTxOut, ok := originOutputs[mtx.TxIn[i].PreviousOutPoint]
+		if !ok {
+			continue
+		}
+
+		// Ignore the error here since an error means the script couldn't
+		// parse and there is no additional information about it anyways.
+		_, addrs, _, _ := txscript.ExtractPkScriptAddrs(originTxOut.Version,
+			originTxOut.PkScript, s.server.chainParams)
+		encodedAddrs := make([]string, len(addrs))
+		for j, addr := range addrs {
+			encodedAddrs[j] = addr.Address()
+		}
+		vin.PrevOut = &types.PrevOut{
+			Addresses: encodedAddrs,
+			Value:     dcrutil.Amount(originTxOut.Value).ToCoin(),
+		}
+	}
+	return nil
+}
+
 // createVinListPrevOut returns a slice of JSON objects for the inputs of the
 // passed transaction.
 func createVinListPrevOut(s *rpcServer, mtx *wire.MsgTx, chainParams *chaincfg.Params, vinExtra bool, filterAddrMap map[string]struct{}) ([]types.VinPrevOut, error) {
@@ -3797,13 +5423,39 @@ func fetchMempoolTxnsForAddress(s *rpcServer, addr dcrutil.Address, numToSkip, n
 		return nil, numAvailable
 	}
 
-	// Filter the available entries based on the number to skip and number
-	// requested.
-	rangeEnd := numToSkip + numRequested
-	if rangeEnd > numAvailable {
-		rangeEnd = numAvailable
+	// Filter the available entries based on the number to skip and number
+	// requested.
+	rangeEnd := numToSkip + numRequested
+	if rangeEnd > numAvailable {
+		rangeEnd = numAvailable
+	}
+	return mpTxns[numToSkip:rangeEnd], numToSkip
+}
+
+// handleReloadWhitelists implements the reloadwhitelists command.
+func handleReloadWhitelists(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	nets, err := reloadWhitelists()
+	if err != nil {
+		return nil, rpcMiscError(fmt.Sprintf("Unable to reload whitelists: "+
+			"%v", err))
+	}
+	s.server.whitelist.replace(nets)
+	rpcsLog.Infof("Reloaded %d whitelisted networks", len(nets))
+
+	results := make([]string, 0, len(nets))
+	for _, ipnet := range nets {
+		results = append(results, ipnet.String())
+	}
+	return results, nil
+}
+
+// handleRestart implements the restart command.
+func handleRestart(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	select {
+	case s.requestProcessRestart <- struct{}{}:
+	default:
 	}
-	return mpTxns[numToSkip:rangeEnd], numToSkip
+	return "dcrd restarting.", nil
 }
 
 // handleSearchRawTransactions implements the searchrawtransactions command.
@@ -3840,6 +5492,23 @@ func handleSearchRawTransactions(s *rpcServer, cmd interface{}, closeChan <-chan
 			err)
 	}
 
+	// Determine the total number of transactions, both confirmed and
+	// unconfirmed, that involve the address when requested.
+	includeTotal := c.IncludeTotal != nil && *c.IncludeTotal
+	var total uint32
+	if includeTotal {
+		err := s.server.db.View(func(dbTx database.Tx) error {
+			var err error
+			total, err = addrIndex.NumEntriesForAddress(dbTx, addr)
+			return err
+		})
+		if err != nil {
+			context := "Failed to count address index entries"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		total += uint32(len(addrIndex.UnconfirmedTxnsForAddress(addr)))
+	}
+
 	// Override the default number of requested entries if needed.  Also,
 	// just return now if the number of requested entries is zero to avoid
 	// extra work.
@@ -3851,6 +5520,9 @@ func handleSearchRawTransactions(s *rpcServer, cmd interface{}, closeChan <-chan
 		}
 	}
 	if numRequested == 0 {
+		if includeTotal {
+			return &types.SearchRawTransactionsTotalResult{Total: total}, nil
+		}
 		return nil, nil
 	}
 
@@ -3958,30 +5630,6 @@ func handleSearchRawTransactions(s *rpcServer, cmd interface{}, closeChan <-chan
 		return nil, rpcInternalError("No Txns available", "")
 	}
 
-	// Serialize all of the transactions to hex.
-	hexTxns := make([]string, len(addressTxns))
-	for i := range addressTxns {
-		// Simply encode the raw bytes to hex when the retrieved
-		// transaction is already in serialized form.
-		rtx := &addressTxns[i]
-		if rtx.txBytes != nil {
-			hexTxns[i] = hex.EncodeToString(rtx.txBytes)
-			continue
-		}
-
-		// Serialize the transaction first and convert to hex when the
-		// retrieved transaction is the deserialized structure.
-		hexTxns[i], err = messageToHex(rtx.tx.MsgTx())
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	// When not in verbose mode, simply return a list of serialized txns.
-	if c.Verbose != nil && *c.Verbose == 0 {
-		return hexTxns, nil
-	}
-
 	// Normalize the provided filter addresses (if any) to ensure there are
 	// no duplicates.
 	filterAddrMap := make(map[string]struct{})
@@ -3991,16 +5639,36 @@ func handleSearchRawTransactions(s *rpcServer, cmd interface{}, closeChan <-chan
 		}
 	}
 
-	// The verbose flag is set, so generate the JSON object and return it.
+	// txResult returns the result for the passed retrieved transaction, which
+	// is the serialized transaction when not in verbose mode and the JSON
+	// object otherwise.
+	verbose := c.Verbose == nil || *c.Verbose != 0
 	best := s.chain.BestSnapshot()
 	chainParams := s.server.chainParams
-	srtList := make([]types.SearchRawTransactionsResult, len(addressTxns))
-	for i := range addressTxns {
+	txResult := func(rtx *retrievedTx) (interface{}, error) {
+		// Simply encode the raw bytes to hex when the retrieved
+		// transaction is already in serialized form.  Otherwise,
+		// serialize the transaction first and convert to hex.
+		var hexTx string
+		if rtx.txBytes != nil {
+			hexTx = hex.EncodeToString(rtx.txBytes)
+		} else {
+			var err error
+			hexTx, err = messageToHex(rtx.tx.MsgTx())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// When not in verbose mode, simply return the serialized txn.
+		if !verbose {
+			return hexTx, nil
+		}
+
 		// The deserialized transaction is needed, so deserialize the
 		// retrieved transaction if it's in serialized form (which will
 		// be the case when it was lookup up from the database).
 		// Otherwise, use the existing deserialized transaction.
-		rtx := &addressTxns[i]
 		var mtx *wire.MsgTx
 		if rtx.tx == nil {
 			// Deserialize the transaction.
@@ -4015,10 +5683,11 @@ func handleSearchRawTransactions(s *rpcServer, cmd interface{}, closeChan <-chan
 			mtx = rtx.tx.MsgTx()
 		}
 
-		result := &srtList[i]
-		result.Hex = hexTxns[i]
+		var err error
+		result := new(types.SearchRawTransactionsResult)
+		result.Hex = hexTx
 		result.Txid = mtx.TxHash().String()
-		result.Vin, err = createVinListPrevOut(s, mtx, s.server.chainParams,
+		result.Vin, err = createVinListPrevOut(s, mtx, chainParams,
 			vinExtra, filterAddrMap)
 		if err != nil {
 			return nil, rpcInternalError(err.Error(),
@@ -4033,10 +5702,6 @@ func handleSearchRawTransactions(s *rpcServer, cmd interface{}, closeChan <-chan
 		// so conditionally fetch block details here.  This will be
 		// reflected in the final JSON output (mempool won't have
 		// confirmations or block information).
-		var blkHeader *wire.BlockHeader
-		var blkHashStr string
-		var blkHeight int64
-		var blkIndex uint32
 		if blkHash := rtx.blkHash; blkHash != nil {
 			// Fetch the header from chain.
 			header, err := s.chain.HeaderByHash(blkHash)
@@ -4054,26 +5719,57 @@ func handleSearchRawTransactions(s *rpcServer, cmd interface{}, closeChan <-chan
 				return nil, rpcInternalError(err.Error(), context)
 			}
 
-			blkHeader = &header
-			blkHashStr = blkHash.String()
-			blkHeight = height
-			blkIndex = rtx.blkIndex
+			// Add the block information to the result.  This is
+			// not a typo, they are identical in Bitcoin Core as
+			// well.
+			result.Time = header.Timestamp.Unix()
+			result.Blocktime = header.Timestamp.Unix()
+			result.BlockHash = blkHash.String()
+			result.BlockHeight = height
+			result.BlockIndex = rtx.blkIndex
+			result.Confirmations = uint64(1 + best.Height - height)
 		}
+		return result, nil
+	}
 
-		// Add the block information to the result if there is any.
-		if blkHeader != nil {
-			// This is not a typo, they are identical in Bitcoin
-			// Core as well.
-			result.Time = blkHeader.Timestamp.Unix()
-			result.Blocktime = blkHeader.Timestamp.Unix()
-			result.BlockHash = blkHashStr
-			result.BlockHeight = blkHeight
-			result.BlockIndex = blkIndex
-			result.Confirmations = uint64(1 + best.Height - blkHeight)
-		}
+	// Produce the results one transaction at a time as they are consumed
+	// so they can be streamed to the client without building the entire
+	// list first.
+	results := &streamedArray{
+		forEach: func(fn func(elem interface{}) error) error {
+			for i := range addressTxns {
+				result, err := txResult(&addressTxns[i])
+				if err != nil {
+					return err
+				}
+				if err := fn(result); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
 	}
 
-	return srtList, nil
+	// The total is returned in an object along with the list, so the list
+	// has to be built in that case.
+	if includeTotal {
+		totalResult := &types.SearchRawTransactionsTotalResult{Total: total}
+		err := results.forEach(func(elem interface{}) error {
+			switch elem := elem.(type) {
+			case string:
+				totalResult.Hex = append(totalResult.Hex, elem)
+			case *types.SearchRawTransactionsResult:
+				totalResult.Transactions = append(
+					totalResult.Transactions, *elem)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return totalResult, nil
+	}
+	return results, nil
 }
 
 // handleSendRawTransaction implements the sendrawtransaction command.
@@ -4142,6 +5838,43 @@ func handleSendRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan st
 	return tx.Hash().String(), nil
 }
 
+// handleSetBan implements the setban command.
+func handleSetBan(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.SetBanCmd)
+
+	ipNet, err := parseSubnet(c.Address)
+	if err != nil {
+		return nil, rpcInvalidError("Invalid address %q: %v", c.Address,
+			err)
+	}
+
+	switch c.Command {
+	case types.SBAdd:
+		// Use the configured ban duration when not specified.
+		duration := cfg.BanDuration
+		if c.BanTime != nil && *c.BanTime != 0 {
+			// Reject ban times that would overflow the duration.
+			const maxBanTime = math.MaxInt64 / int64(time.Second)
+			if *c.BanTime < 0 || *c.BanTime > maxBanTime {
+				return nil, rpcInvalidError("Ban time must be between "+
+					"0 and %d seconds", maxBanTime)
+			}
+			duration = time.Duration(*c.BanTime) * time.Second
+		}
+		s.server.SetBan(ipNet, duration)
+
+	case types.SBRemove:
+		if err := s.server.ClearBan(ipNet); err != nil {
+			return nil, rpcInvalidError("%v: %v", c.Command, err)
+		}
+
+	default:
+		return nil, rpcInvalidError("Invalid subcommand for setban")
+	}
+
+	return nil, nil
+}
+
 // handleSetGenerate implements the setgenerate command.
 func handleSetGenerate(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*types.SetGenerateCmd)
@@ -4292,9 +6025,41 @@ func stdDev(s []dcrutil.Amount) dcrutil.Amount {
 	return amt
 }
 
+// percentile gets the amount at the passed percentile from a slice of amounts
+// using the nearest-rank method.  The percentile must be in the range (0, 100].
+func percentile(s []dcrutil.Amount, p float64) dcrutil.Amount {
+	if len(s) == 0 {
+		return 0
+	}
+
+	sort.Sort(dcrutil.AmountSorter(s))
+
+	rank := int(math.Ceil(p / 100 * float64(len(s))))
+	if rank < 1 {
+		rank = 1
+	}
+	return s[rank-1]
+}
+
+// feePercentiles returns the percentile breakdown for the passed fees when
+// requested, or nil otherwise so the field is omitted from the result.
+func feePercentiles(fees []dcrutil.Amount, requested bool) *types.FeeInfoPercentiles {
+	if !requested {
+		return nil
+	}
+
+	return &types.FeeInfoPercentiles{
+		P10: percentile(fees, 10).ToCoin(),
+		P25: percentile(fees, 25).ToCoin(),
+		P75: percentile(fees, 75).ToCoin(),
+		P90: percentile(fees, 90).ToCoin(),
+	}
+}
+
 // feeInfoForMempool returns the fee information for the passed tx type in the
-// memory pool.
-func feeInfoForMempool(s *rpcServer, txType stake.TxType) *types.FeeInfoMempool {
+// memory pool.  The percentile breakdown is only calculated when
+// withPercentiles is set.
+func feeInfoForMempool(s *rpcServer, txType stake.TxType, withPercentiles bool) *types.FeeInfoMempool {
 	txDs := s.server.txMemPool.TxDescs()
 	ticketFees := make([]dcrutil.Amount, 0, len(txDs))
 	for _, txD := range txDs {
@@ -4312,6 +6077,8 @@ func feeInfoForMempool(s *rpcServer, txType stake.TxType) *types.FeeInfoMempool
 		Mean:   mean(ticketFees).ToCoin(),
 		Median: median(ticketFees).ToCoin(),
 		StdDev: stdDev(ticketFees).ToCoin(),
+
+		Percentiles: feePercentiles(ticketFees, withPercentiles),
 	}
 }
 
@@ -4331,8 +6098,9 @@ func calcFeePerKb(tx *dcrutil.Tx) dcrutil.Amount {
 }
 
 // feeInfoForBlock fetches the ticket fee information for a given tx type in a
-// block.
-func ticketFeeInfoForBlock(s *rpcServer, height int64, txType stake.TxType) (*types.FeeInfoBlock, error) {
+// block.  The percentile breakdown is only calculated when withPercentiles is
+// set.
+func ticketFeeInfoForBlock(s *rpcServer, height int64, txType stake.TxType, withPercentiles bool) (*types.FeeInfoBlock, error) {
 	bl, err := s.chain.BlockByHeight(height)
 	if err != nil {
 		return nil, err
@@ -4380,12 +6148,15 @@ func ticketFeeInfoForBlock(s *rpcServer, height int64, txType stake.TxType) (*ty
 		Mean:   mean(txFees).ToCoin(),
 		Median: median(txFees).ToCoin(),
 		StdDev: stdDev(txFees).ToCoin(),
+
+		Percentiles: feePercentiles(txFees, withPercentiles),
 	}, nil
 }
 
 // ticketFeeInfoForRange fetches the ticket fee information for a given range
-// from [start, end).
-func ticketFeeInfoForRange(s *rpcServer, start int64, end int64, txType stake.TxType) (*types.FeeInfoWindow, error) {
+// from [start, end).  The percentile breakdown is only calculated when
+// withPercentiles is set.
+func ticketFeeInfoForRange(s *rpcServer, start int64, end int64, txType stake.TxType, withPercentiles bool) (*types.FeeInfoWindow, error) {
 	hashes, err := s.chain.HeightRange(start, end)
 	if err != nil {
 		return nil, err
@@ -4426,6 +6197,8 @@ func ticketFeeInfoForRange(s *rpcServer, start int64, end int64, txType stake.Tx
 		Mean:        mean(txFees).ToCoin(),
 		Median:      median(txFees).ToCoin(),
 		StdDev:      stdDev(txFees).ToCoin(),
+
+		Percentiles: feePercentiles(txFees, withPercentiles),
 	}, nil
 }
 
@@ -4436,7 +6209,7 @@ func handleTicketFeeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 	bestHeight := s.server.chain.BestSnapshot().Height
 
 	// Memory pool first.
-	feeInfoMempool := feeInfoForMempool(s, stake.TxTypeSStx)
+	feeInfoMempool := feeInfoForMempool(s, stake.TxTypeSStx, false)
 
 	// Blocks requested, descending from the chain tip.
 	var feeInfoBlocks []types.FeeInfoBlock
@@ -4449,7 +6222,8 @@ func handleTicketFeeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 		end := bestHeight - int64(blocks)
 
 		for i := start; i > end; i-- {
-			feeInfo, err := ticketFeeInfoForBlock(s, i, stake.TxTypeSStx)
+			feeInfo, err := ticketFeeInfoForBlock(s, i, stake.TxTypeSStx,
+				false)
 			if err != nil {
 				return nil, rpcInternalError(err.Error(),
 					"Could not obtain ticket fee info")
@@ -4471,7 +6245,7 @@ func handleTicketFeeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 		lastChange := (bestHeight / winLen) * winLen
 
 		feeInfo, err := ticketFeeInfoForRange(s, lastChange, bestHeight+1,
-			stake.TxTypeSStx)
+			stake.TxTypeSStx, false)
 		if err != nil {
 			return nil, rpcInternalError(err.Error(),
 				"Could not obtain ticket fee info")
@@ -4493,7 +6267,7 @@ func handleTicketFeeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 			}
 			for i := lastChange; i > end+winLen; i -= winLen {
 				feeInfo, err := ticketFeeInfoForRange(s, i-winLen, i,
-					stake.TxTypeSStx)
+					stake.TxTypeSStx, false)
 				if err != nil {
 					return nil, rpcInternalError(err.Error(),
 						"Could not obtain ticket fee info")
@@ -4539,6 +6313,55 @@ func handleTicketsForAddress(s *rpcServer, cmd interface{}, closeChan <-chan str
 	return reply, nil
 }
 
+// handleTicketsForAddresses implements the ticketsforaddresses command.
+func handleTicketsForAddresses(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*types.TicketsForAddressesCmd)
+
+	if len(c.Addresses) > maxTicketsForAddresses {
+		return nil, rpcInvalidError("Number of addresses %d exceeds the "+
+			"maximum of %d", len(c.Addresses), maxTicketsForAddresses)
+	}
+
+	// Decode all of the provided addresses before looking up any tickets so
+	// an invalid address does not result in wasted work.  This also ensures
+	// the network encoded with the addresses matches the network the server
+	// is currently on.
+	addrs := make([]dcrutil.Address, 0, len(c.Addresses))
+	for _, addrStr := range c.Addresses {
+		addr, err := dcrutil.DecodeAddress(addrStr, s.server.chainParams)
+		if err != nil {
+			return nil, rpcInvalidError("Invalid address %q: %v", addrStr,
+				err)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	ticketsByAddr := make(map[string][]string, len(addrs))
+	for i, addr := range addrs {
+		addrStr := c.Addresses[i]
+		if _, ok := ticketsByAddr[addrStr]; ok {
+			continue
+		}
+
+		tickets, err := s.server.chain.TicketsWithAddress(addr)
+		if err != nil {
+			return nil, rpcInternalError(err.Error(),
+				"could not obtain tickets")
+		}
+
+		ticketStrings := make([]string, 0, len(tickets))
+		for _, ticket := range tickets {
+			ticketStrings = append(ticketStrings, ticket.String())
+		}
+		ticketsByAddr[addrStr] = ticketStrings
+	}
+
+	reply := &types.TicketsForAddressesResult{
+		Tickets: ticketsByAddr,
+	}
+	return reply, nil
+}
+
 // handleTicketVWAP implements the ticketvwap command.
 func handleTicketVWAP(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*types.TicketVWAPCmd)
@@ -4601,10 +6424,26 @@ func handleTicketVWAP(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 func handleTxFeeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*types.TxFeeInfoCmd)
 
-	bestHeight := s.server.chain.BestSnapshot().Height
+	withPercentiles := c.Percentiles != nil && *c.Percentiles
+	mempoolOnly := c.MempoolOnly != nil && *c.MempoolOnly
 
 	// Memory pool first.
-	feeInfoMempool := feeInfoForMempool(s, stake.TxTypeRegular)
+	feeInfoMempool := feeInfoForMempool(s, stake.TxTypeRegular,
+		withPercentiles)
+
+	// Skip the block scans entirely when only the memory pool information
+	// is requested.
+	if mempoolOnly {
+		if c.Blocks != nil || c.RangeStart != nil || c.RangeEnd != nil {
+			return nil, rpcInvalidError("Block and range parameters " +
+				"may not be specified in mempool-only mode")
+		}
+		return &types.TxFeeInfoResult{
+			FeeInfoMempool: *feeInfoMempool,
+		}, nil
+	}
+
+	bestHeight := s.server.chain.BestSnapshot().Height
 
 	// Blocks requested, descending from the chain tip.
 	var feeInfoBlocks []types.FeeInfoBlock
@@ -4618,7 +6457,7 @@ func handleTxFeeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (
 
 		for i := start; i > end; i-- {
 			feeInfo, err := ticketFeeInfoForBlock(s, i,
-				stake.TxTypeRegular)
+				stake.TxTypeRegular, withPercentiles)
 			if err != nil {
 				return nil, rpcInternalError(err.Error(),
 					"Could not obtain ticket fee info")
@@ -4660,7 +6499,7 @@ func handleTxFeeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (
 	}
 
 	feeInfo, err := ticketFeeInfoForRange(s, int64(start), int64(end+1),
-		stake.TxTypeRegular)
+		stake.TxTypeRegular, withPercentiles)
 	if err != nil {
 		return nil, rpcInternalError(err.Error(),
 			"Could not obtain ticket fee info")
@@ -4673,6 +6512,8 @@ func handleTxFeeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (
 		Mean:   feeInfo.Mean,
 		Median: feeInfo.Median,
 		StdDev: feeInfo.StdDev,
+
+		Percentiles: feeInfo.Percentiles,
 	}
 
 	return &types.TxFeeInfoResult{
@@ -4849,6 +6690,7 @@ type rpcServer struct {
 	subsidyCache           *standalone.SubsidyCache
 	authsha                [sha256.Size]byte
 	limitauthsha           [sha256.Size]byte
+	adminClientCNs         map[string]struct{}
 	ntfnMgr                *wsNotificationManager
 	numClients             int32
 	statusLines            map[int]string
@@ -4859,6 +6701,19 @@ type rpcServer struct {
 	templatePool           map[[merkleRootPairSize]byte]*workStateBlockInfo
 	helpCacher             *helpCacher
 	requestProcessShutdown chan struct{}
+	requestProcessRestart  chan struct{}
+
+	// coinSupply caches the coin supply breakdown for the verbose
+	// getcoinsupply results.
+	coinSupply coinSupplyCache
+
+	// ticketPoolValues caches the ticket pool values as of recently
+	// requested blocks for the getticketpoolvalue results.
+	ticketPoolValues ticketPoolValueCache
+
+	// rescanSem limits the number of rescans that may run concurrently.  It
+	// is nil when the number is unlimited.
+	rescanSem semaphore
 }
 
 // httpStatusLine returns a response Status-Line (RFC 2616 Section 6.1) for the
@@ -4945,6 +6800,13 @@ func (s *rpcServer) RequestedProcessShutdown() <-chan struct{} {
 	return s.requestProcessShutdown
 }
 
+// RequestedProcessRestart returns a channel that is sent to when an authorized
+// RPC client requests the process to shutdown in order to be restarted.  If the
+// request can not be read immediately, it is dropped.
+func (s *rpcServer) RequestedProcessRestart() <-chan struct{} {
+	return s.requestProcessRestart
+}
+
 // limitConnections responds with a 503 service unavailable and returns true if
 // adding another client would exceed the maximum allow RPC clients.
 //
@@ -4981,7 +6843,9 @@ func (s *rpcServer) decrementClients() {
 
 // checkAuth checks the HTTP Basic authentication supplied by a wallet or RPC
 // client in the HTTP request r.  If the supplied authentication does not match
-// the username and password expected, a non-nil error is returned.
+// the username and password expected, a non-nil error is returned.  Clients
+// that presented a verified client certificate are authenticated without
+// checking the HTTP Basic authentication.
 //
 // This check is time-constant.
 //
@@ -4992,6 +6856,19 @@ func (s *rpcServer) decrementClients() {
 func (s *rpcServer) checkAuth(r *http.Request, require bool) (bool, bool, error) {
 	authhdr := r.Header["Authorization"]
 	if len(authhdr) <= 0 {
+		// Clients that presented a certificate which was verified against
+		// the configured client certificate authorities are authenticated
+		// by it when they do not provide credentials.  The access is
+		// limited unless the common name of the certificate is one of the
+		// configured admin common names.  Credentials that are provided
+		// are always checked below, so a valid certificate does not make
+		// up for invalid credentials.
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			commonName := r.TLS.VerifiedChains[0][0].Subject.CommonName
+			_, isAdmin := s.adminClientCNs[commonName]
+			return true, isAdmin, nil
+		}
+
 		if require {
 			rpcsLog.Warnf("RPC authentication failure from %s",
 				r.RemoteAddr)
@@ -5025,11 +6902,12 @@ func (s *rpcServer) checkAuth(r *http.Request, require bool) (bool, bool, error)
 // a known concrete command along with any error that might have happened while
 // parsing it.
 type parsedRPCCmd struct {
-	jsonrpc string
-	id      interface{}
-	method  types.Method
-	params  interface{}
-	err     *dcrjson.RPCError
+	jsonrpc   string
+	id        interface{}
+	method    types.Method
+	params    interface{}
+	rawParams []json.RawMessage
+	err       *dcrjson.RPCError
 }
 
 // standardCmdResult checks that a parsed command is a standard Bitcoin
@@ -5056,18 +6934,71 @@ handled:
 	return handler(s, cmd.params, closeChan)
 }
 
+// translateGetBlockVerbosity converts the integer verbosity level that may be
+// provided as the second parameter of a getblock request for compatibility with
+// tooling written for Bitcoin Core into the equivalent verbose flags.  The
+// levels are as follows:
+//
+//	0: hex-encoded block (verbose=false)
+//	1: JSON object with transaction hashes (verbose=true)
+//	2: JSON object with transaction details (verbose=true, verbosetx=true)
+//	3: level 2 plus previous output details (all flags true)
+//
+// The params are returned unmodified when the second parameter is not a
+// number.
+func translateGetBlockVerbosity(params []json.RawMessage) ([]json.RawMessage, error) {
+	if len(params) < 2 {
+		return params, nil
+	}
+	var verbosity json.Number
+	decoder := json.NewDecoder(bytes.NewReader(params[1]))
+	decoder.UseNumber()
+	if err := decoder.Decode(&verbosity); err != nil {
+		return params, nil
+	}
+	if len(params) > 2 {
+		return nil, errors.New("no further parameters may be specified " +
+			"with an integer verbosity level")
+	}
+	level, err := verbosity.Int64()
+	if err != nil || level < 0 || level > 3 {
+		return nil, fmt.Errorf("verbosity level %s is invalid -- must be "+
+			"0, 1, 2, or 3", verbosity)
+	}
+
+	// Each level above the first enables the next verbose flag.
+	verbose := json.RawMessage(strconv.FormatBool(level > 0))
+	translated := []json.RawMessage{params[0], verbose}
+	for i := int64(2); i <= level; i++ {
+		translated = append(translated, json.RawMessage("true"))
+	}
+	return translated, nil
+}
+
 // parseCmd parses a JSON-RPC request object into known concrete command.  The
 // err field of the returned parsedRPCCmd struct will contain an RPC error that
 // is suitable for use in replies if the command is invalid in some way such as
 // an unregistered command or invalid parameters.
 func parseCmd(request *dcrjson.Request) *parsedRPCCmd {
 	parsedCmd := parsedRPCCmd{
-		jsonrpc: request.Jsonrpc,
-		id:      request.ID,
-		method:  types.Method(request.Method),
+		jsonrpc:   request.Jsonrpc,
+		id:        request.ID,
+		method:    types.Method(request.Method),
+		rawParams: request.Params,
+	}
+
+	rawParams := request.Params
+	if parsedCmd.method == "getblock" {
+		var err error
+		rawParams, err = translateGetBlockVerbosity(rawParams)
+		if err != nil {
+			parsedCmd.err = rpcInvalidError("Failed to parse request: %v",
+				err)
+			return &parsedCmd
+		}
 	}
 
-	params, err := dcrjson.ParseParams(types.Method(request.Method), request.Params)
+	params, err := dcrjson.ParseParams(types.Method(request.Method), rawParams)
 	if err != nil {
 		// When the error is because the method is not registered,
 		// produce a method not found RPC error.
@@ -5091,6 +7022,19 @@ func parseCmd(request *dcrjson.Request) *parsedRPCCmd {
 // passed parameters.  It will automatically convert errors that are not of the
 // type *dcrjson.RPCError to the appropriate type as needed.
 func createMarshalledReply(rpcVersion string, id interface{}, result interface{}, replyErr error) ([]byte, error) {
+	// Produce and marshal the elements of streamed array results up front
+	// since the entire response is marshalled at once here.  Errors that
+	// occur while producing them, including exceeding the maximum response
+	// size, are returned to the client as usual.
+	if arr, ok := result.(*streamedArray); ok && replyErr == nil {
+		marshalled, err := arr.marshal(cfg.RPCMaxResponseSize)
+		if err != nil {
+			result, replyErr = nil, err
+		} else {
+			result = json.RawMessage(marshalled)
+		}
+	}
+
 	var jsonErr *dcrjson.RPCError
 	if replyErr != nil {
 		if jErr, ok := replyErr.(*dcrjson.RPCError); ok {
@@ -5103,52 +7047,98 @@ func createMarshalledReply(rpcVersion string, id interface{}, result interface{}
 	return dcrjson.MarshalResponse(rpcVersion, id, result, jsonErr)
 }
 
-// processRequest determines the incoming request type (single or batched),
-// parses it and returns a marshalled response.
-func (s *rpcServer) processRequest(request *dcrjson.Request, isAdmin bool, closeChan <-chan struct{}) []byte {
-	var result interface{}
-	var jsonErr error
+// limitResponseSize returns the passed marshalled reply unchanged when the
+// maximum response size is disabled or the reply is within it.  Otherwise, a
+// marshalled error reply is returned in its place so huge results are not
+// sent to the client.
+func (s *rpcServer) limitResponseSize(rpcVersion string, id interface{}, reply []byte) ([]byte, error) {
+	maxSize := cfg.RPCMaxResponseSize
+	if maxSize == 0 || int64(len(reply)) <= maxSize {
+		return reply, nil
+	}
+
+	jsonErr := &dcrjson.RPCError{
+		Code: dcrjson.ErrRPCOutOfMemory,
+		Message: fmt.Sprintf("Response size of %d bytes exceeds the "+
+			"maximum allowed size of %d bytes", len(reply), maxSize),
+	}
+	return createMarshalledReply(rpcVersion, id, nil, jsonErr)
+}
+
+// auditRPCRequest records the invocation of an RPC method with the passed
+// parameters by the client at the given remote address along with its result in
+// the RPC audit log when it is enabled.  Only a hash of the parameters is
+// logged since they may be large, such as raw transactions, or sensitive.
+func auditRPCRequest(remoteAddr string, isAdmin bool, method string, params []json.RawMessage, err error) {
+	if !cfg.RPCAuditLog {
+		return
+	}
+
+	user := "limited"
+	if isAdmin {
+		user = "admin"
+	}
+	paramsJSON, _ := json.Marshal(params)
+	paramsHash := sha256.Sum256(paramsJSON)
+	result := "success"
+	if err != nil {
+		result = "failure"
+		if rpcErr, ok := err.(*dcrjson.RPCError); ok {
+			result = fmt.Sprintf("failure (code %d)", rpcErr.Code)
+		}
+	}
+	rpcAuditLog.Infof("user=%s addr=%s method=%q params=%x result=%s", user,
+		remoteAddr, method, paramsHash[:], result)
+}
+
+// requestResult determines the result for the passed request from the client at
+// the given remote address.  The returned flag indicates whether or not a reply
+// must be sent to the client since valid requests with no ID (notifications)
+// must not have a response per the JSON-RPC spec.
+func (s *rpcServer) requestResult(request *dcrjson.Request, remoteAddr string, isAdmin bool, closeChan <-chan struct{}) (result interface{}, needsReply bool, err error) {
+	// Record the request in the audit log once its result is known.
+	defer func() {
+		auditRPCRequest(remoteAddr, isAdmin, request.Method, request.Params,
+			err)
+	}()
 
 	if !isAdmin {
 		if _, ok := rpcLimited[request.Method]; !ok {
-			jsonErr = rpcInvalidError("limited user not " +
+			return nil, true, rpcInvalidError("limited user not " +
 				"authorized for this method")
 		}
 	}
 
-	if jsonErr == nil {
-		if request.Method == "" || request.Params == nil {
-			jsonErr = &dcrjson.RPCError{
-				Code:    dcrjson.ErrRPCInvalidRequest.Code,
-				Message: fmt.Sprintf("Invalid request: malformed"),
-			}
-			msg, err := createMarshalledReply(request.Jsonrpc, request.ID, result, jsonErr)
-			if err != nil {
-				rpcsLog.Errorf("Failed to marshal reply: %v", err)
-				return nil
-			}
-			return msg
+	if request.Method == "" || request.Params == nil {
+		return nil, true, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCInvalidRequest.Code,
+			Message: fmt.Sprintf("Invalid request: malformed"),
 		}
+	}
 
-		// Valid requests with no ID (notifications) must not have a response
-		// per the JSON-RPC spec.
-		if request.ID == nil {
-			return nil
-		}
+	// Valid requests with no ID (notifications) must not have a response
+	// per the JSON-RPC spec.
+	if request.ID == nil {
+		return nil, false, nil
+	}
 
-		// Attempt to parse the JSON-RPC request into a known
-		// concrete command.
-		parsedCmd := parseCmd(request)
-		if parsedCmd.err != nil {
-			jsonErr = parsedCmd.err
-		} else {
-			result, jsonErr = s.standardCmdResult(parsedCmd,
-				closeChan)
-		}
+	// Attempt to parse the JSON-RPC request into a known concrete command.
+	parsedCmd := parseCmd(request)
+	if parsedCmd.err != nil {
+		return nil, true, parsedCmd.err
 	}
+	result, err = s.standardCmdResult(parsedCmd, closeChan)
+	return result, true, err
+}
 
-	// Marshal the response.
-	msg, err := createMarshalledReply(request.Jsonrpc, request.ID, result, jsonErr)
+// marshalRequestReply returns the marshalled response for the passed request
+// given its result and reply error while enforcing the maximum response size.
+func (s *rpcServer) marshalRequestReply(request *dcrjson.Request, result interface{}, replyErr error) []byte {
+	msg, err := createMarshalledReply(request.Jsonrpc, request.ID, result,
+		replyErr)
+	if err == nil {
+		msg, err = s.limitResponseSize(request.Jsonrpc, request.ID, msg)
+	}
 	if err != nil {
 		rpcsLog.Errorf("Failed to marshal reply: %v", err)
 		return nil
@@ -5156,6 +7146,135 @@ func (s *rpcServer) processRequest(request *dcrjson.Request, isAdmin bool, close
 	return msg
 }
 
+// processRequest determines the incoming request type (single or batched),
+// parses it and returns a marshalled response.
+func (s *rpcServer) processRequest(request *dcrjson.Request, remoteAddr string, isAdmin bool, closeChan <-chan struct{}) []byte {
+	result, needsReply, replyErr := s.requestResult(request, remoteAddr,
+		isAdmin, closeChan)
+	if !needsReply {
+		return nil
+	}
+	return s.marshalRequestReply(request, result, replyErr)
+}
+
+// streamedArray is a result for commands that return arrays which are
+// potentially huge.  The elements are produced one at a time as they are
+// consumed rather than all up front, which allows them to be written to HTTP
+// clients incrementally by writeStreamedReply without ever holding the entire
+// result in memory at once.
+type streamedArray struct {
+	// forEach produces each element of the array in order and invokes the
+	// passed function with it.  Iteration stops with the first error
+	// returned by either the function or producing an element.
+	forEach func(fn func(elem interface{}) error) error
+}
+
+// errResponseTooLarge returns an RPC error that indicates a response exceeds
+// the passed maximum response size.
+func errResponseTooLarge(maxSize int64) *dcrjson.RPCError {
+	return &dcrjson.RPCError{
+		Code: dcrjson.ErrRPCOutOfMemory,
+		Message: fmt.Sprintf("Response size exceeds the maximum allowed "+
+			"size of %d bytes", maxSize),
+	}
+}
+
+// marshal produces all elements of the array and returns their marshalled
+// JSON array encoding.  An out of memory RPC error is returned without
+// producing the remaining elements as soon as the encoding exceeds the passed
+// maximum size, unless it is 0.
+func (a *streamedArray) marshal(maxSize int64) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	err := a.forEach(func(elem interface{}) error {
+		marshalled, err := json.Marshal(elem)
+		if err != nil {
+			return err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(marshalled)
+		if maxSize != 0 && int64(buf.Len()) > maxSize {
+			return errResponseTooLarge(maxSize)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// writeStreamedReply writes a successful JSON-RPC response for the passed
+// request and array result using chunked transfer encoding.  Each element of
+// the array is produced, marshalled, and written as it is reached, so, unlike
+// the normal reply path, neither the result nor its marshalled encoding is
+// ever held in memory in its entirety.
+//
+// The headers are sent before the first element is produced, so the response
+// is aborted without the terminating chunk when producing an element fails so
+// the client sees a truncated response rather than a seemingly valid one.
+// Replies are only streamed when the maximum response size is unlimited since
+// an error reply could otherwise not be sent in place of the result once it
+// turns out to be too large.
+func (s *rpcServer) writeStreamedReply(r *http.Request, headers http.Header, w io.Writer, request *dcrjson.Request, result *streamedArray) error {
+	if !dcrjson.IsValidIDType(request.ID) {
+		return fmt.Errorf("id type %T is invalid", request.ID)
+	}
+	rpcVersion := request.Jsonrpc
+	if rpcVersion != "2.0" && rpcVersion != "1.0" {
+		rpcVersion = "1.0"
+	}
+	marshalledID, err := json.Marshal(request.ID)
+	if err != nil {
+		return err
+	}
+
+	headers.Set("Transfer-Encoding", "chunked")
+	err = s.writeHTTPResponseHeaders(r, headers, http.StatusOK, w)
+	if err != nil {
+		return err
+	}
+
+	cw := httputil.NewChunkedWriter(w)
+	prefix := `{"jsonrpc":"` + rpcVersion + `","result":[`
+	if _, err := io.WriteString(cw, prefix); err != nil {
+		return err
+	}
+	var numElems int
+	err = result.forEach(func(elem interface{}) error {
+		marshalled, err := json.Marshal(elem)
+		if err != nil {
+			return err
+		}
+		if numElems > 0 {
+			marshalled = append([]byte{','}, marshalled...)
+		}
+		numElems++
+		_, err = cw.Write(marshalled)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Terminate with newline to maintain compatibility with Bitcoin Core.
+	suffix := `],"error":null,"id":` + string(marshalledID) + "}\n"
+	if _, err := io.WriteString(cw, suffix); err != nil {
+		return err
+	}
+
+	// Closing the chunked writer writes the final zero-length chunk, but
+	// not the final CRLF which must be written separately.
+	if err := cw.Close(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\r\n")
+	return err
+}
+
 // jsonRPCRead handles reading and responding to RPC messages.
 func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin bool) {
 	if atomic.LoadInt32(&s.shutdown) != 0 {
@@ -5238,7 +7357,30 @@ func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 		}
 
 		if err == nil {
-			resp = s.processRequest(&req, isAdmin, closeChan)
+			result, needsReply, replyErr := s.requestResult(&req,
+				r.RemoteAddr, isAdmin, closeChan)
+
+			// Write array results incrementally for clients that
+			// support chunked transfer encoding when the response
+			// size is unlimited.  Requests with invalid IDs are left
+			// to the normal reply path which rejects them.
+			arr, ok := result.(*streamedArray)
+			if ok && needsReply && replyErr == nil &&
+				r.ProtoAtLeast(1, 1) && cfg.RPCMaxResponseSize == 0 &&
+				dcrjson.IsValidIDType(req.ID) {
+
+				err := s.writeStreamedReply(r, w.Header(), buf, &req,
+					arr)
+				if err != nil {
+					rpcsLog.Errorf("Failed to write streamed reply: %v",
+						err)
+				}
+				return
+			}
+
+			if needsReply {
+				resp = s.marshalRequestReply(&req, result, replyErr)
+			}
 		}
 
 		if resp != nil {
@@ -5327,7 +7469,8 @@ func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 						continue
 					}
 
-					resp = s.processRequest(&req, isAdmin, closeChan)
+					resp = s.processRequest(&req, r.RemoteAddr, isAdmin,
+						closeChan)
 					if resp != nil {
 						results = append(results, resp)
 					}
@@ -5496,6 +7639,10 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 		templatePool:           make(map[[merkleRootPairSize]byte]*workStateBlockInfo),
 		helpCacher:             newHelpCacher(),
 		requestProcessShutdown: make(chan struct{}),
+		requestProcessRestart:  make(chan struct{}),
+	}
+	if cfg.RPCMaxRescans > 0 {
+		rpc.rescanSem = makeSemaphore(cfg.RPCMaxRescans)
 	}
 	if cfg.RPCUser != "" && cfg.RPCPass != "" {
 		login := cfg.RPCUser + ":" + cfg.RPCPass
@@ -5509,6 +7656,10 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 			base64.StdEncoding.EncodeToString([]byte(login))
 		rpc.limitauthsha = sha256.Sum256([]byte(auth))
 	}
+	rpc.adminClientCNs = make(map[string]struct{}, len(cfg.RPCAdminClientCNs))
+	for _, commonName := range cfg.RPCAdminClientCNs {
+		rpc.adminClientCNs[commonName] = struct{}{}
+	}
 	rpc.ntfnMgr = newWsNotificationManager(&rpc)
 
 	// Setup TLS if not disabled.
@@ -5529,7 +7680,26 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 
 		tlsConfig := tls.Config{
 			Certificates: []tls.Certificate{keypair},
-			MinVersion:   tls.VersionTLS12,
+			MinVersion:   cfg.rpcTLSMinVersion,
+			CipherSuites: cfg.rpcTLSCipherSuites,
+		}
+
+		// Verify client certificates against the configured certificate
+		// authorities when enabled.  Clients without a certificate may
+		// still authenticate with a username and password.
+		if cfg.RPCClientCA != "" {
+			pem, err := ioutil.ReadFile(cfg.RPCClientCA)
+			if err != nil {
+				return nil, err
+			}
+			clientCAs := x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no valid certificates found in "+
+					"client certificate authority file %s",
+					cfg.RPCClientCA)
+			}
+			tlsConfig.ClientCAs = clientCAs
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
 		}
 
 		// Change the standard net.Listen function to the tls one.
@@ -5544,8 +7714,9 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 	if err != nil {
 		return nil, err
 	}
+	unixPaths := parseUnixListeners(listenAddrs)
 	listeners := make([]net.Listener, 0,
-		len(ipv6ListenAddrs)+len(ipv4ListenAddrs))
+		len(ipv6ListenAddrs)+len(ipv4ListenAddrs)+len(unixPaths))
 	for _, addr := range ipv4ListenAddrs {
 		listener, err := listenFunc("tcp4", addr)
 		if err != nil {
@@ -5563,6 +7734,15 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 		}
 		listeners = append(listeners, listener)
 	}
+
+	for _, socketPath := range unixPaths {
+		listener, err := listenUnix(listenFunc, socketPath)
+		if err != nil {
+			rpcsLog.Warnf("Can't listen on %s: %v", socketPath, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
 	if len(listeners) == 0 {
 		return nil, errors.New("RPCS: No valid listen address")
 	}
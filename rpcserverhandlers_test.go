@@ -0,0 +1,659 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/addrmgr"
+	"github.com/decred/dcrd/blockchain/standalone"
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/mempool/v3"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/slog"
+)
+
+// TestHandleSetBanTime ensures the setban handler bans for the requested time
+// and rejects ban times that are negative or would overflow the duration.
+func TestHandleSetBanTime(t *testing.T) {
+	origCfg := cfg
+	cfg = &config{BanDuration: time.Hour}
+	defer func() { cfg = origCfg }()
+
+	query := make(chan interface{})
+	s := &rpcServer{server: &server{query: query}}
+	bans := make(chan time.Duration, 1)
+	go func() {
+		for q := range query {
+			msg := q.(setBanMsg)
+			bans <- msg.duration
+			close(msg.reply)
+		}
+	}()
+	defer close(query)
+
+	tests := []struct {
+		name    string
+		banTime int64
+		want    time.Duration
+		wantErr bool
+	}{
+		{"one hour", 3600, time.Hour, false},
+		{"maximum", math.MaxInt64 / int64(time.Second),
+			time.Duration(math.MaxInt64/int64(time.Second)) * time.Second,
+			false},
+		{"negative", -1, 0, true},
+		{"overflows", math.MaxInt64/int64(time.Second) + 1, 0, true},
+		{"max int64", math.MaxInt64, 0, true},
+	}
+	for _, test := range tests {
+		cmd := types.NewSetBanCmd("10.0.0.1", types.SBAdd,
+			dcrjson.Int64(test.banTime))
+		_, err := handleSetBan(s, cmd, nil)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if test.wantErr {
+			continue
+		}
+		if got := <-bans; got != test.want {
+			t.Fatalf("%q: unexpected ban duration -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestHandleGetSubsidySchedule ensures the getsubsidyschedule handler returns
+// the expected subsidy reduction boundaries and rejects ranges that are too
+// large or out of bounds without overflowing.
+func TestHandleGetSubsidySchedule(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	s := &rpcServer{
+		server:       &server{chainParams: params},
+		subsidyCache: standalone.NewSubsidyCache(params),
+	}
+	interval := params.SubsidyReductionInterval
+
+	tests := []struct {
+		name        string
+		start       int64
+		end         int64
+		wantHeights []int64
+		wantErr     bool
+	}{{
+		name:        "single height",
+		start:       100,
+		end:         100,
+		wantHeights: []int64{100},
+	}, {
+		name:        "spans boundaries",
+		start:       100,
+		end:         2*interval + 1,
+		wantHeights: []int64{100, interval, 2 * interval},
+	}, {
+		name:        "max height",
+		start:       maxSubsidyScheduleHeight,
+		end:         maxSubsidyScheduleHeight,
+		wantHeights: []int64{maxSubsidyScheduleHeight},
+	}, {
+		name:    "end before start",
+		start:   100,
+		end:     99,
+		wantErr: true,
+	}, {
+		name:    "negative start",
+		start:   -1,
+		end:     100,
+		wantErr: true,
+	}, {
+		name:    "end exceeds max height",
+		start:   math.MaxInt64,
+		end:     math.MaxInt64,
+		wantErr: true,
+	}, {
+		name:    "too many entries",
+		start:   0,
+		end:     maxSubsidyScheduleEntries * interval,
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		cmd := &types.GetSubsidyScheduleCmd{
+			StartHeight: test.start,
+			EndHeight:   test.end,
+		}
+		result, err := handleGetSubsidySchedule(s, cmd, nil)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		entries := result.([]types.GetSubsidyScheduleResult)
+		if len(entries) != len(test.wantHeights) {
+			t.Fatalf("%q: unexpected number of entries -- got %d, want %d",
+				test.name, len(entries), len(test.wantHeights))
+		}
+		for i, entry := range entries {
+			if entry.Height != test.wantHeights[i] {
+				t.Fatalf("%q: unexpected height at index %d -- got %d, "+
+					"want %d", test.name, i, entry.Height,
+					test.wantHeights[i])
+			}
+		}
+	}
+}
+
+// TestHandleGetMempoolEntry ensures the getmempoolentry handler returns the
+// expected JSON-RPC errors for malformed transaction hashes and transactions
+// that are not in the memory pool.
+func TestHandleGetMempoolEntry(t *testing.T) {
+	txMemPool := mempool.New(&mempool.Config{
+		BestHeight: func() int64 { return 0 },
+	})
+	s := &rpcServer{server: &server{txMemPool: txMemPool}}
+
+	tests := []struct {
+		name     string
+		txid     string
+		wantCode dcrjson.RPCErrorCode
+	}{{
+		name:     "malformed txid",
+		txid:     "zz",
+		wantCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:     "not in pool",
+		txid:     "0000000000000000000000000000000000000000000000000000000000000001",
+		wantCode: dcrjson.ErrRPCNoTxInfo,
+	}}
+
+	for _, test := range tests {
+		cmd := &types.GetMempoolEntryCmd{Txid: test.txid}
+		_, err := handleGetMempoolEntry(s, cmd, nil)
+		rpcErr, ok := err.(*dcrjson.RPCError)
+		if !ok {
+			t.Fatalf("%q: unexpected error type -- got %T, want "+
+				"*dcrjson.RPCError", test.name, err)
+		}
+		if rpcErr.Code != test.wantCode {
+			t.Fatalf("%q: unexpected error code -- got %v, want %v",
+				test.name, rpcErr.Code, test.wantCode)
+		}
+	}
+}
+
+// TestLimitResponseSize ensures marshalled replies that exceed the configured
+// maximum response size are replaced with an error reply while all others are
+// returned unchanged.
+func TestLimitResponseSize(t *testing.T) {
+	origCfg := cfg
+	defer func() {
+		cfg = origCfg
+	}()
+
+	reply, err := createMarshalledReply("1.0", 1, "result", nil)
+	if err != nil {
+		t.Fatalf("unable to create reply: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		maxSize  int64
+		wantSame bool
+	}{
+		{"unlimited", 0, true},
+		{"exactly max size", int64(len(reply)), true},
+		{"exceeds max size", int64(len(reply)) - 1, false},
+	}
+
+	s := &rpcServer{}
+	for _, test := range tests {
+		cfg = &config{RPCMaxResponseSize: test.maxSize}
+		got, err := s.limitResponseSize("1.0", 1, reply)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		if test.wantSame {
+			if !bytes.Equal(got, reply) {
+				t.Fatalf("%q: unexpected reply -- got %s, want %s",
+					test.name, got, reply)
+			}
+			continue
+		}
+
+		var resp dcrjson.Response
+		if err := json.Unmarshal(got, &resp); err != nil {
+			t.Fatalf("%q: unable to unmarshal reply: %v", test.name, err)
+		}
+		if resp.Error == nil || resp.Error.Code != dcrjson.ErrRPCOutOfMemory {
+			t.Fatalf("%q: unexpected reply error -- got %v, want code %v",
+				test.name, resp.Error, dcrjson.ErrRPCOutOfMemory)
+		}
+		if string(resp.Result) != "null" {
+			t.Fatalf("%q: unexpected result in error reply: %s",
+				test.name, resp.Result)
+		}
+	}
+}
+
+// newTestStreamedArray returns a streamed array that produces the passed
+// elements followed by the passed error, if any, along with a pointer to the
+// number of elements that have been produced.
+func newTestStreamedArray(elems []types.GetSubsidyScheduleResult, elemErr error) (*streamedArray, *int) {
+	var numProduced int
+	arr := &streamedArray{
+		forEach: func(fn func(elem interface{}) error) error {
+			numProduced = 0
+			for i := range elems {
+				numProduced++
+				if err := fn(&elems[i]); err != nil {
+					return err
+				}
+			}
+			return elemErr
+		},
+	}
+	return arr, &numProduced
+}
+
+// TestStreamedArrayReply ensures streamed array results marshalled by the
+// normal reply path match the equivalent slice result, stop being produced as
+// soon as they exceed the maximum response size, and return errors produced by
+// the handler to the client.
+func TestStreamedArrayReply(t *testing.T) {
+	origCfg := cfg
+	defer func() {
+		cfg = origCfg
+	}()
+
+	elems := []types.GetSubsidyScheduleResult{
+		{Height: 1, Total: 100},
+		{Height: 2, Total: 200},
+		{Height: 3, Total: 300},
+	}
+	want, err := createMarshalledReply("1.0", 1, elems, nil)
+	if err != nil {
+		t.Fatalf("unable to create reply: %v", err)
+	}
+	marshalledElems, err := json.Marshal(elems)
+	if err != nil {
+		t.Fatalf("unable to marshal elements: %v", err)
+	}
+	elemErr := rpcInternalError("element failure", "")
+
+	tests := []struct {
+		name         string
+		maxSize      int64
+		elemErr      error
+		wantCode     dcrjson.RPCErrorCode
+		wantProduced int
+	}{
+		{"unlimited", 0, nil, 0, 3},
+		{"exactly max size", int64(len(marshalledElems)), nil, 0, 3},
+		{"exceeds max size", int64(len(marshalledElems)) / 2, nil,
+			dcrjson.ErrRPCOutOfMemory, 2},
+		{"element error", 0, elemErr, dcrjson.ErrRPCInternal.Code, 3},
+	}
+
+	for _, test := range tests {
+		cfg = &config{RPCMaxResponseSize: test.maxSize}
+		arr, numProduced := newTestStreamedArray(elems, test.elemErr)
+		got, err := createMarshalledReply("1.0", 1, arr, nil)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		if *numProduced != test.wantProduced {
+			t.Fatalf("%q: unexpected number of produced elements -- got "+
+				"%d, want %d", test.name, *numProduced, test.wantProduced)
+		}
+		if test.wantCode == 0 {
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%q: unexpected reply -- got %s, want %s",
+					test.name, got, want)
+			}
+			continue
+		}
+
+		var resp dcrjson.Response
+		if err := json.Unmarshal(got, &resp); err != nil {
+			t.Fatalf("%q: unable to unmarshal reply: %v", test.name, err)
+		}
+		if resp.Error == nil || resp.Error.Code != test.wantCode {
+			t.Fatalf("%q: unexpected reply error -- got %v, want code %v",
+				test.name, resp.Error, test.wantCode)
+		}
+		if string(resp.Result) != "null" {
+			t.Fatalf("%q: unexpected result in error reply: %s",
+				test.name, resp.Result)
+		}
+	}
+}
+
+// TestWriteStreamedReply ensures streamed array results decode to the same
+// response as the normal reply path, responses for which producing an element
+// fails are aborted without the terminating chunk, and requests with invalid
+// IDs are rejected before anything is written.
+func TestWriteStreamedReply(t *testing.T) {
+	origCfg := cfg
+	defer func() {
+		cfg = origCfg
+	}()
+	cfg = &config{}
+
+	elems := []types.GetSubsidyScheduleResult{
+		{Height: 1, Total: 100},
+		{Height: 2, Total: 200},
+		{Height: 3, Total: 300},
+	}
+	id := interface{}(float64(1))
+	want, err := createMarshalledReply("1.0", id, elems, nil)
+	if err != nil {
+		t.Fatalf("unable to create reply: %v", err)
+	}
+	want = append(want, '\n')
+
+	tests := []struct {
+		name        string
+		id          interface{}
+		elemErr     error
+		wantErr     bool
+		wantWritten bool
+	}{
+		{"valid", id, nil, false, true},
+		{"element error", id, fmt.Errorf("element failure"), true, true},
+		{"invalid id", []int{1}, nil, true, false},
+	}
+
+	for _, test := range tests {
+		s := &rpcServer{statusLines: make(map[int]string)}
+		r := httptest.NewRequest("POST", "/", nil)
+		request := &dcrjson.Request{Jsonrpc: "1.0", ID: test.id}
+		arr, _ := newTestStreamedArray(elems, test.elemErr)
+		var buf bytes.Buffer
+		err := s.writeStreamedReply(r, make(http.Header), &buf, request, arr)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if !test.wantWritten {
+			if buf.Len() != 0 {
+				t.Fatalf("%q: unexpected response written: %q",
+					test.name, buf.Bytes())
+			}
+			continue
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(&buf), r)
+		if err != nil {
+			t.Fatalf("%q: unable to read response: %v", test.name, err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%q: truncated response read without error",
+					test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unable to read body: %v", test.name, err)
+		}
+		if !bytes.Equal(body, want) {
+			t.Fatalf("%q: unexpected body -- got %s, want %s", test.name,
+				body, want)
+		}
+	}
+}
+
+// TestHandleAddPeerAddresses ensures the addpeeraddresses handler adds valid
+// addresses to the address manager using the default port when none is
+// specified and rejects the entire request when any address is invalid.
+func TestHandleAddPeerAddresses(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	future := time.Now().Add(time.Hour).Unix()
+	valid := types.PeerAddress{Address: "93.184.216.34"}
+
+	tests := []struct {
+		name      string
+		addresses []types.PeerAddress
+		want      string
+		wantErr   bool
+	}{{
+		name:    "no addresses",
+		wantErr: true,
+	}, {
+		name:      "default port",
+		addresses: []types.PeerAddress{valid},
+		want:      "93.184.216.34:" + params.DefaultPort,
+	}, {
+		name: "explicit port",
+		addresses: []types.PeerAddress{
+			{Address: "93.184.216.34:19108"},
+		},
+		want: "93.184.216.34:19108",
+	}, {
+		name: "invalid port",
+		addresses: []types.PeerAddress{
+			valid,
+			{Address: "93.184.216.35:0"},
+		},
+		wantErr: true,
+	}, {
+		name: "unroutable",
+		addresses: []types.PeerAddress{
+			valid,
+			{Address: "127.0.0.1"},
+		},
+		wantErr: true,
+	}, {
+		name: "future timestamp",
+		addresses: []types.PeerAddress{
+			valid,
+			{Address: "93.184.216.35", Time: future},
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		amgr := addrmgr.New("addpeeraddresses", net.LookupIP)
+		s := &rpcServer{
+			server: &server{chainParams: params, addrManager: amgr},
+		}
+		cmd := &types.AddPeerAddressesCmd{Addresses: test.addresses}
+		_, err := handleAddPeerAddresses(s, cmd, nil)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+
+		// Ensure no addresses are added when the request is rejected and
+		// the expected one is otherwise.
+		ka := amgr.GetNewTableAddress()
+		if test.wantErr {
+			if ka != nil {
+				t.Fatalf("%q: address %v added for rejected request",
+					test.name, ka.NetAddress())
+			}
+			continue
+		}
+		if ka == nil {
+			t.Fatalf("%q: address was not added", test.name)
+		}
+		if got := addrmgr.NetAddressKey(ka.NetAddress()); got != test.want {
+			t.Fatalf("%q: unexpected address -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestCheckAuthClientCert ensures clients that presented a verified client
+// certificate without credentials are authenticated by it with limited access
+// unless its common name is an admin common name, while clients that provide
+// credentials are always authenticated by HTTP Basic authentication.
+func TestCheckAuthClientCert(t *testing.T) {
+	basicAuth := func(user, pass string) string {
+		login := base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+		return "Basic " + login
+	}
+	s := &rpcServer{
+		authsha:        sha256.Sum256([]byte(basicAuth("admin", "pass"))),
+		limitauthsha:   sha256.Sum256([]byte(basicAuth("limit", "pass"))),
+		adminClientCNs: map[string]struct{}{"automation": {}},
+	}
+	verified := func(commonName string) *tls.ConnectionState {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		return &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		tlsState  *tls.ConnectionState
+		auth      string
+		wantAuth  bool
+		wantAdmin bool
+		wantErr   bool
+	}{{
+		name:      "admin certificate",
+		tlsState:  verified("automation"),
+		wantAuth:  true,
+		wantAdmin: true,
+	}, {
+		name:     "limited certificate",
+		tlsState: verified("monitoring"),
+		wantAuth: true,
+	}, {
+		name:     "certificate with wrong basic auth",
+		tlsState: verified("automation"),
+		auth:     basicAuth("admin", "wrong"),
+		wantErr:  true,
+	}, {
+		name:     "certificate with limited basic auth",
+		tlsState: verified("automation"),
+		auth:     basicAuth("limit", "pass"),
+		wantAuth: true,
+	}, {
+		name:     "unverified certificate without basic auth",
+		tlsState: &tls.ConnectionState{},
+		wantErr:  true,
+	}, {
+		name:      "admin basic auth",
+		auth:      basicAuth("admin", "pass"),
+		wantAuth:  true,
+		wantAdmin: true,
+	}, {
+		name:     "limited basic auth",
+		auth:     basicAuth("limit", "pass"),
+		wantAuth: true,
+	}, {
+		name:    "wrong basic auth",
+		auth:    basicAuth("admin", "wrong"),
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.TLS = test.tlsState
+		if test.auth != "" {
+			r.Header.Set("Authorization", test.auth)
+		}
+		gotAuth, gotAdmin, err := s.checkAuth(r, true)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if gotAuth != test.wantAuth || gotAdmin != test.wantAdmin {
+			t.Fatalf("%q: unexpected auth -- got (%v, %v), want (%v, %v)",
+				test.name, gotAuth, gotAdmin, test.wantAuth,
+				test.wantAdmin)
+		}
+	}
+}
+
+// TestAuditRPCRequest ensures RPC requests are only recorded in the audit log
+// when it is enabled and that they are recorded with the user, method, and
+// result along with a hash of the parameters instead of the parameters
+// themselves.
+func TestAuditRPCRequest(t *testing.T) {
+	origCfg, origLog := cfg, rpcAuditLog
+	defer func() {
+		cfg, rpcAuditLog = origCfg, origLog
+	}()
+	var buf bytes.Buffer
+	rpcAuditLog = slog.NewBackend(&buf).Logger("AUDT")
+	rpcAuditLog.SetLevel(slog.LevelInfo)
+
+	const rawTx = `"0100000001deadbeef"`
+	params := []json.RawMessage{json.RawMessage(rawTx)}
+	failure := dcrjson.NewRPCError(dcrjson.ErrRPCDeserialization, "bad tx")
+
+	tests := []struct {
+		name    string
+		enabled bool
+		isAdmin bool
+		err     error
+		want    []string
+	}{{
+		name: "disabled",
+	}, {
+		name:    "admin success",
+		enabled: true,
+		isAdmin: true,
+		want: []string{"user=admin", `method="sendrawtransaction"`,
+			"result=success"},
+	}, {
+		name:    "limited failure",
+		enabled: true,
+		err:     failure,
+		want: []string{"user=limited", `method="sendrawtransaction"`,
+			fmt.Sprintf("result=failure (code %d)", failure.Code)},
+	}}
+
+	for _, test := range tests {
+		buf.Reset()
+		cfg = &config{RPCAuditLog: test.enabled}
+		auditRPCRequest("127.0.0.1:1234", test.isAdmin,
+			"sendrawtransaction", params, test.err)
+
+		got := buf.String()
+		if len(test.want) == 0 {
+			if got != "" {
+				t.Fatalf("%q: unexpected audit log entry %q", test.name,
+					got)
+			}
+			continue
+		}
+		for _, want := range test.want {
+			if !strings.Contains(got, want) {
+				t.Fatalf("%q: audit log entry %q does not contain %q",
+					test.name, got, want)
+			}
+		}
+		if strings.Contains(got, "deadbeef") {
+			t.Fatalf("%q: audit log entry %q contains the parameters",
+				test.name, got)
+		}
+	}
+}
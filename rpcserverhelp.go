@@ -18,6 +18,16 @@ import (
 // helpDescsEnUS defines the English descriptions used for the help strings.
 var helpDescsEnUS = map[string]string{
 	// DebugLevelCmd help.
+	// DebugDumpCmd help.
+	"debugdump--synopsis": "Returns a stack dump of all running goroutines and optionally writes a CPU or heap profile to the data directory.\n" +
+		"This command is only available to the admin user and must be enabled with the --rpcdebugdump option.",
+	"debugdump-profile": "The profile to write to the data directory in addition to the stack dump: 'cpu' or 'heap'",
+	"debugdump-seconds": "The number of seconds to collect the CPU profile for (max 300)",
+
+	// DebugDumpResult help.
+	"debugdumpresult-goroutines":  "Stack traces of all running goroutines",
+	"debugdumpresult-profilefile": "Path to the written profile file (only present when a profile was requested)",
+
 	"debuglevel--synopsis": "Dynamically changes the debug logging level.\n" +
 		"The levelspec can either a debug level or of the form:\n" +
 		"<subsystem>=<level>,<subsystem2>=<level2>,...\n" +
@@ -35,6 +45,18 @@ var helpDescsEnUS = map[string]string{
 	"addnode-addr":      "IP address and port of the peer to operate on",
 	"addnode-subcmd":    "'add' to add a persistent peer, 'remove' to remove a persistent peer, or 'onetry' to try a single connection to a peer",
 
+	// AddPeerAddressesCmd help.
+	"addpeeraddresses--synopsis": "Imports the provided addresses into the address manager without connecting to them.",
+	"addpeeraddresses-addresses": "The addresses to import",
+	"peeraddress-address":        "The IP address or host and optional port of the peer",
+	"peeraddress-services":       "The services bitmask supported by the peer (default: full node)",
+	"peeraddress-time":           "The time the peer was last seen in seconds since 1 Jan 1970 GMT (default: now)",
+
+	// BanSubnetCmd help.
+	"bansubnet--synopsis": "Bans all peers with an IP address in the provided subnet and disconnects any that are currently connected.",
+	"bansubnet-subnet":    "The subnet to ban in CIDR notation (e.g. 192.168.0.0/16) or a single IP address",
+	"bansubnet-duration":  "The number of seconds to ban the subnet for (default: the configured ban duration)",
+
 	// NodeCmd help.
 	"node--synopsis":     "Attempts to add or remove a peer.",
 	"node-subcmd":        "'disconnect' to remove all matching non-persistent peers, 'remove' to remove a persistent peer, or 'connect' to connect to a peer",
@@ -120,6 +142,7 @@ var helpDescsEnUS = map[string]string{
 	"vin-blockindex":  "The block idx of the origin transaction",
 	"vin-blockheight": "The block height of the origin transaction",
 	"vin-amountin":    "The amount in",
+	"vin-prevOut":     "Data from the origin transaction output with index vout (only with getblock verboseprevout)",
 
 	// ScriptPubKeyResult help.
 	"scriptpubkeyresult-asm":       "Disassembly of the script",
@@ -159,6 +182,29 @@ var helpDescsEnUS = map[string]string{
 	"decodescript-hexscript": "Hex-encoded script",
 	"decodescript-version":   "The script version, defaults to version 0 if not set.",
 
+	// DecodeTicketCmd help.
+	"decodeticket--synopsis": "Returns a JSON object describing the commitment outputs of the provided ticket.",
+	"decodeticket-ticket":    "The hash of a ticket in the mempool or indexed by the transaction index, or a serialized, hex-encoded ticket",
+
+	// DecodeTicketResult help.
+	"decodeticketresult-txid":          "The hash of the ticket",
+	"decodeticketresult-ticketprice":   "The amount paid by the ticket in coins",
+	"decodeticketresult-votingaddress": "The address with the rights to vote or revoke the ticket",
+	"decodeticketresult-commitments":   "The commitments of the ticket in output order",
+
+	// TicketCommitmentResult help.
+	"ticketcommitmentresult-address":            "The address vote and revocation rewards for the commitment are paid to",
+	"ticketcommitmentresult-amount":             "The committed amount in coins",
+	"ticketcommitmentresult-changeaddress":      "The address of the associated change output",
+	"ticketcommitmentresult-changeamount":       "The amount of the associated change output in coins",
+	"ticketcommitmentresult-votefeelimit":       "The maximum fee in coins a vote may pay from the reward for the commitment (omitted when unlimited)",
+	"ticketcommitmentresult-revocationfeelimit": "The maximum fee in coins a revocation may pay from the reward for the commitment (omitted when unlimited)",
+
+	// DropAllPeersCmd help.
+	"dropallpeers--synopsis":         "Disconnects all connected peers so that new connections are made from scratch, which is useful to recover from an unhealthy set of peers.\nPersistent peers are reconnected automatically.",
+	"dropallpeers-excludepersistent": "Do not disconnect persistent peers",
+	"dropallpeers--result0":          "The number of peers that were disconnected",
+
 	// ExistsAddressCmd help.
 	"existsaddress--synopsis": "Test for the existence of the provided address",
 	"existsaddress-address":   "The address to check",
@@ -217,6 +263,25 @@ var helpDescsEnUS = map[string]string{
 	"getaddednodeinfo--condition1": "dns=true",
 	"getaddednodeinfo--result0":    "List of added peers",
 
+	// GetAddrManInfo help.
+	"getaddrmaninfo--synopsis": "Returns statistics about the addresses known to the address manager, which is intended for debugging peer discovery.",
+
+	// GetAddrManInfoResult help.
+	"getaddrmaninforesult-new":   "The number of addresses in the new set of addresses that have not been successfully connected to",
+	"getaddrmaninforesult-tried": "The number of addresses in the tried set of addresses that have been successfully connected to",
+	"getaddrmaninforesult-total": "The total number of known addresses",
+	"getaddrmaninforesult-ipv4":  "The number of known IPv4 addresses",
+	"getaddrmaninforesult-ipv6":  "The number of known IPv6 addresses",
+	"getaddrmaninforesult-onion": "The number of known Tor onion addresses",
+
+	// GetBannedPeersResult help.
+	"getbannedpeersresult-host":                "The banned IP address or subnet in CIDR notation",
+	"getbannedpeersresult-banuntil":            "The time the ban ends in seconds since 1 Jan 1970 GMT",
+	"getbannedpeersresult-bansecondsremaining": "The number of seconds remaining until the ban ends",
+
+	// GetBannedPeers help.
+	"getbannedpeers--synopsis": "Returns the IP addresses and subnets that are currently banned along with when their bans end.",
+
 	// GetBestBlockResult help.
 	"getbestblockresult-hash":   "Hex-encoded bytes of the best block hash",
 	"getbestblockresult-height": "Height of the best block",
@@ -230,13 +295,16 @@ var helpDescsEnUS = map[string]string{
 	"getbestblockhash--result0":  "The hex-encoded block hash",
 
 	// GetBlockCmd help.
-	"getblock--synopsis":   "Returns information about a block given its hash.",
-	"getblock-hash":        "The hash of the block",
-	"getblock-verbose":     "Specifies the block is returned as a JSON object instead of hex-encoded string",
-	"getblock-verbosetx":   "Specifies that each transaction is returned as a JSON object and only applies if the verbose flag is true (dcrd extension)",
-	"getblock--condition0": "verbose=false",
-	"getblock--condition1": "verbose=true",
-	"getblock--result0":    "Hex-encoded bytes of the serialized block",
+	"getblock--synopsis": "Returns information about a block given its hash.\n" +
+		"For compatibility, the verbose parameter may instead be an integer verbosity level with no further parameters: " +
+		"0 for verbose=false, 1 for verbose=true, 2 to also set verbosetx=true, and 3 to also set verboseprevout=true.",
+	"getblock-hash":           "The hash of the block",
+	"getblock-verbose":        "Specifies the block is returned as a JSON object instead of hex-encoded string",
+	"getblock-verbosetx":      "Specifies that each transaction is returned as a JSON object and only applies if the verbose flag is true (dcrd extension)",
+	"getblock-verboseprevout": "Specifies that the previous output referenced by each transaction input is included and only applies if the verbosetx flag is true -- requires the transaction index (dcrd extension)",
+	"getblock--condition0":    "verbose=false",
+	"getblock--condition1":    "verbose=true",
+	"getblock--result0":       "Hex-encoded bytes of the serialized block",
 
 	// GetBlockchainInfoCmd help.
 	"getblockchaininfo--synopsis": "Returns information about the current state of the block chain.",
@@ -250,9 +318,11 @@ var helpDescsEnUS = map[string]string{
 	"getblockchaininforesult-difficulty":           "(DEPRECATED) The current network difficulty.",
 	"getblockchaininforesult-difficultyratio":      "The current proof-of-work difficulty as a multiple of the minimum difficulty.",
 	"getblockchaininforesult-verificationprogress": "The chain verification progress estimate.",
+	"getblockchaininforesult-synceta_seconds":      "The estimated number of seconds until the chain is synced based on the recent rate of connected blocks (omitted when not syncing or the rate is unknown).",
 	"getblockchaininforesult-chainwork":            "Hex encoded total work done for the chain.",
 	"getblockchaininforesult-initialblockdownload": "Best guess of whether this node is in the initial block download mode used to catch up the chain when it is far behind",
 	"getblockchaininforesult-maxblocksize":         "The maximum allowed block size.",
+	"getblockchaininforesult-medianblockinterval":  "The median number of seconds between the most recent blocks (over the configured block interval window).",
 	"getblockchaininforesult-deployments":          "Network consensus deployments.",
 	"getblockchaininforesult-deployments--desc":    "Consensus deployment agendas.",
 	"getblockchaininforesult-deployments--key":     "The consensus deployment agenda id.",
@@ -294,6 +364,11 @@ var helpDescsEnUS = map[string]string{
 	"searchrawtransactionsresult-time":          "Transaction time in seconds since 1 Jan 1970 GMT",
 	"searchrawtransactionsresult-blocktime":     "Block time in seconds since the 1 Jan 1970 GMT",
 
+	// SearchRawTransactionsTotalResult help.
+	"searchrawtransactionstotalresult-total":        "The total number of confirmed and unconfirmed transactions involving the address",
+	"searchrawtransactionstotalresult-hex":          "Hex-encoded serialized transactions (verbose=0)",
+	"searchrawtransactionstotalresult-transactions": "The transactions as JSON objects (verbose=1)",
+
 	// GetBlockVerboseResult help.
 	"getblockverboseresult-hash":              "The hash of the block (same as provided)",
 	"getblockverboseresult-confirmations":     "The number of confirmations",
@@ -332,6 +407,12 @@ var helpDescsEnUS = map[string]string{
 	"getblockhash-index":     "The block height",
 	"getblockhash--result0":  "The block hash",
 
+	// GetBlockHashesCmd help.
+	"getblockhashes--synopsis":   "Returns the hashes of the blocks in the best block chain for the given inclusive range of heights (limited to 2000 blocks per request).",
+	"getblockhashes-startheight": "The height of the first block in the range",
+	"getblockhashes-endheight":   "The height of the last block in the range",
+	"getblockhashes--result0":    "The block hashes ordered by ascending height",
+
 	// GetBlockHeaderCmd help.
 	"getblockheader--synopsis":   "Returns information about a block header given its hash.",
 	"getblockheader-hash":        "The hash of the block",
@@ -340,6 +421,11 @@ var helpDescsEnUS = map[string]string{
 	"getblockheader--condition1": "verbose=true",
 	"getblockheader--result0":    "The serialized block header.",
 
+	// GetBlockHeaderByHeightCmd help.
+	"getblockheaderbyheight--synopsis": "Returns the serialized block header of the block in the best block chain at the given height.",
+	"getblockheaderbyheight-height":    "The block height",
+	"getblockheaderbyheight--result0":  "The hex-encoded serialized block header",
+
 	// GetBlockHeaderVerboseResult help.
 	"getblockheaderverboseresult-hash":              "The hash of the block (same as provided)",
 	"getblockheaderverboseresult-confirmations":     "The number of confirmations",
@@ -404,10 +490,53 @@ var helpDescsEnUS = map[string]string{
 	"getchaintipsresult-status":    "The status of the chain (active, invalid, headers-only, valid-fork, valid-headers)",
 	"getchaintipsresults--result0": "test",
 
+	// GetCompactBlockCmd help.
+	"getcompactblock--synopsis":   "Returns a compact representation of a block given its hash in which the coinbase is included in full and all other transactions are represented by short transaction IDs.",
+	"getcompactblock-hash":        "The hash of the block",
+	"getcompactblock-verbose":     "Specifies the compact block is returned as a JSON object instead of hex-encoded string",
+	"getcompactblock-nonce":       "The nonce used to derive the short transaction IDs (default: random)",
+	"getcompactblock--condition0": "verbose=false",
+	"getcompactblock--condition1": "verbose=true",
+	"getcompactblock--result0":    "Hex-encoded bytes of the serialized compact block",
+
+	// PrefilledTxResult help.
+	"prefilledtxresult-index": "The index of the transaction in its transaction tree",
+	"prefilledtxresult-txid":  "The hash of the transaction",
+
+	// GetCompactBlockVerboseResult help.
+	"getcompactblockverboseresult-hash":         "The hash of the block (same as provided)",
+	"getcompactblockverboseresult-nonce":        "The nonce used to derive the short transaction IDs",
+	"getcompactblockverboseresult-shortids":     "The hex-encoded short IDs of the regular transactions that are not prefilled",
+	"getcompactblockverboseresult-prefilledtx":  "The regular transactions that are included in full",
+	"getcompactblockverboseresult-stxshortids":  "The hex-encoded short IDs of the stake transactions that are not prefilled",
+	"getcompactblockverboseresult-prefilledstx": "The stake transactions that are included in full",
+	"getcompactblockverboseresult-size":         "The size of the serialized compact block in bytes",
+	"getcompactblockverboseresult-blocksize":    "The size of the serialized full block in bytes",
+
+	// GetConfigCmd help.
+	"getconfig--synopsis":       "Returns the effective configuration options the server is running with after merging the defaults, config file, and command line options.  Options that may be changed while running report their current values.  The values of options that contain credentials are redacted.",
+	"getconfig--result0--desc":  "Configuration option values keyed by their long option names",
+	"getconfig--result0--key":   "The long option name",
+	"getconfig--result0--value": "The value of the option",
+
 	// GetConnectionCountCmd help.
 	"getconnectioncount--synopsis": "Returns the number of active connections to other peers.",
 	"getconnectioncount--result0":  "The number of connections",
 
+	// GetConnMgrStatsCmd help.
+	"getconnmgrstats--synopsis": "Returns statistics about the outbound connection requests tracked by the connection manager and the addresses known to the address manager.",
+
+	// GetConnMgrStatsResult help.
+	"getconnmgrstatsresult-targetoutbound":      "The number of outbound connections the connection manager attempts to maintain",
+	"getconnmgrstatsresult-outbound":            "The number of established outbound connections, including persistent connections",
+	"getconnmgrstatsresult-pending":             "The number of connection requests that have yet to succeed",
+	"getconnmgrstatsresult-inflightdials":       "The number of connection attempts that are in the process of dialing",
+	"getconnmgrstatsresult-consecutivefailures": "The number of successive failed attempts to connect to new addresses since the last successful connection",
+	"getconnmgrstatsresult-totalfailures":       "The total number of failed connection attempts",
+	"getconnmgrstatsresult-knownaddresses":      "The total number of addresses known to the address manager",
+	"getconnmgrstatsresult-triedaddresses":      "The number of known addresses that have been successfully connected to",
+	"getconnmgrstatsresult-newaddresses":        "The number of known addresses that have not been successfully connected to",
+
 	// GetCurrentNetCmd help.
 	"getcurrentnet--synopsis": "Get Decred network the server is running on.",
 	"getcurrentnet--result0":  "The network identifier",
@@ -416,6 +545,22 @@ var helpDescsEnUS = map[string]string{
 	"getdifficulty--synopsis": "Returns the proof-of-work difficulty as a multiple of the minimum difficulty.",
 	"getdifficulty--result0":  "The difficulty",
 
+	// GetDifficultyInfoCmd help.
+	"getdifficultyinfo--synopsis": "Returns the current proof-of-work and proof-of-stake difficulties along with estimates for their next retarget intervals based on recent blocks.",
+
+	// GetDifficultyInfoResult help.
+	"getdifficultyinforesult-difficulty":               "The current proof-of-work difficulty as a multiple of the minimum difficulty",
+	"getdifficultyinforesult-bits":                     "The current proof-of-work difficulty bits in hex",
+	"getdifficultyinforesult-nextretargetheight":       "The height of the first block of the next proof-of-work difficulty retarget interval",
+	"getdifficultyinforesult-blocksuntilretarget":      "The number of blocks until the next proof-of-work difficulty retarget",
+	"getdifficultyinforesult-estimateddifficulty":      "The estimated proof-of-work difficulty of the next retarget interval assuming the remaining blocks are mined at the average rate of the most recent blocks",
+	"getdifficultyinforesult-estimatedbits":            "The estimated proof-of-work difficulty bits of the next retarget interval in hex",
+	"getdifficultyinforesult-stakedifficulty":          "The stake difficulty of the current best block in coins",
+	"getdifficultyinforesult-nextstakedifficulty":      "The stake difficulty required for the next block in coins",
+	"getdifficultyinforesult-nextstakeretargetheight":  "The height of the first block of the next stake difficulty retarget interval",
+	"getdifficultyinforesult-blocksuntilstakeretarget": "The number of blocks until the next stake difficulty retarget",
+	"getdifficultyinforesult-estimatedstakedifficulty": "The expected stake difficulty of the next retarget interval in coins assuming the remaining tickets are purchased at the average rate since the last retarget",
+
 	// GetStakeDifficultyCmd help.
 	"getstakedifficulty--synopsis":     "Returns the proof-of-stake difficulty.",
 	"getstakedifficultyresult-current": "The current top block's stake difficulty",
@@ -439,13 +584,26 @@ var helpDescsEnUS = map[string]string{
 	"getstakeversions-hash":                "The start block hash.",
 	"getstakeversions-count":               "The number of blocks that will be returned.",
 	"getstakeversionsresult-stakeversions": "Array of stake versions per block.",
-	"stakeversions-hash":                   "Hash of the block.",
-	"stakeversions-height":                 "Height of the block.",
-	"stakeversions-blockversion":           "The block version",
-	"stakeversions-stakeversion":           "The stake version of the block",
-	"stakeversions-votes":                  "The version and bits of each vote in the block",
-	"versionbits-version":                  "The version of the vote.",
-	"versionbits-bits":                     "The bits assigned by the vote.",
+
+	// GetSubsidyScheduleCmd help.
+	"getsubsidyschedule--synopsis":   "Returns the subsidy breakdown at the start of the provided height range and at each subsidy reduction boundary within it.",
+	"getsubsidyschedule-startheight": "The first block height of the range",
+	"getsubsidyschedule-endheight":   "The last block height of the range (max: 4294967295)",
+	"getsubsidyschedule-voters":      "The number of voters to calculate the subsidy with (default: the maximum number of votes per block)",
+
+	// GetSubsidyScheduleResult help.
+	"getsubsidyscheduleresult-height":    "The block height the subsidy applies from",
+	"getsubsidyscheduleresult-developer": "The developer subsidy",
+	"getsubsidyscheduleresult-pos":       "The Proof-of-Stake subsidy",
+	"getsubsidyscheduleresult-pow":       "The Proof-of-Work subsidy",
+	"getsubsidyscheduleresult-total":     "The total subsidy",
+	"stakeversions-hash":                 "Hash of the block.",
+	"stakeversions-height":               "Height of the block.",
+	"stakeversions-blockversion":         "The block version",
+	"stakeversions-stakeversion":         "The stake version of the block",
+	"stakeversions-votes":                "The version and bits of each vote in the block",
+	"versionbits-version":                "The version of the vote.",
+	"versionbits-bits":                   "The bits assigned by the vote.",
 
 	// GetVoteInfo
 	"getvoteinfo--synopsis":           "Returns the vote info statistics.",
@@ -521,6 +679,66 @@ var helpDescsEnUS = map[string]string{
 	// GetInfoCmd help.
 	"getinfo--synopsis": "Returns a JSON object containing various state info.",
 
+	// GetKnownAddressesCmd help.
+	"getknownaddresses--synopsis": "Returns the addresses known to the address manager ordered from most to least recently seen, which is intended for debugging peer discovery.",
+	"getknownaddresses-count":     "The maximum number of addresses to return or 0 to return all of them",
+
+	// GetKnownAddressesResult help.
+	"getknownaddressesresult-address":     "The IP address and port of the node",
+	"getknownaddressesresult-services":    "Services bitmask which represents the services supported by the node at the address",
+	"getknownaddressesresult-lastseen":    "The time the address was last seen in seconds since 1 Jan 1970 GMT",
+	"getknownaddressesresult-lastattempt": "The time a connection to the address was last attempted in seconds since 1 Jan 1970 GMT or 0 if it has never been attempted",
+	"getknownaddressesresult-source":      "The IP address and port of the peer the address was learned from",
+	"getknownaddressesresult-tried":       "Whether the address is in the tried set of addresses that have been successfully connected to as opposed to the new set",
+
+	// GetLocalAddressesCmd help.
+	"getlocaladdresses--synopsis": "Returns the local addresses known to the address manager ordered from highest to lowest score along with how they were discovered, which is intended for debugging which address is advertised to peers.",
+
+	// GetLocalAddressesResult help.
+	"getlocaladdressesresult-address":  "The local IP address",
+	"getlocaladdressesresult-port":     "The port associated with the local address",
+	"getlocaladdressesresult-priority": "The highest priority method the address was discovered with (interface, bound, upnp, http, or manual)",
+	"getlocaladdressesresult-score":    "The score used to select the best local address to advertise to peers, which is increased when an address is discovered by multiple methods",
+
+	// GetMemoryInfoCmd help.
+	"getmemoryinfo--synopsis": "Returns information about the memory usage of the process and its internal caches.",
+
+	// GetMemoryInfoResult help.
+	"getmemoryinforesult-heapalloc":             "Bytes of allocated heap objects",
+	"getmemoryinforesult-heapinuse":             "Bytes in in-use heap spans",
+	"getmemoryinforesult-heapidle":              "Bytes in idle (unused) heap spans",
+	"getmemoryinforesult-heapreleased":          "Bytes of idle heap memory returned to the operating system",
+	"getmemoryinforesult-heapobjects":           "Number of allocated heap objects",
+	"getmemoryinforesult-totalalloc":            "Cumulative bytes allocated for heap objects",
+	"getmemoryinforesult-sys":                   "Total bytes of memory obtained from the operating system",
+	"getmemoryinforesult-numgc":                 "Number of completed garbage collection cycles",
+	"getmemoryinforesult-gcpausetotal":          "Cumulative nanoseconds spent in garbage collection pauses",
+	"getmemoryinforesult-lastgcpause":           "Nanoseconds spent in the most recent garbage collection pause",
+	"getmemoryinforesult-goroutines":            "Number of goroutines that currently exist",
+	"getmemoryinforesult-sigcacheentries":       "Number of entries in the signature verification cache",
+	"getmemoryinforesult-sigcachemaxentries":    "Maximum number of entries in the signature verification cache",
+	"getmemoryinforesult-knownaddrcacheentries": "Total number of entries in the known address caches of all connected peers",
+
+	// GetMempoolAncestorsCmd help.
+	"getmempoolancestors--synopsis":   "Returns the unconfirmed ancestors of a transaction in the memory pool, which are the transactions in the pool it spends outputs of along with their own ancestors.",
+	"getmempoolancestors-txid":        "The hash of the transaction to return the ancestors of",
+	"getmempoolancestors-verbose":     "Returns JSON object when true or an array of transaction hashes when false",
+	"getmempoolancestors--condition0": "verbose=false",
+	"getmempoolancestors--condition1": "verbose=true",
+	"getmempoolancestors--result0":    "Array of transaction hashes",
+
+	// GetMempoolDescendantsCmd help.
+	"getmempooldescendants--synopsis":   "Returns the descendants of a transaction in the memory pool, which are the transactions in the pool that spend its outputs along with their own descendants.",
+	"getmempooldescendants-txid":        "The hash of the transaction to return the descendants of",
+	"getmempooldescendants-verbose":     "Returns JSON object when true or an array of transaction hashes when false",
+	"getmempooldescendants--condition0": "verbose=false",
+	"getmempooldescendants--condition1": "verbose=true",
+	"getmempooldescendants--result0":    "Array of transaction hashes",
+
+	// GetMempoolEntryCmd help.
+	"getmempoolentry--synopsis": "Returns information about a single transaction in the memory pool.",
+	"getmempoolentry-txid":      "The hash of the transaction to return information about",
+
 	// GetMempoolInfoCmd help.
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
@@ -536,14 +754,15 @@ var helpDescsEnUS = map[string]string{
 	"getmininginforesult-stakedifficulty":  "Stake difficulty required for the next block",
 	"getmininginforesult-errors":           "Any current errors",
 	"getmininginforesult-generate":         "Whether or not server is set to generate coins",
-	"getmininginforesult-genproclimit":     "Number of processors to use for coin generation (-1 when disabled)",
+	"getmininginforesult-genproclimit":     "Number of processors to use for coin generation",
 	"getmininginforesult-hashespersec":     "Recent hashes per second performance measurement while generating coins",
-	"getmininginforesult-networkhashps":    "Estimated network hashes per second for the most recent blocks",
+	"getmininginforesult-networkhashps":    "Estimated network hashes per second for the most recent blocks averaged over the requested number of blocks",
 	"getmininginforesult-pooledtx":         "Number of transactions in the memory pool",
 	"getmininginforesult-testnet":          "Whether or not server is using testnet",
 
 	// GetMiningInfoCmd help.
 	"getmininginfo--synopsis": "Returns a JSON object containing mining-related information.",
+	"getmininginfo-blocks":    "The number of blocks to average the estimated network hashes per second over, or -1 for blocks since last difficulty change",
 
 	// GetNetworkHashPSCmd help.
 	"getnetworkhashps--synopsis": "Returns the estimated network hashes per second for the block heights provided by the parameters.",
@@ -572,6 +791,8 @@ var helpDescsEnUS = map[string]string{
 	"getnetworkinforesult-protocolversion": "The protocol version of the node",
 	"getnetworkinforesult-timeoffset":      "The node clock offset in seconds",
 	"getnetworkinforesult-connections":     "The total number of open connections for the node",
+	"getnetworkinforesult-connections_in":  "The number of open inbound connections for the node",
+	"getnetworkinforesult-connections_out": "The number of open outbound connections for the node",
 	"getnetworkinforesult-networks":        "An array of objects describing IPV4, IPV6 and Onion network interface states",
 	"getnetworkinforesult-relayfee":        "The minimum required transaction fee for the node.",
 	"getnetworkinforesult-localaddresses":  "An array of objects describing local addresses being listened on by the node",
@@ -585,6 +806,17 @@ var helpDescsEnUS = map[string]string{
 	"getnettotalsresult-totalbytessent": "Total bytes sent",
 	"getnettotalsresult-timemillis":     "Number of milliseconds since 1 Jan 1970 GMT",
 
+	// GetNodeAddressesCmd help.
+	"getnodeaddresses--synopsis": "Returns a random sample of known routable addresses that have recently been successfully connected to, which is intended for manually seeding peers.\n" +
+		"Not available on the simulation test network.",
+	"getnodeaddresses-count": "The maximum number of addresses to return or 0 to return all of them",
+
+	// GetNodeAddressesResult help.
+	"getnodeaddressesresult-time":     "The time the address was last seen in seconds since 1 Jan 1970 GMT",
+	"getnodeaddressesresult-services": "Services bitmask which represents the services supported by the node at the address",
+	"getnodeaddressesresult-address":  "The IP address of the node",
+	"getnodeaddressesresult-port":     "The port of the node",
+
 	// GetPeerInfoResult help.
 	"getpeerinforesult-id":             "A unique node ID",
 	"getpeerinforesult-addr":           "The ip address and port of the peer",
@@ -595,6 +827,8 @@ var helpDescsEnUS = map[string]string{
 	"getpeerinforesult-lastrecv":       "Time the last message was sent in seconds since 1 Jan 1970 GMT",
 	"getpeerinforesult-bytessent":      "Total bytes sent",
 	"getpeerinforesult-bytesrecv":      "Total bytes received",
+	"getpeerinforesult-sendrate":       "Average bytes per second sent over the last 10 seconds",
+	"getpeerinforesult-recvrate":       "Average bytes per second received over the last 10 seconds",
 	"getpeerinforesult-conntime":       "Time the connection was made in seconds since 1 Jan 1970 GMT",
 	"getpeerinforesult-timeoffset":     "The time offset of the peer",
 	"getpeerinforesult-pingtime":       "Number of microseconds the last ping took",
@@ -606,9 +840,26 @@ var helpDescsEnUS = map[string]string{
 	"getpeerinforesult-currentheight":  "The current height of the peer",
 	"getpeerinforesult-banscore":       "The ban score",
 	"getpeerinforesult-syncnode":       "Whether or not the peer is the sync peer",
+	"getpeerinforesult-blocksreceived": "The number of blocks the peer has delivered",
+	"getpeerinforesult-txsreceived":    "The number of transactions the peer has delivered",
+
+	// GetOrphanTxsCmd help.
+	"getorphantxs--synopsis":   "Returns the transactions in the orphan pool, which are transactions that spend outputs that are not yet available, ordered by the time they entered the pool.",
+	"getorphantxs-verbose":     "Returns JSON objects when true or an array of transaction hashes when false",
+	"getorphantxs--condition0": "verbose=false",
+	"getorphantxs--condition1": "verbose=true",
+	"getorphantxs--result0":    "Array of transaction hashes",
+
+	// GetOrphanTxsResult help.
+	"getorphantxsresult-txid":           "The hash of the orphan transaction",
+	"getorphantxsresult-size":           "The serialized size of the transaction in bytes",
+	"getorphantxsresult-time":           "Time the transaction entered the orphan pool in seconds since 1 Jan 1970 GMT",
+	"getorphantxsresult-missingparents": "The previous outpoints referenced by the transaction that are not yet available",
+	"getorphantxsresult-tx":             "The decoded transaction",
 
 	// GetPeerInfoCmd help.
 	"getpeerinfo--synopsis": "Returns data about each connected network peer as an array of json objects.",
+	"getpeerinfo-direction": "Only return peers with the specified connection direction (all/inbound/outbound)",
 
 	// GetRawMempoolVerboseResult help.
 	"getrawmempoolverboseresult-size":             "Transaction size in bytes",
@@ -635,17 +886,34 @@ var helpDescsEnUS = map[string]string{
 	"getrawtransaction--condition1": "verbose=true",
 	"getrawtransaction--result0":    "Hex-encoded bytes of the serialized transaction",
 
+	// GetTicketInfoCmd help.
+	"getticketinfo--synopsis": "Returns the current status of a ticket along with the block it was mined in and its maturity and expiry heights.",
+	"getticketinfo-hash":      "The hash of the ticket",
+
+	// GetTicketInfoResult help.
+	"getticketinforesult-hash":           "The hash of the ticket",
+	"getticketinforesult-status":         "The current status of the ticket (immature, live, voted, missed, expired, or revoked)",
+	"getticketinforesult-blockhash":      "The hash of the block the ticket was mined in",
+	"getticketinforesult-blockheight":    "The height of the block the ticket was mined in",
+	"getticketinforesult-maturityheight": "The height at which the ticket becomes live",
+	"getticketinforesult-expiryheight":   "The height at which the ticket expires if it has not voted",
+
 	// GetTicketPoolValue help.
-	"getticketpoolvalue--synopsis": "Return the current value of all locked funds in the ticket pool",
-	"getticketpoolvalue--result0":  "Total value of ticket pool",
+	"getticketpoolvalue--synopsis": "Return the value of all locked funds in the ticket pool as of the current best block or the specified block.\n" +
+		"Requesting the value as of a block deep in history can be expensive since it requires reconstructing the live ticket pool and loading the blocks the tickets were purchased in.\n" +
+		"The results for the 100 most recently requested blocks are cached.",
+	"getticketpoolvalue-block":    "The hash or height of the block to return the value as of (default: the current best block)",
+	"getticketpoolvalue--result0": "Total value of ticket pool",
 
 	// GetTxOutResult help.
-	"gettxoutresult-bestblock":     "The block hash that contains the transaction output",
-	"gettxoutresult-confirmations": "The number of confirmations",
-	"gettxoutresult-value":         "The transaction amount in DCR",
-	"gettxoutresult-scriptPubKey":  "The public key script used to pay coins as a JSON object",
-	"gettxoutresult-version":       "The transaction version",
-	"gettxoutresult-coinbase":      "Whether or not the transaction is a coinbase",
+	"gettxoutresult-bestblock":      "The block hash that contains the transaction output",
+	"gettxoutresult-confirmations":  "The number of confirmations",
+	"gettxoutresult-value":          "The transaction amount in DCR",
+	"gettxoutresult-scriptPubKey":   "The public key script used to pay coins as a JSON object",
+	"gettxoutresult-version":        "The transaction version",
+	"gettxoutresult-coinbase":       "Whether or not the transaction is a coinbase",
+	"gettxoutresult-spendable":      "Whether or not the output may be spent by a regular transaction in the next block given the coinbase and stake maturity rules",
+	"gettxoutresult-maturityheight": "The first block height at which the output may be spent when it is subject to a maturity requirement and mined (omitted otherwise)",
 
 	// GetTxOutCmd help.
 	"gettxout--synopsis":      "Returns information about an unspent transaction output.",
@@ -654,10 +922,11 @@ var helpDescsEnUS = map[string]string{
 	"gettxout-includemempool": "Include the mempool when true",
 
 	// GetWorkResult help.
-	"getworkresult-data":     "Hex-encoded block data",
-	"getworkresult-hash1":    "(DEPRECATED) Hex-encoded formatted hash buffer",
-	"getworkresult-midstate": "(DEPRECATED) Hex-encoded precomputed hash state after hashing first half of the data",
-	"getworkresult-target":   "Hex-encoded little-endian hash target",
+	"getworkresult-data":              "Hex-encoded block data",
+	"getworkresult-hash1":             "(DEPRECATED) Hex-encoded formatted hash buffer",
+	"getworkresult-midstate":          "(DEPRECATED) Hex-encoded precomputed hash state after hashing first half of the data",
+	"getworkresult-target":            "Hex-encoded little-endian hash target",
+	"getworkresult-previousblockhash": "The hash of the block the work builds on which changes when the work is stale",
 
 	// GetWorkCmd help.
 	"getwork--synopsis":   "Returns formatted hash data to work on or checks and submits solved data.",
@@ -684,22 +953,39 @@ var helpDescsEnUS = map[string]string{
 	// RebroadcastWinnerCmd help.
 	"rebroadcastwinners--synopsis": "Asks the daemon to rebroadcast the winners of the voting lottery.\n",
 
+	// ReloadWhitelistsCmd help.
+	"reloadwhitelists--synopsis": "Reloads the whitelisted IP addresses and networks from the config file and command line options.\n" +
+		"The reloaded whitelists apply to newly connecting peers while existing peers retain their current whitelisted status.",
+	"reloadwhitelists--result0": "The whitelisted networks",
+
+	// RestartCmd help.
+	"restart--synopsis": "Shutdown dcrd in order for it to be restarted.\n" +
+		"The process exits with code 75 instead of 0 once the shutdown completes so process managers are able to distinguish it from a normal shutdown and restart the process.",
+	"restart--result0": "The string 'dcrd restarting.'",
+
+	// RelayBlockCmd help.
+	"relayblock--synopsis": "Announces a block to all connected peers that are not already known to have it in the same way as a newly connected block.\n" +
+		"This is only available on the simulation and regression test networks.",
+	"relayblock-hash": "The hash of the block to announce",
+
 	// SearchRawTransactionsCmd help.
 	"searchrawtransactions--synopsis": "Returns raw data for transactions involving the passed address.\n" +
 		"Returned transactions are pulled from both the database, and transactions currently in the mempool.\n" +
 		"Transactions pulled from the mempool will have the 'confirmations' field set to 0.\n" +
 		"Usage of this RPC requires the optional --addrindex flag to be activated, otherwise all responses will simply return with an error stating the address index has not yet been built.\n" +
 		"Similarly, until the address index has caught up with the current best height, all requests will return an error response in order to avoid serving stale data.",
-	"searchrawtransactions-address":     "The Decred address to search for",
-	"searchrawtransactions-verbose":     "Specifies the transaction is returned as a JSON object instead of hex-encoded string",
-	"searchrawtransactions--condition0": "verbose=0",
-	"searchrawtransactions--condition1": "verbose=1",
-	"searchrawtransactions-skip":        "The number of leading transactions to leave out of the final response",
-	"searchrawtransactions-count":       "The maximum number of transactions to return",
-	"searchrawtransactions-vinextra":    "Specify that extra data from previous output will be returned in vin",
-	"searchrawtransactions-reverse":     "Specifies that the transactions should be returned in reverse chronological order",
-	"searchrawtransactions-filteraddrs": "Address list.  Only inputs or outputs with matching address will be returned",
-	"searchrawtransactions--result0":    "Hex-encoded serialized transaction",
+	"searchrawtransactions-address":      "The Decred address to search for",
+	"searchrawtransactions-verbose":      "Specifies the transaction is returned as a JSON object instead of hex-encoded string",
+	"searchrawtransactions--condition0":  "verbose=0",
+	"searchrawtransactions--condition1":  "verbose=1",
+	"searchrawtransactions--condition2":  "includetotal=true",
+	"searchrawtransactions-skip":         "The number of leading transactions to leave out of the final response",
+	"searchrawtransactions-count":        "The maximum number of transactions to return",
+	"searchrawtransactions-vinextra":     "Specify that extra data from previous output will be returned in vin",
+	"searchrawtransactions-reverse":      "Specifies that the transactions should be returned in reverse chronological order",
+	"searchrawtransactions-filteraddrs":  "Address list.  Only inputs or outputs with matching address will be returned",
+	"searchrawtransactions-includetotal": "Return an object which also includes the total number of transactions involving the address for pagination",
+	"searchrawtransactions--result0":     "Hex-encoded serialized transaction",
 
 	// SendRawTransactionCmd help.
 	"sendrawtransaction--synopsis":     "Submits the serialized, hex-encoded transaction to the local peer and relays it to the network.",
@@ -707,10 +993,16 @@ var helpDescsEnUS = map[string]string{
 	"sendrawtransaction-allowhighfees": "Whether or not to allow insanely high fees (dcrd does not yet implement this parameter, so it has no effect)",
 	"sendrawtransaction--result0":      "The hash of the transaction",
 
+	// SetBanCmd help.
+	"setban--synopsis": "Bans or lifts the ban of an IP address or subnet.  Banning disconnects any matching peers that are currently connected.",
+	"setban-address":   "The IP address or subnet in CIDR notation (e.g. 192.168.0.0/16) to operate on",
+	"setban-command":   "'add' to ban the address or 'remove' to lift an existing ban",
+	"setban-bantime":   "The number of seconds to ban the address for when adding a ban, up to 9223372036 (default: the configured ban duration)",
+
 	// SetGenerateCmd help.
 	"setgenerate--synopsis":    "Set the server to generate coins (mine) or not.",
 	"setgenerate-generate":     "Use true to enable generation, false to disable it",
-	"setgenerate-genproclimit": "The number of processors (cores) to limit generation to or -1 for default",
+	"setgenerate-genproclimit": "The number of processors (cores) to limit generation to or -1 for all cores",
 
 	// StopCmd help.
 	"stop--synopsis": "Shutdown dcrd.",
@@ -772,9 +1064,10 @@ var helpDescsEnUS = map[string]string{
 
 	// NotifyBlocksCmd help.
 	"notifyblocks--synopsis": "Request notifications for whenever a block is connected or disconnected from the main (best) chain.",
+	"notifyblocks-sidechain": "Also request notifications for whenever a block is accepted into a side chain without being connected to the main chain",
 
 	// StopNotifyBlocksCmd help.
-	"stopnotifyblocks--synopsis": "Cancel registered notifications for whenever a block is connected or disconnected from the main (best) chain.",
+	"stopnotifyblocks--synopsis": "Cancel registered notifications for whenever a block is connected or disconnected from the main (best) chain or accepted into a side chain.",
 
 	// NotifyNewTransactionsCmd help.
 	"notifynewtransactions--synopsis": "Send either a txaccepted or a txacceptedverbose notification when a new transaction is accepted into the mempool.",
@@ -793,11 +1086,15 @@ var helpDescsEnUS = map[string]string{
 	"loadtxfilter-reload":    "Load a new filter instead of adding data to an existing one",
 	"loadtxfilter-addresses": "Array of addresses to add to the transaction filter",
 	"loadtxfilter-outpoints": "Array of outpoints to add to the transaction filter",
+	"loadtxfilter-tree":      "Limit matches to transactions in the 'regular' or 'stake' tree, or match both with 'all' (default: 'all' for a new filter, unchanged when adding to an existing one)",
 
 	// Rescan help.
 	"rescan--synopsis":   "Rescan blocks for transactions matching the loaded transaction filter.",
 	"rescan-blockhashes": "Array of block hashes to rescan.  Each next block must be a child of the previous.",
 
+	// CancelRescanCmd help.
+	"cancelrescan--synopsis": "Cancel the rescans that are currently running for the websocket client.  The cancelled rescans return the data discovered so far with the cancelled flag set.",
+
 	// -------- Decred-specific help --------
 
 	// EstimateFee help.
@@ -808,7 +1105,7 @@ var helpDescsEnUS = map[string]string{
 	// EstimateSmartFee help.
 	"estimatesmartfee--synopsis":     "Returns the estimated fee using the historical fee data in dcr/kb.",
 	"estimatesmartfee-confirmations": "Estimate the fee rate a transaction requires so that it is mined in up to this number of blocks.",
-	"estimatesmartfee-mode":          "The only supported mode for the moment is 'conservative'.",
+	"estimatesmartfee-mode":          "The estimation mode: 'conservative' uses a long history of blocks and is less likely to undershoot the target, while 'economical' uses only recent blocks and generally results in lower fee rates.",
 	"estimatesmartfee--result0":      "Estimated fee rate (in DCR/KB).",
 
 	// EstimateStakeDiff help.
@@ -819,9 +1116,54 @@ var helpDescsEnUS = map[string]string{
 	"estimatestakediffresult-expected": "Expected estimate for stake difficulty",
 	"estimatestakediffresult-user":     "Estimate for stake difficulty with the passed user amount of tickets",
 
+	// GetCoinbaseTemplateCmd help.
+	"getcoinbasetemplate--synopsis": "Returns the structure of the coinbase transaction of the current block template, including the subsidy split, the outputs, and the location of the extra nonce.",
+
+	// GetCoinbaseTemplateResult help.
+	"getcoinbasetemplateresult-height":            "The height of the block the template is for",
+	"getcoinbasetemplateresult-previousblockhash": "The hash of the block the template builds on",
+	"getcoinbasetemplateresult-voters":            "The number of votes included in the template",
+	"getcoinbasetemplateresult-pow":               "The proof-of-work subsidy paid to the miner in atoms",
+	"getcoinbasetemplateresult-pos":               "The total proof-of-stake subsidy paid to the included votes in atoms",
+	"getcoinbasetemplateresult-developer":         "The treasury subsidy in atoms",
+	"getcoinbasetemplateresult-fees":              "The total fees paid by the transactions in the template in atoms",
+	"getcoinbasetemplateresult-txid":              "The hash of the coinbase transaction",
+	"getcoinbasetemplateresult-hex":               "The serialized, hex-encoded coinbase transaction",
+	"getcoinbasetemplateresult-vout":              "The outputs of the coinbase transaction",
+	"getcoinbasetemplateresult-extranonce":        "The location of the extra nonce in the coinbase transaction (omitted when there is none)",
+
+	// CoinbaseExtraNonceResult help.
+	"coinbaseextranonceresult-output": "The index of the output that houses the extra nonce",
+	"coinbaseextranonceresult-offset": "The byte offset of the extra nonce within the public key script of the output",
+	"coinbaseextranonceresult-size":   "The size of the extra nonce in bytes",
+	"coinbaseextranonceresult-value":  "The value of the extra nonce in the template",
+
 	// GetCoinSupply help
-	"getcoinsupply--synopsis": "Returns current total coin supply in atoms",
-	"getcoinsupply--result0":  "Current coin supply in atoms",
+	"getcoinsupply--synopsis":   "Returns current total coin supply in atoms",
+	"getcoinsupply-verbose":     "Returns a JSON object that breaks the coin supply down by category when true or the total coin supply when false",
+	"getcoinsupply--condition0": "verbose=false",
+	"getcoinsupply--condition1": "verbose=true",
+	"getcoinsupply--result0":    "Current coin supply in atoms",
+
+	// GetCoinSupplyVerboseResult help.
+	"getcoinsupplyverboseresult-total":           "The total coin supply in atoms",
+	"getcoinsupplyverboseresult-mined":           "The portion of the coin supply created by the proof-of-work and proof-of-stake subsidies in atoms",
+	"getcoinsupplyverboseresult-treasury":        "The portion of the coin supply created by the treasury subsidy in atoms",
+	"getcoinsupplyverboseresult-blockone":        "The portion of the coin supply created by the block one ledger in atoms",
+	"getcoinsupplyverboseresult-lockedintickets": "The amount locked in live tickets in atoms",
+	"getcoinsupplyverboseresult-circulating":     "The total coin supply less the amount locked in live tickets in atoms",
+
+	// IsBlockFinalCmd help.
+	"isblockfinal--synopsis": "Returns whether a block is buried under enough confirmations in the main chain that it is considered final along with its current number of confirmations.",
+	"isblockfinal-block":     "The hash or height of the block",
+	"isblockfinal-depth":     "The number of confirmations required for the block to be considered final (default: the value of the finalitydepth config option)",
+
+	// IsBlockFinalResult help.
+	"isblockfinalresult-hash":          "The hash of the block",
+	"isblockfinalresult-height":        "The height of the block",
+	"isblockfinalresult-confirmations": "The number of confirmations of the block or -1 if it is not in the main chain",
+	"isblockfinalresult-finalitydepth": "The number of confirmations required for the block to be considered final",
+	"isblockfinalresult-final":         "Whether or not the block has at least the required number of confirmations",
 
 	// LiveTickets help.
 	"livetickets--synopsis":     "Returns live ticket hashes from the ticket database",
@@ -844,20 +1186,22 @@ var helpDescsEnUS = map[string]string{
 	"ticketfeeinforesult-feeinfoblocks":  "Ticket fee information for a given list of blocks descending from the chain tip (units: DCR/kB)",
 	"ticketfeeinforesult-feeinfowindows": "Ticket fee information for a window period where the stake difficulty was the same (units: DCR/kB)",
 
-	"feeinfomempool-number": "Number of transactions in the mempool",
-	"feeinfomempool-min":    "Minimum transaction fee in the mempool",
-	"feeinfomempool-max":    "Maximum transaction fee in the mempool",
-	"feeinfomempool-mean":   "Mean of transaction fees in the mempool",
-	"feeinfomempool-median": "Median of transaction fees in the mempool",
-	"feeinfomempool-stddev": "Standard deviation of transaction fees in the mempool",
-
-	"feeinfoblock-height": "Height of the block",
-	"feeinfoblock-number": "Number of transactions in the block",
-	"feeinfoblock-min":    "Minimum transaction fee in the block",
-	"feeinfoblock-max":    "Maximum transaction fee in the block",
-	"feeinfoblock-mean":   "Mean of transaction fees in the block",
-	"feeinfoblock-median": "Median of transaction fees in the block",
-	"feeinfoblock-stddev": "Standard deviation of transaction fees in the block",
+	"feeinfomempool-number":      "Number of transactions in the mempool",
+	"feeinfomempool-min":         "Minimum transaction fee in the mempool",
+	"feeinfomempool-max":         "Maximum transaction fee in the mempool",
+	"feeinfomempool-mean":        "Mean of transaction fees in the mempool",
+	"feeinfomempool-median":      "Median of transaction fees in the mempool",
+	"feeinfomempool-stddev":      "Standard deviation of transaction fees in the mempool",
+	"feeinfomempool-percentiles": "Percentile breakdown of transaction fees in the mempool (only when requested)",
+
+	"feeinfoblock-height":      "Height of the block",
+	"feeinfoblock-number":      "Number of transactions in the block",
+	"feeinfoblock-min":         "Minimum transaction fee in the block",
+	"feeinfoblock-max":         "Maximum transaction fee in the block",
+	"feeinfoblock-mean":        "Mean of transaction fees in the block",
+	"feeinfoblock-median":      "Median of transaction fees in the block",
+	"feeinfoblock-stddev":      "Standard deviation of transaction fees in the block",
+	"feeinfoblock-percentiles": "Percentile breakdown of transaction fees in the block (only when requested)",
 
 	"feeinfowindow-startheight": "First block in the window (inclusive)",
 	"feeinfowindow-endheight":   "Last block in the window (exclusive)",
@@ -867,12 +1211,26 @@ var helpDescsEnUS = map[string]string{
 	"feeinfowindow-mean":        "Mean of transaction fees in the window",
 	"feeinfowindow-median":      "Median of transaction fees in the window",
 	"feeinfowindow-stddev":      "Standard deviation of transaction fees in the window",
+	"feeinfowindow-percentiles": "Percentile breakdown of transaction fees in the window (only when requested)",
+
+	"feeinfopercentiles-p10": "10th percentile of the transaction fees",
+	"feeinfopercentiles-p25": "25th percentile of the transaction fees",
+	"feeinfopercentiles-p75": "75th percentile of the transaction fees",
+	"feeinfopercentiles-p90": "90th percentile of the transaction fees",
 
 	// TicketsForAddress help.
 	"ticketsforaddress--synopsis":     "Request all the tickets for an address.",
 	"ticketsforaddress-address":       "Address to look for.",
 	"ticketsforaddressresult-tickets": "Tickets owned by the specified address.",
 
+	// TicketsForAddressesCmd help.
+	"ticketsforaddresses--synopsis":            "Request all the tickets for each of up to 100 addresses.",
+	"ticketsforaddresses-addresses":            "Addresses to look for.",
+	"ticketsforaddressesresult-tickets":        "Tickets owned by each of the specified addresses.",
+	"ticketsforaddressesresult-tickets--desc":  "Tickets keyed by address.",
+	"ticketsforaddressesresult-tickets--key":   "address",
+	"ticketsforaddressesresult-tickets--value": "The tickets owned by the address",
+
 	// TicketsForBucket help.
 	"ticketsforbucket--synopsis":     "Request all the tickets and owners in a given bucket.",
 	"ticketsforbucket-bucket":        "Bucket to look for.",
@@ -891,16 +1249,19 @@ var helpDescsEnUS = map[string]string{
 	"txfeeinfo-blocks":               "The number of blocks to calculate transaction fees for, starting from the end of the tip moving backwards",
 	"txfeeinfo-rangestart":           "The start height of the block range to calculate transaction fees for",
 	"txfeeinfo-rangeend":             "The end height of the block range to calculate transaction fees for",
+	"txfeeinfo-percentiles":          "Include a percentile breakdown of the transaction fees in each result",
+	"txfeeinfo-mempoolonly":          "Only return transaction fee information for the mempool, skipping all block scans",
 	"txfeeinforesult-feeinfomempool": "Transaction fee information for all regular transactions in the mempool",
 	"txfeeinforesult-feeinfoblocks":  "Transaction fee information for a given list of blocks descending from the chain tip",
 	"txfeeinforesult-feeinforange":   "Transaction fee information for a window period where the stake difficulty was the same",
 
-	"feeinforange-number": "Number of transactions in the window",
-	"feeinforange-min":    "Minimum transaction fee in the window",
-	"feeinforange-max":    "Maximum transaction fee in the window",
-	"feeinforange-mean":   "Mean of transaction fees in the window",
-	"feeinforange-median": "Median of transaction fees in the window",
-	"feeinforange-stddev": "Standard deviation of transaction fees in the window",
+	"feeinforange-number":      "Number of transactions in the window",
+	"feeinforange-min":         "Minimum transaction fee in the window",
+	"feeinforange-max":         "Maximum transaction fee in the window",
+	"feeinforange-mean":        "Mean of transaction fees in the window",
+	"feeinforange-median":      "Median of transaction fees in the window",
+	"feeinforange-stddev":      "Standard deviation of transaction fees in the window",
+	"feeinforange-percentiles": "Percentile breakdown of transaction fees in the window (only when requested)",
 
 	// Version help.
 	"version--synopsis":       "Returns the JSON-RPC API version (semver)",
@@ -913,79 +1274,110 @@ var helpDescsEnUS = map[string]string{
 // This information is used to generate the help.  Each result type must be a
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[types.Method][]interface{}{
-	"addnode":               nil,
-	"createrawsstx":         {(*string)(nil)},
-	"createrawssrtx":        {(*string)(nil)},
-	"createrawtransaction":  {(*string)(nil)},
-	"debuglevel":            {(*string)(nil), (*string)(nil)},
-	"decoderawtransaction":  {(*types.TxRawDecodeResult)(nil)},
-	"decodescript":          {(*types.DecodeScriptResult)(nil)},
-	"estimatefee":           {(*float64)(nil)},
-	"estimatesmartfee":      {(*float64)(nil)},
-	"estimatestakediff":     {(*types.EstimateStakeDiffResult)(nil)},
-	"existsaddress":         {(*bool)(nil)},
-	"existsaddresses":       {(*string)(nil)},
-	"existsmissedtickets":   {(*string)(nil)},
-	"existsexpiredtickets":  {(*string)(nil)},
-	"existsliveticket":      {(*bool)(nil)},
-	"existslivetickets":     {(*string)(nil)},
-	"existsmempooltxs":      {(*string)(nil)},
-	"getaddednodeinfo":      {(*[]string)(nil), (*[]types.GetAddedNodeInfoResult)(nil)},
-	"getbestblock":          {(*types.GetBestBlockResult)(nil)},
-	"generate":              {(*[]string)(nil)},
-	"getbestblockhash":      {(*string)(nil)},
-	"getblock":              {(*string)(nil), (*types.GetBlockVerboseResult)(nil)},
-	"getblockchaininfo":     {(*types.GetBlockChainInfoResult)(nil)},
-	"getblockcount":         {(*int64)(nil)},
-	"getblockhash":          {(*string)(nil)},
-	"getblockheader":        {(*string)(nil), (*types.GetBlockHeaderVerboseResult)(nil)},
-	"getblocksubsidy":       {(*types.GetBlockSubsidyResult)(nil)},
-	"getcfilter":            {(*string)(nil)},
-	"getcfilterheader":      {(*string)(nil)},
-	"getchaintips":          {(*[]types.GetChainTipsResult)(nil)},
-	"getconnectioncount":    {(*int32)(nil)},
-	"getcurrentnet":         {(*uint32)(nil)},
-	"getdifficulty":         {(*float64)(nil)},
-	"getstakedifficulty":    {(*types.GetStakeDifficultyResult)(nil)},
-	"getstakeversioninfo":   {(*types.GetStakeVersionInfoResult)(nil)},
-	"getstakeversions":      {(*types.GetStakeVersionsResult)(nil)},
-	"getgenerate":           {(*bool)(nil)},
-	"gethashespersec":       {(*float64)(nil)},
-	"getheaders":            {(*types.GetHeadersResult)(nil)},
-	"getinfo":               {(*types.InfoChainResult)(nil)},
-	"getmempoolinfo":        {(*types.GetMempoolInfoResult)(nil)},
-	"getmininginfo":         {(*types.GetMiningInfoResult)(nil)},
-	"getnettotals":          {(*types.GetNetTotalsResult)(nil)},
-	"getnetworkhashps":      {(*int64)(nil)},
-	"getnetworkinfo":        {(*[]types.GetNetworkInfoResult)(nil)},
-	"getpeerinfo":           {(*[]types.GetPeerInfoResult)(nil)},
-	"getrawmempool":         {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
-	"getrawtransaction":     {(*string)(nil), (*types.TxRawResult)(nil)},
-	"getticketpoolvalue":    {(*float64)(nil)},
-	"gettxout":              {(*types.GetTxOutResult)(nil)},
-	"getvoteinfo":           {(*types.GetVoteInfoResult)(nil)},
-	"getwork":               {(*types.GetWorkResult)(nil), (*bool)(nil)},
-	"getcoinsupply":         {(*int64)(nil)},
-	"help":                  {(*string)(nil), (*string)(nil)},
-	"livetickets":           {(*types.LiveTicketsResult)(nil)},
-	"missedtickets":         {(*types.MissedTicketsResult)(nil)},
-	"node":                  nil,
-	"ping":                  nil,
-	"searchrawtransactions": {(*string)(nil), (*[]types.SearchRawTransactionsResult)(nil)},
-	"sendrawtransaction":    {(*string)(nil)},
-	"setgenerate":           nil,
-	"stop":                  {(*string)(nil)},
-	"submitblock":           {nil, (*string)(nil)},
-	"ticketfeeinfo":         {(*types.TicketFeeInfoResult)(nil)},
-	"ticketsforaddress":     {(*types.TicketsForAddressResult)(nil)},
-	"ticketvwap":            {(*float64)(nil)},
-	"txfeeinfo":             {(*types.TxFeeInfoResult)(nil)},
-	"validateaddress":       {(*types.ValidateAddressChainResult)(nil)},
-	"verifychain":           {(*bool)(nil)},
-	"verifymessage":         {(*bool)(nil)},
-	"version":               {(*map[string]types.VersionResult)(nil)},
+	"addnode":                nil,
+	"addpeeraddresses":       nil,
+	"bansubnet":              nil,
+	"createrawsstx":          {(*string)(nil)},
+	"createrawssrtx":         {(*string)(nil)},
+	"createrawtransaction":   {(*string)(nil)},
+	"debugdump":              {(*types.DebugDumpResult)(nil)},
+	"debuglevel":             {(*string)(nil), (*string)(nil)},
+	"decoderawtransaction":   {(*types.TxRawDecodeResult)(nil)},
+	"decodescript":           {(*types.DecodeScriptResult)(nil)},
+	"decodeticket":           {(*types.DecodeTicketResult)(nil)},
+	"dropallpeers":           {(*int)(nil)},
+	"estimatefee":            {(*float64)(nil)},
+	"estimatesmartfee":       {(*float64)(nil)},
+	"estimatestakediff":      {(*types.EstimateStakeDiffResult)(nil)},
+	"existsaddress":          {(*bool)(nil)},
+	"existsaddresses":        {(*string)(nil)},
+	"existsmissedtickets":    {(*string)(nil)},
+	"existsexpiredtickets":   {(*string)(nil)},
+	"existsliveticket":       {(*bool)(nil)},
+	"existslivetickets":      {(*string)(nil)},
+	"existsmempooltxs":       {(*string)(nil)},
+	"getaddednodeinfo":       {(*[]string)(nil), (*[]types.GetAddedNodeInfoResult)(nil)},
+	"getaddrmaninfo":         {(*types.GetAddrManInfoResult)(nil)},
+	"getbannedpeers":         {(*[]types.GetBannedPeersResult)(nil)},
+	"getbestblock":           {(*types.GetBestBlockResult)(nil)},
+	"generate":               {(*[]string)(nil)},
+	"getbestblockhash":       {(*string)(nil)},
+	"getblock":               {(*string)(nil), (*types.GetBlockVerboseResult)(nil)},
+	"getblockchaininfo":      {(*types.GetBlockChainInfoResult)(nil)},
+	"getblockcount":          {(*int64)(nil)},
+	"getblockhash":           {(*string)(nil)},
+	"getblockhashes":         {(*[]string)(nil)},
+	"getblockheader":         {(*string)(nil), (*types.GetBlockHeaderVerboseResult)(nil)},
+	"getblockheaderbyheight": {(*string)(nil)},
+	"getblocksubsidy":        {(*types.GetBlockSubsidyResult)(nil)},
+	"getcfilter":             {(*string)(nil)},
+	"getcfilterheader":       {(*string)(nil)},
+	"getchaintips":           {(*[]types.GetChainTipsResult)(nil)},
+	"getcompactblock":        {(*string)(nil), (*types.GetCompactBlockVerboseResult)(nil)},
+	"getconfig":              {(*map[string]interface{})(nil)},
+	"getconnectioncount":     {(*int32)(nil)},
+	"getconnmgrstats":        {(*types.GetConnMgrStatsResult)(nil)},
+	"getcurrentnet":          {(*uint32)(nil)},
+	"getdifficulty":          {(*float64)(nil)},
+	"getdifficultyinfo":      {(*types.GetDifficultyInfoResult)(nil)},
+	"getstakedifficulty":     {(*types.GetStakeDifficultyResult)(nil)},
+	"getstakeversioninfo":    {(*types.GetStakeVersionInfoResult)(nil)},
+	"getstakeversions":       {(*types.GetStakeVersionsResult)(nil)},
+	"getsubsidyschedule":     {(*[]types.GetSubsidyScheduleResult)(nil)},
+	"getgenerate":            {(*bool)(nil)},
+	"gethashespersec":        {(*float64)(nil)},
+	"getheaders":             {(*types.GetHeadersResult)(nil)},
+	"getinfo":                {(*types.InfoChainResult)(nil)},
+	"getknownaddresses":      {(*[]types.GetKnownAddressesResult)(nil)},
+	"getlocaladdresses":      {(*[]types.GetLocalAddressesResult)(nil)},
+	"getmemoryinfo":          {(*types.GetMemoryInfoResult)(nil)},
+	"getmempoolancestors":    {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
+	"getmempooldescendants":  {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
+	"getmempoolentry":        {(*types.GetRawMempoolVerboseResult)(nil)},
+	"getmempoolinfo":         {(*types.GetMempoolInfoResult)(nil)},
+	"getmininginfo":          {(*types.GetMiningInfoResult)(nil)},
+	"getnettotals":           {(*types.GetNetTotalsResult)(nil)},
+	"getnetworkhashps":       {(*int64)(nil)},
+	"getnetworkinfo":         {(*[]types.GetNetworkInfoResult)(nil)},
+	"getnodeaddresses":       {(*[]types.GetNodeAddressesResult)(nil)},
+	"getorphantxs":           {(*[]string)(nil), (*[]types.GetOrphanTxsResult)(nil)},
+	"getpeerinfo":            {(*[]types.GetPeerInfoResult)(nil)},
+	"getrawmempool":          {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
+	"getrawtransaction":      {(*string)(nil), (*types.TxRawResult)(nil)},
+	"getticketinfo":          {(*types.GetTicketInfoResult)(nil)},
+	"getticketpoolvalue":     {(*float64)(nil)},
+	"gettxout":               {(*types.GetTxOutResult)(nil)},
+	"getvoteinfo":            {(*types.GetVoteInfoResult)(nil)},
+	"getwork":                {(*types.GetWorkResult)(nil), (*bool)(nil)},
+	"getcoinbasetemplate":    {(*types.GetCoinbaseTemplateResult)(nil)},
+	"getcoinsupply":          {(*int64)(nil), (*types.GetCoinSupplyVerboseResult)(nil)},
+	"help":                   {(*string)(nil), (*string)(nil)},
+	"isblockfinal":           {(*types.IsBlockFinalResult)(nil)},
+	"livetickets":            {(*types.LiveTicketsResult)(nil)},
+	"missedtickets":          {(*types.MissedTicketsResult)(nil)},
+	"node":                   nil,
+	"ping":                   nil,
+	"relayblock":             nil,
+	"reloadwhitelists":       {(*[]string)(nil)},
+	"restart":                {(*string)(nil)},
+	"searchrawtransactions":  {(*string)(nil), (*[]types.SearchRawTransactionsResult)(nil), (*types.SearchRawTransactionsTotalResult)(nil)},
+	"sendrawtransaction":     {(*string)(nil)},
+	"setban":                 nil,
+	"setgenerate":            nil,
+	"stop":                   {(*string)(nil)},
+	"submitblock":            {nil, (*string)(nil)},
+	"ticketfeeinfo":          {(*types.TicketFeeInfoResult)(nil)},
+	"ticketsforaddress":      {(*types.TicketsForAddressResult)(nil)},
+	"ticketsforaddresses":    {(*types.TicketsForAddressesResult)(nil)},
+	"ticketvwap":             {(*float64)(nil)},
+	"txfeeinfo":              {(*types.TxFeeInfoResult)(nil)},
+	"validateaddress":        {(*types.ValidateAddressChainResult)(nil)},
+	"verifychain":            {(*bool)(nil)},
+	"verifymessage":          {(*bool)(nil)},
+	"version":                {(*map[string]types.VersionResult)(nil)},
 
 	// Websocket commands.
+	"cancelrescan":                nil,
 	"loadtxfilter":                nil,
 	"notifywinningtickets":        nil,
 	"notifyspentandmissedtickets": nil,
@@ -15,8 +15,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -41,6 +43,17 @@ const (
 	// handler since notifications have their own queuing mechanism
 	// independent of the send channel buffer.
 	websocketSendBufferSize = 50
+
+	// ntfnOverflowDropOldest and ntfnOverflowDisconnect are the supported
+	// policies for handling a full websocket client notification queue.
+	// They drop the oldest queued notification to make room for the new
+	// one and disconnect the client, respectively.
+	ntfnOverflowDropOldest = "dropoldest"
+	ntfnOverflowDisconnect = "disconnect"
+
+	// wsFilterTreeAll is the transaction tree of a websocket client filter
+	// that matches transactions from both the regular and stake trees.
+	wsFilterTreeAll int8 = -1
 )
 
 type semaphore chan struct{}
@@ -65,6 +78,7 @@ type wsCommandHandler func(*wsClient, interface{}) (interface{}, error)
 // causes a dependency loop.
 var wsHandlers map[types.Method]wsCommandHandler
 var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
+	"cancelrescan":                handleCancelRescan,
 	"help":                        handleWebsocketHelp,
 	"loadtxfilter":                handleLoadTxFilter,
 	"notifyblocks":                handleNotifyBlocks,
@@ -126,6 +140,10 @@ func (s *rpcServer) WebsocketHandler(conn *websocket.Conn, remoteAddr string, au
 // have registered for and notifies them accordingly.  It is also used to keep
 // track of all connected websocket clients.
 type wsNotificationManager struct {
+	// numStakeDifficultyClients is the number of clients registered for
+	// stake difficulty notifications.  It must only be used atomically.
+	numStakeDifficultyClients int32
+
 	// server is the RPC server the notification manager is associated with.
 	server *rpcServer
 
@@ -227,6 +245,20 @@ func (m *wsNotificationManager) NotifyBlockDisconnected(block *dcrutil.Block) {
 	}
 }
 
+// NotifySideChainBlockAccepted passes a block that was accepted into a side
+// chain without being connected to the best chain to the notification manager
+// for block notification processing.
+func (m *wsNotificationManager) NotifySideChainBlockAccepted(band *blockchain.BlockAcceptedNtfnsData) {
+	// As NotifySideChainBlockAccepted will be called by the block manager
+	// and the RPC server may no longer be running, use a select
+	// statement to unblock enqueuing the notification once the RPC
+	// server has begun shutting down.
+	select {
+	case m.queueNotification <- (*notificationSideChainBlockAccepted)(band):
+	case <-m.quit:
+	}
+}
+
 // NotifyReorganization passes a blockchain reorganization notification for
 // reorganization notification processing.
 func (m *wsNotificationManager) NotifyReorganization(rd *blockchain.ReorganizationNtfnsData) {
@@ -327,11 +359,13 @@ type WinningTicketsNtfnData struct {
 }
 
 // StakeDifficultyNtfnData is the data that is used to generate
-// stake difficulty notifications.
+// stake difficulty notifications.  The estimated stake difficulty is nil when
+// it was not estimated.
 type StakeDifficultyNtfnData struct {
-	BlockHash       chainhash.Hash
-	BlockHeight     int64
-	StakeDifficulty int64
+	BlockHash                chainhash.Hash
+	BlockHeight              int64
+	StakeDifficulty          int64
+	EstimatedStakeDifficulty *int64
 }
 
 type wsClientFilter struct {
@@ -353,10 +387,15 @@ type wsClientFilter struct {
 
 	// Outpoints of unspent outputs.
 	unspent map[wire.OutPoint]struct{}
+
+	// The transaction tree matches are limited to.  It is wsFilterTreeAll
+	// when transactions from both trees are matched.
+	tree int8
 }
 
-func makeWSClientFilter(addresses []string, unspentOutPoints []*wire.OutPoint, params dcrutil.AddressParams) *wsClientFilter {
+func makeWSClientFilter(addresses []string, unspentOutPoints []*wire.OutPoint, tree int8, params dcrutil.AddressParams) *wsClientFilter {
 	filter := &wsClientFilter{
+		tree:                tree,
 		params:              params,
 		pubKeyHashes:        map[[ripemd160.Size]byte]struct{}{},
 		scriptHashes:        map[[ripemd160.Size]byte]struct{}{},
@@ -448,6 +487,12 @@ func (f *wsClientFilter) existsAddress(a dcrutil.Address) bool {
 	return ok
 }
 
+// matchesTree returns whether transactions in the provided tree are matched by
+// the filter.
+func (f *wsClientFilter) matchesTree(tree int8) bool {
+	return f.tree == wsFilterTreeAll || f.tree == tree
+}
+
 func (f *wsClientFilter) addUnspentOutPoint(op *wire.OutPoint) {
 	f.unspent[*op] = struct{}{}
 }
@@ -460,6 +505,7 @@ func (f *wsClientFilter) existsUnspentOutPoint(op *wire.OutPoint) bool {
 // Notification types
 type notificationBlockConnected dcrutil.Block
 type notificationBlockDisconnected dcrutil.Block
+type notificationSideChainBlockAccepted blockchain.BlockAcceptedNtfnsData
 type notificationReorganization blockchain.ReorganizationNtfnsData
 type notificationWinningTickets WinningTicketsNtfnData
 type notificationSpentAndMissedTickets blockchain.TicketNotificationsData
@@ -475,6 +521,8 @@ type notificationRegisterClient wsClient
 type notificationUnregisterClient wsClient
 type notificationRegisterBlocks wsClient
 type notificationUnregisterBlocks wsClient
+type notificationRegisterSideChainBlocks wsClient
+type notificationUnregisterSideChainBlocks wsClient
 type notificationRegisterWinningTickets wsClient
 type notificationUnregisterWinningTickets wsClient
 type notificationRegisterSpentAndMissedTickets wsClient
@@ -500,6 +548,7 @@ func (m *wsNotificationManager) notificationHandler() {
 	// Where possible, the quit channel is used as the unique id for a client
 	// since it is quite a bit more efficient than using the entire struct.
 	blockNotifications := make(map[chan struct{}]*wsClient)
+	sideChainBlockNotifications := make(map[chan struct{}]*wsClient)
 	winningTicketNotifications := make(map[chan struct{}]*wsClient)
 	ticketSMNotifications := make(map[chan struct{}]*wsClient)
 	ticketNewNotifications := make(map[chan struct{}]*wsClient)
@@ -530,6 +579,10 @@ out:
 				m.notifyBlockDisconnected(blockNotifications,
 					(*dcrutil.Block)(n))
 
+			case *notificationSideChainBlockAccepted:
+				m.notifySideChainBlockAccepted(sideChainBlockNotifications,
+					(*blockchain.BlockAcceptedNtfnsData)(n))
+
 			case *notificationReorganization:
 				m.notifyReorganization(blockNotifications,
 					(*blockchain.ReorganizationNtfnsData)(n))
@@ -564,6 +617,14 @@ out:
 				wsc := (*wsClient)(n)
 				delete(blockNotifications, wsc.quit)
 
+			case *notificationRegisterSideChainBlocks:
+				wsc := (*wsClient)(n)
+				sideChainBlockNotifications[wsc.quit] = wsc
+
+			case *notificationUnregisterSideChainBlocks:
+				wsc := (*wsClient)(n)
+				delete(sideChainBlockNotifications, wsc.quit)
+
 			case *notificationRegisterWinningTickets:
 				wsc := (*wsClient)(n)
 				winningTicketNotifications[wsc.quit] = wsc
@@ -591,10 +652,14 @@ out:
 			case *notificationRegisterStakeDifficulty:
 				wsc := (*wsClient)(n)
 				stakeDifficultyNotifications[wsc.quit] = wsc
+				atomic.StoreInt32(&m.numStakeDifficultyClients,
+					int32(len(stakeDifficultyNotifications)))
 
 			case *notificationUnregisterStakeDifficulty:
 				wsc := (*wsClient)(n)
 				delete(stakeDifficultyNotifications, wsc.quit)
+				atomic.StoreInt32(&m.numStakeDifficultyClients,
+					int32(len(stakeDifficultyNotifications)))
 
 			case *notificationRegisterClient:
 				wsc := (*wsClient)(n)
@@ -605,8 +670,12 @@ out:
 				// Remove any requests made by the client as well as
 				// the client itself.
 				delete(blockNotifications, wsc.quit)
+				delete(sideChainBlockNotifications, wsc.quit)
+				delete(stakeDifficultyNotifications, wsc.quit)
 				delete(txNotifications, wsc.quit)
 				delete(clients, wsc.quit)
+				atomic.StoreInt32(&m.numStakeDifficultyClients,
+					int32(len(stakeDifficultyNotifications)))
 
 			case *notificationRegisterNewMempoolTxs:
 				wsc := (*wsClient)(n)
@@ -634,6 +703,14 @@ out:
 	m.wg.Done()
 }
 
+// HasStakeDifficultyClients returns whether or not any clients are registered
+// for stake difficulty notifications.
+//
+// This function is safe for concurrent access.
+func (m *wsNotificationManager) HasStakeDifficultyClients() bool {
+	return atomic.LoadInt32(&m.numStakeDifficultyClients) > 0
+}
+
 // NumClients returns the number of clients actively being served.
 func (m *wsNotificationManager) NumClients() (n int) {
 	select {
@@ -655,6 +732,18 @@ func (m *wsNotificationManager) UnregisterBlockUpdates(wsc *wsClient) {
 	m.queueNotification <- (*notificationUnregisterBlocks)(wsc)
 }
 
+// RegisterSideChainBlockUpdates requests notifications for blocks that are
+// accepted into a side chain to the passed websocket client.
+func (m *wsNotificationManager) RegisterSideChainBlockUpdates(wsc *wsClient) {
+	m.queueNotification <- (*notificationRegisterSideChainBlocks)(wsc)
+}
+
+// UnregisterSideChainBlockUpdates removes notifications for blocks that are
+// accepted into a side chain for the passed websocket client.
+func (m *wsNotificationManager) UnregisterSideChainBlockUpdates(wsc *wsClient) {
+	m.queueNotification <- (*notificationUnregisterSideChainBlocks)(wsc)
+}
+
 // subscribedClients returns the set of all websocket client quit channels that
 // are registered to receive notifications regarding tx, either due to tx
 // spending a watched output or outputting to a watched address.  Matching
@@ -678,6 +767,10 @@ func (m *wsNotificationManager) subscribedClients(tx *dcrutil.Tx, clients map[ch
 			continue
 		}
 		f.mu.Lock()
+		if !f.matchesTree(tx.Tree()) {
+			f.mu.Unlock()
+			continue
+		}
 
 		for _, input := range msgTx.TxIn {
 			if f.existsUnspentOutPoint(&input.PreviousOutPoint) {
@@ -821,6 +914,37 @@ func (*wsNotificationManager) notifyBlockDisconnected(clients map[chan struct{}]
 	}
 }
 
+// notifySideChainBlockAccepted notifies websocket clients that have registered
+// for side chain block updates when a block is accepted into a side chain
+// without being connected to the main chain.
+func (*wsNotificationManager) notifySideChainBlockAccepted(clients map[chan struct{}]*wsClient, band *blockchain.BlockAcceptedNtfnsData) {
+	// Skip notification creation if no clients have requested side chain
+	// block notifications.
+	if len(clients) == 0 {
+		return
+	}
+
+	// Notify interested websocket clients about the accepted block.
+	headerBytes, err := band.Block.MsgBlock().Header.Bytes()
+	if err != nil {
+		// This should never error.  The header is written to an
+		// in-memory expandable buffer, and given that the block was
+		// just accepted, there should be no issues serializing it.
+		panic(err)
+	}
+	ntfn := types.NewSideChainBlockAcceptedNtfn(hex.EncodeToString(headerBytes),
+		band.ForkLen)
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		rpcsLog.Errorf("Failed to marshal side chain block accepted "+
+			"notification: %v", err)
+		return
+	}
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
+}
+
 // notifyReorganization notifies websocket clients that have registered for
 // block updates when the blockchain is beginning a reorganization.
 func (m *wsNotificationManager) notifyReorganization(clients map[chan struct{}]*wsClient, rd *blockchain.ReorganizationNtfnsData) {
@@ -977,9 +1101,12 @@ func (*wsNotificationManager) notifyNewTickets(clients map[chan struct{}]*wsClie
 // maturing ticket updates.
 func (*wsNotificationManager) notifyStakeDifficulty(clients map[chan struct{}]*wsClient, sdnd *StakeDifficultyNtfnData) {
 	// Notify interested websocket clients about the connected block.
-	ntfn := types.NewStakeDifficultyNtfn(sdnd.BlockHash.String(),
-		int32(sdnd.BlockHeight),
-		sdnd.StakeDifficulty)
+	ntfn := &types.StakeDifficultyNtfn{
+		BlockHash:          sdnd.BlockHash.String(),
+		BlockHeight:        int32(sdnd.BlockHeight),
+		StakeDiff:          sdnd.StakeDifficulty,
+		EstimatedStakeDiff: sdnd.EstimatedStakeDifficulty,
+	}
 
 	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
 	if err != nil {
@@ -1082,6 +1209,10 @@ func (m *wsNotificationManager) notifyRelevantTxAccepted(tx *dcrutil.Tx,
 			continue
 		}
 		f.mu.Lock()
+		if !f.matchesTree(tx.Tree()) {
+			f.mu.Unlock()
+			continue
+		}
 
 		for _, input := range msgTx.TxIn {
 			if f.existsUnspentOutPoint(&input.PreviousOutPoint) {
@@ -1232,6 +1363,13 @@ type wsClient struct {
 
 	filterData *wsClientFilter
 
+	// rescanCancel is closed by the cancelrescan command to signal the
+	// rescans that are currently running for the client to stop early.  It
+	// is nil when there are no rescans that can be cancelled.  numRescans is
+	// the number of rescans currently running for the client.
+	rescanCancel chan struct{}
+	numRescans   int
+
 	// Networking infrastructure.
 	serviceRequestSem semaphore
 	ntfnChan          chan []byte
@@ -1240,9 +1378,43 @@ type wsClient struct {
 	wg                sync.WaitGroup
 }
 
+// extendIdleDeadline pushes back the time at which the client is disconnected
+// for being idle when an idle timeout is configured.  It is called whenever a
+// message is sent to or received from the client, including pings and pongs,
+// so clients that only receive notifications are not considered idle.
+//
+// This function is safe for concurrent access since it only sets the read
+// deadline of the underlying connection.
+func (c *wsClient) extendIdleDeadline() error {
+	if cfg.RPCIdleTimeout <= 0 {
+		return nil
+	}
+	return c.conn.SetReadDeadline(time.Now().Add(cfg.RPCIdleTimeout))
+}
+
 // inHandler handles all incoming messages for the websocket connection.  It
 // must be run as a goroutine.
 func (c *wsClient) inHandler() {
+	// Treat pings and pongs from the client as activity.  Pings are still
+	// answered with a pong as they are by default.
+	c.conn.SetPongHandler(func(string) error {
+		return c.extendIdleDeadline()
+	})
+	c.conn.SetPingHandler(func(appData string) error {
+		if err := c.extendIdleDeadline(); err != nil {
+			return err
+		}
+		err := c.conn.WriteControl(websocket.PongMessage, []byte(appData),
+			time.Now().Add(time.Second))
+		if err == websocket.ErrCloseSent {
+			return nil
+		}
+		if nerr, ok := err.(net.Error); ok && nerr.Temporary() {
+			return nil
+		}
+		return err
+	})
+
 out:
 	for {
 		// Break out of the loop once the quit channel has been closed.
@@ -1253,10 +1425,22 @@ out:
 		default:
 		}
 
+		// Disconnect the client when there is no activity within the
+		// idle timeout when one is configured.
+		if err := c.extendIdleDeadline(); err != nil {
+			rpcsLog.Errorf("Unable to set read deadline for websocket "+
+				"client %s: %v", c.addr, err)
+			break out
+		}
+
 		_, msg, err := c.conn.ReadMessage()
 		if err != nil {
-			// Log the error if it's not due to disconnecting.
-			if err != io.EOF {
+			// Log the error if it's not due to disconnecting or the
+			// client being idle.
+			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+				rpcsLog.Infof("Disconnecting idle websocket client "+
+					"%s", c.addr)
+			} else if err != io.EOF {
 				rpcsLog.Errorf("Websocket receive error from "+
 					"%s: %v", c.addr, err)
 			}
@@ -1382,6 +1566,8 @@ out:
 						Code:    dcrjson.ErrRPCInvalidParams.Code,
 						Message: "limited user not authorized for this method",
 					}
+					auditRPCRequest(c.addr, c.isAdmin, req.Method,
+						req.Params, jsonErr)
 					// Marshal and send response.
 					reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
 					if err != nil {
@@ -1623,6 +1809,8 @@ out:
 									Code:    dcrjson.ErrRPCInvalidParams.Code,
 									Message: "limited user not authorized for this method",
 								}
+								auditRPCRequest(c.addr, c.isAdmin, req.Method,
+									req.Params, jsonErr)
 								// Marshal and send response.
 								reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
 								if err != nil {
@@ -1647,9 +1835,15 @@ out:
 						} else {
 							resp, err = c.rpcServer.standardCmdResult(cmd, nil)
 						}
+						auditRPCRequest(c.addr, c.isAdmin, req.Method,
+							req.Params, err)
 
 						// Marshal request output.
 						reply, err := createMarshalledReply(cmd.jsonrpc, cmd.id, resp, err)
+						if err == nil {
+							reply, err = c.rpcServer.limitResponseSize(cmd.jsonrpc,
+								cmd.id, reply)
+						}
 						if err != nil {
 							rpcsLog.Errorf("Failed to marshal reply for <%s> "+
 								"command: %v", cmd.method, err)
@@ -1718,7 +1912,11 @@ func (c *wsClient) serviceRequest(r *parsedRPCCmd) {
 	} else {
 		result, err = c.rpcServer.standardCmdResult(r, nil)
 	}
+	auditRPCRequest(c.addr, c.isAdmin, string(r.method), r.rawParams, err)
 	reply, err := createMarshalledReply(r.jsonrpc, r.id, result, err)
+	if err == nil {
+		reply, err = c.rpcServer.limitResponseSize(r.jsonrpc, r.id, reply)
+	}
 	if err != nil {
 		rpcsLog.Errorf("Failed to marshal reply for <%s> "+
 			"command: %v", r.method, err)
@@ -1728,12 +1926,51 @@ func (c *wsClient) serviceRequest(r *parsedRPCCmd) {
 	c.SendMessage(reply, nil)
 }
 
+// wsNtfnQueue is a first-in first-out queue of marshalled notifications that
+// are pending delivery to a websocket client.  It is optionally limited to a
+// maximum size, in which case it either drops the oldest notification to make
+// room for new ones or refuses them depending on its policy.
+type wsNtfnQueue struct {
+	ntfns      [][]byte
+	maxSize    int
+	dropOldest bool
+	dropped    uint64
+}
+
+// push adds the passed notification to the end of the queue.  When the queue
+// is full, the oldest notification is dropped to make room if the queue is
+// configured to do so, otherwise false is returned to indicate the
+// notification was not queued.
+func (q *wsNtfnQueue) push(ntfn []byte) bool {
+	if q.maxSize > 0 && len(q.ntfns) >= q.maxSize {
+		if !q.dropOldest {
+			return false
+		}
+		q.pop()
+		q.dropped++
+	}
+	q.ntfns = append(q.ntfns, ntfn)
+	return true
+}
+
+// pop removes and returns the notification at the front of the queue.  It
+// must not be called when the queue is empty.
+func (q *wsNtfnQueue) pop() []byte {
+	ntfn := q.ntfns[0]
+	q.ntfns[0] = nil
+	q.ntfns = q.ntfns[1:]
+	return ntfn
+}
+
 // notificationQueueHandler handles the queuing of outgoing notifications for
 // the websocket client.  This runs as a muxer for various sources of input to
 // ensure that queuing up notifications to be sent will not block.  Otherwise,
 // slow clients could bog down the other systems (such as the mempool or block
 // manager) which are queuing the data.  The data is passed on to outHandler to
-// actually be written.  It must be run as a goroutine.
+// actually be written.  The queue is limited by the rpcmaxntfnqueue option and
+// the rpcntfnoverflow option determines whether the oldest notifications are
+// dropped or the client is disconnected when a slow client causes it to fill
+// up.  It must be run as a goroutine.
 func (c *wsClient) notificationQueueHandler() {
 	ntfnSentChan := make(chan bool, 1) // nonblocking sync
 
@@ -1746,7 +1983,10 @@ func (c *wsClient) notificationQueueHandler() {
 	// future, not knowing what has and hasn't been sent to the outHandler
 	// (and thus who should respond to the done channel) would be
 	// problematic without using this approach.
-	var pendingNtfns [][]byte
+	pendingNtfns := wsNtfnQueue{
+		maxSize:    cfg.RPCMaxNtfnQueue,
+		dropOldest: cfg.RPCNtfnOverflow == ntfnOverflowDropOldest,
+	}
 	waiting := false
 out:
 	for {
@@ -1759,26 +1999,35 @@ out:
 		case msg := <-c.ntfnChan:
 			if !waiting {
 				c.SendMessage(msg, ntfnSentChan)
-			} else {
-				pendingNtfns = append(pendingNtfns, msg)
+				waiting = true
+				continue
+			}
+			if !pendingNtfns.push(msg) {
+				rpcsLog.Warnf("Disconnecting slow websocket client %s: "+
+					"notification queue limit of %d reached", c.addr,
+					pendingNtfns.maxSize)
+				c.Disconnect()
+				break out
 			}
-			waiting = true
 
 		// This channel is notified when a notification has been sent
 		// across the network socket.
 		case <-ntfnSentChan:
 			// No longer waiting if there are no more messages in
 			// the pending messages queue.
-			if len(pendingNtfns) == 0 {
+			if len(pendingNtfns.ntfns) == 0 {
+				if pendingNtfns.dropped > 0 {
+					rpcsLog.Warnf("Dropped %d notifications for slow "+
+						"websocket client %s", pendingNtfns.dropped,
+						c.addr)
+					pendingNtfns.dropped = 0
+				}
 				waiting = false
 				continue
 			}
 			// Notify the outHandler about the next item to
 			// asynchronously send.
-			msg := pendingNtfns[0]
-			pendingNtfns[0] = nil
-			pendingNtfns = pendingNtfns[1:]
-			c.SendMessage(msg, ntfnSentChan)
+			c.SendMessage(pendingNtfns.pop(), ntfnSentChan)
 
 		case <-c.quit:
 			break out
@@ -1821,6 +2070,13 @@ out:
 				r.doneChan <- true
 			}
 
+			// Sending responses and notifications to the client is
+			// activity that prevents it from being considered idle.
+			if err := c.extendIdleDeadline(); err != nil {
+				c.Disconnect()
+				break out
+			}
+
 		case <-c.quit:
 			break out
 		}
@@ -2000,11 +2256,40 @@ func handleWebsocketHelp(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	return help, nil
 }
 
+// parseFilterTree returns the transaction tree a websocket client filter is
+// limited to for the provided loadtxfilter tree parameter.
+func parseFilterTree(tree string) (int8, error) {
+	switch tree {
+	case "all":
+		return wsFilterTreeAll, nil
+	case "regular":
+		return wire.TxTreeRegular, nil
+	case "stake":
+		return wire.TxTreeStake, nil
+	}
+	return 0, &dcrjson.RPCError{
+		Code: dcrjson.ErrRPCInvalidParameter,
+		Message: fmt.Sprintf("Invalid transaction tree %q: must be one "+
+			"of all, regular, or stake", tree),
+	}
+}
+
 // handleLoadTxFilter implements the loadtxfilter command extension for
 // websocket connections.
 func handleLoadTxFilter(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	cmd := icmd.(*types.LoadTxFilterCmd)
 
+	// Filters match both transaction trees unless a tree is specified.  An
+	// existing filter keeps its tree when data is added to it without one.
+	tree := wsFilterTreeAll
+	if cmd.Tree != nil {
+		var err error
+		tree, err = parseFilterTree(*cmd.Tree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	outPoints := make([]*wire.OutPoint, len(cmd.OutPoints))
 	for i := range cmd.OutPoints {
 		hash, err := chainhash.NewHashFromStr(cmd.OutPoints[i].Hash)
@@ -2024,13 +2309,16 @@ func handleLoadTxFilter(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	wsc.Lock()
 	if cmd.Reload || wsc.filterData == nil {
 		wsc.filterData = makeWSClientFilter(cmd.Addresses, outPoints,
-			wsc.rpcServer.server.chainParams)
+			tree, wsc.rpcServer.server.chainParams)
 		wsc.Unlock()
 	} else {
 		filter := wsc.filterData
 		wsc.Unlock()
 
 		filter.mu.Lock()
+		if cmd.Tree != nil {
+			filter.tree = tree
+		}
 		for _, a := range cmd.Addresses {
 			filter.addAddressStr(a)
 		}
@@ -2046,7 +2334,15 @@ func handleLoadTxFilter(wsc *wsClient, icmd interface{}) (interface{}, error) {
 // handleNotifyBlocks implements the notifyblocks command extension for
 // websocket connections.
 func handleNotifyBlocks(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.NotifyBlocksCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
 	wsc.rpcServer.ntfnMgr.RegisterBlockUpdates(wsc)
+	if cmd.SideChain != nil && *cmd.SideChain {
+		wsc.rpcServer.ntfnMgr.RegisterSideChainBlockUpdates(wsc)
+	}
 	return nil, nil
 }
 
@@ -2145,6 +2441,7 @@ func handleStakeDifficulty(wsc *wsClient, icmd interface{}) (interface{}, error)
 // websocket connections.
 func handleStopNotifyBlocks(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	wsc.rpcServer.ntfnMgr.UnregisterBlockUpdates(wsc)
+	wsc.rpcServer.ntfnMgr.UnregisterSideChainBlockUpdates(wsc)
 	return nil, nil
 }
 
@@ -2182,6 +2479,10 @@ func rescanBlock(filter *wsClientFilter, block *dcrutil.Block, params *chaincfg.
 	// This makes unsynchronized calls to the filter and thus must only be
 	// called with the filter mutex held.
 	checkTransaction := func(tx *wire.MsgTx, tree int8) {
+		if !filter.matchesTree(tree) {
+			return
+		}
+
 		// Keep track of whether the transaction has already been added
 		// to the result.  It shouldn't be added twice.
 		added := false
@@ -2274,13 +2575,59 @@ func handleRescan(wsc *wsClient, icmd interface{}) (interface{}, error) {
 		return nil, err
 	}
 
+	// Register the rescan with the client so it can be cancelled by the
+	// cancelrescan command.
+	wsc.Lock()
+	if wsc.rescanCancel == nil {
+		wsc.rescanCancel = make(chan struct{})
+	}
+	cancel := wsc.rescanCancel
+	wsc.numRescans++
+	wsc.Unlock()
+	defer func() {
+		wsc.Lock()
+		wsc.numRescans--
+		if wsc.numRescans == 0 {
+			wsc.rescanCancel = nil
+		}
+		wsc.Unlock()
+	}()
+
 	discoveredData := make([]types.RescannedBlock, 0, len(blockHashes))
 
+	// Limit the number of rescans that run concurrently across all clients
+	// since they are expensive.  Rescans beyond the limit wait for a running
+	// one to finish unless the client disconnects or cancels it first.
+	if sem := wsc.rpcServer.rescanSem; sem != nil {
+		select {
+		case sem <- struct{}{}:
+		case <-cancel:
+			return &types.RescanResult{
+				DiscoveredData: discoveredData,
+				Cancelled:      true,
+			}, nil
+		case <-wsc.quit:
+			return nil, ErrClientQuit
+		}
+		defer sem.release()
+	}
+
 	// Iterate over each block in the request and rescan.  When a block
 	// contains relevant transactions, add it to the response.
 	bc := wsc.rpcServer.chain
 	var lastBlockHash *chainhash.Hash
 	for i := range blockHashes {
+		// Stop early with the data discovered so far when the rescan is
+		// cancelled.
+		select {
+		case <-cancel:
+			return &types.RescanResult{
+				DiscoveredData: discoveredData,
+				Cancelled:      true,
+			}, nil
+		default:
+		}
+
 		block, err := bc.BlockByHash(&blockHashes[i])
 		if err != nil {
 			return nil, &dcrjson.RPCError{
@@ -2309,6 +2656,25 @@ func handleRescan(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	return &types.RescanResult{DiscoveredData: discoveredData}, nil
 }
 
+// handleCancelRescan implements the cancelrescan command extension for
+// websocket connections.  It signals all rescans that are currently running
+// for the client to stop early.  Rescans requested by other clients are not
+// affected.
+func handleCancelRescan(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	wsc.Lock()
+	defer wsc.Unlock()
+
+	if wsc.rescanCancel == nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCMisc,
+			Message: "No rescan in progress",
+		}
+	}
+	close(wsc.rescanCancel)
+	wsc.rescanCancel = nil
+	return nil, nil
+}
+
 func init() {
 	wsHandlers = wsHandlersBeforeInit
 }
@@ -0,0 +1,354 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
+	"github.com/gorilla/websocket"
+)
+
+// TestWsNtfnQueue ensures the websocket client notification queue delivers
+// notifications in order and applies the configured overflow policy once it
+// is full.
+func TestWsNtfnQueue(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxSize    int
+		dropOldest bool
+		pushes     int
+		wantPushed int
+		wantFirst  byte
+		wantDrops  uint64
+	}{
+		{"unlimited", 0, false, 10, 10, 0, 0},
+		{"within limit", 5, false, 5, 5, 0, 0},
+		{"refuse when full", 5, false, 10, 5, 0, 0},
+		{"drop oldest when full", 5, true, 10, 10, 5, 5},
+	}
+
+	for _, test := range tests {
+		q := wsNtfnQueue{maxSize: test.maxSize, dropOldest: test.dropOldest}
+		var pushed int
+		for i := 0; i < test.pushes; i++ {
+			if q.push([]byte{byte(i)}) {
+				pushed++
+			}
+		}
+		if pushed != test.wantPushed {
+			t.Fatalf("%q: unexpected number of queued notifications -- got "+
+				"%d, want %d", test.name, pushed, test.wantPushed)
+		}
+		if q.dropped != test.wantDrops {
+			t.Fatalf("%q: unexpected number of dropped notifications -- "+
+				"got %d, want %d", test.name, q.dropped, test.wantDrops)
+		}
+		if test.maxSize > 0 && len(q.ntfns) > test.maxSize {
+			t.Fatalf("%q: queue size %d exceeds max size %d", test.name,
+				len(q.ntfns), test.maxSize)
+		}
+
+		// Ensure the remaining notifications are delivered in order.
+		want := test.wantFirst
+		for len(q.ntfns) > 0 {
+			if got := q.pop()[0]; got != want {
+				t.Fatalf("%q: unexpected notification -- got %d, want %d",
+					test.name, got, want)
+			}
+			want++
+		}
+	}
+}
+
+// TestHasStakeDifficultyClients ensures the notification manager tracks
+// whether any clients are registered for stake difficulty notifications as
+// clients register, unregister, and disconnect.
+func TestHasStakeDifficultyClients(t *testing.T) {
+	m := newWsNotificationManager(nil)
+	m.Start()
+	defer func() {
+		m.Shutdown()
+		m.WaitForShutdown()
+	}()
+
+	// waitFor waits for the notification manager to process the queued
+	// requests until it reports the provided state.
+	waitFor := func(want bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second * 5)
+		for m.HasStakeDifficultyClients() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout waiting for stake difficulty clients "+
+					"state %v", want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	if m.HasStakeDifficultyClients() {
+		t.Fatal("stake difficulty clients reported without any clients")
+	}
+	wsc := &wsClient{quit: make(chan struct{})}
+	m.RegisterStakeDifficulty(wsc)
+	waitFor(true)
+	m.UnregisterStakeDifficulty(wsc)
+	waitFor(false)
+
+	// Ensure removing a client also removes its registration.
+	m.RegisterStakeDifficulty(wsc)
+	waitFor(true)
+	m.RemoveClient(wsc)
+	waitFor(false)
+}
+
+// TestWSClientFilterTree ensures the loadtxfilter tree parameter is parsed as
+// expected and that filters only match transactions in the requested tree.
+func TestWSClientFilterTree(t *testing.T) {
+	tests := []struct {
+		name        string
+		tree        string
+		wantErr     bool
+		wantRegular bool
+		wantStake   bool
+	}{
+		{"all", "all", false, true, true},
+		{"regular", "regular", false, true, false},
+		{"stake", "stake", false, false, true},
+		{"invalid", "bogus", true, false, false},
+	}
+
+	for _, test := range tests {
+		tree, err := parseFilterTree(test.tree)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+
+		filter := makeWSClientFilter(nil, nil, tree,
+			chaincfg.MainNetParams())
+		gotRegular := filter.matchesTree(wire.TxTreeRegular)
+		if gotRegular != test.wantRegular {
+			t.Fatalf("%q: unexpected regular tree match -- got %v, want %v",
+				test.name, gotRegular, test.wantRegular)
+		}
+		gotStake := filter.matchesTree(wire.TxTreeStake)
+		if gotStake != test.wantStake {
+			t.Fatalf("%q: unexpected stake tree match -- got %v, want %v",
+				test.name, gotStake, test.wantStake)
+		}
+	}
+}
+
+// TestWSClientIdleTimeout ensures websocket clients without any activity
+// within the configured idle timeout are disconnected, clients that are sent
+// notifications or that send pings are not, and clients are never disconnected
+// when no timeout is configured.
+func TestWSClientIdleTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		idleTimeout time.Duration
+		activity    func(c *wsClient, remote *websocket.Conn)
+		wantClosed  bool
+	}{{
+		name:        "idle timeout disconnects",
+		idleTimeout: time.Millisecond * 50,
+		wantClosed:  true,
+	}, {
+		name:        "no idle timeout",
+		idleTimeout: 0,
+		wantClosed:  false,
+	}, {
+		name:        "subscribed client receiving notifications",
+		idleTimeout: time.Millisecond * 50,
+		activity: func(c *wsClient, remote *websocket.Conn) {
+			c.SendMessage([]byte(`{"method":"blockconnected"}`), nil)
+		},
+		wantClosed: false,
+	}, {
+		name:        "client sending pings",
+		idleTimeout: time.Millisecond * 50,
+		activity: func(c *wsClient, remote *websocket.Conn) {
+			remote.WriteControl(websocket.PingMessage, nil,
+				time.Now().Add(time.Second))
+		},
+		wantClosed: false,
+	}}
+
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+
+	for _, test := range tests {
+		cfg = &config{
+			RPCIdleTimeout:       test.idleTimeout,
+			RPCMaxConcurrentReqs: 1,
+		}
+
+		// Accept a single websocket connection and hand it off for use
+		// by the client under test.
+		conns := make(chan *websocket.Conn, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+			r *http.Request) {
+
+			var upgrader websocket.Upgrader
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Errorf("%q: unable to upgrade: %v", test.name, err)
+				return
+			}
+			conns <- conn
+		}))
+		url := "ws" + strings.TrimPrefix(srv.URL, "http")
+		remote, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			srv.Close()
+			t.Fatalf("%q: unable to dial: %v", test.name, err)
+		}
+		conn := <-conns
+
+		c, err := newWebsocketClient(&rpcServer{}, conn, srv.URL, true, true)
+		if err != nil {
+			remote.Close()
+			srv.Close()
+			t.Fatalf("%q: unable to create client: %v", test.name, err)
+		}
+		done := make(chan struct{})
+		c.wg.Add(2)
+		go c.inHandler()
+		go c.outHandler()
+		go func() {
+			c.wg.Wait()
+			close(done)
+		}()
+
+		// Perform the activity, if any, more often than the idle timeout
+		// for several times the duration of the timeout.
+		stopActivity := make(chan struct{})
+		activityDone := make(chan struct{})
+		go func() {
+			defer close(activityDone)
+			if test.activity == nil {
+				return
+			}
+			ticker := time.NewTicker(time.Millisecond * 10)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					test.activity(c, remote)
+				case <-stopActivity:
+					return
+				}
+			}
+		}()
+
+		var closed bool
+		select {
+		case <-done:
+			closed = true
+		case <-time.After(time.Millisecond * 500):
+		}
+		close(stopActivity)
+		<-activityDone
+		if closed != test.wantClosed {
+			t.Errorf("%q: unexpected disconnect -- got %v, want %v",
+				test.name, closed, test.wantClosed)
+		}
+
+		// Ensure the handlers exit once the remote end goes away.
+		remote.Close()
+		c.Disconnect()
+		select {
+		case <-done:
+		case <-time.After(time.Second * 5):
+			t.Errorf("%q: handlers did not exit", test.name)
+		}
+		srv.Close()
+	}
+}
+
+// TestHandleCancelRescan ensures the cancelrescan command stops a rescan that
+// is running for the client and returns an error when there is no rescan to
+// cancel.
+func TestHandleCancelRescan(t *testing.T) {
+	// Fill the rescan semaphore so the rescan below blocks until it is
+	// cancelled.
+	sem := makeSemaphore(1)
+	sem.acquire()
+	wsc := &wsClient{
+		rpcServer: &rpcServer{rescanSem: sem},
+		filterData: makeWSClientFilter(nil, nil, wsFilterTreeAll,
+			chaincfg.MainNetParams()),
+		quit: make(chan struct{}),
+	}
+
+	type rescanReply struct {
+		result interface{}
+		err    error
+	}
+	replies := make(chan rescanReply, 1)
+	go func() {
+		var zeroHash chainhash.Hash
+		cmd := types.NewRescanCmd([]string{zeroHash.String()})
+		result, err := handleRescan(wsc, cmd)
+		replies <- rescanReply{result, err}
+	}()
+
+	// Wait for the rescan to be registered with the client.
+	registered := func() bool {
+		wsc.Lock()
+		defer wsc.Unlock()
+		return wsc.numRescans == 1
+	}
+	for start := time.Now(); !registered(); time.Sleep(time.Millisecond) {
+		if time.Since(start) > time.Second*5 {
+			t.Fatal("rescan was not registered")
+		}
+	}
+
+	if _, err := handleCancelRescan(wsc, types.NewCancelRescanCmd()); err != nil {
+		t.Fatalf("unexpected error cancelling rescan: %v", err)
+	}
+
+	var reply rescanReply
+	select {
+	case reply = <-replies:
+	case <-time.After(time.Second * 5):
+		t.Fatal("rescan was not cancelled")
+	}
+	if reply.err != nil {
+		t.Fatalf("unexpected rescan error: %v", reply.err)
+	}
+	result, ok := reply.result.(*types.RescanResult)
+	if !ok {
+		t.Fatalf("unexpected rescan result type %T", reply.result)
+	}
+	if !result.Cancelled {
+		t.Fatal("rescan result is not marked cancelled")
+	}
+	if len(result.DiscoveredData) != 0 {
+		t.Fatalf("unexpected discovered data -- got %d, want 0",
+			len(result.DiscoveredData))
+	}
+
+	// Ensure cancelling again fails now that no rescans are running.
+	_, err := handleCancelRescan(wsc, types.NewCancelRescanCmd())
+	rpcErr, ok := err.(*dcrjson.RPCError)
+	if !ok || rpcErr.Code != dcrjson.ErrRPCMisc {
+		t.Fatalf("unexpected error with no rescan -- got %v, want %v", err,
+			dcrjson.ErrRPCMisc)
+	}
+}
@@ -7,6 +7,16 @@ package sampleconfig
 // FileContents is a string containing the commented example config for dcrd.
 const FileContents = `[Application Options]
 
+; NOTE: Most options only take effect on startup.  However, the following
+; options may be changed while dcrd is running by editing this file and sending
+; the process a SIGHUP signal on POSIX-style operating systems:
+;   - banthreshold
+;   - maxpeers (the target number of outbound peers is not changed)
+;   - minrelaytxfee (the fee estimator buckets are not changed)
+;   - whitelist
+; Invalid values are rejected and logged while the current values are retained.
+; Options that are removed revert to their defaults.
+
 ; ------------------------------------------------------------------------------
 ; Data settings
 ; ------------------------------------------------------------------------------
@@ -22,6 +32,23 @@ const FileContents = `[Application Options]
 ; datadir=$LOCALAPPDATA/Dcrd/data                 ; Windows
 ; datadir=~/Library/Application Support/Dcrd/data ; macOS
 
+; The maximum size in MiB the database write cache may grow to before it is
+; flushed to disk.  A larger cache trades additional memory for fewer disk
+; accesses, which notably speeds up the initial chain sync on machines with
+; plenty of RAM.  The minimum is 4, the maximum is 1048576 (1 TiB), and the
+; default is 100.
+; dbcache=100
+
+; Skip transaction script validation for the specified block and all of its
+; ancestors in order to speed up the initial chain sync.  The headers leading up
+; to the block are downloaded first to ensure only blocks that lead to it skip
+; validation.  All other validation is still performed and a block at the same
+; height with a different hash is rejected.  WARNING: Invalid scripts in the skipped blocks would go unnoticed,
+; so only specify a block you have independently verified to be valid.  The
+; format is '<height>:<hash>'.  By default, the scripts of all blocks after the
+; most recent checkpoint are validated.
+; assumevalid=
+
 
 ; ------------------------------------------------------------------------------
 ; Network settings
@@ -53,6 +80,13 @@ const FileContents = `[Application Options]
 ; onionuser=
 ; onionpass=
 
+; Only connect to .onion addresses and refuse to resolve or connect to any other
+; addresses so no clearnet DNS queries or connections are ever made for
+; outbound peers.  DNS seeding and feeler connections are disabled in this mode
+; and any added peers must be .onion addresses.  Requires either onion or proxy
+; to be set.
+; oniononly=1
+
 ; Enable Tor stream isolation by randomizing proxy user credentials resulting in
 ; Tor creating a new circuit for each connection.  This makes it more difficult
 ; to correlate connections.
@@ -112,13 +146,89 @@ const FileContents = `[Application Options]
 ; connect=fe80::1
 ; connect=[fe80::2]:9108
 
-; Maximum number of inbound and outbound peers.
+; Maximum number of inbound and outbound peers.  This option may be reloaded
+; via SIGHUP.
 ; maxpeers=8
 
+; Maximum number of inbound connections with the same IP.  This is enforced in
+; addition to the limit on all connections with the same IP (maxsameip) and
+; allows a stricter limit on inbound connections to resist inbound flooding
+; while still permitting multiple outbound connections to the same IP, such as
+; a shared gateway.  Localhost connections are exempt.  The default of 0
+; disables the limit.
+; maxsameipinbound=1
+
+; Minimum number of distinct network groups, such as IPv4 /16 subnets, among
+; outbound peers.  Automatic outbound connections are normally made to peers in
+; network groups the node is not already connected to, however whitelisted
+; addresses are exempt and persistent peers may share network groups.  Once the
+; number of remaining automatic outbound connection slots is no more than the
+; number of additional network groups needed to reach the minimum, those slots
+; are reserved for peers in new network groups except for whitelisted peers.
+; This makes it harder for an attacker that controls many addresses in a few
+; network groups to take over all of the outbound connections.  The maximum is
+; 8.
+; minoutboundgroups=4
+
+; Maximum number of outbound connection attempts that may be dialing at once.
+; Higher values speed up acquiring peers on startup while lower values reduce
+; the load on constrained links.  The default of 0 is unlimited.
+; maxconcurrentdials=0
+
+; Abandon outbound connection attempts that remain pending for longer than the
+; specified duration and try another address instead.  This speeds up recovering
+; outbound connection slots when dials hang, such as behind flaky proxies.
+; Persistent peers are retried instead.  Valid time units are {s, m, h}.  The
+; default of 0 disables the timeout.
+; pendingconntimeout=1m
+
+; Interval between short-lived feeler connections made to random addresses that
+; have not been successfully connected to yet once all outbound peer slots are
+; full.  Addresses that complete the version handshake are promoted in the
+; address manager, which keeps it populated with good candidates for future
+; outbound connections.  Valid time units are {s, m, h}.  A value of 0 disables
+; feeler connections.
+; feelerinterval=2m
+
+; Number of consecutive failed connections, including connections that fail to
+; complete the version handshake, after which an address that has never been
+; successfully connected to is evicted from the address manager.  This limits
+; the effectiveness of flooding the address manager with unreachable addresses.
+; A value of 0 disables eviction.
+; addrfailurethreshold=10
+
+; Interval between pings sent to each connected peer.  Pings are used to measure
+; peer latency and to detect connections that are no longer responsive.  Valid
+; time units are {s, m, h}.  Minimum 1 second.
+; pinginterval=2m
+
+; Number of consecutive pings a peer may fail to answer with a pong before it is
+; disconnected.  A ping is considered unanswered when the next ping is due
+; without a matching pong having been received.  A value of 0 disables
+; disconnecting peers for unanswered pings.
+; maxmissedpongs=0
+
+; Maximum amount of time the local clock is adjusted by in either direction
+; based on the median of the times reported by peers.  When the median differs
+; from the local clock by more than this amount, no adjustment is made.  Nodes
+; with a reliable local clock may reduce it, or set it to 0 to disable the
+; adjustment entirely, in order to prevent malicious peers from skewing the
+; time used to validate block timestamps.  Valid time units are {s, m, h}.  The
+; maximum and default is 1h10m.
+; maxtimeadjustment=1h10m
+
+; Minimum protocol version to accept from peers.  Both inbound and outbound
+; peers that advertise an older protocol version are sent a reject message that
+; states the required minimum and are then disconnected.  This is useful to stop
+; relying on peers that lack newer protocol features.  The default of 0 accepts
+; all versions supported by the wire protocol.
+; minprotocolversion=6
+
 ; Disable banning of misbehaving peers.
 ; nobanning=1
 
 ; Maximum allowed ban score before disconnecting and banning misbehaving peers.
+; This option may be reloaded via SIGHUP.
 ; banthreshold=100
 
 ; How long to ban misbehaving peers. Valid time units are {s, m, h}.
@@ -126,17 +236,80 @@ const FileContents = `[Application Options]
 ; banduration=24h
 ; banduration=11h30m15s
 
+; Automatically ban the entire /24 (IPv4) or /64 (IPv6) subnet of misbehaving
+; peers once the specified number of distinct IPs within it are banned within
+; the ban escalation window.  The subnet is banned for the ban duration.  This
+; helps defend against distributed abuse from a single network, however, it
+; may also ban innocent peers that share a network with the offenders, so use
+; a conservative value.  Whitelisted and persistent peers are not affected by
+; subnet bans.  The default of 0 disables escalation.
+; banescalation=5
+
+; The window of time in which bans of distinct IPs within the same subnet count
+; toward escalating to a subnet ban.  Valid time units are {s, m, h}.  Minimum
+; 1s.
+; banescalationwindow=1h
+
 ; Add whitelisted IP networks and IPs. Connected peers whose IP matches a
-; whitelist will not have their ban score increased.
+; whitelist will not have their ban score increased.  Whitelisted peers are also
+; exempt from the following connection limits regardless of whether they are
+; inbound, outbound, or persistent:
+;   - The maximum number of connections with the same IP (maxsameip)
+;   - The maximum number of inbound connections with the same IP
+;     (maxsameipinbound)
+;   - The maximum number of peers (maxpeers)
+;   - Only making one automatic outbound connection per network group
+;   - Reserving outbound slots for new network groups (minoutboundgroups)
+; The whitelists may be reloaded without restarting via the reloadwhitelists RPC
+; or SIGHUP, which applies to newly connecting peers.
 ; whitelist=127.0.0.1
 ; whitelist=::1
 ; whitelist=192.168.0.0/24
 ; whitelist=fd00::/16
 
+; Whitelist peers whose user agent contains the specified substring.  They will
+; not have their ban score increased once their version message is received.
+; This is useful for companion software that connects from varying IPs.  Since
+; user agents are reported by the peers themselves and can be trivially
+; spoofed, these peers are not exempt from the connection limits or bans like
+; peers whose IP matches a whitelist are.  Only use this when the node is not
+; reachable by untrusted peers or when the exemption is acceptable for any
+; peer.
+; whitelistuseragent=/dcrwallet:
+
 ; Disable DNS seeding for peers.  By default, when dcrd starts, it will use
 ; DNS to query for available peers to connect with.
 ; nodnsseed=1
 
+; Add DNS seed hosts to query for peers in addition to the built-in seeds for
+; the active network.  This is useful for bootstrapping private networks or
+; adding redundant seeders.  The seeds are queried on the default port of the
+; active network, so they must be hostnames without a port.  Malformed seeds
+; are skipped with a warning.  One seed per line.
+; dnsseed=seed.example.com
+
+; Only query the DNS seeds specified with dnsseed instead of also querying the
+; built-in seeds.
+; replacednsseeds=1
+
+; Only add peers from DNS seeds that are reported to provide the specified
+; services in addition to being full nodes.  Built-in seeds that do not support
+; filtering by services are skipped when any services are specified, while
+; custom seeds specified with dnsseed are assumed to support filtering.  One
+; service per line.  Valid services are {bloom, cf, cmpctblock}.
+; dnsseedservice=cf
+
+; Only advertise the specified services to peers instead of all of the services
+; the node supports.  This allows the node to present itself to the network as,
+; for example, a limited-service node that only serves recent blocks.  Only
+; services the node is able to provide may be advertised, so cf may not be
+; specified along with nocfilters and network may not be specified along with
+; retainblocks.  The network and networklimited services are mutually exclusive.
+; One service per line.  Valid services are {network, networklimited, cf,
+; cmpctblock}.
+; advertiseservice=networklimited
+; advertiseservice=cf
+
 ; Specify the interfaces to listen on.  One listen address per line.
 ; NOTE: The default port is modified by some options such as 'testnet', so it is
 ; recommended to not specify a port and allow a proper default to be chosen
@@ -165,6 +338,8 @@ const FileContents = `[Application Options]
 ;   listen=0.0.0.0:8336
 ; All ipv6 interfaces on non-standard port 8336:
 ;   listen=[::]:8336
+; Unix domain socket for peers on the same host:
+;   listen=unix:~/.dcrd/dcrd.sock
 
 ; Disable listening for incoming connections.  This will override all listeners.
 ; nolisten=1
@@ -212,6 +387,31 @@ const FileContents = `[Application Options]
 ;   rpclisten=0.0.0.0:8337
 ; All ipv6 interfaces on non-standard port 8337:
 ;   rpclisten=[::]:8337
+; Unix domain socket for RPC clients on the same host:
+;   rpclisten=unix:~/.dcrd/rpc.sock
+
+; Specify the minimum TLS version for RPC connections.  Valid options are 1.2
+; and 1.3.
+; rpctlsminversion=1.2
+
+; Restrict the cipher suites allowed for RPC connections using TLS 1.2.  One
+; cipher suite per line.  All secure cipher suites are allowed when none are
+; specified.  The cipher suites for TLS 1.3 are not configurable.
+; rpctlsciphersuite=TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384
+; rpctlsciphersuite=TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256
+
+; Authenticate RPC clients by TLS client certificates that are signed by one of
+; the certificate authorities in the specified file.  Clients that present a
+; valid certificate without a username and password are granted limited access,
+; unless the common name of the certificate is one of the admin common names
+; below.  Clients that provide a username and password are authenticated by
+; them instead, so invalid credentials are rejected even with a valid
+; certificate.  This may not be used with notls.
+; rpcclientca=~/.dcrd/clients.pem
+
+; Client certificate common names that grant admin RPC access.  One common name
+; per line.
+; rpcadminclientcn=automation
 
 ; Specify the maximum number of concurrent RPC clients for standard connections.
 ; rpcmaxclients=10
@@ -219,6 +419,66 @@ const FileContents = `[Application Options]
 ; Specify the maximum number of concurrent RPC websocket clients.
 ; rpcmaxwebsockets=25
 
+; Specify the maximum number of rescans requested by websocket clients that may
+; run concurrently.  Rescans are expensive in terms of disk IO and CPU, so
+; limiting them prevents several wallets rescanning at once from exhausting the
+; resources of the node.  Additional rescans wait for a running one to finish.
+; Set to 0 for unlimited.
+; rpcmaxrescans=2
+
+; Specify the maximum size in bytes of a single marshalled RPC response.  Requests
+; which would produce a larger response, such as verbose block or address
+; searches, receive an error instead.  Large array results are only streamed to
+; HTTP/1.1 clients with chunked transfer encoding when the size is unlimited.
+; The default of 0 means unlimited.
+; rpcmaxresponsesize=0
+
+; Specify the maximum number of notifications that may be queued for a single
+; websocket client that is not reading them quickly enough.  Once the limit is
+; reached, the action specified by rpcntfnoverflow is taken to prevent a stuck
+; client from consuming an unbounded amount of memory.  Set to 0 for unlimited.
+; rpcmaxntfnqueue=10000
+
+; Specify the action to take when the notification queue of a websocket client
+; is full.  The dropoldest policy discards the oldest queued notification to
+; make room for the new one, which means the client silently misses
+; notifications.  The disconnect policy disconnects the client instead so it
+; can reconnect and resynchronize its state.
+; rpcntfnoverflow=disconnect
+
+; Specify the number of most recent blocks used to calculate the median time
+; between blocks reported by getblockchaininfo.  A smaller window reacts more
+; quickly to changes in block production, such as a drop in hashrate, while a
+; larger one is less affected by the natural variance of block times.
+; blockintervalwindow=144
+
+; Specify the default number of confirmations a block requires in order for the
+; isblockfinal RPC to report it as final, meaning a chain reorganization that
+; removes it is considered practically impossible.  Clients may override the
+; depth per request.
+; finalitydepth=6
+
+; Disconnect websocket RPC clients without any activity for the specified
+; duration.  Requests, responses, notifications sent to the client, and
+; websocket pings and pongs all count as activity, so clients that only wait
+; for notifications remain connected as long as notifications are sent.  HTTP
+; POST clients are already disconnected after every request.  Valid time units are {s, m, h}.  The default of 0
+; disables the timeout.
+; rpcidletimeout=30m
+
+; Log every RPC command to rpcaudit.log in the log directory.  Each entry
+; records whether the client is an admin or limited user, its address, the
+; method, a hash of the parameters, and whether the command succeeded.  The
+; parameters themselves are not logged.  This may not be used with
+; nofilelogging.
+; rpcauditlog=1
+
+; Enable the admin-only debugdump RPC.  It returns a stack dump of all running
+; goroutines and can optionally write a CPU or heap profile to the data
+; directory, which is useful for diagnosing a node that appears to be stuck.
+; It is disabled by default.
+; rpcdebugdump=1
+
 ; Use the following setting to disable the RPC server even if the rpcuser and
 ; rpcpass are specified above.  This allows one to quickly disable the RPC
 ; server without having to remove credentials from the config file.
@@ -230,7 +490,8 @@ const FileContents = `[Application Options]
 ; Mempool Settings - The following options
 ; ------------------------------------------------------------------------------
 
-; Set the minimum transaction fee to be considered a non-zero fee,
+; Set the minimum transaction fee to be considered a non-zero fee.  This option
+; may be reloaded via SIGHUP.
 ; minrelaytxfee=0.0001
 
 ; Rate-limit free transactions to the value 15 * 1000 bytes per
@@ -246,6 +507,63 @@ const FileContents = `[Application Options]
 ; Do not accept transactions from remote peers.
 ; blocksonly=1
 
+; Only serve the specified number of most recent blocks to peers.  The node
+; advertises the limited network service instead of the full network service so
+; that peers which need older blocks, such as those performing their initial
+; sync, get them from other nodes.  Requests for older blocks are answered with
+; notfound.  Note that blocks are not pruned from the database.  The minimum is
+; 288 blocks, which is roughly one day.  The default of 0 serves all blocks.
+; retainblocks=288
+
+; Limit the number of inventory items a peer may request in a single getdata
+; message.  Requests for more items are rejected without serving any of them
+; and the peer is banned.  The default is the maximum number of items a message
+; is able to hold.
+; maxgetdatainv=50000
+
+; Limit the number of inventory items requested via getdata that may be queued
+; to a peer without having been sent yet.  Once the limit is reached, serving
+; the rest of the request is deferred until the outstanding items have been
+; sent, which bounds the memory a single peer is able to tie up.  Whitelisted
+; peers are exempt.  The default of 0 does not limit outstanding items.
+; maxgetdatapending=100
+
+; Limit the number of inventory items requested via getdata that are served to
+; each peer per minute.  Peers may burst up to the full budget at once, after
+; which it refills steadily over the course of a minute.  Items requested beyond
+; the budget are answered with notfound and increase the peer's ban score in
+; proportion to the excess.  The default of 0 serves all requested items.
+; maxgetdataperminute=6000
+
+; Do not relay transactions of the specified types to peers.  The transactions
+; are still accepted into the mempool and mined, they are just not announced to
+; other peers, included in responses to mempool requests, or served when
+; requested.  Valid types are regular, ticket, vote, and revocation.  One type
+; per line.  All transaction types are relayed by default.
+; norelaytxtype=revocation
+
+; Preferred method for peers to announce new blocks to this node.  Valid
+; options are inv, headers, and compact.  Peers that do not support the method
+; fall back to the best one they do.  Compact blocks are not requested when
+; blocksonly is set since they can't be reconstructed without transactions.
+; Announcements via headers are not requested until the chain is synced since
+; the block manager only handles them as new block announcements.
+; blockannounce=compact
+
+; How long to avoid relaying inventory such as transactions and blocks back to
+; the peer it was received from.  This reduces redundant announcements in the
+; window before the peer is known to have the inventory.  Valid time units are
+; {ms, s, m}.  Set to 0 to disable.
+; invsuppresswindow=5s
+
+; How often to rebroadcast transactions submitted via RPC, such as ticket
+; purchases, that have not yet made it into a block.  A random jitter of up to
+; a tenth of the interval, capped at one minute, is added so the rebroadcasts
+; are not perfectly predictable to observers.  Valid time units are {s, m, h}.
+; The minimum is 1m.  The default of 0 rebroadcasts at a random time up to 30
+; minutes in the future instead.
+; rebroadcastinterval=0
+
 ; Accept and relay non-standard transactions to the network regardless of the
 ; default network settings.
 ; acceptnonstd=1
@@ -283,6 +601,19 @@ const FileContents = `[Application Options]
 ; sigcachemaxsize=50000
 
 
+; ------------------------------------------------------------------------------
+; UTXO Cache
+; ------------------------------------------------------------------------------
+
+; The maximum size in MiB of the in-memory cache of unspent transaction outputs
+; that is consulted before the database when validating blocks and
+; transactions.  The cache is kept in sync with the database as blocks are
+; connected and disconnected, so it never needs to be flushed.  Larger values
+; trade memory for fewer database accesses.  The default is 150 and 0 disables
+; the cache.
+; utxocachemaxsize=150
+
+
 ; ------------------------------------------------------------------------------
 ; Coin Generation (Mining) Settings - The following options control the
 ; generation of block templates used by external mining applications through RPC
@@ -321,6 +652,13 @@ const FileContents = `[Application Options]
 ; by the blockmaxsize option and will be limited as needed.
 ; blockprioritysize=20000
 
+; Order the transactions in generated block templates strictly by fee per
+; kilobyte and then transaction hash instead of making use of the high-priority
+; area.  This ensures nodes with identical mempools generate identical block
+; templates which is useful for testing and comparing templates across nodes.
+; The blockprioritysize option is ignored when this is enabled.
+; deterministictxorder=1
+
 
 ; ------------------------------------------------------------------------------
 ; Debug
@@ -349,4 +687,15 @@ const FileContents = `[Application Options]
 ;   profile=192.168.1.123:6061
 ; Listen on ipv6 loopback interface:
 ;   profile=[::1]:6061
+
+; Automatically write a heap or goroutine profile when the allocated heap (in
+; MiB) or the number of goroutines crosses the given threshold.  This helps
+; capture transient resource spikes that are gone by the time an operator is
+; able to investigate.  The runtime stats are sampled every 10 seconds and a
+; profile of each kind is written at most once every 10 minutes.  Profiles are
+; timestamped and written to the data directory unless autoprofiledir is set.
+; Both thresholds default to 0 which disables automatic profiling.
+; autoprofileheap=4096
+; autoprofilegoroutines=10000
+; autoprofiledir=~/.dcrd/profiles
 `
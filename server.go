@@ -13,8 +13,10 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"os"
 	"path"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -46,7 +48,7 @@ import (
 const (
 	// defaultServices describes the default services that are supported by
 	// the server.
-	defaultServices = wire.SFNodeNetwork | wire.SFNodeCF
+	defaultServices = wire.SFNodeNetwork | wire.SFNodeCF | wire.SFNodeCmpctBlock
 
 	// defaultRequiredServices describes the default services that are
 	// required to be supported by outbound peers.
@@ -62,11 +64,69 @@ const (
 	connectionRetryInterval = time.Second * 5
 
 	// maxProtocolVersion is the max protocol version the server supports.
-	maxProtocolVersion = wire.NodeCFVersion
+	maxProtocolVersion = wire.CmpctBlockVersion
 
 	// maxKnownAddrsPerPeer is the maximum number of items to keep in the
 	// per-peer known address cache.
 	maxKnownAddrsPerPeer = 10000
+
+	// maxRecentInvPerPeer is the maximum number of items to keep in the
+	// per-peer recently received inventory tracker.
+	maxRecentInvPerPeer = 5000
+
+	// bandwidthSampleInterval is the duration covered by each of the samples
+	// used to calculate the per-peer bandwidth rates.
+	bandwidthSampleInterval = time.Second
+
+	// bandwidthNumSamples is the number of samples the per-peer bandwidth
+	// rates are averaged over.
+	bandwidthNumSamples = 10
+
+	// feelerDialTimeout is the maximum amount of time to wait for the
+	// connection of a feeler to be established before abandoning it.
+	feelerDialTimeout = time.Second * 10
+
+	// maxRebroadcastJitter is the maximum random jitter added to a
+	// configured rebroadcast interval.
+	maxRebroadcastJitter = time.Minute
+
+	// minPeerEvictionAge is the minimum amount of time a peer must be
+	// connected before it may be evicted to make room for a new peer.  This
+	// gives new peers a chance to prove their usefulness.
+	minPeerEvictionAge = time.Minute * 5
+
+	// evictionProtectedBlockPeers is the number of inbound peers eligible
+	// for eviction that most recently delivered a block which are protected
+	// from eviction.  This ensures peers that are currently relaying blocks
+	// are kept regardless of their other characteristics.
+	evictionProtectedBlockPeers = 4
+
+	// evictionProtectedGroupPeers is the number of inbound peers eligible
+	// for eviction from distinct network groups which are protected from
+	// eviction.  The network groups are selected using a keyed hash that is
+	// unique to each node, so an attacker is unable to predict which groups
+	// are protected in order to cover them.
+	evictionProtectedGroupPeers = 4
+
+	// evictionProtectedPingPeers is the number of inbound peers eligible for
+	// eviction with the lowest ping times which are protected from eviction.
+	// An attacker would need to be closer to the node than its honest peers
+	// in order to cover them.
+	evictionProtectedPingPeers = 8
+
+	// maxScoredBlocks is the maximum number of blocks received from a peer
+	// that count toward its usefulness score.  It prevents long-lived peers
+	// from accumulating a score that newer useful peers are unable to match.
+	maxScoredBlocks = 100
+
+	// peerPingPenaltyMicros is the round trip ping time in microseconds that
+	// reduces the usefulness score of a peer by one point.
+	peerPingPenaltyMicros = 10000
+
+	// maxPeerPingPenalty is the maximum number of points the usefulness score
+	// of a peer is reduced by due to its ping time.  It is also applied to
+	// peers without a ping time.
+	maxPeerPingPenalty = 100
 )
 
 var (
@@ -128,14 +188,284 @@ type peerState struct {
 	outboundPeers   map[int32]*serverPeer
 	persistentPeers map[int32]*serverPeer
 	banned          map[string]time.Time
+	bannedSubnets   map[string]bannedSubnet
+	recentBans      map[string]map[string]time.Time
 	outboundGroups  map[string]int
 
+	// netGroupSalt is the random salt used when hashing network groups to
+	// select which ones are protected from eviction.
+	netGroupSalt [8]byte
+
 	// suggestions represents public network address suggestions from outbound
 	// peers.
 	suggestions    map[addrmgr.NetworkAddress]map[string]int32
 	suggestionsMtx sync.Mutex
 }
 
+// evictionCandidate describes an inbound peer that is eligible for eviction
+// along with the details used to select which peer to evict.
+type evictionCandidate struct {
+	sp            *serverPeer
+	netGroupKey   uint64
+	pingMicros    int64
+	score         int64
+	lastBlockTime time.Time
+	connected     time.Time
+}
+
+// numProtectedCandidates returns the number of candidates at the front of the
+// provided candidates, up to the given maximum, that satisfy the provided
+// eligibility function.
+func numProtectedCandidates(candidates []evictionCandidate, max int, eligible func(c *evictionCandidate) bool) int {
+	n := 0
+	for n < len(candidates) && n < max && eligible(&candidates[n]) {
+		n++
+	}
+	return n
+}
+
+// selectEvictionCandidate returns the candidate to evict from the provided
+// candidates or nil when there are none that may be evicted.
+//
+// Several sets of candidates are protected from eviction in turn so that an
+// attacker has to beat honest peers on multiple independent characteristics in
+// order to take over all of the inbound slots:
+//   - A candidate from each of a maximum number of distinct network groups
+//     selected via their keyed network group hash
+//   - A maximum number of the candidates with the lowest known ping times
+//   - A maximum number of the candidates that most recently delivered a block
+//   - Half of the remaining candidates that have been connected the longest
+//
+// The remaining candidate with the lowest score is selected with ties broken
+// in favor of evicting the most recently connected candidate.
+//
+// NOTE: The provided slice is reordered.
+func selectEvictionCandidate(candidates []evictionCandidate) *evictionCandidate {
+	// Protect a candidate from each of the distinct network groups with the
+	// lowest keyed hashes.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].netGroupKey < candidates[j].netGroupKey
+	})
+	remaining := make([]evictionCandidate, 0, len(candidates))
+	protectedGroups := make(map[uint64]struct{})
+	for _, c := range candidates {
+		if _, ok := protectedGroups[c.netGroupKey]; !ok &&
+			len(protectedGroups) < evictionProtectedGroupPeers {
+
+			protectedGroups[c.netGroupKey] = struct{}{}
+			continue
+		}
+		remaining = append(remaining, c)
+	}
+	candidates = remaining
+
+	// Protect the candidates with the lowest known ping times.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		pi, pj := candidates[i].pingMicros, candidates[j].pingMicros
+		if pi == 0 || pj == 0 {
+			return pj == 0 && pi != 0
+		}
+		return pi < pj
+	})
+	candidates = candidates[numProtectedCandidates(candidates,
+		evictionProtectedPingPeers, func(c *evictionCandidate) bool {
+			return c.pingMicros > 0
+		}):]
+
+	// Protect the candidates that most recently delivered a block.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].lastBlockTime.After(candidates[j].lastBlockTime)
+	})
+	candidates = candidates[numProtectedCandidates(candidates,
+		evictionProtectedBlockPeers, func(c *evictionCandidate) bool {
+			return !c.lastBlockTime.IsZero()
+		}):]
+
+	// Protect half of the remaining candidates that have been connected the
+	// longest.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].connected.Before(candidates[j].connected)
+	})
+	candidates = candidates[len(candidates)/2:]
+
+	var lowest *evictionCandidate
+	for i := range candidates {
+		c := &candidates[i]
+		if lowest == nil || c.score < lowest.score ||
+			(c.score == lowest.score && c.connected.After(lowest.connected)) {
+
+			lowest = c
+		}
+	}
+	return lowest
+}
+
+// netGroupKey returns the keyed hash of the network group of the provided
+// address that is used to select the network groups which are protected from
+// eviction.
+func (ps *peerState) netGroupKey(na *wire.NetAddress) uint64 {
+	group := addrmgr.GroupKey(na)
+	buf := make([]byte, 0, len(ps.netGroupSalt)+len(group))
+	buf = append(buf, ps.netGroupSalt[:]...)
+	buf = append(buf, group...)
+	return binary.LittleEndian.Uint64(chainhash.HashB(buf))
+}
+
+// lowestScoringPeer returns the connected inbound peer that is eligible for
+// eviction with the lowest score as of the provided time or nil when there are
+// none.  The score of a peer is its usefulness score reduced by its ban score.
+// Peers that are persistent, exempt from connection limits, or have not been
+// connected for the minimum eviction age are not eligible.  See
+// selectEvictionCandidate for details regarding peers that are protected from
+// eviction and how ties are broken.
+func (ps *peerState) lowestScoringPeer(now time.Time) *serverPeer {
+	candidates := make([]evictionCandidate, 0, len(ps.inboundPeers))
+	for _, sp := range ps.inboundPeers {
+		if !sp.Connected() || sp.persistent || sp.connLimitsExempt() {
+			continue
+		}
+		connected := sp.TimeConnected()
+		if now.Sub(connected) < minPeerEvictionAge {
+			continue
+		}
+
+		pingMicros := sp.LastPingMicros()
+		score := sp.usefulness.score(pingMicros, now)
+		score -= int64(sp.banScore.Int())
+		candidates = append(candidates, evictionCandidate{
+			sp:            sp,
+			netGroupKey:   ps.netGroupKey(sp.NA()),
+			pingMicros:    pingMicros,
+			score:         score,
+			lastBlockTime: sp.usefulness.lastBlock(),
+			connected:     connected,
+		})
+	}
+
+	c := selectEvictionCandidate(candidates)
+	if c == nil {
+		return nil
+	}
+	return c.sp
+}
+
+// outboundGroupsReserved returns whether the remaining outbound connection
+// slots are reserved for peers in new network groups given the number of
+// distinct network groups among the current outbound peers, the number of
+// current outbound peers, the target number of outbound peers, and the minimum
+// number of distinct network groups.  The slots are reserved once there are no
+// more of them than the number of additional network groups needed to reach
+// the minimum.
+func outboundGroupsReserved(numGroups, numOutbound, targetOutbound, minGroups int) bool {
+	needed := minGroups - numGroups
+	return needed > 0 && targetOutbound-numOutbound <= needed
+}
+
+// newOutboundGroupRequired returns whether the next automatic outbound peer
+// must be in a network group that none of the current outbound peers are in so
+// that the provided minimum number of distinct network groups among the
+// provided target number of outbound peers is guaranteed.
+func (ps *peerState) newOutboundGroupRequired(targetOutbound, minGroups int) bool {
+	var numGroups int
+	for _, count := range ps.outboundGroups {
+		if count > 0 {
+			numGroups++
+		}
+	}
+	numOutbound := len(ps.outboundPeers) + len(ps.persistentPeers)
+	return outboundGroupsReserved(numGroups, numOutbound, targetOutbound,
+		minGroups)
+}
+
+// rejectOutboundGroup returns whether the provided new peer must be rejected
+// because it is an automatic outbound peer in a network group that other
+// outbound peers are already in while the remaining outbound slots are reserved
+// for new network groups.  Inbound and persistent peers along with peers that
+// are exempt from connection limits are never rejected.
+func (ps *peerState) rejectOutboundGroup(sp *serverPeer, targetOutbound, minGroups int) bool {
+	if sp.Inbound() || sp.persistent || sp.connLimitsExempt() {
+		return false
+	}
+	key := addrmgr.GroupKey(sp.NA())
+	return ps.outboundGroups[key] != 0 &&
+		ps.newOutboundGroupRequired(targetOutbound, minGroups)
+}
+
+// bannedSubnet describes a range of IP addresses that are banned along with
+// when the ban ends.
+type bannedSubnet struct {
+	ipNet  *net.IPNet
+	banEnd time.Time
+}
+
+// subnetBanEnd returns when the ban of the subnet that contains the provided IP
+// ends.  Expired subnet bans that contain the IP are removed.  False is
+// returned when the IP is not in any banned subnet.
+func (ps *peerState) subnetBanEnd(ip net.IP, now time.Time) (time.Time, bool) {
+	var banEnd time.Time
+	var banned bool
+	for key, ban := range ps.bannedSubnets {
+		if !ban.ipNet.Contains(ip) {
+			continue
+		}
+		if !now.Before(ban.banEnd) {
+			srvrLog.Infof("Subnet %s is no longer banned", key)
+			delete(ps.bannedSubnets, key)
+			continue
+		}
+		if ban.banEnd.After(banEnd) {
+			banEnd = ban.banEnd
+			banned = true
+		}
+	}
+	return banEnd, banned
+}
+
+// banEscalationSubnet returns the subnet that bans of the provided IP count
+// toward when determining whether to escalate to a subnet ban.  This is the
+// /24 for IPv4 addresses and the /64 for IPv6 addresses.
+func banEscalationSubnet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		mask := net.CIDRMask(24, net.IPv4len*8)
+		return &net.IPNet{IP: ip4.Mask(mask), Mask: mask}
+	}
+	mask := net.CIDRMask(64, net.IPv6len*8)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// recordBan records a ban of the provided IP and returns the subnet that
+// should be banned as a result when at least the given threshold of distinct
+// IPs within the same subnet have been banned within the provided window.
+// Nil is returned when the ban does not result in escalation.  Records of bans
+// that are older than the window are pruned.
+func (ps *peerState) recordBan(ip net.IP, now time.Time, threshold uint32, window time.Duration) *net.IPNet {
+	// Prune bans that no longer fall within the window.
+	for key, bans := range ps.recentBans {
+		for banIP, banTime := range bans {
+			if now.Sub(banTime) >= window {
+				delete(bans, banIP)
+			}
+		}
+		if len(bans) == 0 {
+			delete(ps.recentBans, key)
+		}
+	}
+
+	subnet := banEscalationSubnet(ip)
+	key := subnet.String()
+	bans, ok := ps.recentBans[key]
+	if !ok {
+		bans = make(map[string]time.Time)
+		ps.recentBans[key] = bans
+	}
+	bans[ip.String()] = now
+	if uint32(len(bans)) < threshold {
+		return nil
+	}
+	delete(ps.recentBans, key)
+	return subnet
+}
+
 // ConnectionsWithIP returns the number of connections with the given IP.
 func (ps *peerState) ConnectionsWithIP(ip net.IP) int {
 	var total int
@@ -157,6 +487,30 @@ func (ps *peerState) ConnectionsWithIP(ip net.IP) int {
 	return total
 }
 
+// InboundConnectionsWithIP returns the number of inbound connections with the
+// given IP.
+func (ps *peerState) InboundConnectionsWithIP(ip net.IP) int {
+	var total int
+	for _, p := range ps.inboundPeers {
+		if ip.Equal(p.NA().IP) {
+			total++
+		}
+	}
+	return total
+}
+
+// exceedsSameIPLimits returns whether adding a peer would exceed the limits on
+// connections with the same IP given whether the peer is inbound and the
+// number of existing connections and existing inbound connections with its IP.
+// The inbound limit only applies to inbound peers and is enforced in addition
+// to the overall limit.  A limit of 0 disables it.
+func exceedsSameIPLimits(inbound bool, conns, inboundConns, maxSameIP, maxSameIPInbound int) bool {
+	if maxSameIP > 0 && conns+1 > maxSameIP {
+		return true
+	}
+	return inbound && maxSameIPInbound > 0 && inboundConns+1 > maxSameIPInbound
+}
+
 // Count returns the count of all known peers.
 func (ps *peerState) Count() int {
 	return len(ps.inboundPeers) + len(ps.outboundPeers) +
@@ -240,6 +594,7 @@ type server struct {
 	subsidyCache         *standalone.SubsidyCache
 	rpcServer            *rpcServer
 	blockManager         *blockManager
+	tmplGenerator        *BlkTmplGenerator
 	bg                   *BgBlkTmplGenerator
 	chain                *blockchain.BlockChain
 	txMemPool            *mempool.TxPool
@@ -262,6 +617,11 @@ type server struct {
 	context              context.Context
 	cancel               context.CancelFunc
 
+	// whitelist houses the whitelisted IP networks and addresses.  It is
+	// populated from the configuration when the server is created and may be
+	// reloaded via the reloadwhitelists RPC or SIGHUP.
+	whitelist ipWhitelist
+
 	// The following fields are used for optional indexes.  They will be nil
 	// if the associated index is not enabled.  These fields are set during
 	// initial creation of the server and never changed afterwards, so they
@@ -275,6 +635,9 @@ type server struct {
 // serverPeer extends the peer to maintain state shared by the server and
 // the blockmanager.
 type serverPeer struct {
+	// The following variables must only be used atomically.
+	txsReceived uint64
+
 	*peer.Peer
 
 	connReq         *connmgr.ConnReq
@@ -284,6 +647,10 @@ type serverPeer struct {
 	relayMtx        sync.Mutex
 	disableRelayTx  bool
 	isWhitelisted   bool
+	isUAWhitelisted bool
+	isUnixSocket    bool
+	isFeeler        bool
+	feelerGood      bool
 	requestedTxns   map[chainhash.Hash]struct{}
 	requestedBlocks map[chainhash.Hash]struct{}
 	knownAddresses  lru.Cache
@@ -303,6 +670,291 @@ type serverPeer struct {
 	// peerNa is network address of the peer connected to.
 	peerNa    *wire.NetAddress
 	peerNaMtx sync.Mutex
+
+	// pendingCmpctBlock houses the compact block from the peer that is
+	// waiting on the missing transactions requested via getblocktxns.  It is
+	// only accessed from the message listeners, which are invoked serially
+	// by the peer's input handler, so it does not require a mutex.
+	pendingCmpctBlock *partialCmpctBlock
+
+	// blockAnnounceRequested is the method the peer was asked to use to
+	// announce new blocks and sendHeadersDeferred indicates the peer is yet
+	// to be asked to announce new blocks via headers because the chain was
+	// not current during version negotiation.  They are set during version
+	// negotiation and are only modified afterwards when the deferred request
+	// is sent.
+	blockAnnounceMtx       sync.Mutex
+	blockAnnounceRequested peer.BlockAnnounceMode
+	sendHeadersDeferred    bool
+
+	// recentInv tracks the inventory recently received from the peer so it
+	// is not immediately relayed back to it.
+	recentInv *recentInventory
+
+	// sendRate and recvRate track the bytes recently sent to and received
+	// from the peer in order to provide rolling bandwidth rates.
+	sendRate bandwidthMeter
+	recvRate bandwidthMeter
+
+	// usefulness tracks the blocks received from the peer in order to rank it
+	// against other peers when choosing one to evict.
+	usefulness peerUsefulness
+
+	// getDataBudget limits the rate at which inventory requested by the peer
+	// via getdata is served.  It is only accessed from the message
+	// listeners, which are invoked serially by the peer's input handler, so
+	// it does not require a mutex.
+	getDataBudget getDataBudget
+}
+
+// getDataBudget is a token bucket that limits the number of inventory items
+// requested via getdata that are served to a peer.  It holds up to a full
+// minute worth of tokens so honest peers are able to burst, and it refills
+// continuously at the configured per-minute rate.
+type getDataBudget struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// limit refills the budget for the time elapsed since it was last refilled
+// based on the provided per-minute rate and splits the provided inventory into
+// the items that fit within the remaining budget and the excess items that do
+// not.  The budget is reduced by the number of items that fit.  All items fit
+// when the rate is zero.
+func (b *getDataBudget) limit(invList []*wire.InvVect, ratePerMinute int, now time.Time) ([]*wire.InvVect, []*wire.InvVect) {
+	if ratePerMinute == 0 {
+		return invList, nil
+	}
+
+	// Start with a full budget the first time and refill it in proportion to
+	// the time elapsed otherwise.
+	capacity := float64(ratePerMinute)
+	if b.lastRefill.IsZero() {
+		b.tokens = capacity
+	} else if elapsed := now.Sub(b.lastRefill); elapsed > 0 {
+		b.tokens += elapsed.Minutes() * capacity
+		if b.tokens > capacity {
+			b.tokens = capacity
+		}
+	}
+	b.lastRefill = now
+
+	allowed := len(invList)
+	if float64(allowed) > b.tokens {
+		allowed = int(b.tokens)
+	}
+	b.tokens -= float64(allowed)
+	return invList[:allowed], invList[allowed:]
+}
+
+// partialCmpctBlock houses a block that is being reconstructed from a compact
+// block along with the indexes of the transactions that are still missing.
+type partialCmpctBlock struct {
+	hash         chainhash.Hash
+	header       wire.BlockHeader
+	txns         []*wire.MsgTx
+	stxns        []*wire.MsgTx
+	missingTxns  []uint32
+	missingSTxns []uint32
+}
+
+// recentInventory tracks inventory received from a peer along with the time it
+// was received.  It is used to avoid relaying inventory back to the peer it was
+// just received from during the window before the peer's known inventory
+// reflects it.
+//
+// Entries older than the window are pruned lazily as new inventory is added,
+// and no new entries are added once the maximum number of entries is reached
+// until some of them expire, which bounds the memory used per peer.
+type recentInventory struct {
+	mtx       sync.Mutex
+	window    time.Duration
+	lastPrune time.Time
+	items     map[wire.InvVect]time.Time
+}
+
+// newRecentInventory returns a new recent inventory tracker that suppresses
+// inventory for the provided window.  A window of zero disables it.
+func newRecentInventory(window time.Duration) *recentInventory {
+	return &recentInventory{
+		window: window,
+		items:  make(map[wire.InvVect]time.Time),
+	}
+}
+
+// add records that the provided inventory was received at the given time.
+//
+// This function is safe for concurrent access.
+func (r *recentInventory) add(iv *wire.InvVect, now time.Time) {
+	if r.window <= 0 {
+		return
+	}
+
+	r.mtx.Lock()
+	if now.Sub(r.lastPrune) >= r.window {
+		for item, received := range r.items {
+			if now.Sub(received) >= r.window {
+				delete(r.items, item)
+			}
+		}
+		r.lastPrune = now
+	}
+	if len(r.items) < maxRecentInvPerPeer {
+		r.items[*iv] = now
+	}
+	r.mtx.Unlock()
+}
+
+// contains returns whether or not the provided inventory was received within
+// the window prior to the given time.
+//
+// This function is safe for concurrent access.
+func (r *recentInventory) contains(iv *wire.InvVect, now time.Time) bool {
+	if r.window <= 0 {
+		return false
+	}
+
+	r.mtx.Lock()
+	received, ok := r.items[*iv]
+	r.mtx.Unlock()
+	return ok && now.Sub(received) < r.window
+}
+
+// bandwidthMeter tracks the number of bytes transferred during the most recent
+// sample intervals in order to provide a rolling bandwidth rate.  The samples
+// are kept in a fixed size ring buffer indexed by the interval they cover, so
+// recording bytes only requires updating a single sample and stale samples are
+// simply overwritten.
+//
+// The zero value is ready for use.
+type bandwidthMeter struct {
+	mtx       sync.Mutex
+	intervals [bandwidthNumSamples]int64
+	bytes     [bandwidthNumSamples]uint64
+}
+
+// add records the provided number of bytes as transferred at the given time.
+//
+// This function is safe for concurrent access.
+func (m *bandwidthMeter) add(numBytes uint64, now time.Time) {
+	interval := now.UnixNano() / int64(bandwidthSampleInterval)
+	idx := interval % bandwidthNumSamples
+
+	m.mtx.Lock()
+	if m.intervals[idx] != interval {
+		m.intervals[idx] = interval
+		m.bytes[idx] = 0
+	}
+	m.bytes[idx] += numBytes
+	m.mtx.Unlock()
+}
+
+// rate returns the average number of bytes per second transferred over the
+// sample intervals leading up to and including the given time.
+//
+// This function is safe for concurrent access.
+func (m *bandwidthMeter) rate(now time.Time) float64 {
+	interval := now.UnixNano() / int64(bandwidthSampleInterval)
+
+	var total uint64
+	m.mtx.Lock()
+	for i, sampleInterval := range m.intervals {
+		age := interval - sampleInterval
+		if age >= 0 && age < bandwidthNumSamples {
+			total += m.bytes[i]
+		}
+	}
+	m.mtx.Unlock()
+
+	window := bandwidthSampleInterval * bandwidthNumSamples
+	return float64(total) / window.Seconds()
+}
+
+// peerUsefulness tracks the blocks received from a peer in order to determine
+// how useful the peer is relative to others.
+//
+// The zero value is ready for use.
+type peerUsefulness struct {
+	mtx            sync.Mutex
+	blocksReceived uint64
+	lastBlockTime  time.Time
+}
+
+// recordBlock records a block as received from the peer at the given time.
+//
+// This function is safe for concurrent access.
+func (u *peerUsefulness) recordBlock(now time.Time) {
+	u.mtx.Lock()
+	u.blocksReceived++
+	u.lastBlockTime = now
+	u.mtx.Unlock()
+}
+
+// blocks returns the number of blocks the peer has delivered.
+//
+// This function is safe for concurrent access.
+func (u *peerUsefulness) blocks() uint64 {
+	u.mtx.Lock()
+	blocksReceived := u.blocksReceived
+	u.mtx.Unlock()
+	return blocksReceived
+}
+
+// lastBlock returns the time the peer last delivered a block or the zero time
+// when it has never delivered one.
+//
+// This function is safe for concurrent access.
+func (u *peerUsefulness) lastBlock() time.Time {
+	u.mtx.Lock()
+	lastBlockTime := u.lastBlockTime
+	u.mtx.Unlock()
+	return lastBlockTime
+}
+
+// score returns the usefulness score of the peer given its round trip ping time
+// in microseconds as of the provided time.  See peerUsefulnessScore for
+// details.
+//
+// This function is safe for concurrent access.
+func (u *peerUsefulness) score(pingMicros int64, now time.Time) int64 {
+	u.mtx.Lock()
+	blocks, lastBlockTime := u.blocksReceived, u.lastBlockTime
+	u.mtx.Unlock()
+
+	return peerUsefulnessScore(blocks, lastBlockTime, pingMicros, now)
+}
+
+// peerUsefulnessScore returns a score that ranks how useful a peer is based on
+// the number of blocks received from it, how recently it delivered a block, and
+// its round trip ping time in microseconds as of the provided time.  Higher
+// scores are more useful.
+//
+// Each block received counts for ten points up to a maximum number of blocks.
+// Delivering a block within the last 100 minutes earns up to another 100 points
+// that decrease by one point per minute.  Finally, the score is reduced by one
+// point per 10 milliseconds of ping time up to a maximum penalty, which also
+// applies to peers that do not have a ping time yet.
+func peerUsefulnessScore(blocks uint64, lastBlockTime time.Time, pingMicros int64, now time.Time) int64 {
+	if blocks > maxScoredBlocks {
+		blocks = maxScoredBlocks
+	}
+	score := int64(blocks) * 10
+
+	if !lastBlockTime.IsZero() {
+		minutesSince := int64(now.Sub(lastBlockTime) / time.Minute)
+		if minutesSince < 0 {
+			minutesSince = 0
+		}
+		if minutesSince < 100 {
+			score += 100 - minutesSince
+		}
+	}
+
+	pingPenalty := int64(maxPeerPingPenalty)
+	if pingMicros > 0 && pingMicros/peerPingPenaltyMicros < pingPenalty {
+		pingPenalty = pingMicros / peerPingPenaltyMicros
+	}
+	return score - pingPenalty
 }
 
 // newServerPeer returns a new serverPeer instance. The peer needs to be set by
@@ -317,6 +969,7 @@ func newServerPeer(s *server, isPersistent bool) *serverPeer {
 		quit:            make(chan struct{}),
 		txProcessed:     make(chan struct{}, 1),
 		blockProcessed:  make(chan struct{}, 1),
+		recentInv:       newRecentInventory(cfg.InvSuppressWindow),
 	}
 }
 
@@ -378,6 +1031,58 @@ func (sp *serverPeer) pushAddrMsg(addresses []*wire.NetAddress) {
 	sp.addKnownAddresses(known)
 }
 
+// connLimitsExempt returns whether the peer is exempt from the connection
+// limits enforced when adding peers.  Whitelisted peers are exempt regardless
+// of their direction or whether they are persistent.
+//
+// Whitelisting bypasses the following limits:
+//   - The maximum number of connections with the same IP (--maxsameip)
+//   - The maximum number of inbound connections with the same IP
+//     (--maxsameipinbound)
+//   - The maximum number of peers (--maxpeers)
+//   - The restriction of one outbound connection per network group when
+//     selecting addresses for automatic outbound connections
+//   - The reservation of the remaining outbound slots for new network groups
+//     (--minoutboundgroups)
+func (sp *serverPeer) connLimitsExempt() bool {
+	return sp.isWhitelisted
+}
+
+// subnetBanExempt returns whether or not the peer is exempt from subnet bans.
+// Whitelisted and persistent peers are exempt since the operator explicitly
+// trusts them, so they are not disconnected due to misbehaving peers that
+// happen to share their subnet.  They are still subject to bans of their own
+// address.
+func (sp *serverPeer) subnetBanExempt() bool {
+	return sp.isWhitelisted || sp.persistent
+}
+
+// ipBanExempt returns whether or not the peer is exempt from bans of IP
+// addresses and subnets.  Peers connected over a unix domain socket are exempt
+// since they are only assigned the loopback address for tracking purposes, so
+// banning it would otherwise ban every connection from the local host.  They
+// are still disconnected when their ban score exceeds the ban threshold.
+func (sp *serverPeer) ipBanExempt() bool {
+	return sp.isUnixSocket
+}
+
+// maybeWhitelistUserAgent marks the peer as whitelisted by its user agent when
+// the provided user agent contains any of the provided whitelisted user agent
+// substrings.  The peer is then exempt from ban scoring.  Unlike peers with a
+// whitelisted IP, it remains subject to the connection limits and bans since
+// user agents are reported by the peers themselves and can be trivially
+// spoofed.
+//
+// This must only be called before the peer is added to the server.
+func (sp *serverPeer) maybeWhitelistUserAgent(userAgent string, whitelist []string) {
+	if sp.isUAWhitelisted || !isWhitelistedUserAgent(userAgent, whitelist) {
+		return
+	}
+
+	srvrLog.Debugf("Whitelisting peer %s with user agent %q", sp, userAgent)
+	sp.isUAWhitelisted = true
+}
+
 // addBanScore increases the persistent and decaying ban score fields by the
 // values passed as parameters. If the resulting score exceeds half of the ban
 // threshold, a warning is logged including the reason provided. Further, if
@@ -388,12 +1093,13 @@ func (sp *serverPeer) addBanScore(persistent, transient uint32, reason string) {
 	if cfg.DisableBanning {
 		return
 	}
-	if sp.isWhitelisted {
+	if sp.isWhitelisted || sp.isUAWhitelisted {
 		peerLog.Debugf("Misbehaving whitelisted peer %s: %s", sp, reason)
 		return
 	}
 
-	warnThreshold := cfg.BanThreshold >> 1
+	banThreshold := reloadable.BanThreshold()
+	warnThreshold := banThreshold >> 1
 	if transient == 0 && persistent == 0 {
 		// The score is not being increased, but a warning message is still
 		// logged if the score is above the warn threshold.
@@ -408,7 +1114,7 @@ func (sp *serverPeer) addBanScore(persistent, transient uint32, reason string) {
 	if score > warnThreshold {
 		peerLog.Warnf("Misbehaving peer %s: %s -- ban score increased to %d",
 			sp, reason, score)
-		if score > cfg.BanThreshold {
+		if score > banThreshold {
 			peerLog.Warnf("Misbehaving peer %s -- banning and disconnecting",
 				sp)
 			sp.server.BanPeer(sp)
@@ -423,6 +1129,85 @@ func hasServices(advertised, desired wire.ServiceFlag) bool {
 	return advertised&desired == desired
 }
 
+// blockAnnounceModeForPeer returns the method a peer with the provided
+// negotiated protocol version and advertised services is asked to use to
+// announce new blocks given the preferred method.  It falls back to the best
+// method supported by the peer when it does not support the preferred one.
+func blockAnnounceModeForPeer(preferred peer.BlockAnnounceMode, pver uint32, services wire.ServiceFlag) peer.BlockAnnounceMode {
+	if preferred == peer.BlockAnnounceCmpct {
+		if pver >= wire.CmpctBlockVersion &&
+			hasServices(services, wire.SFNodeCmpctBlock) {
+
+			return peer.BlockAnnounceCmpct
+		}
+		preferred = peer.BlockAnnounceHeaders
+	}
+	if preferred == peer.BlockAnnounceHeaders &&
+		pver >= wire.SendHeadersVersion {
+
+		return peer.BlockAnnounceHeaders
+	}
+	return peer.BlockAnnounceInv
+}
+
+// requestBlockAnnounce records the method the peer is asked to use to announce
+// new blocks and returns the message that must be sent to the peer to request
+// it, if any.  Requests to announce via headers are deferred when the chain is
+// not current since the announced headers are not useful until the chain is
+// synced, in which case no message is returned, the peer continues to announce
+// new blocks via inventory, and the returned flag is set.  The deferred request
+// is obtained with deferredHeadersRequest once the chain is current.
+//
+// This function is safe for concurrent access.
+func (sp *serverPeer) requestBlockAnnounce(mode peer.BlockAnnounceMode, current bool) (wire.Message, bool) {
+	sp.blockAnnounceMtx.Lock()
+	defer sp.blockAnnounceMtx.Unlock()
+
+	sp.blockAnnounceRequested = mode
+	sp.sendHeadersDeferred = false
+	switch mode {
+	case peer.BlockAnnounceCmpct:
+		return wire.NewMsgSendCmpct(), false
+
+	case peer.BlockAnnounceHeaders:
+		if !current {
+			sp.blockAnnounceRequested = peer.BlockAnnounceInv
+			sp.sendHeadersDeferred = true
+			return nil, true
+		}
+		return wire.NewMsgSendHeaders(), false
+	}
+	return nil, false
+}
+
+// deferredHeadersRequest returns the sendheaders message for a request to
+// announce new blocks via headers that was deferred because the chain was not
+// current and records that the peer was asked to announce new blocks via
+// headers.  It returns nil when there is no deferred request.
+//
+// This function is safe for concurrent access.
+func (sp *serverPeer) deferredHeadersRequest() wire.Message {
+	sp.blockAnnounceMtx.Lock()
+	defer sp.blockAnnounceMtx.Unlock()
+
+	if !sp.sendHeadersDeferred {
+		return nil
+	}
+	sp.blockAnnounceRequested = peer.BlockAnnounceHeaders
+	sp.sendHeadersDeferred = false
+	return wire.NewMsgSendHeaders()
+}
+
+// requestedBlockAnnounce returns the method the peer was asked to use to
+// announce new blocks.
+//
+// This function is safe for concurrent access.
+func (sp *serverPeer) requestedBlockAnnounce() peer.BlockAnnounceMode {
+	sp.blockAnnounceMtx.Lock()
+	defer sp.blockAnnounceMtx.Unlock()
+	return sp.blockAnnounceRequested
+}
+
 // OnVersion is invoked when a peer receives a version wire message and is used
 // to negotiate the protocol version details as well as kick start the
 // communications.
@@ -450,6 +1235,18 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 		return nil
 	}
 
+	// Reject peers that have a protocol version below the configured minimum.
+	if cfg.MinProtocolVersion != 0 &&
+		msg.ProtocolVersion < int32(cfg.MinProtocolVersion) {
+
+		srvrLog.Debugf("Rejecting peer %s with protocol version %d due to "+
+			"being below the configured minimum %d", sp.Peer,
+			msg.ProtocolVersion, cfg.MinProtocolVersion)
+		reason := fmt.Sprintf("protocol version must be %d or greater",
+			cfg.MinProtocolVersion)
+		return wire.NewMsgReject(msg.Command(), wire.RejectObsolete, reason)
+	}
+
 	// Reject outbound peers that are not full nodes.
 	wantServices := wire.SFNodeNetwork
 	if !isInbound && !hasServices(msg.Services, wantServices) {
@@ -462,6 +1259,23 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 		return wire.NewMsgReject(msg.Command(), wire.RejectNonstandard, reason)
 	}
 
+	// Feeler connections are only made to verify the remote address is
+	// reachable and running a compatible full node, so record the success
+	// and disconnect now that the handshake has made it this far.  The flag
+	// is only read once the peer has disconnected.
+	if sp.isFeeler {
+		srvrLog.Debugf("Feeler connection to %s succeeded", sp.Peer)
+		sp.feelerGood = true
+		p.Disconnect()
+		return nil
+	}
+
+	// Whitelist peers with a whitelisted user agent.  This is done prior to
+	// adding the peer to the server so the flag is not modified after the
+	// peer is added, which makes it safe for the other goroutines that check
+	// it for ban scoring.
+	sp.maybeWhitelistUserAgent(msg.UserAgent, cfg.WhitelistUserAgents)
+
 	// Update the address manager and request known addresses from the
 	// remote peer for outbound connections.  This is skipped when running
 	// on the simulation test network since it is only intended to connect
@@ -504,6 +1318,30 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 	// the local clock to keep the network time in sync.
 	sp.server.timeSource.AddTimeSample(p.Addr(), msg.Timestamp)
 
+	// Request the peer announce new blocks using the preferred method or the
+	// best method it supports otherwise.  Compact blocks are not requested
+	// in blocks only mode since they can't be reconstructed without the
+	// transactions in the memory pool.
+	//
+	// NOTE: This must be done prior to signalling the block manager about
+	// the new peer since it relies on the requested method to recognize
+	// header announcements.
+	preferred := cfg.blockAnnounce
+	if cfg.BlocksOnly && preferred == peer.BlockAnnounceCmpct {
+		preferred = peer.BlockAnnounceHeaders
+	}
+	mode := blockAnnounceModeForPeer(preferred, p.ProtocolVersion(),
+		msg.Services)
+	current := mode != peer.BlockAnnounceHeaders ||
+		sp.server.blockManager.IsCurrent()
+	announceMsg, deferred := sp.requestBlockAnnounce(mode, current)
+	if announceMsg != nil {
+		p.QueueMessage(announceMsg, nil)
+	}
+	if deferred {
+		sp.server.blockManager.sendHeadersAfterSync(sp)
+	}
+
 	// Signal the block manager this peer is a new sync candidate.
 	sp.server.blockManager.NewPeer(sp)
 
@@ -526,15 +1364,19 @@ func (sp *serverPeer) OnMemPool(p *peer.Peer, msg *wire.MsgMemPool) {
 	// transaction memory pool.  Limit it to the max allowed inventory
 	// per message.  The NewMsgInvSizeHint function automatically limits
 	// the passed hint to the maximum allowed, so it's safe to pass it
-	// without double checking it here.
+	// without double checking it here.  Transactions of types that are
+	// configured to not be relayed are not announced.
 	txMemPool := sp.server.txMemPool
 	txDescs := txMemPool.TxDescs()
 	invMsg := wire.NewMsgInvSizeHint(uint(len(txDescs)))
 
-	for i, txDesc := range txDescs {
+	for _, txDesc := range txDescs {
+		if isNoRelayTxType(txDesc.Type) {
+			continue
+		}
 		iv := wire.NewInvVect(wire.InvTypeTx, txDesc.Tx.Hash())
 		invMsg.AddInvVect(iv)
-		if i+1 >= wire.MaxInvPerMsg {
+		if len(invMsg.InvList) >= wire.MaxInvPerMsg {
 			break
 		}
 	}
@@ -676,6 +1518,8 @@ func (sp *serverPeer) OnTx(p *peer.Peer, msg *wire.MsgTx) {
 	tx := dcrutil.NewTx(msg)
 	iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
 	p.AddKnownInventory(iv)
+	sp.recentInv.add(iv, time.Now())
+	atomic.AddUint64(&sp.txsReceived, 1)
 
 	// Queue the transaction up to be handled by the block manager and
 	// intentionally block further receives until the transaction is fully
@@ -686,6 +1530,33 @@ func (sp *serverPeer) OnTx(p *peer.Peer, msg *wire.MsgTx) {
 	<-sp.txProcessed
 }
 
+// maybePrecomputeFilters starts building the committed filters for the
+// provided block in the background when the committed filter index is enabled
+// and the block extends the current best chain.  Blocks that do not extend the
+// best chain are not precomputed since they are orphans, side chain blocks, or
+// otherwise unlikely to be connected next.  It returns whether the filters are
+// being precomputed.
+func (s *server) maybePrecomputeFilters(block *dcrutil.Block) bool {
+	if s.cfIndex == nil {
+		return false
+	}
+	best := s.chain.BestSnapshot()
+	if block.MsgBlock().Header.PrevBlock != best.Hash {
+		return false
+	}
+	s.cfIndex.PrecomputeFilters(block)
+	return true
+}
+
+// maybeDiscardFilters discards the committed filters that were precomputed for
+// the provided block once it has been processed when it did not become part of
+// the main chain, such as when it was rejected.
+func (s *server) maybeDiscardFilters(block *dcrutil.Block) {
+	if !s.chain.MainChainHasBlock(block.Hash()) {
+		s.cfIndex.DiscardFilters(block.Hash())
+	}
+}
+
 // OnBlock is invoked when a peer receives a block wire message.  It blocks
 // until the network block has been fully processed.
 func (sp *serverPeer) OnBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
@@ -696,6 +1567,13 @@ func (sp *serverPeer) OnBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	// Add the block to the known inventory for the peer.
 	iv := wire.NewInvVect(wire.InvTypeBlock, block.Hash())
 	p.AddKnownInventory(iv)
+	now := time.Now()
+	sp.recentInv.add(iv, now)
+	sp.usefulness.recordBlock(now)
+
+	// Start building the committed filters for the block in the background
+	// so they are computed concurrently with the validation of the block.
+	precomputed := sp.server.maybePrecomputeFilters(block)
 
 	// Queue the block up to be handled by the block manager and
 	// intentionally block further receives until the network block is fully
@@ -708,6 +1586,250 @@ func (sp *serverPeer) OnBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	// fully processed.
 	sp.server.blockManager.QueueBlock(block, sp)
 	<-sp.blockProcessed
+	if precomputed {
+		sp.server.maybeDiscardFilters(block)
+	}
+}
+
+// reconstructCmpctTxTree reconstructs a transaction tree of a compact block from
+// its prefilled transactions and the provided transactions keyed by their short
+// transaction IDs.  It returns the transactions of the tree, where those that
+// could not be found are nil, along with the indexes of the missing
+// transactions.
+func reconstructCmpctTxTree(shortIDs []uint64, prefilled []wire.PrefilledTx, knownTxns map[uint64]*dcrutil.Tx) ([]*wire.MsgTx, []uint32, error) {
+	txns := make([]*wire.MsgTx, len(shortIDs)+len(prefilled))
+	lastIndex := -1
+	for _, ptx := range prefilled {
+		index := int(ptx.Index)
+		if index <= lastIndex || index >= len(txns) {
+			return nil, nil, fmt.Errorf("prefilled transaction index %d "+
+				"is out of order or out of range", index)
+		}
+		txns[index] = ptx.Tx
+		lastIndex = index
+	}
+
+	var missing []uint32
+	index := 0
+	for _, shortID := range shortIDs {
+		// Skip over the slots taken by prefilled transactions.
+		for txns[index] != nil {
+			index++
+		}
+		if tx := knownTxns[shortID]; tx != nil {
+			txns[index] = tx.MsgTx()
+		} else {
+			missing = append(missing, uint32(index))
+		}
+		index++
+	}
+
+	return txns, missing, nil
+}
+
+// cmpctBlockMatchesHeader returns whether the transactions of a block that has
+// been reconstructed from a compact block commit to the merkle roots in its
+// header.  They do not match when a short transaction ID collides with that of
+// a different transaction in the memory pool.
+func cmpctBlockMatchesHeader(msgBlock *wire.MsgBlock) bool {
+	return standalone.CalcTxTreeMerkleRoot(msgBlock.Transactions) ==
+		msgBlock.Header.MerkleRoot &&
+		standalone.CalcTxTreeMerkleRoot(msgBlock.STransactions) ==
+			msgBlock.Header.StakeRoot
+}
+
+// processCmpctBlock submits a block that has been fully reconstructed from a
+// compact block to the block manager.  The full block is requested from the
+// peer instead when the reconstructed block does not match its header.
+func (sp *serverPeer) processCmpctBlock(partial *partialCmpctBlock) {
+	msgBlock := &wire.MsgBlock{
+		Header:        partial.header,
+		Transactions:  partial.txns,
+		STransactions: partial.stxns,
+	}
+	if !cmpctBlockMatchesHeader(msgBlock) {
+		peerLog.Debugf("Failed to reconstruct block %v from compact block "+
+			"sent by %v -- requesting full block", partial.hash, sp)
+		blocks := []*chainhash.Hash{&partial.hash}
+		err := sp.server.blockManager.RequestFromPeer(sp, blocks, nil)
+		if err != nil {
+			peerLog.Errorf("Failed to request block %v from %v: %v",
+				partial.hash, sp, err)
+		}
+		return
+	}
+
+	block := dcrutil.NewBlock(msgBlock)
+	sp.usefulness.recordBlock(time.Now())
+	precomputed := sp.server.maybePrecomputeFilters(block)
+
+	// Queue the block up to be handled by the block manager and
+	// intentionally block further receives until it is fully processed in
+	// the same way as full blocks.
+	sp.server.blockManager.QueueCmpctBlock(block, sp)
+	<-sp.blockProcessed
+	if precomputed {
+		sp.server.maybeDiscardFilters(block)
+	}
+}
+
+// OnCmpctBlock is invoked when a peer receives a cmpctblock wire message.  It
+// reconstructs the block from the transactions in the memory pool and either
+// submits it for processing or requests any missing transactions from the
+// peer.
+func (sp *serverPeer) OnCmpctBlock(p *peer.Peer, msg *wire.MsgCmpctBlock) {
+	blockHash := msg.Header.BlockHash()
+
+	// Compact blocks are only expected from peers that were asked to announce
+	// new blocks with them, so consider any others a protocol violation.
+	if sp.requestedBlockAnnounce() != peer.BlockAnnounceCmpct {
+		peerLog.Debugf("Unrequested compact block %v from %v", blockHash, sp)
+		sp.addBanScore(100, 0, msg.Command())
+		sp.Disconnect()
+		return
+	}
+
+	iv := wire.NewInvVect(wire.InvTypeBlock, &blockHash)
+	p.AddKnownInventory(iv)
+	sp.recentInv.add(iv, time.Now())
+
+	// Ignore compact blocks in blocks only mode and while syncing since the
+	// transactions will not be in the memory pool.  The block will be
+	// downloaded via the normal sync process in that case.
+	if cfg.BlocksOnly || !sp.server.blockManager.IsCurrent() {
+		return
+	}
+
+	// Ignore compact blocks that are already known.
+	if haveBlock, err := sp.server.chain.HaveBlock(&blockHash); err != nil ||
+		haveBlock {
+
+		return
+	}
+
+	// Request the full block instead of reconstructing it when it does not
+	// connect to a block in the block index so the work of looking up
+	// transactions in the memory pool is not done for blocks that can't be
+	// processed anyway.  The full block is then handled by the normal orphan
+	// processing.
+	if _, err := sp.server.chain.HeaderByHash(&msg.Header.PrevBlock); err != nil {
+		peerLog.Debugf("Compact block %v from %v does not connect to a "+
+			"known block -- requesting full block", blockHash, sp)
+		blocks := []*chainhash.Hash{&blockHash}
+		err := sp.server.blockManager.RequestFromPeer(sp, blocks, nil)
+		if err != nil {
+			peerLog.Errorf("Failed to request block %v from %v: %v",
+				blockHash, sp, err)
+		}
+		return
+	}
+
+	key := wire.CmpctBlockShortIDKey(&msg.Header, msg.Nonce)
+	knownTxns := sp.server.txMemPool.TxsByShortID(&key)
+	txns, missingTxns, err := reconstructCmpctTxTree(msg.ShortIDs,
+		msg.PrefilledTxns, knownTxns)
+	if err != nil {
+		peerLog.Debugf("Malformed compact block %v from %v: %v", blockHash,
+			sp, err)
+		sp.addBanScore(100, 0, msg.Command())
+		sp.Disconnect()
+		return
+	}
+	stxns, missingSTxns, err := reconstructCmpctTxTree(msg.STxShortIDs,
+		msg.PrefilledSTxns, knownTxns)
+	if err != nil {
+		peerLog.Debugf("Malformed compact block %v from %v: %v", blockHash,
+			sp, err)
+		sp.addBanScore(100, 0, msg.Command())
+		sp.Disconnect()
+		return
+	}
+
+	partial := &partialCmpctBlock{
+		hash:         blockHash,
+		header:       msg.Header,
+		txns:         txns,
+		stxns:        stxns,
+		missingTxns:  missingTxns,
+		missingSTxns: missingSTxns,
+	}
+	if len(missingTxns) == 0 && len(missingSTxns) == 0 {
+		sp.processCmpctBlock(partial)
+		return
+	}
+
+	// Request the transactions that are not in the memory pool.  Any
+	// previously pending compact block from the peer is superseded.
+	peerLog.Debugf("Requesting %d missing transactions of compact block %v "+
+		"from %v", len(missingTxns)+len(missingSTxns), blockHash, sp)
+	sp.pendingCmpctBlock = partial
+	p.QueueMessage(wire.NewMsgGetBlockTxns(&blockHash, missingTxns,
+		missingSTxns), nil)
+}
+
+// OnGetBlockTxns is invoked when a peer receives a getblocktxns wire message.
+// It responds with the requested transactions of the block.
+func (sp *serverPeer) OnGetBlockTxns(p *peer.Peer, msg *wire.MsgGetBlockTxns) {
+	block, err := sp.server.chain.BlockByHash(&msg.BlockHash)
+	if err != nil {
+		peerLog.Debugf("Unable to fetch block %v requested by %v: %v",
+			msg.BlockHash, sp, err)
+		return
+	}
+
+	msgBlock := block.MsgBlock()
+	resp := wire.NewMsgBlockTxns(&msg.BlockHash)
+	for _, index := range msg.TxIndexes {
+		if index >= uint32(len(msgBlock.Transactions)) {
+			peerLog.Debugf("%v requested out of range transaction %d of "+
+				"block %v", sp, index, msg.BlockHash)
+			sp.addBanScore(100, 0, msg.Command())
+			sp.Disconnect()
+			return
+		}
+		resp.AddTransaction(msgBlock.Transactions[index])
+	}
+	for _, index := range msg.STxIndexes {
+		if index >= uint32(len(msgBlock.STransactions)) {
+			peerLog.Debugf("%v requested out of range stake transaction "+
+				"%d of block %v", sp, index, msg.BlockHash)
+			sp.addBanScore(100, 0, msg.Command())
+			sp.Disconnect()
+			return
+		}
+		resp.AddSTransaction(msgBlock.STransactions[index])
+	}
+	p.QueueMessage(resp, nil)
+}
+
+// OnBlockTxns is invoked when a peer receives a blocktxns wire message.  It
+// completes the reconstruction of the pending compact block from the peer with
+// the provided transactions.
+func (sp *serverPeer) OnBlockTxns(p *peer.Peer, msg *wire.MsgBlockTxns) {
+	partial := sp.pendingCmpctBlock
+	if partial == nil || partial.hash != msg.BlockHash {
+		peerLog.Debugf("Ignoring unrequested blocktxns for block %v from %v",
+			msg.BlockHash, sp)
+		return
+	}
+	sp.pendingCmpctBlock = nil
+
+	if len(msg.Transactions) != len(partial.missingTxns) ||
+		len(msg.STransactions) != len(partial.missingSTxns) {
+
+		peerLog.Debugf("%v sent the wrong number of transactions for "+
+			"compact block %v", sp, msg.BlockHash)
+		sp.addBanScore(100, 0, msg.Command())
+		sp.Disconnect()
+		return
+	}
+	for i, index := range partial.missingTxns {
+		partial.txns[index] = msg.Transactions[i]
+	}
+	for i, index := range partial.missingSTxns {
+		partial.stxns[index] = msg.STransactions[i]
+	}
+	sp.processCmpctBlock(partial)
 }
 
 // OnInv is invoked when a peer receives an inv wire message and is used to
@@ -759,7 +1881,18 @@ func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
 	numAdded := 0
 	notFound := wire.NewMsgNotFound()
 
+	// Reject requests for more inventory than the configured hard limit
+	// outright without serving any of it and ban the peer by increasing its
+	// ban score beyond the ban threshold.
 	length := len(msg.InvList)
+	if length > cfg.MaxGetDataInv {
+		peerLog.Debugf("Peer %s requested %d items via getdata which "+
+			"exceeds the max of %d -- rejecting", sp, length,
+			cfg.MaxGetDataInv)
+		sp.addBanScore(reloadable.BanThreshold()+1, 0, msg.Command())
+		return
+	}
+
 	// A decaying ban score increase is applied to prevent exhausting resources
 	// with unusually large inventory queries.
 	// Requesting more than the maximum inventory vector length within a short
@@ -769,6 +1902,23 @@ func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
 	// This incremental score decays each minute to half of its value.
 	sp.addBanScore(0, uint32(length)*99/wire.MaxInvPerMsg, "getdata")
 
+	// Limit the number of items served to the peer's getdata budget.  The
+	// excess items are answered with notfound and increase the ban score in
+	// proportion to the budget, so only peers that repeatedly exceed it by a
+	// large margin are banned.
+	invList, excess := sp.getDataBudget.limit(msg.InvList,
+		cfg.MaxGetDataPerMinute, time.Now())
+	if len(excess) > 0 {
+		peerLog.Debugf("Peer %s exceeded its getdata budget -- not "+
+			"serving %d of %d requested items", sp, len(excess), length)
+		for _, iv := range excess {
+			notFound.AddInvVect(iv)
+		}
+		score := uint64(len(excess)) * 50 / uint64(cfg.MaxGetDataPerMinute)
+		sp.addBanScore(0, uint32(score), "getdata budget exceeded")
+	}
+	length = len(invList)
+
 	// We wait on this wait channel periodically to prevent queuing
 	// far more data than we can send in a reasonable time, wasting memory.
 	// The waiting occurs after the database fetch for the next one to
@@ -776,12 +1926,24 @@ func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
 	var waitChan chan struct{}
 	doneChan := make(chan struct{}, 1)
 
-	for i, iv := range msg.InvList {
+	// Limit the number of items that are queued to the peer and not yet
+	// sent.  Serving the remaining items is deferred until the outstanding
+	// ones have been sent once the limit is reached.  Whitelisted peers are
+	// exempt.
+	maxOutstanding := cfg.MaxGetDataPending
+	if sp.isWhitelisted {
+		maxOutstanding = 0
+	}
+	var outstanding int
+
+	for i, iv := range invList {
 		var c chan struct{}
+		limitReached := maxOutstanding > 0 &&
+			outstanding+1 >= maxOutstanding && i != length-1
 		// If this will be the last message we send.
 		if i == length-1 && len(notFound.InvList) == 0 {
 			c = doneChan
-		} else if (i+1)%3 == 0 {
+		} else if (i+1)%3 == 0 || limitReached {
 			// Buffered so as to not make the send goroutine block.
 			c = make(chan struct{}, 1)
 		}
@@ -804,12 +1966,22 @@ func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
 			// being no outstanding not found inventory, consume
 			// it here because there is now not found inventory
 			// that will use the channel momentarily.
-			if i == len(msg.InvList)-1 && c != nil {
+			if i == length-1 && c != nil {
 				<-c
 			}
 		}
 		numAdded++
 		waitChan = c
+
+		// Wait for all of the outstanding items to be sent when the limit
+		// is reached.  Messages are sent in the order they are queued, so
+		// the final one being sent implies the rest were sent as well.
+		outstanding++
+		if limitReached {
+			<-c
+			waitChan = nil
+			outstanding = 0
+		}
 	}
 	if len(notFound.InvList) != 0 {
 		p.QueueMessage(notFound, doneChan)
@@ -1153,12 +2325,14 @@ func (sp *serverPeer) OnAddr(p *peer.Peer, msg *wire.MsgAddr) {
 // the bytes received by the server.
 func (sp *serverPeer) OnRead(p *peer.Peer, bytesRead int, msg wire.Message, err error) {
 	sp.server.AddBytesReceived(uint64(bytesRead))
+	sp.recvRate.add(uint64(bytesRead), time.Now())
 }
 
 // OnWrite is invoked when a peer sends a message and it is used to update
 // the bytes sent by the server.
 func (sp *serverPeer) OnWrite(p *peer.Peer, bytesWritten int, msg wire.Message, err error) {
 	sp.server.AddBytesSent(uint64(bytesWritten))
+	sp.sendRate.add(uint64(bytesWritten), time.Now())
 }
 
 // randomUint16Number returns a random uint16 in a specified input range.  Note
@@ -1179,6 +2353,83 @@ func randomUint16Number(max uint16) uint16 {
 	}
 }
 
+// txMined returns whether the transaction with the provided hash is known to be
+// in a block in the main chain according to the utxo set or, when it is
+// enabled, the transaction index.  The transaction index is required to detect
+// mined transactions whose outputs have all been spent since they are no
+// longer in the utxo set.
+func (s *server) txMined(txHash *chainhash.Hash) bool {
+	entry, err := s.chain.FetchUtxoEntry(txHash)
+	if err == nil && entry != nil && !entry.IsFullySpent() {
+		return true
+	}
+
+	if s.txIndex != nil {
+		entry, err := s.txIndex.Entry(txHash)
+		if err == nil && entry != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pruneMinedRebroadcastTxns removes all regular transactions from the provided
+// pending rebroadcast inventory that the provided function reports as mined.
+// Stake transactions are pruned separately based on the stake-specific
+// conditions that make them invalid.
+func pruneMinedRebroadcastTxns(pendingInvs map[wire.InvVect]interface{}, mined func(*chainhash.Hash) bool) {
+	for iv, data := range pendingInvs {
+		if iv.Type != wire.InvTypeTx {
+			continue
+		}
+		tx, ok := data.(*dcrutil.Tx)
+		if !ok {
+			continue
+		}
+		if stake.DetermineTxType(tx.MsgTx()) != stake.TxTypeRegular {
+			continue
+		}
+
+		if mined(tx.Hash()) {
+			delete(pendingInvs, iv)
+			srvrLog.Debugf("Pending transaction broadcast inventory for "+
+				"tx %v removed. Transaction already mined.", tx.Hash())
+		}
+	}
+}
+
+// rebroadcastPendingInvs relays all of the provided pending rebroadcast
+// inventory via the provided function after first removing any regular
+// transactions the provided function reports as mined so confirmed
+// transactions are not needlessly relayed again.
+func rebroadcastPendingInvs(pendingInvs map[wire.InvVect]interface{}, mined func(*chainhash.Hash) bool, relay func(*wire.InvVect, interface{})) {
+	pruneMinedRebroadcastTxns(pendingInvs, mined)
+	for iv, data := range pendingInvs {
+		ivCopy := iv
+		relay(&ivCopy, data)
+	}
+}
+
+// rebroadcastTimeout returns how long to wait before the next rebroadcast of
+// pending inventory.  When the provided interval is zero, the result is a random
+// time up to 30 minutes in the future.  Otherwise, it is the interval plus a
+// random jitter of up to a tenth of the interval capped at maxRebroadcastJitter.
+func rebroadcastTimeout(interval time.Duration) time.Duration {
+	if interval == 0 {
+		return time.Second * time.Duration(randomUint16Number(1800))
+	}
+
+	jitter := interval / 10
+	if jitter > maxRebroadcastJitter {
+		jitter = maxRebroadcastJitter
+	}
+	if jitterSecs := uint16(jitter / time.Second); jitterSecs > 0 {
+		interval += time.Second * time.Duration(randomUint16Number(jitterSecs+1))
+	}
+	return interval
+}
+
 // AddRebroadcastInventory adds 'iv' to the list of inventories to be
 // rebroadcasted at random intervals until they show up in a block.
 func (s *server) AddRebroadcastInventory(iv *wire.InvVect, data interface{}) {
@@ -1261,6 +2512,17 @@ func (s *server) pushTxMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<-
 		return err
 	}
 
+	// Treat transactions of types that are configured to not be relayed as
+	// if they were not found so they are not served either.
+	if isNoRelayTxType(stake.DetermineTxType(tx.MsgTx())) {
+		peerLog.Tracef("Not serving tx %v due to relay policy", hash)
+
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return fmt.Errorf("tx %v is not relayed due to relay policy", hash)
+	}
+
 	// Once we have fetched data wait for any previous operation to finish.
 	if waitChan != nil {
 		<-waitChan
@@ -1271,9 +2533,36 @@ func (s *server) pushTxMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<-
 	return nil
 }
 
+// blockOutsideRetainWindow returns whether a block at the provided height is
+// older than the provided number of most recent blocks relative to the given
+// best chain height.  No blocks are outside of the window when the number of
+// retained blocks is zero.
+func blockOutsideRetainWindow(height, bestHeight int64, retainBlocks uint32) bool {
+	return retainBlocks > 0 && bestHeight-height >= int64(retainBlocks)
+}
+
 // pushBlockMsg sends a block message for the provided block hash to the
 // connected peer.  An error is returned if the block hash is not known.
 func (s *server) pushBlockMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{}, waitChan <-chan struct{}) error {
+	// Refuse to serve blocks older than the window of recent blocks that is
+	// advertised when the node is configured with limited block history.
+	if cfg.RetainBlocks > 0 {
+		header, err := s.chain.HeaderByHash(hash)
+		best := s.chain.BestSnapshot()
+		if err == nil && blockOutsideRetainWindow(int64(header.Height),
+			best.Height, cfg.RetainBlocks) {
+
+			peerLog.Debugf("Not serving block %v at height %d to %v since "+
+				"it is older than the %d retained blocks", hash,
+				header.Height, sp, cfg.RetainBlocks)
+			if doneChan != nil {
+				doneChan <- struct{}{}
+			}
+			return fmt.Errorf("block %v is outside of the retained "+
+				"block window", hash)
+		}
+	}
+
 	block, err := sp.server.chain.BlockByHash(hash)
 	if err != nil {
 		peerLog.Tracef("Unable to fetch requested block hash %v: %v",
@@ -1359,47 +2648,86 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 	}
 
 	// Disconnect banned peers.
-	host, _, err := net.SplitHostPort(sp.Addr())
-	if err != nil {
-		srvrLog.Debugf("can't split hostport %v", err)
-		sp.Disconnect()
-		return false
-	}
-	if banEnd, ok := state.banned[host]; ok {
-		if time.Now().Before(banEnd) {
-			srvrLog.Debugf("Peer %s is banned for another %v - disconnecting",
-				host, time.Until(banEnd))
+	if !sp.ipBanExempt() {
+		host, _, err := net.SplitHostPort(sp.Addr())
+		if err != nil {
+			srvrLog.Debugf("can't split hostport %v", err)
 			sp.Disconnect()
 			return false
 		}
+		if banEnd, ok := state.banned[host]; ok {
+			if time.Now().Before(banEnd) {
+				srvrLog.Debugf("Peer %s is banned for another %v - "+
+					"disconnecting", host, time.Until(banEnd))
+				sp.Disconnect()
+				return false
+			}
 
-		srvrLog.Infof("Peer %s is no longer banned", host)
-		delete(state.banned, host)
+			srvrLog.Infof("Peer %s is no longer banned", host)
+			delete(state.banned, host)
+		}
+		if ip := net.ParseIP(host); ip != nil && !sp.subnetBanExempt() {
+			if banEnd, ok := state.subnetBanEnd(ip, time.Now()); ok {
+				srvrLog.Debugf("Peer %s is in a banned subnet for another "+
+					"%v - disconnecting", host, time.Until(banEnd))
+				sp.Disconnect()
+				return false
+			}
+		}
 	}
 
-	// Limit max number of connections from a single IP.  However, allow
-	// whitelisted inbound peers and localhost connections regardless.
-	isInboundWhitelisted := sp.isWhitelisted && sp.Inbound()
+	// Limit max number of connections from a single IP as well as the max
+	// number of inbound connections from a single IP.  The new peer is the
+	// one disconnected when a limit is exceeded.  However, allow peers that
+	// are exempt from connection limits and localhost connections regardless.
+	isExempt := sp.connLimitsExempt()
 	peerIP := sp.NA().IP
-	if cfg.MaxSameIP > 0 && !isInboundWhitelisted && !peerIP.IsLoopback() &&
-		state.ConnectionsWithIP(peerIP)+1 > cfg.MaxSameIP {
-		srvrLog.Infof("Max connections with %s reached [%d] - "+
-			"disconnecting peer", sp, cfg.MaxSameIP)
+	if !isExempt && !peerIP.IsLoopback() && exceedsSameIPLimits(sp.Inbound(),
+		state.ConnectionsWithIP(peerIP), state.InboundConnectionsWithIP(peerIP),
+		cfg.MaxSameIP, cfg.MaxSameIPInbound) {
+
+		srvrLog.Infof("Max connections with %s reached [%d total, %d "+
+			"inbound] - disconnecting peer", sp, cfg.MaxSameIP,
+			cfg.MaxSameIPInbound)
 		sp.Disconnect()
 		return false
 	}
 
-	// Limit max number of total peers.  However, allow whitelisted inbound
-	// peers regardless.
-	if state.Count()+1 > cfg.MaxPeers && !isInboundWhitelisted {
-		srvrLog.Infof("Max peers reached [%d] - disconnecting peer %s",
-			cfg.MaxPeers, sp)
+	// Reject automatic outbound peers in a network group that other outbound
+	// peers are already in when the remaining outbound slots are reserved for
+	// new network groups.  This is also checked when choosing addresses to
+	// connect to, but concurrent connection attempts may still end up in the
+	// same network group.
+	if state.rejectOutboundGroup(sp, targetOutboundPeers(),
+		cfg.MinOutboundGroups) {
+
+		srvrLog.Debugf("Disconnecting outbound peer %s to reserve the "+
+			"remaining outbound slots for new network groups", sp)
 		sp.Disconnect()
-		// TODO: how to handle permanent peers here?
-		// they should be rescheduled.
 		return false
 	}
 
+	// Limit max number of total peers.  However, allow peers that are exempt
+	// from connection limits regardless.  Room is made for the new peer by
+	// evicting the least useful inbound peer when there is one eligible for
+	// eviction.
+	maxPeers := reloadable.MaxPeers()
+	if state.Count()+1 > maxPeers && !isExempt {
+		evict := state.lowestScoringPeer(time.Now())
+		if evict == nil {
+			srvrLog.Infof("Max peers reached [%d] - disconnecting peer %s",
+				maxPeers, sp)
+			sp.Disconnect()
+			// TODO: how to handle permanent peers here?
+			// they should be rescheduled.
+			return false
+		}
+
+		srvrLog.Infof("Max peers reached [%d] - evicting least useful "+
+			"peer %s to make room for peer %s", maxPeers, evict, sp)
+		evict.Disconnect()
+	}
+
 	// Add the new peer and start it.
 	srvrLog.Debugf("New peer %s", sp)
 	if sp.Inbound() {
@@ -1510,51 +2838,215 @@ func (s *server) handleDonePeerMsg(state *peerState, sp *serverPeer) {
 	if sp.VerAckReceived() && sp.VersionKnown() && sp.NA() != nil {
 		s.addrManager.Connected(sp.NA())
 	}
-
-	// If we get here it means that either we didn't know about the peer
-	// or we purposefully deleted it.
+
+	// If we get here it means that either we didn't know about the peer
+	// or we purposefully deleted it.
+}
+
+// handleBanPeerMsg deals with banning peers.  It is invoked from the
+// peerHandler goroutine.
+func (s *server) handleBanPeerMsg(state *peerState, sp *serverPeer) {
+	if sp.ipBanExempt() {
+		srvrLog.Infof("Not banning peer %s since it is connected over a "+
+			"unix domain socket", sp)
+		return
+	}
+
+	host, _, err := net.SplitHostPort(sp.Addr())
+	if err != nil {
+		srvrLog.Debugf("can't split ban peer %s %v", sp.Addr(), err)
+		return
+	}
+	direction := directionString(sp.Inbound())
+	srvrLog.Infof("Banned peer %s (%s) for %v", host, direction,
+		cfg.BanDuration)
+	now := time.Now()
+	state.banned[host] = now.Add(cfg.BanDuration)
+
+	// Escalate to banning the entire subnet of the peer when enough
+	// distinct IPs within it have recently been banned.  Loopback addresses
+	// are ignored since they all belong to the local host.
+	ip := net.ParseIP(host)
+	if cfg.BanEscalation == 0 || ip == nil || ip.IsLoopback() {
+		return
+	}
+	subnet := state.recordBan(ip, now, cfg.BanEscalation,
+		cfg.BanEscalationWindow)
+	if subnet != nil {
+		srvrLog.Infof("Escalating to a subnet ban of %s after banning %d "+
+			"distinct peers within it", subnet, cfg.BanEscalation)
+		s.handleBanSubnet(state, subnet, cfg.BanDuration)
+	}
+}
+
+// handleBanSubnet bans all peers with an IP address in the provided subnet for
+// the given duration and disconnects any that are currently connected other
+// than those that are exempt from subnet bans.  It is invoked from the
+// peerHandler goroutine.
+func (s *server) handleBanSubnet(state *peerState, ipNet *net.IPNet, duration time.Duration) {
+	key := ipNet.String()
+	banEnd := time.Now().Add(duration)
+	state.bannedSubnets[key] = bannedSubnet{ipNet: ipNet, banEnd: banEnd}
+	srvrLog.Infof("Banned subnet %s for %v", key, duration)
+
+	// Disconnect any connected peers within the newly banned subnet.
+	state.forAllPeers(func(sp *serverPeer) {
+		if sp.subnetBanExempt() || sp.ipBanExempt() {
+			return
+		}
+		if na := sp.NA(); na != nil && ipNet.Contains(na.IP) {
+			srvrLog.Infof("Disconnecting peer %s in banned subnet %s", sp,
+				key)
+			sp.Disconnect()
+		}
+	})
+}
+
+// handleSetBan manually bans the provided IP address or subnet for the given
+// duration and disconnects any matching peers that are currently connected.
+// Networks that only contain a single IP address are banned as a host rather
+// than a subnet.  It is invoked from the peerHandler goroutine.
+func (s *server) handleSetBan(state *peerState, ipNet *net.IPNet, duration time.Duration) {
+	ones, bits := ipNet.Mask.Size()
+	if ones != bits {
+		s.handleBanSubnet(state, ipNet, duration)
+		return
+	}
+
+	host := ipNet.IP.String()
+	state.banned[host] = time.Now().Add(duration)
+	srvrLog.Infof("Banned peer %s for %v", host, duration)
+
+	// Disconnect any connected peers with the newly banned address.
+	state.forAllPeers(func(sp *serverPeer) {
+		if sp.ipBanExempt() {
+			return
+		}
+		if na := sp.NA(); na != nil && ipNet.IP.Equal(na.IP) {
+			srvrLog.Infof("Disconnecting banned peer %s", sp)
+			sp.Disconnect()
+		}
+	})
+}
+
+// handleClearBan lifts the ban of the provided IP address or subnet.  An error
+// is returned when it is not currently banned.  It is invoked from the
+// peerHandler goroutine.
+func (s *server) handleClearBan(state *peerState, ipNet *net.IPNet) error {
+	now := time.Now()
+	var found bool
+
+	// Networks that only contain a single IP address may have been banned
+	// as either a host or a subnet.
+	if ones, bits := ipNet.Mask.Size(); ones == bits {
+		host := ipNet.IP.String()
+		if banEnd, ok := state.banned[host]; ok {
+			delete(state.banned, host)
+			found = now.Before(banEnd)
+		}
+	}
+	key := ipNet.String()
+	if ban, ok := state.bannedSubnets[key]; ok {
+		delete(state.bannedSubnets, key)
+		found = found || now.Before(ban.banEnd)
+	}
+	if !found {
+		return fmt.Errorf("address %s is not banned", ipNet)
+	}
+
+	srvrLog.Infof("Lifted ban of %s", ipNet)
+	return nil
+}
+
+// announceBlock announces the block in the provided relay message to the peer
+// via the given compact block or headers announcement mode unless the peer is
+// already known to have the block.  The compact block is only generated once
+// for all peers and is stored in the provided cmpctBlock parameter.  It is
+// invoked from the peerHandler goroutine.
+func (s *server) announceBlock(sp *serverPeer, mode peer.BlockAnnounceMode, msg relayMsg, cmpctBlock **wire.MsgCmpctBlock) {
+	if sp.IsKnownInventory(msg.invVect) {
+		return
+	}
+	block, ok := msg.data.(*dcrutil.Block)
+	if !ok {
+		peerLog.Warnf("Underlying data for block announcement is not a " +
+			"block")
+		return
+	}
+
+	switch mode {
+	case peer.BlockAnnounceCmpct:
+		// The same compact block is sent to all peers so the short ids
+		// are only calculated once.
+		if *cmpctBlock == nil {
+			nonce, err := wire.RandomUint64()
+			if err != nil {
+				peerLog.Errorf("Failed to generate compact block "+
+					"nonce: %v", err)
+				return
+			}
+			*cmpctBlock = wire.NewMsgCmpctBlockFromBlock(block.MsgBlock(),
+				nonce)
+		}
+		sp.AddKnownInventory(msg.invVect)
+		sp.QueueMessage(*cmpctBlock, nil)
+
+	case peer.BlockAnnounceHeaders:
+		msgHeaders := wire.NewMsgHeaders()
+		blockHeader := &block.MsgBlock().Header
+		if err := msgHeaders.AddBlockHeader(blockHeader); err != nil {
+			peerLog.Errorf("Failed to add block header: %v", err)
+			return
+		}
+		sp.AddKnownInventory(msg.invVect)
+		sp.QueueMessage(msgHeaders, nil)
+	}
 }
 
-// handleBanPeerMsg deals with banning peers.  It is invoked from the
-// peerHandler goroutine.
-func (s *server) handleBanPeerMsg(state *peerState, sp *serverPeer) {
-	host, _, err := net.SplitHostPort(sp.Addr())
-	if err != nil {
-		srvrLog.Debugf("can't split ban peer %s %v", sp.Addr(), err)
-		return
-	}
-	direction := directionString(sp.Inbound())
-	srvrLog.Infof("Banned peer %s (%s) for %v", host, direction,
-		cfg.BanDuration)
-	state.banned[host] = time.Now().Add(cfg.BanDuration)
+// isNoRelayTxType returns whether or not transactions of the passed type are
+// configured to not be relayed to peers.
+func isNoRelayTxType(txType stake.TxType) bool {
+	_, ok := cfg.noRelayTxTypes[txType]
+	return ok
 }
 
 // handleRelayInvMsg deals with relaying inventory to peers that are not already
 // known to have it.  It is invoked from the peerHandler goroutine.
 func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
+	// Don't relay transactions of types that are configured to not be
+	// relayed.
+	if msg.invVect.Type == wire.InvTypeTx && len(cfg.noRelayTxTypes) > 0 {
+		if tx, ok := msg.data.(*dcrutil.Tx); ok {
+			if isNoRelayTxType(stake.DetermineTxType(tx.MsgTx())) {
+				srvrLog.Tracef("Not relaying tx %v due to relay policy",
+					tx.Hash())
+				return
+			}
+		}
+	}
+
+	var cmpctBlock *wire.MsgCmpctBlock
+	now := time.Now()
 	state.forAllPeers(func(sp *serverPeer) {
 		if !sp.Connected() {
 			return
 		}
 
-		// If the inventory is a block and the peer prefers headers,
-		// generate and send a headers message instead of an inventory
-		// message.
-		if msg.invVect.Type == wire.InvTypeBlock && sp.WantsHeaders() {
-			blockHeader, ok := msg.data.(wire.BlockHeader)
-			if !ok {
-				peerLog.Warnf("Underlying data for headers" +
-					" is not a block header")
-				return
-			}
-			msgHeaders := wire.NewMsgHeaders()
-			if err := msgHeaders.AddBlockHeader(&blockHeader); err != nil {
-				peerLog.Errorf("Failed to add block"+
-					" header: %v", err)
+		// Don't relay the inventory back to the peer it was just received
+		// from.
+		if sp.recentInv.contains(msg.invVect, now) {
+			return
+		}
+
+		// Announce blocks using the method preferred by the peer.  Peers
+		// that prefer compact blocks or headers are sent a cmpctblock or
+		// headers message, respectively, instead of an inventory message.
+		if msg.invVect.Type == wire.InvTypeBlock {
+			mode := sp.BlockAnnounceMode()
+			if mode != peer.BlockAnnounceInv {
+				s.announceBlock(sp, mode, msg, &cmpctBlock)
 				return
 			}
-			sp.QueueMessage(msgHeaders, nil)
-			return
 		}
 
 		if msg.invVect.Type == wire.InvTypeTx {
@@ -1600,6 +3092,21 @@ type getConnCountMsg struct {
 	reply chan int32
 }
 
+type getConnCountsMsg struct {
+	reply chan connCounts
+}
+
+// connCounts houses the number of connected inbound and outbound peers.
+type connCounts struct {
+	inbound  int32
+	outbound int32
+}
+
+type dropAllPeersMsg struct {
+	excludePersistent bool
+	reply             chan int
+}
+
 type getPeersMsg struct {
 	reply chan []*serverPeer
 }
@@ -1629,6 +3136,27 @@ type removeNodeMsg struct {
 	reply chan error
 }
 
+type setBanMsg struct {
+	ipNet    *net.IPNet
+	duration time.Duration
+	reply    chan struct{}
+}
+
+type clearBanMsg struct {
+	ipNet *net.IPNet
+	reply chan error
+}
+
+type getBannedPeersMsg struct {
+	reply chan map[string]time.Time
+}
+
+type banSubnetMsg struct {
+	ipNet    *net.IPNet
+	duration time.Duration
+	reply    chan struct{}
+}
+
 // handleQuery is the central handler for all queries and commands from other
 // goroutines related to peer state.
 func (s *server) handleQuery(state *peerState, querymsg interface{}) {
@@ -1642,6 +3170,38 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		})
 		msg.reply <- nconnected
 
+	case dropAllPeersMsg:
+		// The peers are only told to disconnect here.  They are removed
+		// from the peer state once they are done, the same as any other
+		// disconnected peer, which also ensures persistent peers are
+		// reconnected.
+		var dropped int
+		state.forAllPeers(func(sp *serverPeer) {
+			if !sp.Connected() {
+				return
+			}
+			if msg.excludePersistent && sp.persistent {
+				return
+			}
+			sp.Disconnect()
+			dropped++
+		})
+		msg.reply <- dropped
+
+	case getConnCountsMsg:
+		var counts connCounts
+		state.forAllPeers(func(sp *serverPeer) {
+			if !sp.Connected() {
+				return
+			}
+			if sp.Inbound() {
+				counts.inbound++
+			} else {
+				counts.outbound++
+			}
+		})
+		msg.reply <- counts
+
 	case getPeersMsg:
 		peers := make([]*serverPeer, 0, state.Count())
 		state.forAllPeers(func(sp *serverPeer) {
@@ -1654,11 +3214,6 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 
 	case connectNodeMsg:
 		// XXX duplicate oneshots?
-		// Limit max number of total peers.
-		if state.Count() >= cfg.MaxPeers {
-			msg.reply <- errors.New("max peers reached")
-			return
-		}
 		for _, peer := range state.persistentPeers {
 			if peer.Addr() == msg.addr {
 				if msg.permanent {
@@ -1676,6 +3231,13 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 			return
 		}
 
+		// Limit max number of total peers.  However, allow whitelisted
+		// peers regardless since they are exempt from connection limits.
+		if state.Count() >= reloadable.MaxPeers() && !s.isWhitelisted(netAddr) {
+			msg.reply <- errors.New("max peers reached")
+			return
+		}
+
 		// TODO: if too many, nuke a non-perm peer.
 		go s.connManager.Connect(&connmgr.ConnReq{
 			Addr:      netAddr,
@@ -1703,6 +3265,35 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		} else {
 			msg.reply <- errors.New("peer not found")
 		}
+	case banSubnetMsg:
+		s.handleBanSubnet(state, msg.ipNet, msg.duration)
+		msg.reply <- struct{}{}
+
+	case setBanMsg:
+		s.handleSetBan(state, msg.ipNet, msg.duration)
+		msg.reply <- struct{}{}
+
+	case clearBanMsg:
+		msg.reply <- s.handleClearBan(state, msg.ipNet)
+
+	case getBannedPeersMsg:
+		// Respond with a snapshot of the hosts and subnets whose bans have
+		// not yet ended.
+		now := time.Now()
+		banned := make(map[string]time.Time, len(state.banned)+
+			len(state.bannedSubnets))
+		for host, banEnd := range state.banned {
+			if now.Before(banEnd) {
+				banned[host] = banEnd
+			}
+		}
+		for key, ban := range state.bannedSubnets {
+			if now.Before(ban.banEnd) {
+				banned[key] = ban.banEnd
+			}
+		}
+		msg.reply <- banned
+
 	case getOutboundGroup:
 		count, ok := state.outboundGroups[msg.key]
 		if ok {
@@ -1710,6 +3301,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		} else {
 			msg.reply <- 0
 		}
+
 	// Request a list of the persistent (added) peers.
 	case getAddedNodesMsg:
 		// Respond with a slice of the relevant peers.
@@ -1791,6 +3383,9 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			OnBlock:          sp.OnBlock,
 			OnInv:            sp.OnInv,
 			OnHeaders:        sp.OnHeaders,
+			OnCmpctBlock:     sp.OnCmpctBlock,
+			OnGetBlockTxns:   sp.OnGetBlockTxns,
+			OnBlockTxns:      sp.OnBlockTxns,
 			OnGetData:        sp.OnGetData,
 			OnGetBlocks:      sp.OnGetBlocks,
 			OnGetHeaders:     sp.OnGetHeaders,
@@ -1812,6 +3407,8 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 		Services:          sp.server.services,
 		DisableRelayTx:    cfg.BlocksOnly,
 		ProtocolVersion:   maxProtocolVersion,
+		PingInterval:      cfg.PingInterval,
+		MaxMissedPongs:    cfg.MaxMissedPongs,
 	}
 }
 
@@ -1821,7 +3418,8 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 // for disconnection.
 func (s *server) inboundPeerConnected(conn net.Conn) {
 	sp := newServerPeer(s, false)
-	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	sp.isWhitelisted = s.isWhitelisted(conn.RemoteAddr())
+	_, sp.isUnixSocket = conn.RemoteAddr().(*net.UnixAddr)
 	sp.Peer = peer.NewInboundPeer(newPeerConfig(sp))
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
@@ -1842,16 +3440,33 @@ func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	}
 	sp.Peer = p
 	sp.connReq = c
-	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	sp.isWhitelisted = s.isWhitelisted(conn.RemoteAddr())
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
 	s.addrManager.Attempt(sp.NA())
 }
 
+// outboundPeerFailed is invoked by the connection manager when an outbound
+// connection attempt fails.  It records the failure in the address manager so
+// addresses that are repeatedly unreachable are evicted.
+func (s *server) outboundPeerFailed(c *connmgr.ConnReq, _ error) {
+	na, err := s.addrManager.DeserializeNetAddress(c.Addr.String())
+	if err != nil {
+		return
+	}
+	s.addrManager.Failed(na, cfg.AddrFailureThreshold)
+}
+
 // peerDoneHandler handles peer disconnects by notifying the server that it's
 // done.
 func (s *server) peerDoneHandler(sp *serverPeer) {
 	sp.WaitForDisconnect()
+
+	// Outbound connections that never completed the version handshake are
+	// treated as failed connections to the address.
+	if !sp.Inbound() && !sp.VersionKnown() {
+		s.addrManager.Failed(sp.NA(), cfg.AddrFailureThreshold)
+	}
 	s.donePeers <- sp
 
 	// Only tell block manager we are gone if we ever told it we existed.
@@ -1861,6 +3476,28 @@ func (s *server) peerDoneHandler(sp *serverPeer) {
 	close(sp.quit)
 }
 
+// dnsSeedHosts returns the hosts of the DNS seeds to query for peers by merging
+// the provided built-in seeds for the active network with the custom seeds.
+// The built-in seeds are omitted when they are replaced by the custom seeds and
+// built-in seeds that are unable to filter by services are skipped when
+// filtering is required since they would otherwise fill the address manager
+// with peers that potentially lack the required services.  Custom seeds are
+// assumed to support filtering.
+func dnsSeedHosts(builtin []chaincfg.DNSSeed, custom []string, replace, filter bool) []string {
+	seeds := make([]string, 0, len(builtin)+len(custom))
+	if !replace {
+		for _, seed := range builtin {
+			if filter && !seed.HasFiltering {
+				srvrLog.Debugf("Skipping DNS seed %s since it does not "+
+					"support filtering by services", seed.Host)
+				continue
+			}
+			seeds = append(seeds, seed.Host)
+		}
+	}
+	return append(seeds, custom...)
+}
+
 // peerHandler is used to handle peer operations such as adding and removing
 // peers to and from the server, banning peers, and broadcasting messages to
 // peers.  It must be run in a goroutine.
@@ -1880,22 +3517,28 @@ func (s *server) peerHandler() {
 		persistentPeers: make(map[int32]*serverPeer),
 		outboundPeers:   make(map[int32]*serverPeer),
 		banned:          make(map[string]time.Time),
+		bannedSubnets:   make(map[string]bannedSubnet),
+		recentBans:      make(map[string]map[string]time.Time),
 		outboundGroups:  make(map[string]int),
 		suggestions: map[addrmgr.NetworkAddress]map[string]int32{
 			addrmgr.IPv4Address: make(map[string]int32),
 			addrmgr.IPv6Address: make(map[string]int32),
 		},
 	}
+	rand.Read(state.netGroupSalt[:])
 
-	if !cfg.DisableDNSSeed {
+	// DNS seeds only provide addresses that are not tor hidden services, so
+	// there is no point in querying them when only connecting to hidden
+	// services.
+	if !cfg.DisableDNSSeed && !cfg.OnionOnly {
 		// Add peers discovered through DNS to the address manager.
 		params := activeNetParams.Params
-		seeds := make([]string, 0, len(params.DNSSeeds))
-		for _, seed := range params.DNSSeeds {
-			seeds = append(seeds, seed.Host)
-		}
+		reqServices := cfg.dnsSeedServices
+		filter := reqServices != defaultRequiredServices
+		seeds := dnsSeedHosts(params.DNSSeeds, cfg.DNSSeeds,
+			cfg.ReplaceDNSSeeds, filter)
 		defaultPort, _ := strconv.Atoi(params.DefaultPort)
-		connmgr.SeedFromDNS(seeds, uint16(defaultPort), defaultRequiredServices,
+		connmgr.SeedFromDNS(seeds, uint16(defaultPort), reqServices,
 			dcrdLookup, func(addrs []*wire.NetAddress) {
 				// Bitcoind uses a lookup of the dns seeder here. This
 				// is rather strange since the values looked up by the
@@ -2005,6 +3648,31 @@ func (s *server) ConnectedCount() int32 {
 	return <-replyChan
 }
 
+// DropAllPeers disconnects all connected peers, optionally excluding persistent
+// peers, so that new connections are made from scratch.  It returns the number
+// of peers that were disconnected.
+func (s *server) DropAllPeers(excludePersistent bool) int {
+	replyChan := make(chan int)
+
+	s.query <- dropAllPeersMsg{
+		excludePersistent: excludePersistent,
+		reply:             replyChan,
+	}
+
+	return <-replyChan
+}
+
+// ConnectedCounts returns the number of currently connected inbound and
+// outbound peers, respectively.
+func (s *server) ConnectedCounts() (int32, int32) {
+	replyChan := make(chan connCounts)
+
+	s.query <- getConnCountsMsg{reply: replyChan}
+
+	counts := <-replyChan
+	return counts.inbound, counts.outbound
+}
+
 // OutboundGroupCount returns the number of peers connected to the given
 // outbound group key.
 func (s *server) OutboundGroupCount(key string) int {
@@ -2095,6 +3763,46 @@ func (s *server) ConnectNode(addr string, permanent bool) error {
 	return <-replyChan
 }
 
+// BanSubnet bans all peers with an IP address in the provided subnet for the
+// given duration and disconnects any that are currently connected.
+func (s *server) BanSubnet(ipNet *net.IPNet, duration time.Duration) {
+	replyChan := make(chan struct{})
+
+	s.query <- banSubnetMsg{ipNet: ipNet, duration: duration, reply: replyChan}
+
+	<-replyChan
+}
+
+// SetBan bans the provided IP address or subnet for the given duration and
+// disconnects any matching peers that are currently connected.
+func (s *server) SetBan(ipNet *net.IPNet, duration time.Duration) {
+	replyChan := make(chan struct{})
+
+	s.query <- setBanMsg{ipNet: ipNet, duration: duration, reply: replyChan}
+
+	<-replyChan
+}
+
+// ClearBan lifts the ban of the provided IP address or subnet.  An error is
+// returned when it is not currently banned.
+func (s *server) ClearBan(ipNet *net.IPNet) error {
+	replyChan := make(chan error)
+
+	s.query <- clearBanMsg{ipNet: ipNet, reply: replyChan}
+
+	return <-replyChan
+}
+
+// BannedPeers returns the hosts and subnets that are currently banned along with
+// when their bans end.  Subnets are keyed by their CIDR notation.
+func (s *server) BannedPeers() map[string]time.Time {
+	replyChan := make(chan map[string]time.Time)
+
+	s.query <- getBannedPeersMsg{reply: replyChan}
+
+	return <-replyChan
+}
+
 // AddBytesSent adds the passed number of bytes to the total bytes sent counter
 // for the server.  It is safe for concurrent access.
 func (s *server) AddBytesSent(bytesSent uint64) {
@@ -2130,8 +3838,13 @@ func (s *server) UpdatePeerHeights(latestBlkHash *chainhash.Hash, latestHeight i
 // sent out but have not yet made it into a block. We periodically rebroadcast
 // them in case our peers restarted or otherwise lost track of them.
 func (s *server) rebroadcastHandler() {
-	// Wait 5 min before first tx rebroadcast.
-	timer := time.NewTimer(5 * time.Minute)
+	// Wait for the configured interval before the first tx rebroadcast or 5
+	// min when no interval is configured.
+	firstTimeout := 5 * time.Minute
+	if cfg.RebroadcastInterval != 0 {
+		firstTimeout = rebroadcastTimeout(cfg.RebroadcastInterval)
+	}
+	timer := time.NewTimer(firstTimeout)
 	pendingInvs := make(map[wire.InvVect]interface{})
 
 out:
@@ -2151,6 +3864,10 @@ out:
 				delete(pendingInvs, *msg)
 
 			case broadcastPruneInventory:
+				// Remove any regular transactions that have already been
+				// mined in case their confirmation was missed.
+				pruneMinedRebroadcastTxns(pendingInvs, s.txMined)
+
 				best := s.chain.BestSnapshot()
 				nextStakeDiff, err :=
 					s.chain.CalcNextRequiredStakeDifficulty()
@@ -2206,16 +3923,14 @@ out:
 
 		case <-timer.C:
 			// Any inventory we have has not made it into a block
-			// yet. We periodically resubmit them until they have.
-			for iv, data := range pendingInvs {
-				ivCopy := iv
-				s.RelayInventory(&ivCopy, data, false)
-			}
+			// yet. We periodically resubmit them until they have,
+			// skipping any transactions that have since been mined.
+			rebroadcastPendingInvs(pendingInvs, s.txMined,
+				func(iv *wire.InvVect, data interface{}) {
+					s.RelayInventory(iv, data, false)
+				})
 
-			// Process at a random time up to 30mins (in seconds)
-			// in the future.
-			timer.Reset(time.Second *
-				time.Duration(randomUint16Number(1800)))
+			timer.Reset(rebroadcastTimeout(cfg.RebroadcastInterval))
 
 		case <-s.quit:
 			break out
@@ -2256,6 +3971,19 @@ func (s *server) Start() {
 		go s.upnpUpdateThread()
 	}
 
+	// Start the feeler connection handler when automatic outbound
+	// connections are made.  See the comments in newServer for why this is
+	// never the case on the simulation test network.  Feeler connections are
+	// also not made when only connecting to tor hidden services since they
+	// would otherwise count the refused connections to other addresses as
+	// failures.
+	if !cfg.SimNet && len(cfg.ConnectPeers) == 0 && cfg.FeelerInterval > 0 &&
+		!cfg.OnionOnly {
+
+		s.wg.Add(1)
+		go s.feelerHandler()
+	}
+
 	if !cfg.DisableRPC {
 		s.wg.Add(1)
 
@@ -2332,6 +4060,11 @@ func parseListeners(addrs []string) ([]string, []string, bool, error) {
 	haveWildcard := false
 
 	for _, addr := range addrs {
+		// Unix domain socket addresses are handled separately.
+		if _, ok := unixListenPath(addr); ok {
+			continue
+		}
+
 		host, _, err := net.SplitHostPort(addr)
 		if err != nil {
 			// Shouldn't happen due to already being normalized.
@@ -2371,6 +4104,168 @@ func parseListeners(addrs []string) ([]string, []string, bool, error) {
 	return ipv4ListenAddrs, ipv6ListenAddrs, haveWildcard, nil
 }
 
+// parseUnixListeners returns the unix domain socket paths specified by the
+// list of listen addresses passed in addrs.
+func parseUnixListeners(addrs []string) []string {
+	var paths []string
+	for _, addr := range addrs {
+		if socketPath, ok := unixListenPath(addr); ok {
+			paths = append(paths, socketPath)
+		}
+	}
+	return paths
+}
+
+// listenUnix listens on the unix domain socket at the provided path using the
+// passed listen function.  A stale socket file left behind by an unclean
+// shutdown is removed first, while a socket that is still being listened on
+// results in an error.
+func listenUnix(listenFunc func(string, string) (net.Listener, error), socketPath string) (net.Listener, error) {
+	if fi, err := os.Lstat(socketPath); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		conn, err := net.Dial("unix", socketPath)
+		if err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("unix socket %s is already in use",
+				socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, err
+		}
+	}
+	return listenFunc("unix", socketPath)
+}
+
+// dialFeeler connects to the passed address using the dial function while
+// giving up once the timeout elapses or the quit channel is closed.  Any
+// connection established after giving up is closed.
+func dialFeeler(dial func(string, string) (net.Conn, error), addr string,
+	timeout time.Duration, quit <-chan struct{}) (net.Conn, error) {
+
+	type dialResult struct {
+		conn net.Conn
+		err  error
+	}
+	result := make(chan dialResult, 1)
+	go func() {
+		conn, err := dial("tcp", addr)
+		result <- dialResult{conn, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case r := <-result:
+		return r.conn, r.err
+	case <-timer.C:
+		err = fmt.Errorf("dial timed out after %v", timeout)
+	case <-quit:
+		err = errors.New("server shutting down")
+	}
+	go func() {
+		if r := <-result; r.conn != nil {
+			r.conn.Close()
+		}
+	}()
+	return nil, err
+}
+
+// feelerConnect makes a short-lived feeler connection to a random address in
+// the new table of the address manager, which houses addresses that have not
+// been successfully connected to yet, and waits for it to disconnect.  The
+// address is promoted to the tried table when the remote node completes the
+// version handshake and is otherwise marked as failed, including when the
+// remote node is rejected due to its protocol version or services.
+func (s *server) feelerConnect() {
+	ka := s.addrManager.GetNewTableAddress()
+	if ka == nil {
+		return
+	}
+
+	// Avoid addresses in network groups that are already used by outbound
+	// peers for the same reasons regular outbound connections do.
+	na := ka.NetAddress()
+	if s.OutboundGroupCount(addrmgr.GroupKey(na)) != 0 {
+		return
+	}
+
+	addrString := addrmgr.NetAddressKey(na)
+	s.addrManager.Attempt(na)
+	conn, err := dialFeeler(dcrdDial, addrString, feelerDialTimeout, s.quit)
+	if err != nil {
+		srvrLog.Debugf("Feeler connection to %s failed: %v", addrString, err)
+		select {
+		case <-s.quit:
+		default:
+			s.addrManager.Failed(na, cfg.AddrFailureThreshold)
+		}
+		return
+	}
+
+	sp := newServerPeer(s, false)
+	sp.isFeeler = true
+	p, err := peer.NewOutboundPeer(newPeerConfig(sp), addrString)
+	if err != nil {
+		srvrLog.Debugf("Cannot create feeler peer %s: %v", addrString, err)
+		conn.Close()
+		return
+	}
+	sp.Peer = p
+	sp.AssociateConnection(conn)
+
+	// The peer is disconnected by the version handler once the handshake
+	// succeeds or by the peer negotiation logic when it fails or times out.
+	done := make(chan struct{})
+	go func() {
+		sp.WaitForDisconnect()
+		close(done)
+	}()
+	defer close(sp.quit)
+	select {
+	case <-done:
+	case <-s.quit:
+		sp.Disconnect()
+		<-done
+		return
+	}
+	if !sp.feelerGood {
+		s.addrManager.Failed(na, cfg.AddrFailureThreshold)
+		return
+	}
+	s.addrManager.Good(na)
+}
+
+// feelerHandler periodically makes feeler connections to addresses in the
+// address manager that have not been successfully connected to yet in order to
+// keep it populated with addresses that are known to be reachable.  Feeler
+// connections are only made while all of the outbound peer slots are full
+// since the address manager is otherwise already exercised by the regular
+// outbound connection attempts.
+//
+// It must be run as a goroutine.
+func (s *server) feelerHandler() {
+	ticker := time.NewTicker(cfg.FeelerInterval)
+	defer ticker.Stop()
+
+out:
+	for {
+		select {
+		case <-ticker.C:
+			_, numOutbound := s.ConnectedCounts()
+			if int(numOutbound) < targetOutboundPeers() {
+				continue
+			}
+			s.feelerConnect()
+
+		case <-s.quit:
+			break out
+		}
+	}
+
+	s.wg.Done()
+}
+
 func (s *server) upnpUpdateThread() {
 	// Go off immediately to prevent code duplication, thereafter we renew
 	// lease every 15 minutes.
@@ -2447,6 +4342,76 @@ func standardScriptVerifyFlags(chain *blockchain.BlockChain) (txscript.ScriptFla
 	return scriptFlags, nil
 }
 
+// advertisedServices returns the services to advertise to peers given the
+// services the node supports and the services explicitly configured to be
+// advertised.  All supported services are advertised when no services are
+// configured.  An error is returned when the configured services include any
+// the node is unable to provide.
+func advertisedServices(supported, configured wire.ServiceFlag) (wire.ServiceFlag, error) {
+	if configured == 0 {
+		return supported, nil
+	}
+
+	// Full nodes are also able to serve the most recent blocks.
+	if hasServices(supported, wire.SFNodeNetwork) {
+		supported |= wire.SFNodeNetworkLimited
+	}
+
+	if hasServices(configured, wire.SFNodeNetwork|wire.SFNodeNetworkLimited) {
+		return 0, fmt.Errorf("the %v and %v services may not be advertised "+
+			"together", wire.SFNodeNetwork, wire.SFNodeNetworkLimited)
+	}
+	if unsupported := configured &^ supported; unsupported != 0 {
+		return 0, fmt.Errorf("unable to advertise unsupported services %v",
+			unsupported)
+	}
+	return configured, nil
+}
+
+// reloadConfig reloads the subset of the configuration that may be changed
+// while the server is running from the config file and command line options and
+// applies the new values.  The current values are retained and the error is
+// logged when any of the reloaded values are invalid.
+//
+// The reloadable options are the ban threshold, maximum number of peers,
+// minimum relay transaction fee, and whitelisted networks.  All other options
+// require a restart.
+//
+// The minimum relay transaction fee is applied to both the memory pool and the
+// block template generator so they remain consistent.  However, the fee
+// estimator buckets, target number of outbound peers, and the sizes of the
+// peer-related channel buffers are based on the values at startup since
+// changing them requires rebuilding the fee estimator database and the
+// connection manager, respectively.
+func (s *server) reloadConfig() {
+	reloaded, err := loadReloadableConfig()
+	if err != nil {
+		srvrLog.Errorf("Unable to reload configuration: %v", err)
+		return
+	}
+
+	reloadable.set(reloaded.banThreshold, reloaded.maxPeers,
+		reloaded.minRelayTxFee)
+	s.whitelist.replace(reloaded.whitelists)
+	s.txMemPool.SetMinRelayTxFee(reloaded.minRelayTxFee)
+	s.tmplGenerator.SetTxMinFreeFee(reloaded.minRelayTxFee)
+
+	srvrLog.Infof("Reloaded configuration: banthreshold=%d, maxpeers=%d, "+
+		"minrelaytxfee=%v, %d whitelisted networks", reloaded.banThreshold,
+		reloaded.maxPeers, reloaded.minRelayTxFee, len(reloaded.whitelists))
+}
+
+// targetOutboundPeers returns the target number of outbound peers, which is
+// the default target limited by the maximum number of peers.  It is based on
+// the maximum number of peers at startup since the target of the connection
+// manager can't be changed while it is running.
+func targetOutboundPeers() int {
+	if cfg.MaxPeers < defaultTargetOutbound {
+		return cfg.MaxPeers
+	}
+	return defaultTargetOutbound
+}
+
 // newServer returns a new dcrd server configured to listen on addr for the
 // Decred network type specified by chainParams.  Use start to begin accepting
 // connections from peers.
@@ -2455,6 +4420,15 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 	if cfg.NoCFilters {
 		services &^= wire.SFNodeCF
 	}
+	if cfg.RetainBlocks > 0 {
+		services &^= wire.SFNodeNetwork
+		services |= wire.SFNodeNetworkLimited
+	}
+	services, err := advertisedServices(services, cfg.advertiseServices)
+	if err != nil {
+		return nil, err
+	}
+	reloadable.set(cfg.BanThreshold, cfg.MaxPeers, cfg.minRelayTxFee)
 
 	amgr := addrmgr.New(cfg.DataDir, dcrdLookup)
 
@@ -2466,7 +4440,9 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 		if err != nil {
 			return nil, err
 		}
-		listeners = make([]net.Listener, 0, len(ipv4Addrs)+len(ipv6Addrs))
+		unixPaths := parseUnixListeners(listenAddrs)
+		listeners = make([]net.Listener, 0,
+			len(ipv4Addrs)+len(ipv6Addrs)+len(unixPaths))
 		discover := true
 		if len(cfg.ExternalIPs) != 0 {
 			discover = false
@@ -2580,6 +4556,18 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 			}
 		}
 
+		// Unix domain sockets are only reachable from the local host, so
+		// they are never advertised as local addresses.
+		for _, socketPath := range unixPaths {
+			listener, err := listenUnix(net.Listen, socketPath)
+			if err != nil {
+				srvrLog.Warnf("Can't listen on %s: %v", socketPath,
+					err)
+				continue
+			}
+			listeners = append(listeners, listener)
+		}
+
 		if len(listeners) == 0 {
 			return nil, errors.New("no valid listen address")
 		}
@@ -2600,12 +4588,13 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 		peerHeightsUpdate:    make(chan updatePeerHeightsMsg),
 		nat:                  nat,
 		db:                   db,
-		timeSource:           blockchain.NewMedianTime(),
+		timeSource:           blockchain.NewMedianTimeWithMaxOffset(cfg.MaxTimeAdjustment),
 		services:             services,
 		sigCache:             txscript.NewSigCache(cfg.SigCacheMaxSize),
 		subsidyCache:         standalone.NewSubsidyCache(chainParams),
 		context:              ctx,
 		cancel:               cancel,
+		whitelist:            ipWhitelist{nets: cfg.whitelists},
 	}
 
 	// Create the transaction and address indexes if needed.
@@ -2682,9 +4671,11 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 				s.blockManager.handleBlockchainNotification(notification)
 			}
 		},
-		SigCache:     s.sigCache,
-		SubsidyCache: s.subsidyCache,
-		IndexManager: indexManager,
+		SigCache:         s.sigCache,
+		UtxoCacheMaxSize: cfg.UtxoCacheMaxSize * 1024 * 1024,
+		AssumeValid:      cfg.assumeValid,
+		SubsidyCache:     s.subsidyCache,
+		IndexManager:     indexManager,
 	})
 	if err != nil {
 		return nil, err
@@ -2760,13 +4751,15 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 	// NOTE: The CPU miner relies on the mempool, so the mempool has to be
 	// created before calling the function to create the CPU miner.
 	policy := mining.Policy{
-		BlockMinSize:      cfg.BlockMinSize,
-		BlockMaxSize:      cfg.BlockMaxSize,
-		BlockPrioritySize: cfg.BlockPrioritySize,
-		TxMinFreeFee:      cfg.minRelayTxFee,
+		BlockMinSize:         cfg.BlockMinSize,
+		BlockMaxSize:         cfg.BlockMaxSize,
+		BlockPrioritySize:    cfg.BlockPrioritySize,
+		TxMinFreeFee:         cfg.minRelayTxFee,
+		DeterministicTxOrder: cfg.DeterministicTxOrder,
 	}
 	tg := newBlkTmplGenerator(&policy, s.txMemPool, s.timeSource, s.sigCache,
 		s.subsidyCache, s.chainParams, s.chain, s.blockManager)
+	s.tmplGenerator = tg
 
 	// Create the background block template generator if the config has a
 	// mining address.
@@ -2798,15 +4791,25 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 				if addr == nil {
 					break
 				}
+				addrString := addrmgr.NetAddressKey(addr.NetAddress())
+
+				// Only connect to tor hidden services when
+				// requested.
+				if cfg.OnionOnly && !strings.Contains(addrString, ".onion:") {
+					continue
+				}
 
 				// Address will not be invalid, local or unroutable
 				// because addrmanager rejects those on addition.
 				// Just check that we don't already have an address
 				// in the same group so that we are not connecting
 				// to the same network segment at the expense of
-				// others.
+				// others.  Whitelisted addresses are exempt since
+				// they are trusted, even when the remaining outbound
+				// slots are reserved for new network groups.
 				key := addrmgr.GroupKey(addr.NetAddress())
-				if s.OutboundGroupCount(key) != 0 {
+				if s.OutboundGroupCount(key) != 0 &&
+					!s.whitelist.contains(addr.NetAddress().IP) {
 					continue
 				}
 
@@ -2822,7 +4825,6 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 					continue
 				}
 
-				addrString := addrmgr.NetAddressKey(addr.NetAddress())
 				return addrStringToNetAddr(addrString)
 			}
 
@@ -2831,18 +4833,18 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 	}
 
 	// Create a connection manager.
-	targetOutbound := defaultTargetOutbound
-	if cfg.MaxPeers < targetOutbound {
-		targetOutbound = cfg.MaxPeers
-	}
+	targetOutbound := targetOutboundPeers()
 	cmgr, err := connmgr.New(&connmgr.Config{
-		Listeners:      listeners,
-		OnAccept:       s.inboundPeerConnected,
-		RetryDuration:  connectionRetryInterval,
-		TargetOutbound: uint32(targetOutbound),
-		Dial:           dcrdDial,
-		OnConnection:   s.outboundPeerConnected,
-		GetNewAddress:  newAddressFunc,
+		Listeners:          listeners,
+		OnAccept:           s.inboundPeerConnected,
+		RetryDuration:      connectionRetryInterval,
+		TargetOutbound:     uint32(targetOutbound),
+		MaxConcurrentDials: cfg.MaxConcurrentDials,
+		MaxPendingDuration: cfg.PendingConnTimeout,
+		Dial:               dcrdDial,
+		OnConnection:       s.outboundPeerConnected,
+		GetNewAddress:      newAddressFunc,
+		OnConnectionFailed: s.outboundPeerFailed,
 	})
 	if err != nil {
 		return nil, err
@@ -2872,25 +4874,69 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 			return nil, err
 		}
 
-		// Signal process shutdown when the RPC server requests it.
+		// Signal process shutdown or restart when the RPC server requests
+		// it.
 		go func() {
-			<-s.rpcServer.RequestedProcessShutdown()
-			shutdownRequestChannel <- struct{}{}
+			select {
+			case <-s.rpcServer.RequestedProcessShutdown():
+				shutdownRequestChannel <- struct{}{}
+			case <-s.rpcServer.RequestedProcessRestart():
+				restartRequestChannel <- struct{}{}
+			}
 		}()
 	}
 
 	return &s, nil
 }
 
+// onionAddr implements the net.Addr interface for tor hidden services, which
+// are dialed by name via a proxy instead of being resolved to an IP address.
+type onionAddr struct {
+	addr string
+}
+
+// String returns the onion address along with the port in the form
+// 'host.onion:port'.
+//
+// This is part of the net.Addr interface.
+func (oa onionAddr) String() string {
+	return oa.addr
+}
+
+// Network returns "tcp" since hidden services are dialed over TCP via the
+// proxy.
+//
+// This is part of the net.Addr interface.
+func (oa onionAddr) Network() string {
+	return "tcp"
+}
+
+// Ensure onionAddr implements the net.Addr interface.
+var _ net.Addr = onionAddr{}
+
 // addrStringToNetAddr takes an address in the form of 'host:port' and returns
 // a net.Addr which maps to the original address with any host names resolved
-// to IP addresses.
+// to IP addresses.  When --oniononly was specified, tor hidden services are
+// returned without being resolved and all other addresses are refused.
 func addrStringToNetAddr(addr string) (net.Addr, error) {
 	host, strPort, err := net.SplitHostPort(addr)
 	if err != nil {
 		return nil, err
 	}
 
+	// Tor hidden services are not able to be resolved to an IP address, so
+	// pass them through to be dialed via the onion proxy as is when only
+	// connecting to them.  All other addresses are refused.
+	if cfg.OnionOnly {
+		if !strings.HasSuffix(host, ".onion") {
+			return nil, errOnionOnly
+		}
+		if _, err := strconv.ParseUint(strPort, 10, 16); err != nil {
+			return nil, err
+		}
+		return onionAddr{addr: addr}, nil
+	}
+
 	// Attempt to look up an IP address associated with the parsed host.
 	// The dcrdLookup function will transparently handle performing the
 	// lookup over Tor if necessary.
@@ -2913,13 +4959,59 @@ func addrStringToNetAddr(addr string) (net.Addr, error) {
 	}, nil
 }
 
-// isWhitelisted returns whether the IP address is included in the whitelisted
-// networks and IPs.
-func isWhitelisted(addr net.Addr) bool {
-	if len(cfg.whitelists) == 0 {
-		return false
+// ipWhitelist houses whitelisted IP networks and addresses that may be
+// replaced while the server is running.
+//
+// The zero value is an empty whitelist that is ready for use.
+type ipWhitelist struct {
+	mtx  sync.RWMutex
+	nets []*net.IPNet
+}
+
+// replace replaces the whitelisted networks with the provided ones.
+//
+// This function is safe for concurrent access.
+func (w *ipWhitelist) replace(nets []*net.IPNet) {
+	w.mtx.Lock()
+	w.nets = nets
+	w.mtx.Unlock()
+}
+
+// contains returns whether the provided IP is included in any of the
+// whitelisted networks.
+//
+// This function is safe for concurrent access.
+func (w *ipWhitelist) contains(ip net.IP) bool {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	for _, ipnet := range w.nets {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// strings returns the whitelisted networks in CIDR notation.
+//
+// This function is safe for concurrent access.
+func (w *ipWhitelist) strings() []string {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	nets := make([]string, 0, len(w.nets))
+	for _, ipnet := range w.nets {
+		nets = append(nets, ipnet.String())
 	}
+	return nets
+}
 
+// isWhitelisted returns whether the IP address is included in the whitelisted
+// networks and IPs.
+//
+// This function is safe for concurrent access.
+func (s *server) isWhitelisted(addr net.Addr) bool {
 	host, _, err := net.SplitHostPort(addr.String())
 	if err != nil {
 		srvrLog.Warnf("Unable to SplitHostPort on '%s': %v", addr, err)
@@ -2930,9 +5022,14 @@ func isWhitelisted(addr net.Addr) bool {
 		srvrLog.Warnf("Unable to parse IP '%s'", addr)
 		return false
 	}
+	return s.whitelist.contains(ip)
+}
 
-	for _, ipnet := range cfg.whitelists {
-		if ipnet.Contains(ip) {
+// isWhitelistedUserAgent returns whether the provided user agent contains any
+// of the provided whitelisted user agent substrings.
+func isWhitelistedUserAgent(userAgent string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(userAgent, substr) {
 			return true
 		}
 	}
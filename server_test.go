@@ -0,0 +1,2045 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/addrmgr"
+	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/blockchain/standalone"
+	"github.com/decred/dcrd/blockchain/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrd/mempool/v3"
+	"github.com/decred/dcrd/peer/v2"
+	"github.com/decred/dcrd/txscript/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestReconstructCmpctTxTree ensures transaction trees are reconstructed from
+// compact blocks as expected.
+func TestReconstructCmpctTxTree(t *testing.T) {
+	// Create some distinct transactions along with a map of those that are
+	// known keyed by their short ids.
+	var key chainhash.Hash
+	txns := make([]*wire.MsgTx, 5)
+	shortIDs := make([]uint64, len(txns))
+	for i := range txns {
+		txns[i] = wire.NewMsgTx()
+		txns[i].LockTime = uint32(i)
+		txHash := txns[i].TxHash()
+		shortIDs[i] = wire.CmpctBlockShortID(&key, &txHash)
+	}
+	known := make(map[uint64]*dcrutil.Tx)
+	for _, i := range []int{1, 3} {
+		known[shortIDs[i]] = dcrutil.NewTx(txns[i])
+	}
+
+	tests := []struct {
+		name        string
+		shortIDs    []uint64
+		prefilled   []wire.PrefilledTx
+		wantTxns    []*wire.MsgTx
+		wantMissing []uint32
+		wantErr     bool
+	}{{
+		name:     "all known",
+		shortIDs: []uint64{shortIDs[1], shortIDs[3]},
+		wantTxns: []*wire.MsgTx{txns[1], txns[3]},
+	}, {
+		name:     "prefilled and missing",
+		shortIDs: []uint64{shortIDs[1], shortIDs[2], shortIDs[3]},
+		prefilled: []wire.PrefilledTx{
+			{Index: 0, Tx: txns[0]},
+			{Index: 3, Tx: txns[4]},
+		},
+		wantTxns:    []*wire.MsgTx{txns[0], txns[1], nil, txns[4], txns[3]},
+		wantMissing: []uint32{2},
+	}, {
+		name:      "prefilled out of range",
+		shortIDs:  []uint64{shortIDs[1]},
+		prefilled: []wire.PrefilledTx{{Index: 2, Tx: txns[0]}},
+		wantErr:   true,
+	}, {
+		name:     "prefilled out of order",
+		shortIDs: []uint64{shortIDs[1]},
+		prefilled: []wire.PrefilledTx{
+			{Index: 1, Tx: txns[0]},
+			{Index: 0, Tx: txns[2]},
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		gotTxns, gotMissing, err := reconstructCmpctTxTree(test.shortIDs,
+			test.prefilled, known)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if test.wantErr {
+			continue
+		}
+		if !reflect.DeepEqual(gotTxns, test.wantTxns) {
+			t.Fatalf("%q: unexpected txns -- got %v, want %v", test.name,
+				gotTxns, test.wantTxns)
+		}
+		if !reflect.DeepEqual(gotMissing, test.wantMissing) {
+			t.Fatalf("%q: unexpected missing txns -- got %v, want %v",
+				test.name, gotMissing, test.wantMissing)
+		}
+	}
+}
+
+// TestCmpctBlockMatchesHeader ensures reconstructed compact blocks are only
+// considered to match their header when both transaction trees commit to the
+// merkle roots so that the full block is requested otherwise.
+func TestCmpctBlockMatchesHeader(t *testing.T) {
+	txns := make([]*wire.MsgTx, 3)
+	for i := range txns {
+		txns[i] = wire.NewMsgTx()
+		txns[i].LockTime = uint32(i)
+	}
+	header := wire.BlockHeader{
+		MerkleRoot: standalone.CalcTxTreeMerkleRoot(txns[:2]),
+		StakeRoot:  standalone.CalcTxTreeMerkleRoot(txns[2:]),
+	}
+
+	tests := []struct {
+		name  string
+		txns  []*wire.MsgTx
+		stxns []*wire.MsgTx
+		want  bool
+	}{{
+		name:  "matching trees",
+		txns:  txns[:2],
+		stxns: txns[2:],
+		want:  true,
+	}, {
+		name:  "regular tree collision",
+		txns:  []*wire.MsgTx{txns[0], txns[2]},
+		stxns: txns[2:],
+		want:  false,
+	}, {
+		name:  "stake tree collision",
+		txns:  txns[:2],
+		stxns: txns[:1],
+		want:  false,
+	}}
+
+	for _, test := range tests {
+		msgBlock := &wire.MsgBlock{
+			Header:        header,
+			Transactions:  test.txns,
+			STransactions: test.stxns,
+		}
+		if got := cmpctBlockMatchesHeader(msgBlock); got != test.want {
+			t.Fatalf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestOnCmpctBlockUnrequested ensures compact blocks are only accepted from
+// peers that were asked to announce new blocks via compact blocks.
+func TestOnCmpctBlockUnrequested(t *testing.T) {
+	// Ignore the compact blocks after the request checks by running in
+	// blocks only mode and ensure ban scores do not result in a ban.
+	origCfg := cfg
+	cfg = &config{BlocksOnly: true}
+	origBanThreshold := reloadable.BanThreshold()
+	origMaxPeers := reloadable.MaxPeers()
+	origMinRelayTxFee := reloadable.MinRelayTxFee()
+	reloadable.set(1000, origMaxPeers, origMinRelayTxFee)
+	defer func() {
+		cfg = origCfg
+		reloadable.set(origBanThreshold, origMaxPeers, origMinRelayTxFee)
+	}()
+
+	tests := []struct {
+		name      string
+		mode      peer.BlockAnnounceMode
+		wantScore uint32
+		wantInv   bool
+	}{
+		{"not requested", peer.BlockAnnounceInv, 100, false},
+		{"headers requested", peer.BlockAnnounceHeaders, 100, false},
+		{"compact blocks requested", peer.BlockAnnounceCmpct, 0, true},
+	}
+
+	for _, test := range tests {
+		sp := &serverPeer{
+			Peer:      peer.NewInboundPeer(&peer.Config{}),
+			recentInv: newRecentInventory(time.Minute),
+		}
+		sp.requestBlockAnnounce(test.mode, true)
+
+		msg := &wire.MsgCmpctBlock{Header: wire.BlockHeader{Height: 1}}
+		sp.OnCmpctBlock(sp.Peer, msg)
+		if score := sp.banScore.Int(); score != test.wantScore {
+			t.Fatalf("%q: unexpected ban score -- got %d, want %d",
+				test.name, score, test.wantScore)
+		}
+		blockHash := msg.Header.BlockHash()
+		iv := wire.NewInvVect(wire.InvTypeBlock, &blockHash)
+		if got := sp.recentInv.contains(iv, time.Now()); got != test.wantInv {
+			t.Fatalf("%q: unexpected recent inventory -- got %v, want %v",
+				test.name, got, test.wantInv)
+		}
+	}
+}
+
+// TestBlockAnnounceModeForPeer ensures peers are asked to announce new blocks
+// using the preferred method when they support it and fall back to the best
+// method they support otherwise.
+func TestBlockAnnounceModeForPeer(t *testing.T) {
+	const (
+		inv     = peer.BlockAnnounceInv
+		headers = peer.BlockAnnounceHeaders
+		cmpct   = peer.BlockAnnounceCmpct
+	)
+	full := wire.SFNodeNetwork | wire.SFNodeCmpctBlock
+	tests := []struct {
+		name      string
+		preferred peer.BlockAnnounceMode
+		pver      uint32
+		services  wire.ServiceFlag
+		want      peer.BlockAnnounceMode
+	}{
+		{"compact supported", cmpct, wire.CmpctBlockVersion, full, cmpct},
+		{"compact no service", cmpct, wire.CmpctBlockVersion,
+			wire.SFNodeNetwork, headers},
+		{"compact old version", cmpct, wire.NodeCFVersion, full, headers},
+		{"compact very old version", cmpct, wire.SendHeadersVersion - 1,
+			full, inv},
+		{"headers supported", headers, wire.CmpctBlockVersion, full,
+			headers},
+		{"headers old version", headers, wire.SendHeadersVersion - 1, full,
+			inv},
+		{"inv", inv, wire.CmpctBlockVersion, full, inv},
+	}
+
+	for _, test := range tests {
+		got := blockAnnounceModeForPeer(test.preferred, test.pver,
+			test.services)
+		if got != test.want {
+			t.Fatalf("%q: unexpected mode -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestRequestBlockAnnounce ensures peers are only asked to announce new blocks
+// via headers once the chain is current and that deferred requests are sent
+// exactly once.
+func TestRequestBlockAnnounce(t *testing.T) {
+	const (
+		inv     = peer.BlockAnnounceInv
+		headers = peer.BlockAnnounceHeaders
+		cmpct   = peer.BlockAnnounceCmpct
+	)
+	tests := []struct {
+		name         string
+		mode         peer.BlockAnnounceMode
+		current      bool
+		wantCmd      string
+		wantDeferred bool
+		wantMode     peer.BlockAnnounceMode
+	}{
+		{"headers when current", headers, true, wire.CmdSendHeaders, false,
+			headers},
+		{"headers when not current", headers, false, "", true, inv},
+		{"compact when current", cmpct, true, wire.CmdSendCmpct, false,
+			cmpct},
+		{"compact when not current", cmpct, false, wire.CmdSendCmpct, false,
+			cmpct},
+		{"inv when current", inv, true, "", false, inv},
+		{"inv when not current", inv, false, "", false, inv},
+	}
+
+	for _, test := range tests {
+		sp := &serverPeer{}
+		msg, deferred := sp.requestBlockAnnounce(test.mode, test.current)
+		var gotCmd string
+		if msg != nil {
+			gotCmd = msg.Command()
+		}
+		if gotCmd != test.wantCmd {
+			t.Fatalf("%q: unexpected message -- got %q, want %q", test.name,
+				gotCmd, test.wantCmd)
+		}
+		if deferred != test.wantDeferred {
+			t.Fatalf("%q: unexpected deferred flag -- got %v, want %v",
+				test.name, deferred, test.wantDeferred)
+		}
+		if got := sp.requestedBlockAnnounce(); got != test.wantMode {
+			t.Fatalf("%q: unexpected requested mode -- got %v, want %v",
+				test.name, got, test.wantMode)
+		}
+
+		// Ensure the deferred sendheaders request is only provided when the
+		// request was deferred and that it is only provided once.
+		msg = sp.deferredHeadersRequest()
+		if (msg != nil) != test.wantDeferred {
+			t.Fatalf("%q: unexpected deferred request -- got %v, want %v",
+				test.name, msg != nil, test.wantDeferred)
+		}
+		if !test.wantDeferred {
+			continue
+		}
+		if msg.Command() != wire.CmdSendHeaders {
+			t.Fatalf("%q: unexpected deferred message -- got %q, want %q",
+				test.name, msg.Command(), wire.CmdSendHeaders)
+		}
+		if got := sp.requestedBlockAnnounce(); got != headers {
+			t.Fatalf("%q: unexpected requested mode after deferred request "+
+				"-- got %v, want %v", test.name, got, headers)
+		}
+		if msg := sp.deferredHeadersRequest(); msg != nil {
+			t.Fatalf("%q: deferred request provided more than once",
+				test.name)
+		}
+	}
+}
+
+// TestRecentInventory ensures the recently received inventory tracker only
+// reports inventory within the suppression window, prunes expired entries, and
+// never reports anything when disabled.
+func TestRecentInventory(t *testing.T) {
+	window := time.Second * 5
+	start := time.Now()
+	txIV := wire.NewInvVect(wire.InvTypeTx, &chainhash.Hash{0x01})
+	blockIV := wire.NewInvVect(wire.InvTypeBlock, &chainhash.Hash{0x01})
+
+	recent := newRecentInventory(window)
+	recent.add(txIV, start)
+	tests := []struct {
+		name   string
+		iv     *wire.InvVect
+		offset time.Duration
+		want   bool
+	}{
+		{"just received", txIV, 0, true},
+		{"within window", txIV, window - time.Millisecond, true},
+		{"window expired", txIV, window, false},
+		{"different type same hash", blockIV, 0, false},
+	}
+	for _, test := range tests {
+		got := recent.contains(test.iv, start.Add(test.offset))
+		if got != test.want {
+			t.Fatalf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+
+	// Ensure expired entries are pruned once the window elapses.
+	recent.add(blockIV, start.Add(window))
+	if _, ok := recent.items[*txIV]; ok {
+		t.Fatal("expired entry was not pruned")
+	}
+	if !recent.contains(blockIV, start.Add(window)) {
+		t.Fatal("entry added after pruning is not reported")
+	}
+
+	// Ensure the number of entries is bounded.
+	for i := 0; i < maxRecentInvPerPeer+10; i++ {
+		var hash chainhash.Hash
+		hash[0], hash[1] = byte(i), byte(i>>8)
+		recent.add(wire.NewInvVect(wire.InvTypeTx, &hash), start.Add(window))
+	}
+	if len(recent.items) > maxRecentInvPerPeer {
+		t.Fatalf("too many entries -- got %d, max %d", len(recent.items),
+			maxRecentInvPerPeer)
+	}
+
+	// Ensure a disabled tracker never reports inventory.
+	disabled := newRecentInventory(0)
+	disabled.add(txIV, start)
+	if disabled.contains(txIV, start) {
+		t.Fatal("disabled tracker reported inventory")
+	}
+}
+
+// TestBandwidthMeter ensures the bandwidth meter reports the average rate over
+// the most recent sample intervals, excludes samples that have aged out, and
+// reuses the ring buffer slots of stale samples.
+func TestBandwidthMeter(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	window := bandwidthSampleInterval * bandwidthNumSamples
+	tests := []struct {
+		name     string
+		numBytes uint64
+		offset   time.Duration
+		want     float64
+	}{
+		{"no samples", 0, 0, 0},
+		{"first sample", 1000, 0, 1000 / window.Seconds()},
+		{"second interval", 2000, time.Second, 3000 / window.Seconds()},
+		{"same interval", 3000, time.Second * 3 / 2, 6000 / window.Seconds()},
+		{"end of window", 0, window - time.Millisecond, 6000 / window.Seconds()},
+		{"first sample aged out", 0, window, 5000 / window.Seconds()},
+		{"stale slot reused", 500, window, 5500 / window.Seconds()},
+		{"all samples aged out", 0, window * 3, 0},
+	}
+
+	var meter bandwidthMeter
+	for _, test := range tests {
+		now := start.Add(test.offset)
+		if test.numBytes != 0 {
+			meter.add(test.numBytes, now)
+		}
+		if got := meter.rate(now); got != test.want {
+			t.Fatalf("%q: unexpected rate -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestUnixListeners ensures unix domain socket listen addresses are separated
+// from the IP listen addresses and that listening on them replaces stale
+// sockets while refusing to replace sockets that are in use.
+func TestUnixListeners(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unixlisteners")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "dcrd.sock")
+
+	// Ensure unix domain socket addresses are only returned as unix paths.
+	addrs := []string{"127.0.0.1:9108", unixListenPrefix + socketPath}
+	ipv4Addrs, ipv6Addrs, _, err := parseListeners(addrs)
+	if err != nil {
+		t.Fatalf("parseListeners: unexpected error: %v", err)
+	}
+	if len(ipv4Addrs) != 1 || len(ipv6Addrs) != 0 {
+		t.Fatalf("parseListeners: unexpected addresses -- ipv4 %v, ipv6 %v",
+			ipv4Addrs, ipv6Addrs)
+	}
+	unixPaths := parseUnixListeners(addrs)
+	if !reflect.DeepEqual(unixPaths, []string{socketPath}) {
+		t.Fatalf("parseUnixListeners: unexpected paths -- got %v, want %v",
+			unixPaths, []string{socketPath})
+	}
+
+	// Leave a stale socket behind and ensure it is replaced.
+	stale, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("unable to create stale socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+	listener, err := listenUnix(net.Listen, socketPath)
+	if err != nil {
+		t.Fatalf("listenUnix: unable to replace stale socket: %v", err)
+	}
+	defer listener.Close()
+
+	// Ensure a socket that is in use is not replaced.
+	if _, err := listenUnix(net.Listen, socketPath); err == nil {
+		t.Fatal("listenUnix: replaced socket that is in use")
+	}
+}
+
+// TestSubnetBanEnd ensures peers are reported as banned when their IP is within
+// a banned subnet and that expired subnet bans are removed.
+func TestSubnetBanEnd(t *testing.T) {
+	now := time.Now()
+	mustParseCIDR := func(s string) *net.IPNet {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			t.Fatalf("unable to parse CIDR %q: %v", s, err)
+		}
+		return ipNet
+	}
+	state := &peerState{bannedSubnets: map[string]bannedSubnet{
+		"10.0.0.0/8":     {mustParseCIDR("10.0.0.0/8"), now.Add(time.Hour)},
+		"10.1.0.0/16":    {mustParseCIDR("10.1.0.0/16"), now.Add(time.Hour * 2)},
+		"192.168.0.0/24": {mustParseCIDR("192.168.0.0/24"), now.Add(-time.Second)},
+		"fd00::/16":      {mustParseCIDR("fd00::/16"), now.Add(time.Hour)},
+	}}
+
+	tests := []struct {
+		ip      string
+		banned  bool
+		wantEnd time.Time
+	}{
+		{"10.2.3.4", true, now.Add(time.Hour)},
+		{"10.1.2.3", true, now.Add(time.Hour * 2)},
+		{"11.0.0.1", false, time.Time{}},
+		{"192.168.0.1", false, time.Time{}},
+		{"fd00::1", true, now.Add(time.Hour)},
+		{"fd01::1", false, time.Time{}},
+	}
+	for _, test := range tests {
+		banEnd, banned := state.subnetBanEnd(net.ParseIP(test.ip), now)
+		if banned != test.banned || !banEnd.Equal(test.wantEnd) {
+			t.Fatalf("%s: unexpected result -- got (%v, %v), want (%v, %v)",
+				test.ip, banEnd, banned, test.wantEnd, test.banned)
+		}
+	}
+
+	// Ensure the expired subnet ban was removed.
+	if _, ok := state.bannedSubnets["192.168.0.0/24"]; ok {
+		t.Fatal("expired subnet ban was not removed")
+	}
+	if len(state.bannedSubnets) != 3 {
+		t.Fatalf("unexpected number of subnet bans -- got %d, want 3",
+			len(state.bannedSubnets))
+	}
+}
+
+// TestRecordBan ensures bans of distinct IPs within the same subnet escalate to
+// a subnet ban once the threshold is reached within the window.
+func TestRecordBan(t *testing.T) {
+	const threshold = 3
+	const window = time.Hour
+	now := time.Now()
+	state := &peerState{recentBans: make(map[string]map[string]time.Time)}
+
+	tests := []struct {
+		name   string
+		ip     string
+		offset time.Duration
+		want   string
+	}{
+		{"first ban", "10.0.0.1", 0, ""},
+		{"same ip again", "10.0.0.1", time.Minute, ""},
+		{"other subnet", "10.0.1.1", time.Minute, ""},
+		{"second distinct ip", "10.0.0.2", time.Minute * 2, ""},
+		{"third distinct ip", "10.0.0.3", time.Minute * 3, "10.0.0.0/24"},
+		{"restart after escalation", "10.0.0.4", time.Minute * 4, ""},
+		{"old bans pruned", "10.0.1.2", window + time.Minute*2, ""},
+		{"ipv6 first", "fd00::1", window * 3, ""},
+		{"ipv6 second", "fd00::1:2", window * 3, ""},
+		{"ipv6 third", "fd00::ffff:3", window * 3, "fd00::/64"},
+	}
+	for _, test := range tests {
+		subnet := state.recordBan(net.ParseIP(test.ip), now.Add(test.offset),
+			threshold, window)
+		var got string
+		if subnet != nil {
+			got = subnet.String()
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected escalation -- got %q, want %q",
+				test.name, got, test.want)
+		}
+	}
+
+	// Ensure all records outside of the window were pruned.
+	if len(state.recentBans) != 0 {
+		t.Fatalf("unexpected ban records remain: %v", state.recentBans)
+	}
+}
+
+// TestBanSubnetExempt ensures banning a subnet disconnects the peers within it
+// other than whitelisted and persistent peers.
+func TestBanSubnetExempt(t *testing.T) {
+	newPeer := func(addr string) *serverPeer {
+		p, err := peer.NewOutboundPeer(&peer.Config{}, addr)
+		if err != nil {
+			t.Fatalf("unable to create peer %s: %v", addr, err)
+		}
+		return &serverPeer{Peer: p}
+	}
+	banned := newPeer("10.0.0.1:9108")
+	whitelisted := newPeer("10.0.0.2:9108")
+	whitelisted.isWhitelisted = true
+	persistent := newPeer("10.0.0.3:9108")
+	persistent.persistent = true
+	outside := newPeer("10.0.1.1:9108")
+	state := &peerState{
+		outboundPeers: map[int32]*serverPeer{
+			1: banned,
+			2: whitelisted,
+			4: outside,
+		},
+		persistentPeers: map[int32]*serverPeer{3: persistent},
+		bannedSubnets:   make(map[string]bannedSubnet),
+	}
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/24")
+	s := &server{}
+	s.handleBanSubnet(state, ipNet, time.Hour)
+
+	// isDisconnected returns whether or not the peer was disconnected.
+	isDisconnected := func(sp *serverPeer) bool {
+		done := make(chan struct{})
+		go func() {
+			sp.WaitForDisconnect()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return true
+		case <-time.After(time.Millisecond * 50):
+			return false
+		}
+	}
+	tests := []struct {
+		name string
+		sp   *serverPeer
+		want bool
+	}{
+		{"peer in subnet", banned, true},
+		{"whitelisted peer in subnet", whitelisted, false},
+		{"persistent peer in subnet", persistent, false},
+		{"peer outside subnet", outside, false},
+	}
+	for _, test := range tests {
+		if got := isDisconnected(test.sp); got != test.want {
+			t.Fatalf("%q: unexpected disconnect -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestSetAndClearBan ensures manually banning addresses and subnets and lifting
+// the bans updates the ban state as expected.
+func TestSetAndClearBan(t *testing.T) {
+	s := &server{}
+	state := &peerState{
+		banned:        make(map[string]time.Time),
+		bannedSubnets: make(map[string]bannedSubnet),
+	}
+	mustParseSubnet := func(subnet string) *net.IPNet {
+		ipNet, err := parseSubnet(subnet)
+		if err != nil {
+			t.Fatalf("unable to parse subnet %q: %v", subnet, err)
+		}
+		return ipNet
+	}
+
+	// Ensure single addresses are banned as hosts while networks are banned
+	// as subnets.
+	s.handleSetBan(state, mustParseSubnet("10.0.0.1"), time.Hour)
+	s.handleSetBan(state, mustParseSubnet("fd00::1"), time.Hour)
+	s.handleSetBan(state, mustParseSubnet("192.168.0.0/24"), time.Hour)
+	for _, host := range []string{"10.0.0.1", "fd00::1"} {
+		if _, ok := state.banned[host]; !ok {
+			t.Fatalf("%s: host is not banned", host)
+		}
+	}
+	if _, ok := state.bannedSubnets["192.168.0.0/24"]; !ok {
+		t.Fatal("subnet is not banned")
+	}
+
+	// Ensure a single address that was banned as a subnet is also lifted.
+	s.handleBanSubnet(state, mustParseSubnet("10.0.0.2"), time.Hour)
+
+	tests := []struct {
+		name    string
+		address string
+		wantErr bool
+	}{
+		{"banned host", "10.0.0.1", false},
+		{"banned host again", "10.0.0.1", true},
+		{"banned ipv6 host", "fd00::1", false},
+		{"host banned as subnet", "10.0.0.2", false},
+		{"host within banned subnet", "192.168.0.1", true},
+		{"banned subnet", "192.168.0.0/24", false},
+		{"never banned", "10.0.0.3", true},
+	}
+	for _, test := range tests {
+		err := s.handleClearBan(state, mustParseSubnet(test.address))
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+	}
+	if len(state.banned) != 0 || len(state.bannedSubnets) != 0 {
+		t.Fatalf("unexpected bans remain: %v %v", state.banned,
+			state.bannedSubnets)
+	}
+
+	// Ensure expired bans are not considered banned.
+	state.banned["10.0.0.4"] = time.Now().Add(-time.Second)
+	if err := s.handleClearBan(state, mustParseSubnet("10.0.0.4")); err == nil {
+		t.Fatal("lifting an expired ban did not fail")
+	}
+}
+
+// TestUnixSocketPeerBans ensures peers connected over a unix domain socket are
+// exempt from bans of the loopback address they are assigned while other
+// localhost peers are not.
+func TestUnixSocketPeerBans(t *testing.T) {
+	origCfg := cfg
+	cfg = &config{BanDuration: time.Hour}
+	defer func() { cfg = origCfg }()
+
+	newPeer := func(addr string) *serverPeer {
+		p, err := peer.NewOutboundPeer(&peer.Config{}, addr)
+		if err != nil {
+			t.Fatalf("unable to create peer %s: %v", addr, err)
+		}
+		return &serverPeer{Peer: p}
+	}
+	unixPeer := newPeer("127.0.0.1:0")
+	unixPeer.isUnixSocket = true
+	tcpPeer := newPeer("127.0.0.1:9108")
+	state := &peerState{
+		outboundPeers: map[int32]*serverPeer{1: unixPeer, 2: tcpPeer},
+		banned:        make(map[string]time.Time),
+		bannedSubnets: make(map[string]bannedSubnet),
+	}
+	s := &server{}
+
+	// Ensure banning the unix socket peer does not ban the loopback address.
+	s.handleBanPeerMsg(state, unixPeer)
+	if len(state.banned) != 0 {
+		t.Fatalf("unix socket peer banned the loopback address: %v",
+			state.banned)
+	}
+
+	// Ensure banning the localhost peer bans the loopback address.
+	s.handleBanPeerMsg(state, tcpPeer)
+	if _, ok := state.banned["127.0.0.1"]; !ok {
+		t.Fatal("localhost peer is not banned")
+	}
+
+	// Ensure manually banning the loopback address only disconnects the
+	// localhost peer.
+	ipNet, err := parseSubnet("127.0.0.1")
+	if err != nil {
+		t.Fatalf("unable to parse subnet: %v", err)
+	}
+	s.handleSetBan(state, ipNet, time.Hour)
+	isDisconnected := func(sp *serverPeer) bool {
+		done := make(chan struct{})
+		go func() {
+			sp.WaitForDisconnect()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return true
+		case <-time.After(time.Millisecond * 50):
+			return false
+		}
+	}
+	if isDisconnected(unixPeer) {
+		t.Fatal("unix socket peer disconnected by loopback address ban")
+	}
+	if !isDisconnected(tcpPeer) {
+		t.Fatal("localhost peer not disconnected by loopback address ban")
+	}
+}
+
+// TestRebroadcastTimeout ensures the rebroadcast timeout uses the configured
+// interval as its base with a bounded jitter and falls back to a random time up
+// to 30 minutes when no interval is configured.
+func TestRebroadcastTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		min      time.Duration
+		max      time.Duration
+	}{
+		{"unset", 0, 0, 30 * time.Minute},
+		{"sub-second jitter", 5 * time.Second, 5 * time.Second, 5 * time.Second},
+		{"proportional jitter", 5 * time.Minute, 5 * time.Minute, 5*time.Minute + 30*time.Second},
+		{"capped jitter", time.Hour, time.Hour, time.Hour + maxRebroadcastJitter},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 100; i++ {
+			got := rebroadcastTimeout(test.interval)
+			if got < test.min || got > test.max {
+				t.Fatalf("%q: unexpected timeout -- got %v, want [%v, %v]",
+					test.name, got, test.min, test.max)
+			}
+		}
+	}
+}
+
+// TestPruneMinedRebroadcastTxns ensures mined regular transactions are removed
+// from the pending rebroadcast inventory while all other entries remain,
+// including when the inventory is rebroadcast.
+func TestPruneMinedRebroadcastTxns(t *testing.T) {
+	// Create some distinct regular transactions where only the first one is
+	// mined.
+	txns := make([]*dcrutil.Tx, 2)
+	for i := range txns {
+		msgTx := wire.NewMsgTx()
+		msgTx.LockTime = uint32(i)
+		txns[i] = dcrutil.NewTx(msgTx)
+	}
+	mined := func(txHash *chainhash.Hash) bool {
+		return *txHash == *txns[0].Hash()
+	}
+
+	minedIV := wire.NewInvVect(wire.InvTypeTx, txns[0].Hash())
+	unminedIV := wire.NewInvVect(wire.InvTypeTx, txns[1].Hash())
+	blockIV := wire.NewInvVect(wire.InvTypeBlock, txns[0].Hash())
+
+	// Ensure mined transactions are pruned both on their own and at
+	// rebroadcast time, in which case they must not be relayed again while
+	// all other pending inventory is.
+	tests := []struct {
+		name        string
+		rebroadcast bool
+	}{
+		{"prune", false},
+		{"rebroadcast", true},
+	}
+
+	for _, test := range tests {
+		pendingInvs := map[wire.InvVect]interface{}{
+			*minedIV:   txns[0],
+			*unminedIV: txns[1],
+			*blockIV:   txns[0],
+		}
+		relayed := make(map[wire.InvVect]interface{})
+		if test.rebroadcast {
+			relay := func(iv *wire.InvVect, data interface{}) {
+				relayed[*iv] = data
+			}
+			rebroadcastPendingInvs(pendingInvs, mined, relay)
+		} else {
+			pruneMinedRebroadcastTxns(pendingInvs, mined)
+		}
+
+		if _, ok := pendingInvs[*minedIV]; ok {
+			t.Fatalf("%q: mined transaction was not pruned", test.name)
+		}
+		if _, ok := pendingInvs[*unminedIV]; !ok {
+			t.Fatalf("%q: unmined transaction was pruned", test.name)
+		}
+		if _, ok := pendingInvs[*blockIV]; !ok {
+			t.Fatalf("%q: non-transaction inventory was pruned", test.name)
+		}
+		if !test.rebroadcast {
+			continue
+		}
+		if _, ok := relayed[*minedIV]; ok {
+			t.Fatalf("%q: mined transaction was relayed", test.name)
+		}
+		if data, ok := relayed[*unminedIV]; !ok || data != txns[1] {
+			t.Fatalf("%q: unmined transaction was not relayed", test.name)
+		}
+		if _, ok := relayed[*blockIV]; !ok {
+			t.Fatalf("%q: non-transaction inventory was not relayed",
+				test.name)
+		}
+		if len(relayed) != 2 {
+			t.Fatalf("%q: unexpected number of relayed inventory vectors "+
+				"-- got %d, want 2", test.name, len(relayed))
+		}
+	}
+}
+
+// TestBlockOutsideRetainWindow ensures blocks older than the configured number
+// of retained blocks are identified as outside of the window so getdata
+// requests for them are answered with notfound.
+func TestBlockOutsideRetainWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		height     int64
+		bestHeight int64
+		retain     uint32
+		want       bool
+	}{
+		{"unlimited genesis", 0, 100000, 0, false},
+		{"best block", 100000, 100000, 288, false},
+		{"oldest retained block", 99713, 100000, 288, false},
+		{"first block outside window", 99712, 100000, 288, true},
+		{"genesis", 0, 100000, 288, true},
+		{"chain shorter than window", 0, 100, 288, false},
+	}
+
+	for _, test := range tests {
+		got := blockOutsideRetainWindow(test.height, test.bestHeight,
+			test.retain)
+		if got != test.want {
+			t.Fatalf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestGetDataBudget ensures the getdata budget serves bursts up to the full
+// budget, answers the excess items with notfound, and refills over time.
+func TestGetDataBudget(t *testing.T) {
+	const rate = 100
+	invList := make([]*wire.InvVect, 0, 150)
+	for i := 0; i < cap(invList); i++ {
+		hash := chainhash.Hash{byte(i)}
+		invList = append(invList, wire.NewInvVect(wire.InvTypeTx, &hash))
+	}
+
+	start := time.Now()
+	tests := []struct {
+		name       string
+		numItems   int
+		offset     time.Duration
+		wantServed int
+	}{
+		{"burst beyond budget", 150, 0, 100},
+		{"budget exhausted", 10, time.Second, 1},
+		{"partially refilled", 50, time.Second * 31, 50},
+		{"refill capped at budget", 150, time.Hour, 100},
+		{"empty request", 0, time.Hour, 0},
+	}
+
+	var budget getDataBudget
+	for _, test := range tests {
+		served, excess := budget.limit(invList[:test.numItems], rate,
+			start.Add(test.offset))
+		if len(served) != test.wantServed {
+			t.Fatalf("%q: unexpected number of served items -- got %d, "+
+				"want %d", test.name, len(served), test.wantServed)
+		}
+		wantExcess := test.numItems - test.wantServed
+		if len(excess) != wantExcess {
+			t.Fatalf("%q: unexpected number of notfound items -- got %d, "+
+				"want %d", test.name, len(excess), wantExcess)
+		}
+		if len(excess) > 0 && excess[0] != invList[test.wantServed] {
+			t.Fatalf("%q: notfound items do not start after the served "+
+				"items", test.name)
+		}
+	}
+
+	// Ensure all items are served when the budget is disabled.
+	var unlimited getDataBudget
+	served, excess := unlimited.limit(invList, 0, start)
+	if len(served) != len(invList) || len(excess) != 0 {
+		t.Fatalf("unexpected split with disabled budget -- got %d served "+
+			"and %d notfound, want %d served", len(served), len(excess),
+			len(invList))
+	}
+}
+
+// TestWhitelistUserAgent ensures peers are only whitelisted when their user
+// agent contains one of the whitelisted substrings and that whitelisted peers
+// are exempt from ban scoring.
+func TestWhitelistUserAgent(t *testing.T) {
+	whitelist := []string{"/dcrwallet:", "/companion:1.2"}
+	tests := []struct {
+		name      string
+		userAgent string
+		want      bool
+	}{
+		{"exact match", "/dcrwallet:", true},
+		{"substring match", "/dcrd:1.6.0/dcrwallet:1.6.0/", true},
+		{"versioned match", "/companion:1.2.3/", true},
+		{"unmatched agent", "/dcrd:1.6.0/", false},
+		{"unmatched version", "/companion:1.3.0/", false},
+		{"case sensitive", "/DCRWALLET:1.6.0/", false},
+		{"empty agent", "", false},
+	}
+
+	// Ban scoring relies on the configuration.
+	origCfg := cfg
+	cfg = &config{BanThreshold: defaultBanThreshold}
+	defer func() {
+		cfg = origCfg
+	}()
+
+	for _, test := range tests {
+		sp := &serverPeer{Peer: peer.NewInboundPeer(&peer.Config{})}
+		sp.maybeWhitelistUserAgent(test.userAgent, whitelist)
+		if sp.isUAWhitelisted != test.want {
+			t.Fatalf("%q: unexpected whitelisted state -- got %v, want %v",
+				test.name, sp.isUAWhitelisted, test.want)
+		}
+
+		// Ensure the user agent alone does not exempt peers from the
+		// connection limits or bans since it can be spoofed.
+		if sp.connLimitsExempt() || sp.subnetBanExempt() {
+			t.Fatalf("%q: user agent whitelisted peer exempt from "+
+				"connection limits or bans", test.name)
+		}
+
+		// Ensure whitelisted peers do not accrue ban score.
+		if !test.want {
+			continue
+		}
+		sp.addBanScore(10, 10, "test")
+		if score := sp.banScore.Int(); score != 0 {
+			t.Fatalf("%q: whitelisted peer ban score increased to %d",
+				test.name, score)
+		}
+	}
+
+	// Ensure peers are not whitelisted when there are no whitelisted user
+	// agents.
+	sp := &serverPeer{Peer: peer.NewInboundPeer(&peer.Config{})}
+	sp.maybeWhitelistUserAgent("/dcrwallet:1.6.0/", nil)
+	if sp.isUAWhitelisted {
+		t.Fatal("peer whitelisted without any whitelisted user agents")
+	}
+}
+
+// TestPeerUsefulnessCounters ensures the blocks delivered by a peer are counted
+// along with the time of the most recent one so the same counter is used for
+// both the usefulness score and the counts reported by getpeerinfo.
+func TestPeerUsefulnessCounters(t *testing.T) {
+	var u peerUsefulness
+	if blocks := u.blocks(); blocks != 0 {
+		t.Fatalf("unexpected initial block count -- got %d, want 0", blocks)
+	}
+	if lastBlock := u.lastBlock(); !lastBlock.IsZero() {
+		t.Fatalf("unexpected initial last block time -- got %v, want zero",
+			lastBlock)
+	}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		u.recordBlock(now.Add(time.Duration(i) * time.Second))
+	}
+	if blocks := u.blocks(); blocks != 3 {
+		t.Fatalf("unexpected block count -- got %d, want 3", blocks)
+	}
+	want := now.Add(2 * time.Second)
+	if lastBlock := u.lastBlock(); !lastBlock.Equal(want) {
+		t.Fatalf("unexpected last block time -- got %v, want %v", lastBlock,
+			want)
+	}
+}
+
+// TestPeerUsefulnessScore ensures peer usefulness scores rank peers as expected
+// based on the blocks received from them, how recently they delivered a block,
+// and their ping times.
+func TestPeerUsefulnessScore(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		blocks     uint64
+		lastBlock  time.Time
+		pingMicros int64
+		want       int64
+	}{{
+		name: "no blocks and no ping",
+		want: -maxPeerPingPenalty,
+	}, {
+		name:       "no blocks with fast ping",
+		pingMicros: 5000,
+		want:       0,
+	}, {
+		name:       "no blocks with slow ping",
+		pingMicros: 500000,
+		want:       -50,
+	}, {
+		name:       "no blocks with very slow ping",
+		pingMicros: 5000000,
+		want:       -maxPeerPingPenalty,
+	}, {
+		name:       "recent block",
+		blocks:     1,
+		lastBlock:  now,
+		pingMicros: 20000,
+		want:       10 + 100 - 2,
+	}, {
+		name:       "older block",
+		blocks:     1,
+		lastBlock:  now.Add(-time.Minute * 30),
+		pingMicros: 20000,
+		want:       10 + 70 - 2,
+	}, {
+		name:       "stale block",
+		blocks:     1,
+		lastBlock:  now.Add(-time.Hour * 3),
+		pingMicros: 20000,
+		want:       10 - 2,
+	}, {
+		name:       "block count capped",
+		blocks:     maxScoredBlocks * 5,
+		lastBlock:  now.Add(-time.Hour * 3),
+		pingMicros: 20000,
+		want:       maxScoredBlocks*10 - 2,
+	}, {
+		name:       "block time in the future",
+		blocks:     1,
+		lastBlock:  now.Add(time.Minute),
+		pingMicros: 20000,
+		want:       10 + 100 - 2,
+	}}
+
+	for _, test := range tests {
+		got := peerUsefulnessScore(test.blocks, test.lastBlock,
+			test.pingMicros, now)
+		if got != test.want {
+			t.Fatalf("%q: unexpected score -- got %d, want %d", test.name,
+				got, test.want)
+		}
+	}
+
+	// Ensure recording blocks increases the score.
+	var usefulness peerUsefulness
+	before := usefulness.score(20000, now)
+	usefulness.recordBlock(now)
+	if after := usefulness.score(20000, now); after <= before {
+		t.Fatalf("score did not increase after recording a block -- got "+
+			"%d, before %d", after, before)
+	}
+}
+
+// TestSelectEvictionCandidate ensures the expected inbound peer is selected for
+// eviction from a set of candidates and that the protected sets of candidates
+// are not evicted.
+func TestSelectEvictionCandidate(t *testing.T) {
+	now := time.Now()
+	block := func(minutesAgo int) time.Time {
+		return now.Add(-time.Duration(minutesAgo) * time.Minute)
+	}
+	connected := func(hoursAgo int) time.Time {
+		return now.Add(-time.Duration(hoursAgo) * time.Hour)
+	}
+
+	// Unless otherwise noted, the candidates are all in the same network
+	// group, so the first one is protected as the sole candidate from its
+	// network group.
+	type candidate struct {
+		name      string
+		group     uint64
+		ping      int64
+		score     int64
+		lastBlock time.Time
+		connected time.Time
+	}
+	tests := []struct {
+		name       string
+		candidates []candidate
+		want       string
+	}{{
+		name: "no candidates",
+		want: "",
+	}, {
+		name: "lowest score",
+		candidates: []candidate{
+			{"group", 1, 0, 100, time.Time{}, connected(1)},
+			{"a", 1, 0, 10, time.Time{}, connected(3)},
+			{"b", 1, 0, -50, time.Time{}, connected(1)},
+			{"c", 1, 0, 0, time.Time{}, connected(3)},
+			{"d", 1, 0, 20, time.Time{}, connected(1)},
+		},
+		want: "b",
+	}, {
+		name: "tie evicts most recently connected",
+		candidates: []candidate{
+			{"group", 1, 0, 100, time.Time{}, connected(1)},
+			{"a", 1, 0, 0, time.Time{}, connected(6)},
+			{"b", 1, 0, 0, time.Time{}, connected(5)},
+			{"c", 1, 0, -10, time.Time{}, connected(2)},
+			{"d", 1, 0, -10, time.Time{}, connected(1)},
+		},
+		want: "d",
+	}, {
+		name: "distinct network groups protected",
+		candidates: []candidate{
+			{"a", 1, 0, -100, time.Time{}, connected(1)},
+			{"b", 2, 0, -90, time.Time{}, connected(1)},
+			{"c", 3, 0, -80, time.Time{}, connected(1)},
+			{"d", 4, 0, -70, time.Time{}, connected(1)},
+			{"e", 5, 0, 0, time.Time{}, connected(1)},
+		},
+		want: "e",
+	}, {
+		name: "lowest ping times protected",
+		candidates: []candidate{
+			{"group", 1, 0, 100, time.Time{}, connected(1)},
+			{"a", 1, 1000, -100, time.Time{}, connected(1)},
+			{"b", 1, 2000, -100, time.Time{}, connected(1)},
+			{"c", 1, 3000, -100, time.Time{}, connected(1)},
+			{"d", 1, 4000, -100, time.Time{}, connected(1)},
+			{"e", 1, 5000, -100, time.Time{}, connected(1)},
+			{"f", 1, 6000, -100, time.Time{}, connected(1)},
+			{"g", 1, 7000, -100, time.Time{}, connected(1)},
+			{"h", 1, 8000, -100, time.Time{}, connected(1)},
+			{"i", 1, 0, 0, time.Time{}, connected(1)},
+		},
+		want: "i",
+	}, {
+		name: "recent block relayers protected",
+		candidates: []candidate{
+			{"group", 1, 0, 100, time.Time{}, connected(1)},
+			{"a", 1, 0, -100, block(1), connected(1)},
+			{"b", 1, 0, -90, block(2), connected(1)},
+			{"c", 1, 0, -80, block(3), connected(1)},
+			{"d", 1, 0, -70, block(4), connected(1)},
+			{"e", 1, 0, -60, block(5), connected(1)},
+			{"f", 1, 0, 0, time.Time{}, connected(2)},
+		},
+		want: "e",
+	}, {
+		name: "longest connected protected",
+		candidates: []candidate{
+			{"group", 1, 0, 100, time.Time{}, connected(1)},
+			{"a", 1, 0, -100, time.Time{}, connected(4)},
+			{"b", 1, 0, -90, time.Time{}, connected(3)},
+			{"c", 1, 0, -80, time.Time{}, connected(2)},
+			{"d", 1, 0, -70, time.Time{}, connected(1)},
+		},
+		want: "c",
+	}, {
+		name: "all candidates protected",
+		candidates: []candidate{
+			{"a", 1, 0, -100, block(1), connected(1)},
+			{"b", 2, 0, -90, block(2), connected(1)},
+		},
+		want: "",
+	}}
+
+	for _, test := range tests {
+		candidates := make([]evictionCandidate, 0, len(test.candidates))
+		names := make(map[*serverPeer]string)
+		for _, c := range test.candidates {
+			sp := new(serverPeer)
+			names[sp] = c.name
+			candidates = append(candidates, evictionCandidate{
+				sp:            sp,
+				netGroupKey:   c.group,
+				pingMicros:    c.ping,
+				score:         c.score,
+				lastBlockTime: c.lastBlock,
+				connected:     c.connected,
+			})
+		}
+
+		var got string
+		if c := selectEvictionCandidate(candidates); c != nil {
+			got = names[c.sp]
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected candidate -- got %q, want %q",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestOutboundGroupsReserved ensures the remaining outbound connection slots
+// are only reserved for new network groups once there are no more of them than
+// the number of additional network groups needed to reach the minimum.
+func TestOutboundGroupsReserved(t *testing.T) {
+	tests := []struct {
+		name        string
+		numGroups   int
+		numOutbound int
+		target      int
+		minGroups   int
+		want        bool
+	}{
+		{"disabled", 0, 7, 8, 0, false},
+		{"no peers", 0, 0, 8, 4, false},
+		{"plenty of slots", 1, 3, 8, 4, false},
+		{"slots equal needed groups", 1, 5, 8, 4, true},
+		{"fewer slots than needed groups", 1, 6, 8, 4, true},
+		{"one slot one needed group", 3, 7, 8, 4, true},
+		{"minimum reached", 4, 7, 8, 4, false},
+		{"minimum exceeded", 6, 7, 8, 4, false},
+		{"no slots left", 2, 8, 8, 4, true},
+	}
+
+	for _, test := range tests {
+		got := outboundGroupsReserved(test.numGroups, test.numOutbound,
+			test.target, test.minGroups)
+		if got != test.want {
+			t.Fatalf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestRejectOutboundGroup ensures automatic outbound peers in network groups
+// that are already in use are only rejected when the remaining outbound slots
+// are reserved for new network groups and that persistent and whitelisted
+// peers are exempt.
+func TestRejectOutboundGroup(t *testing.T) {
+	newPeer := func(addr string) *serverPeer {
+		p, err := peer.NewOutboundPeer(&peer.Config{}, addr)
+		if err != nil {
+			t.Fatalf("unable to create peer %s: %v", addr, err)
+		}
+		return &serverPeer{Peer: p}
+	}
+	existing := newPeer("93.184.216.1:9108")
+	state := &peerState{
+		outboundPeers:  map[int32]*serverPeer{1: existing},
+		outboundGroups: map[string]int{addrmgr.GroupKey(existing.NA()): 1},
+	}
+
+	sameGroup := newPeer("93.184.216.2:9108")
+	newGroup := newPeer("151.101.1.1:9108")
+	persistent := newPeer("93.184.216.3:9108")
+	persistent.persistent = true
+	whitelisted := newPeer("93.184.216.4:9108")
+	whitelisted.isWhitelisted = true
+	inbound := &serverPeer{Peer: peer.NewInboundPeer(&peer.Config{})}
+
+	tests := []struct {
+		name      string
+		sp        *serverPeer
+		minGroups int
+		want      bool
+	}{
+		{"same group not reserved", sameGroup, 0, false},
+		{"same group reserved", sameGroup, 8, true},
+		{"new group reserved", newGroup, 8, false},
+		{"persistent reserved", persistent, 8, false},
+		{"whitelisted reserved", whitelisted, 8, false},
+		{"inbound reserved", inbound, 8, false},
+	}
+
+	for _, test := range tests {
+		got := state.rejectOutboundGroup(test.sp, defaultTargetOutbound,
+			test.minGroups)
+		if got != test.want {
+			t.Fatalf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestExceedsSameIPLimits ensures the limits on connections with the same IP
+// are enforced as expected including the interaction between the overall limit
+// and the inbound only limit.
+func TestExceedsSameIPLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		inbound      bool
+		conns        int
+		inboundConns int
+		maxSameIP    int
+		maxInbound   int
+		want         bool
+	}{
+		{"both disabled", true, 100, 100, 0, 0, false},
+		{"under overall limit", false, 4, 0, 5, 0, false},
+		{"at overall limit", false, 5, 0, 5, 0, true},
+		{"inbound at overall limit", true, 5, 5, 5, 0, true},
+		{"outbound ignores inbound limit", false, 2, 1, 5, 1, false},
+		{"inbound at inbound limit", true, 2, 1, 5, 1, true},
+		{"inbound under both limits", true, 2, 1, 5, 2, false},
+		{"inbound limit with overall disabled", true, 3, 3, 0, 3, true},
+		{"overall limit stricter than inbound", true, 3, 1, 3, 5, true},
+		{"outbound counted toward overall limit", true, 4, 0, 5, 1, false},
+	}
+
+	for _, test := range tests {
+		got := exceedsSameIPLimits(test.inbound, test.conns, test.inboundConns,
+			test.maxSameIP, test.maxInbound)
+		if got != test.want {
+			t.Fatalf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestDNSSeedHosts ensures the built-in and custom DNS seeds are merged into the
+// hosts passed to the DNS seeding function as expected.
+func TestDNSSeedHosts(t *testing.T) {
+	builtin := []chaincfg.DNSSeed{
+		{Host: "seed1.example.org", HasFiltering: true},
+		{Host: "seed2.example.org", HasFiltering: false},
+	}
+	custom := []string{"seed.private.example"}
+
+	tests := []struct {
+		name    string
+		builtin []chaincfg.DNSSeed
+		custom  []string
+		replace bool
+		filter  bool
+		want    []string
+	}{{
+		name:    "built-in only",
+		builtin: builtin,
+		want:    []string{"seed1.example.org", "seed2.example.org"},
+	}, {
+		name:    "custom seeds appended",
+		builtin: builtin,
+		custom:  custom,
+		want: []string{"seed1.example.org", "seed2.example.org",
+			"seed.private.example"},
+	}, {
+		name:    "custom seeds replace built-in",
+		builtin: builtin,
+		custom:  custom,
+		replace: true,
+		want:    []string{"seed.private.example"},
+	}, {
+		name:    "built-in without filtering skipped",
+		builtin: builtin,
+		custom:  custom,
+		filter:  true,
+		want:    []string{"seed1.example.org", "seed.private.example"},
+	}, {
+		name:    "no seeds",
+		replace: true,
+		want:    []string{},
+	}}
+
+	for _, test := range tests {
+		got := dnsSeedHosts(test.builtin, test.custom, test.replace,
+			test.filter)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Fatalf("%q: unexpected seeds -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestAdvertisedServices ensures the services advertised to peers are limited
+// to the services the node supports.
+func TestAdvertisedServices(t *testing.T) {
+	const full = wire.SFNodeNetwork | wire.SFNodeCF | wire.SFNodeCmpctBlock
+	const pruned = wire.SFNodeNetworkLimited | wire.SFNodeCF |
+		wire.SFNodeCmpctBlock
+	tests := []struct {
+		name       string
+		supported  wire.ServiceFlag
+		configured wire.ServiceFlag
+		want       wire.ServiceFlag
+		wantErr    bool
+	}{{
+		name:      "none configured",
+		supported: full,
+		want:      full,
+	}, {
+		name:       "subset of supported",
+		supported:  full,
+		configured: wire.SFNodeNetwork | wire.SFNodeCmpctBlock,
+		want:       wire.SFNodeNetwork | wire.SFNodeCmpctBlock,
+	}, {
+		name:       "full node as limited",
+		supported:  full,
+		configured: wire.SFNodeNetworkLimited | wire.SFNodeCF,
+		want:       wire.SFNodeNetworkLimited | wire.SFNodeCF,
+	}, {
+		name:       "pruned node as full",
+		supported:  pruned,
+		configured: wire.SFNodeNetwork,
+		wantErr:    true,
+	}, {
+		name:       "cf without cfilters",
+		supported:  wire.SFNodeNetwork | wire.SFNodeCmpctBlock,
+		configured: wire.SFNodeNetwork | wire.SFNodeCF,
+		wantErr:    true,
+	}, {
+		name:       "network and networklimited",
+		supported:  full,
+		configured: wire.SFNodeNetwork | wire.SFNodeNetworkLimited,
+		wantErr:    true,
+	}}
+
+	for _, test := range tests {
+		got, err := advertisedServices(test.supported, test.configured)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Fatalf("%q: unexpected services -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestOnionOnly ensures addresses that are not tor hidden services are refused
+// without being resolved or dialed when only connecting to hidden services is
+// enabled while hidden services are dialed via the onion proxy.
+func TestOnionOnly(t *testing.T) {
+	// The dial and lookup functions are intentionally not set for addresses
+	// that are not hidden services to ensure they are never invoked.
+	errOnionDial := errors.New("onion dial")
+	origCfg := cfg
+	cfg = &config{
+		OnionOnly: true,
+		oniondial: func(network, addr string) (net.Conn, error) {
+			return nil, errOnionDial
+		},
+	}
+	defer func() {
+		cfg = origCfg
+	}()
+
+	const onion = "abcdefghijklmnop.onion:9108"
+	refused := []string{"203.0.113.45:9108", "[2001:db8::1]:9108",
+		"seed.example.org:9108"}
+	for _, addr := range refused {
+		if _, err := addrStringToNetAddr(addr); err != errOnionOnly {
+			t.Fatalf("%q: unexpected address conversion error -- got %v, "+
+				"want %v", addr, err, errOnionOnly)
+		}
+		if _, err := dcrdDial("tcp", addr); err != errOnionOnly {
+			t.Fatalf("%q: unexpected dial error -- got %v, want %v", addr,
+				err, errOnionOnly)
+		}
+	}
+	if _, err := dcrdLookup("seed.example.org"); err != errOnionOnly {
+		t.Fatalf("unexpected lookup error -- got %v, want %v", err,
+			errOnionOnly)
+	}
+
+	// Ensure hidden services are passed through without being resolved and
+	// are dialed via the onion proxy.
+	netAddr, err := addrStringToNetAddr(onion)
+	if err != nil {
+		t.Fatalf("unexpected address conversion error: %v", err)
+	}
+	if netAddr.String() != onion {
+		t.Fatalf("unexpected address -- got %v, want %v", netAddr, onion)
+	}
+	if _, err := dcrdDial(netAddr.Network(), netAddr.String()); err != errOnionDial {
+		t.Fatalf("unexpected dial error -- got %v, want %v", err,
+			errOnionDial)
+	}
+	if _, err := addrStringToNetAddr("abcdefghijklmnop.onion:99999"); err == nil {
+		t.Fatal("did not refuse hidden service with an invalid port")
+	}
+}
+
+// TestDialFeeler ensures feeler dials are abandoned once they time out or the
+// server is shutting down and that connections established afterwards are
+// closed.
+func TestDialFeeler(t *testing.T) {
+	const addr = "203.0.113.45:9108"
+	quit := make(chan struct{})
+
+	// Ensure connections are returned when the dial completes in time.
+	local, remote := net.Pipe()
+	defer remote.Close()
+	dial := func(network, addr string) (net.Conn, error) {
+		return local, nil
+	}
+	conn, err := dialFeeler(dial, addr, time.Minute, quit)
+	if err != nil {
+		t.Fatalf("unexpected dial error: %v", err)
+	}
+	if conn != local {
+		t.Fatalf("unexpected conn -- got %v, want %v", conn, local)
+	}
+	local.Close()
+
+	// Ensure a pending dial is abandoned once the timeout elapses and the
+	// connection is closed when it eventually completes.
+	local, remote = net.Pipe()
+	defer remote.Close()
+	release := make(chan struct{})
+	dial = func(network, addr string) (net.Conn, error) {
+		<-release
+		return local, nil
+	}
+	if _, err := dialFeeler(dial, addr, time.Millisecond, quit); err == nil {
+		t.Fatal("dial did not time out")
+	}
+	close(release)
+	remote.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := remote.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("abandoned conn was not closed -- got %v, want %v", err,
+			io.EOF)
+	}
+
+	// Ensure a pending dial is abandoned when the quit channel is closed.
+	block := make(chan struct{})
+	defer close(block)
+	dial = func(network, addr string) (net.Conn, error) {
+		<-block
+		return nil, errors.New("dial failed")
+	}
+	close(quit)
+	if _, err := dialFeeler(dial, addr, time.Minute, quit); err == nil {
+		t.Fatal("dial was not abandoned on quit")
+	}
+}
+
+// TestOnVersionMinProtocolVersion ensures peers that advertise a protocol
+// version below the configured minimum are rejected with a reason that states
+// the required minimum while all others are accepted.
+func TestOnVersionMinProtocolVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		minVersion  uint32
+		version     int32
+		wantReject  bool
+		wantReason  string
+		wantAllowed bool
+	}{{
+		name:        "no minimum",
+		minVersion:  0,
+		version:     int32(wire.InitialProcotolVersion),
+		wantAllowed: true,
+	}, {
+		name:       "below minimum",
+		minVersion: wire.ProtocolVersion,
+		version:    int32(wire.ProtocolVersion - 1),
+		wantReject: true,
+		wantReason: fmt.Sprintf("protocol version must be %d or greater",
+			wire.ProtocolVersion),
+		wantAllowed: false,
+	}, {
+		name:        "at minimum",
+		minVersion:  wire.ProtocolVersion,
+		version:     int32(wire.ProtocolVersion),
+		wantAllowed: true,
+	}}
+
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+
+	for _, test := range tests {
+		cfg = &config{MinProtocolVersion: test.minVersion}
+
+		// Use an inbound feeler connection so the callback returns as soon as
+		// the remote peer is deemed acceptable.
+		sp := &serverPeer{
+			Peer:     peer.NewInboundPeer(&peer.Config{}),
+			server:   &server{},
+			isFeeler: true,
+		}
+		msg := &wire.MsgVersion{ProtocolVersion: test.version}
+		reject := sp.OnVersion(sp.Peer, msg)
+		if gotReject := reject != nil; gotReject != test.wantReject {
+			t.Fatalf("%q: unexpected reject -- got %v, want %v", test.name,
+				gotReject, test.wantReject)
+		}
+		if reject != nil {
+			if reject.Code != wire.RejectObsolete {
+				t.Fatalf("%q: unexpected reject code -- got %v, want %v",
+					test.name, reject.Code, wire.RejectObsolete)
+			}
+			if reject.Reason != test.wantReason {
+				t.Fatalf("%q: unexpected reject reason -- got %q, want %q",
+					test.name, reject.Reason, test.wantReason)
+			}
+		}
+		if sp.feelerGood != test.wantAllowed {
+			t.Fatalf("%q: unexpected acceptance -- got %v, want %v",
+				test.name, sp.feelerGood, test.wantAllowed)
+		}
+	}
+}
+
+// TestConnCountsQuery ensures the connection counts query reports the number
+// of connected inbound and outbound peers while ignoring peers that are not
+// connected.
+func TestConnCountsQuery(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer listener.Close()
+
+	// connectedPeer associates the provided peer with a loopback connection
+	// that remains open for the duration of the test.  The remote end never
+	// completes the handshake, so the peer remains connected until it is
+	// disconnected below.
+	var conns []net.Conn
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	connectedPeer := func(p *peer.Peer) *serverPeer {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("unable to dial: %v", err)
+		}
+		remote, err := listener.Accept()
+		if err != nil {
+			t.Fatalf("unable to accept: %v", err)
+		}
+		conns = append(conns, conn, remote)
+		p.AssociateConnection(conn)
+		return &serverPeer{Peer: p}
+	}
+	outboundPeer := func() *peer.Peer {
+		p, err := peer.NewOutboundPeer(&peer.Config{}, "127.0.0.1:9108")
+		if err != nil {
+			t.Fatalf("unable to create outbound peer: %v", err)
+		}
+		return p
+	}
+
+	disconnected := connectedPeer(peer.NewInboundPeer(&peer.Config{}))
+	disconnected.Disconnect()
+	state := &peerState{
+		inboundPeers: map[int32]*serverPeer{
+			1: connectedPeer(peer.NewInboundPeer(&peer.Config{})),
+			2: connectedPeer(peer.NewInboundPeer(&peer.Config{})),
+			3: disconnected,
+		},
+		outboundPeers: map[int32]*serverPeer{
+			4: connectedPeer(outboundPeer()),
+			5: {Peer: outboundPeer()},
+		},
+		persistentPeers: map[int32]*serverPeer{
+			6: connectedPeer(outboundPeer()),
+		},
+	}
+	defer func() {
+		state.forAllPeers(func(sp *serverPeer) {
+			sp.Disconnect()
+		})
+	}()
+
+	var s server
+	reply := make(chan connCounts, 1)
+	s.handleQuery(state, getConnCountsMsg{reply: reply})
+	want := connCounts{inbound: 2, outbound: 2}
+	if got := <-reply; got != want {
+		t.Fatalf("unexpected connection counts -- got %+v, want %+v", got,
+			want)
+	}
+}
+
+// TestOnGetDataMaxInv ensures peers that request more inventory than the
+// configured maximum via a single getdata message are banned unless they are
+// whitelisted.
+func TestOnGetDataMaxInv(t *testing.T) {
+	origCfg := cfg
+	cfg = &config{MaxGetDataInv: 2}
+	origBanThreshold := reloadable.BanThreshold()
+	origMaxPeers := reloadable.MaxPeers()
+	origMinRelayTxFee := reloadable.MinRelayTxFee()
+	reloadable.set(defaultBanThreshold, origMaxPeers, origMinRelayTxFee)
+	defer func() {
+		cfg = origCfg
+		reloadable.set(origBanThreshold, origMaxPeers, origMinRelayTxFee)
+	}()
+
+	tests := []struct {
+		name        string
+		whitelisted bool
+		wantBanned  bool
+	}{
+		{"not whitelisted", false, true},
+		{"whitelisted", true, false},
+	}
+
+	msg := wire.NewMsgGetData()
+	for i := 0; i < cfg.MaxGetDataInv+1; i++ {
+		hash := chainhash.Hash{byte(i)}
+		msg.AddInvVect(wire.NewInvVect(wire.InvTypeTx, &hash))
+	}
+	for _, test := range tests {
+		sp := &serverPeer{
+			Peer:          peer.NewInboundPeer(&peer.Config{}),
+			server:        &server{banPeers: make(chan *serverPeer, 1)},
+			isWhitelisted: test.whitelisted,
+		}
+		sp.OnGetData(sp.Peer, msg)
+
+		var banned bool
+		select {
+		case bannedPeer := <-sp.server.banPeers:
+			banned = bannedPeer == sp
+		default:
+		}
+		if banned != test.wantBanned {
+			t.Fatalf("%q: unexpected ban -- got %v, want %v", test.name,
+				banned, test.wantBanned)
+		}
+	}
+}
+
+// newTestTxPool returns a transaction pool that does not require a chain along
+// with the requested number of transactions that it accepts.  The transactions
+// are not added to the pool.
+func newTestTxPool(t *testing.T, numTxns int) (*mempool.TxPool, []*dcrutil.Tx) {
+	t.Helper()
+
+	// Create a funding transaction with an output for each transaction that
+	// is assumed to be confirmed in the main chain.
+	params := chaincfg.RegNetParams()
+	fundingTx := wire.NewMsgTx()
+	prevOut := wire.NewOutPoint(&chainhash.Hash{0x01}, 0, wire.TxTreeRegular)
+	fundingTx.AddTxIn(wire.NewTxIn(prevOut, wire.NullValueIn, nil))
+	for i := 0; i < numTxns; i++ {
+		fundingTx.AddTxOut(wire.NewTxOut(1e8, []byte{txscript.OP_TRUE}))
+	}
+	fundingHash := fundingTx.TxHash()
+	txns := make([]*dcrutil.Tx, 0, numTxns)
+	for i := 0; i < numTxns; i++ {
+		prevOut := wire.NewOutPoint(&fundingHash, uint32(i),
+			wire.TxTreeRegular)
+		tx := wire.NewMsgTx()
+		tx.AddTxIn(wire.NewTxIn(prevOut, 1e8, nil))
+		tx.AddTxOut(wire.NewTxOut(1e8-1e6, []byte{txscript.OP_TRUE}))
+		txns = append(txns, dcrutil.NewTx(tx))
+	}
+
+	bestHash := params.GenesisHash
+	txPool := mempool.New(&mempool.Config{
+		Policy: mempool.Policy{
+			MaxTxVersion:   wire.TxVersion,
+			AcceptNonStd:   true,
+			MaxSigOpsPerTx: 1000,
+			MinRelayTxFee:  mempool.DefaultMinRelayTxFee,
+			StandardVerifyFlags: func() (txscript.ScriptFlags, error) {
+				return 0, nil
+			},
+			AcceptSequenceLocks: func() (bool, error) {
+				return true, nil
+			},
+		},
+		ChainParams: params,
+		FetchUtxoView: func(*dcrutil.Tx, bool) (*blockchain.UtxoViewpoint, error) {
+			utxoView := blockchain.NewUtxoViewpoint()
+			utxoView.AddTxOuts(dcrutil.NewTx(fundingTx), 1, 1)
+			return utxoView, nil
+		},
+		BestHash:       func() *chainhash.Hash { return &bestHash },
+		BestHeight:     func() int64 { return 1 },
+		PastMedianTime: time.Now,
+		CalcSequenceLock: func(*dcrutil.Tx, *blockchain.UtxoViewpoint) (*blockchain.SequenceLock, error) {
+			return &blockchain.SequenceLock{MinHeight: -1, MinTime: -1}, nil
+		},
+		SubsidyCache: standalone.NewSubsidyCache(params),
+	})
+	return txPool, txns
+}
+
+// addTestTxns adds the passed transactions to the passed transaction pool.
+func addTestTxns(t *testing.T, txPool *mempool.TxPool, txns []*dcrutil.Tx) {
+	t.Helper()
+
+	for _, tx := range txns {
+		_, err := txPool.ProcessTransaction(tx, false, false, true)
+		if err != nil {
+			t.Fatalf("unable to add transaction %v: %v", tx.Hash(), err)
+		}
+	}
+}
+
+// pipeConn is an in-memory connection that reports a TCP remote address so it
+// can be associated with peers.
+type pipeConn struct {
+	net.Conn
+}
+
+// RemoteAddr returns a loopback TCP address for the connection.
+func (c pipeConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9108}
+}
+
+// connectTestPeer associates the passed inbound peer with one end of an
+// in-memory connection and completes the version handshake from the other
+// end, which is returned.  Since the connection is synchronous, messages
+// queued to the peer are only sent as the returned connection is read.
+func connectTestPeer(t *testing.T, p *peer.Peer, params *chaincfg.Params) net.Conn {
+	t.Helper()
+
+	local, remote := net.Pipe()
+	p.AssociateConnection(pipeConn{local})
+	na := wire.NewNetAddressIPPort(net.ParseIP("127.0.0.1"), 9108, 0)
+	version := wire.NewMsgVersion(na, na, 0, 0)
+	err := wire.WriteMessage(remote, version, wire.ProtocolVersion, params.Net)
+	if err != nil {
+		t.Fatalf("unable to write version: %v", err)
+	}
+	for _, want := range []string{wire.CmdVersion, wire.CmdVerAck} {
+		msg, _, err := wire.ReadMessage(remote, wire.ProtocolVersion,
+			params.Net)
+		if err != nil {
+			t.Fatalf("unable to read %s: %v", want, err)
+		}
+		if msg.Command() != want {
+			t.Fatalf("unexpected message -- got %s, want %s",
+				msg.Command(), want)
+		}
+	}
+	return remote
+}
+
+// TestOnGetDataMaxPending ensures getdata requests for more items than the
+// configured maximum number of pending items defer fetching the remaining
+// items until the earlier ones have been sent, are then fully served, and do
+// not block forever when the peer disconnects while waiting.
+func TestOnGetDataMaxPending(t *testing.T) {
+	origCfg := cfg
+	cfg = &config{MaxGetDataInv: 100, MaxGetDataPending: 2}
+	defer func() {
+		cfg = origCfg
+	}()
+
+	const numTxns = 5
+	params := chaincfg.RegNetParams()
+	newTestPeer := func(txPool *mempool.TxPool) (*serverPeer, net.Conn) {
+		p := peer.NewInboundPeer(&peer.Config{Net: params.Net})
+		sp := &serverPeer{
+			Peer:   p,
+			server: &server{txMemPool: txPool},
+		}
+		return sp, connectTestPeer(t, p, params)
+	}
+	getData := func(txns []*dcrutil.Tx) *wire.MsgGetData {
+		msg := wire.NewMsgGetData()
+		for _, tx := range txns {
+			msg.AddInvVect(wire.NewInvVect(wire.InvTypeTx, tx.Hash()))
+		}
+		return msg
+	}
+
+	// Request all of the transactions while only the first two are in the
+	// pool and the remote end is not reading, so sending the first one
+	// blocks.  The remaining transactions are only added to the pool once
+	// the request had the opportunity to fetch them, so they are only
+	// served when fetching them was deferred until the earlier ones were
+	// sent.
+	txPool, txns := newTestTxPool(t, numTxns)
+	addTestTxns(t, txPool, txns[:2])
+	sp, remote := newTestPeer(txPool)
+	defer remote.Close()
+	done := make(chan struct{})
+	go func() {
+		sp.OnGetData(sp.Peer, getData(txns))
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	addTestTxns(t, txPool, txns[2:])
+
+	remote.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for i := 0; i < numTxns; {
+		msg, _, err := wire.ReadMessage(remote, wire.ProtocolVersion,
+			params.Net)
+		if err != nil {
+			t.Fatalf("unable to read message: %v", err)
+		}
+		switch msg := msg.(type) {
+		case *wire.MsgTx:
+			if got, want := msg.TxHash(), *txns[i].Hash(); got != want {
+				t.Fatalf("unexpected tx %d -- got %v, want %v", i, got,
+					want)
+			}
+			i++
+		case *wire.MsgNotFound:
+			t.Fatalf("unexpected notfound for %d items after %d txns",
+				len(msg.InvList), i)
+		}
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("getdata request did not complete")
+	}
+	sp.Disconnect()
+
+	// Ensure a request that is waiting for earlier items to be sent
+	// completes once the peer disconnects.
+	txPool, txns = newTestTxPool(t, numTxns)
+	addTestTxns(t, txPool, txns)
+	sp, remote = newTestPeer(txPool)
+	defer remote.Close()
+	done = make(chan struct{})
+	go func() {
+		sp.OnGetData(sp.Peer, getData(txns))
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("getdata request completed without sending any items")
+	default:
+	}
+	sp.Disconnect()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("getdata request did not complete after disconnect")
+	}
+}
+
+// TestNoRelayTxTypes ensures transactions of types that are configured to not
+// be relayed are neither announced in response to mempool requests nor served
+// in response to getdata requests.
+func TestNoRelayTxTypes(t *testing.T) {
+	origCfg := cfg
+	defer func() {
+		cfg = origCfg
+	}()
+
+	const numTxns = 3
+	params := chaincfg.RegNetParams()
+	txPool, txns := newTestTxPool(t, numTxns)
+	addTestTxns(t, txPool, txns)
+	getData := wire.NewMsgGetData()
+	for _, tx := range txns {
+		getData.AddInvVect(wire.NewInvVect(wire.InvTypeTx, tx.Hash()))
+	}
+
+	tests := []struct {
+		name         string
+		noRelayTypes map[stake.TxType]struct{}
+		wantRelayed  bool
+	}{{
+		name:        "all types relayed",
+		wantRelayed: true,
+	}, {
+		name:         "other type not relayed",
+		noRelayTypes: map[stake.TxType]struct{}{stake.TxTypeSStx: {}},
+		wantRelayed:  true,
+	}, {
+		name:         "regular not relayed",
+		noRelayTypes: map[stake.TxType]struct{}{stake.TxTypeRegular: {}},
+		wantRelayed:  false,
+	}}
+	for _, test := range tests {
+		cfg = &config{
+			DisableBanning: true,
+			MaxGetDataInv:  100,
+			noRelayTxTypes: test.noRelayTypes,
+		}
+		p := peer.NewInboundPeer(&peer.Config{Net: params.Net})
+		sp := &serverPeer{Peer: p, server: &server{txMemPool: txPool}}
+		remote := connectTestPeer(t, p, params)
+
+		// Request the mempool contents followed by the transactions.  The
+		// responses are sent in order, so the first response is the
+		// notfound message when the mempool inventory is not announced.
+		done := make(chan struct{})
+		go func() {
+			sp.OnMemPool(p, wire.NewMsgMemPool())
+			sp.OnGetData(p, getData)
+			close(done)
+		}()
+		readMsg := func() wire.Message {
+			remote.SetReadDeadline(time.Now().Add(5 * time.Second))
+			msg, _, err := wire.ReadMessage(remote, wire.ProtocolVersion,
+				params.Net)
+			if err != nil {
+				t.Fatalf("%q: unable to read message: %v", test.name, err)
+			}
+			return msg
+		}
+		if test.wantRelayed {
+			msg, ok := readMsg().(*wire.MsgInv)
+			if !ok || len(msg.InvList) != numTxns {
+				t.Fatalf("%q: unexpected mempool response -- got %v, "+
+					"want inv with %d items", test.name, msg, numTxns)
+			}
+			for i := 0; i < numTxns; i++ {
+				if _, ok := readMsg().(*wire.MsgTx); !ok {
+					t.Fatalf("%q: tx %d was not served", test.name, i)
+				}
+			}
+		} else {
+			msg, ok := readMsg().(*wire.MsgNotFound)
+			if !ok || len(msg.InvList) != numTxns {
+				t.Fatalf("%q: unexpected response -- got %v, want "+
+					"notfound with %d items", test.name, msg, numTxns)
+			}
+		}
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%q: requests did not complete", test.name)
+		}
+		sp.Disconnect()
+		remote.Close()
+	}
+}
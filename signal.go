@@ -15,13 +15,27 @@ import (
 // subsystems using the same code paths as when an interrupt signal is received.
 var shutdownRequestChannel = make(chan struct{})
 
+// restartRequestChannel is used to initiate shutdown from one of the
+// subsystems in order for the process to be restarted by a process manager.
+var restartRequestChannel = make(chan struct{})
+
+// restartRequested indicates shutdown was initiated via restartRequestChannel
+// and therefore the process must exit with restartExitCode.  It is only set
+// prior to canceling the context returned by shutdownListener.
+var restartRequested bool
+
 // interruptSignals defines the default signals to catch in order to do a proper
 // shutdown.  This may be modified during init depending on the platform.
 var interruptSignals = []os.Signal{os.Interrupt}
 
+// reloadSignals defines the signals to catch in order to reload the subset of
+// the configuration that may be changed while running.  It is empty by default
+// and may be modified during init depending on the platform.
+var reloadSignals []os.Signal
+
 // shutdownListener listens for OS Signals such as SIGINT (Ctrl+C) and shutdown
-// requests from shutdownRequestChannel.  It returns a context that is canceled
-// when either signal is received.
+// requests from shutdownRequestChannel and restartRequestChannel.  It returns
+// a context that is canceled when any of them is received.
 func shutdownListener() context.Context {
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
@@ -35,6 +49,10 @@ func shutdownListener() context.Context {
 
 		case <-shutdownRequestChannel:
 			dcrdLog.Infof("Shutdown requested.  Shutting down...")
+
+		case <-restartRequestChannel:
+			dcrdLog.Infof("Restart requested.  Shutting down...")
+			restartRequested = true
 		}
 		cancel()
 
@@ -50,6 +68,10 @@ func shutdownListener() context.Context {
 			case <-shutdownRequestChannel:
 				dcrdLog.Info("Shutdown requested.  Already " +
 					"shutting down...")
+
+			case <-restartRequestChannel:
+				dcrdLog.Info("Restart requested.  Already " +
+					"shutting down...")
 			}
 		}
 	}()
@@ -69,3 +91,29 @@ func shutdownRequested(ctx context.Context) bool {
 
 	return false
 }
+
+// reloadListener listens for OS signals that request the configuration to be
+// reloaded and invokes the provided reload function for each one received
+// until the provided context is canceled.  It does nothing on platforms that do
+// not define any reload signals.
+func reloadListener(ctx context.Context, reload func()) {
+	if len(reloadSignals) == 0 {
+		return
+	}
+
+	reloadChannel := make(chan os.Signal, 1)
+	signal.Notify(reloadChannel, reloadSignals...)
+	defer signal.Stop(reloadChannel)
+
+	for {
+		select {
+		case sig := <-reloadChannel:
+			dcrdLog.Infof("Received signal (%s).  Reloading configuration...",
+				sig)
+			reload()
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
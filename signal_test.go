@@ -0,0 +1,36 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestShutdownListenerRestart ensures a restart request cancels the context
+// returned by shutdownListener and marks the restart as requested.
+func TestShutdownListenerRestart(t *testing.T) {
+	defer func() { restartRequested = false }()
+
+	ctx := shutdownListener()
+	if shutdownRequested(ctx) {
+		t.Fatal("context canceled before any request")
+	}
+
+	select {
+	case restartRequestChannel <- struct{}{}:
+	case <-time.After(time.Second):
+		t.Fatal("timeout sending restart request")
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not canceled after restart request")
+	}
+	if !restartRequested {
+		t.Fatal("restart not marked as requested")
+	}
+}
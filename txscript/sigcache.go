@@ -68,6 +68,17 @@ func (s *SigCache) Exists(sigHash chainhash.Hash, sig *secp256k1.Signature, pubK
 		bytes.Equal(entry.sig.Serialize(), sig.Serialize())
 }
 
+// Len returns the number of entries currently in the SigCache.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *SigCache) Len() int {
+	s.RLock()
+	numEntries := len(s.validSigs)
+	s.RUnlock()
+
+	return numEntries
+}
+
 // Add adds an entry for a signature over 'sigHash' under public key 'pubKey'
 // to the signature cache. In the event that the SigCache is 'full', an
 // existing entry is randomly chosen to be evicted in order to make space for
@@ -99,9 +99,9 @@ func TestSigCacheAddEvictEntry(t *testing.T) {
 	sigCache.Add(*msgNew, sigNew, keyNew)
 
 	// The sigcache should still have sigCache entries.
-	if uint(len(sigCache.validSigs)) != sigCacheSize {
+	if uint(sigCache.Len()) != sigCacheSize {
 		t.Fatalf("sigcache should now have %v entries, instead it has %v",
-			sigCacheSize, len(sigCache.validSigs))
+			sigCacheSize, sigCache.Len())
 	}
 
 	// The entry added above should be found within the sigcache.
@@ -55,6 +55,10 @@ const (
 	CmdCFilter        = "cfilter"
 	CmdCFHeaders      = "cfheaders"
 	CmdCFTypes        = "cftypes"
+	CmdCmpctBlock     = "cmpctblock"
+	CmdSendCmpct      = "sendcmpct"
+	CmdGetBlockTxns   = "getblocktxns"
+	CmdBlockTxns      = "blocktxns"
 )
 
 // Message is an interface that describes a Decred message.  A type that
@@ -151,6 +155,18 @@ func makeEmptyMessage(command string) (Message, error) {
 	case CmdCFTypes:
 		msg = &MsgCFTypes{}
 
+	case CmdCmpctBlock:
+		msg = &MsgCmpctBlock{}
+
+	case CmdSendCmpct:
+		msg = &MsgSendCmpct{}
+
+	case CmdGetBlockTxns:
+		msg = &MsgGetBlockTxns{}
+
+	case CmdBlockTxns:
+		msg = &MsgBlockTxns{}
+
 	default:
 		return nil, fmt.Errorf("unhandled command [%s]", command)
 	}
@@ -77,6 +77,10 @@ func TestMessage(t *testing.T) {
 	msgCFHeaders := NewMsgCFHeaders()
 	msgCFTypes := NewMsgCFTypes([]FilterType{GCSFilterExtended})
 	msgReject := NewMsgReject("block", RejectDuplicate, "duplicate block")
+	msgCmpctBlock := NewMsgCmpctBlock(&blockOne.Header, 123123)
+	msgSendCmpct := NewMsgSendCmpct()
+	msgGetBlockTxns := NewMsgGetBlockTxns(&chainhash.Hash{}, nil, nil)
+	msgBlockTxns := NewMsgBlockTxns(&chainhash.Hash{})
 
 	tests := []struct {
 		in     Message     // Value to encode
@@ -107,6 +111,10 @@ func TestMessage(t *testing.T) {
 		{msgCFilter, msgCFilter, pver, MainNet, 65},           // [24]
 		{msgCFHeaders, msgCFHeaders, pver, MainNet, 58},       // [25]
 		{msgCFTypes, msgCFTypes, pver, MainNet, 26},           // [26]
+		{msgCmpctBlock, msgCmpctBlock, pver, MainNet, 216},    // [27]
+		{msgSendCmpct, msgSendCmpct, pver, MainNet, 24},       // [28]
+		{msgGetBlockTxns, msgGetBlockTxns, pver, MainNet, 58}, // [29]
+		{msgBlockTxns, msgBlockTxns, pver, MainNet, 58},       // [30]
 	}
 
 	t.Logf("Running %d tests", len(tests))
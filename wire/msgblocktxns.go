@@ -0,0 +1,148 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// readBlockTxnsTree reads the transactions of a single transaction tree of a
+// blocktxns message from r.
+func readBlockTxnsTree(r io.Reader, pver uint32, tree string) ([]*MsgTx, error) {
+	// Prevent more transactions than could possibly fit into a transaction
+	// tree.  It would be possible to cause memory exhaustion and panics
+	// without a sane upper bound on the count.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxTxPerTree {
+		str := fmt.Sprintf("too many %s transactions for message "+
+			"[count %d, max %d]", tree, count, maxTxPerTree)
+		return nil, messageError("MsgBlockTxns.BtcDecode", str)
+	}
+
+	var txns []*MsgTx
+	if count > 0 {
+		txns = make([]*MsgTx, 0, count)
+	}
+	for i := uint64(0); i < count; i++ {
+		var tx MsgTx
+		if err := tx.BtcDecode(r, pver); err != nil {
+			return nil, err
+		}
+		txns = append(txns, &tx)
+	}
+
+	return txns, nil
+}
+
+// writeBlockTxnsTree writes the provided transactions of a single transaction
+// tree of a blocktxns message to w.
+func writeBlockTxnsTree(w io.Writer, pver uint32, txns []*MsgTx) error {
+	err := WriteVarInt(w, pver, uint64(len(txns)))
+	if err != nil {
+		return err
+	}
+	for _, tx := range txns {
+		if err := tx.BtcEncode(w, pver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MsgBlockTxns implements the Message interface and represents a Decred
+// blocktxns message.  It is used to deliver the transactions of a block
+// requested via a getblocktxns message in the same order they were requested.
+//
+// This message was not added until protocol versions starting with
+// CmpctBlockVersion.
+type MsgBlockTxns struct {
+	BlockHash     chainhash.Hash
+	Transactions  []*MsgTx
+	STransactions []*MsgTx
+}
+
+// AddTransaction adds a transaction from the regular transaction tree to the
+// message.
+func (msg *MsgBlockTxns) AddTransaction(tx *MsgTx) {
+	msg.Transactions = append(msg.Transactions, tx)
+}
+
+// AddSTransaction adds a transaction from the stake transaction tree to the
+// message.
+func (msg *MsgBlockTxns) AddSTransaction(tx *MsgTx) {
+	msg.STransactions = append(msg.STransactions, tx)
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgBlockTxns) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < CmpctBlockVersion {
+		str := fmt.Sprintf("blocktxns message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgBlockTxns.BtcDecode", str)
+	}
+
+	err := readElement(r, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+
+	msg.Transactions, err = readBlockTxnsTree(r, pver, "regular")
+	if err != nil {
+		return err
+	}
+
+	msg.STransactions, err = readBlockTxnsTree(r, pver, "stake")
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgBlockTxns) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < CmpctBlockVersion {
+		str := fmt.Sprintf("blocktxns message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgBlockTxns.BtcEncode", str)
+	}
+
+	err := writeElement(w, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+
+	err = writeBlockTxnsTree(w, pver, msg.Transactions)
+	if err != nil {
+		return err
+	}
+
+	return writeBlockTxnsTree(w, pver, msg.STransactions)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgBlockTxns) Command() string {
+	return CmdBlockTxns
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgBlockTxns) MaxPayloadLength(pver uint32) uint32 {
+	// The transactions can't be larger than the block they are from.
+	return chainhash.HashSize + MaxBlockPayload
+}
+
+// NewMsgBlockTxns returns a new Decred blocktxns message that conforms to the
+// Message interface using the passed parameters.  See MsgBlockTxns for
+// details.
+func NewMsgBlockTxns(blockHash *chainhash.Hash) *MsgBlockTxns {
+	return &MsgBlockTxns{BlockHash: *blockHash}
+}
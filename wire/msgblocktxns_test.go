@@ -0,0 +1,109 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestBlockTxns tests the MsgBlockTxns API and wire encoding.
+func TestBlockTxns(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "blocktxns"
+	blockHash := testBlock.BlockHash()
+	msg := NewMsgBlockTxns(&blockHash)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Fatalf("NewMsgBlockTxns: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+	for _, tx := range testBlock.Transactions {
+		msg.AddTransaction(tx)
+	}
+	for _, stx := range testBlock.STransactions {
+		msg.AddSTransaction(stx)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol "+
+			"version %d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+
+	// Ensure the message round trips through the wire encoding.
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, pver); err != nil {
+		t.Fatalf("BtcEncode: %v", err)
+	}
+	if uint32(buf.Len()) > maxPayload {
+		t.Fatalf("BtcEncode: encoded size %d exceeds max payload %d",
+			buf.Len(), maxPayload)
+	}
+	serialized := buf.Bytes()
+	var decoded MsgBlockTxns
+	err := decoded.BtcDecode(bytes.NewReader(serialized), pver)
+	if err != nil {
+		t.Fatalf("BtcDecode: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, msg) {
+		t.Fatalf("BtcDecode: mismatched message - got %v, want %v",
+			spew.Sdump(&decoded), spew.Sdump(msg))
+	}
+
+	// Ensure encoding and decoding fail for protocol versions prior to the
+	// introduction of the message.
+	oldPver := CmpctBlockVersion - 1
+	if err := msg.BtcEncode(&buf, oldPver); err == nil {
+		t.Fatal("BtcEncode: did not fail for old protocol version")
+	}
+	err = decoded.BtcDecode(bytes.NewReader(serialized), oldPver)
+	if err == nil {
+		t.Fatal("BtcDecode: did not fail for old protocol version")
+	}
+
+	// Ensure decoding fails for truncated messages.
+	for i := 0; i < len(serialized); i += 7 {
+		var truncated MsgBlockTxns
+		err := truncated.BtcDecode(bytes.NewReader(serialized[:i]), pver)
+		if err == nil {
+			t.Fatalf("BtcDecode: did not fail for truncated message of "+
+				"length %d", i)
+		}
+	}
+
+	// Ensure decoding fails for more transactions than fit in a transaction
+	// tree.
+	var tooMany bytes.Buffer
+	tooMany.Write(blockHash[:])
+	WriteVarInt(&tooMany, pver, MaxTxPerTxTree(pver)+1)
+	err = decoded.BtcDecode(bytes.NewReader(tooMany.Bytes()), pver)
+	if _, ok := err.(*MessageError); !ok {
+		t.Fatalf("BtcDecode: unexpected error for too many transactions - "+
+			"got %v, want MessageError", err)
+	}
+
+	// Ensure an empty message round trips.
+	empty := NewMsgBlockTxns(&chainhash.Hash{})
+	buf.Reset()
+	if err := empty.BtcEncode(&buf, pver); err != nil {
+		t.Fatalf("BtcEncode: %v", err)
+	}
+	var decodedEmpty MsgBlockTxns
+	if err := decodedEmpty.BtcDecode(&buf, pver); err != nil {
+		t.Fatalf("BtcDecode: %v", err)
+	}
+	if !reflect.DeepEqual(&decodedEmpty, empty) {
+		t.Fatalf("BtcDecode: mismatched message - got %v, want %v",
+			spew.Sdump(&decodedEmpty), spew.Sdump(empty))
+	}
+}
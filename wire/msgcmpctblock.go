@@ -0,0 +1,316 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// CmpctBlockShortIDSize is the number of bytes used to encode a short
+	// transaction ID in a compact block.
+	CmpctBlockShortIDSize = 6
+
+	// cmpctBlockShortIDMask is the mask used to truncate short transaction
+	// IDs to the number of bits that are encoded.
+	cmpctBlockShortIDMask = 1<<(CmpctBlockShortIDSize*8) - 1
+)
+
+// PrefilledTx houses a transaction that is included in full in a compact block
+// along with its index in the transaction tree of the block.
+type PrefilledTx struct {
+	Index uint32
+	Tx    *MsgTx
+}
+
+// MsgCmpctBlock implements the Message interface and represents a Decred
+// cmpctblock message.  It is used to relay a block by sending its header along
+// with short transaction IDs for the transactions the receiver is expected to
+// already have, such as those in its memory pool, and the full transactions
+// for the rest.
+//
+// The prefilled transactions of each transaction tree must be in order of
+// increasing index.  The short IDs represent the remaining transactions of the
+// tree in order, such that the position of each transaction is the first index
+// not already taken by a prefilled transaction.
+//
+// Use the AddShortID, AddPrefilledTx, AddSTxShortID, and AddPrefilledSTx
+// functions to build up the transaction trees.
+//
+// This message was not added until protocol versions starting with
+// CmpctBlockVersion.
+type MsgCmpctBlock struct {
+	Header         BlockHeader
+	Nonce          uint64
+	ShortIDs       []uint64
+	PrefilledTxns  []PrefilledTx
+	STxShortIDs    []uint64
+	PrefilledSTxns []PrefilledTx
+}
+
+// AddShortID adds a short transaction ID for the regular transaction tree to
+// the message.
+func (msg *MsgCmpctBlock) AddShortID(shortID uint64) {
+	msg.ShortIDs = append(msg.ShortIDs, shortID&cmpctBlockShortIDMask)
+}
+
+// AddPrefilledTx adds a prefilled transaction for the regular transaction tree
+// to the message.
+func (msg *MsgCmpctBlock) AddPrefilledTx(index uint32, tx *MsgTx) {
+	msg.PrefilledTxns = append(msg.PrefilledTxns, PrefilledTx{index, tx})
+}
+
+// AddSTxShortID adds a short transaction ID for the stake transaction tree to
+// the message.
+func (msg *MsgCmpctBlock) AddSTxShortID(shortID uint64) {
+	msg.STxShortIDs = append(msg.STxShortIDs, shortID&cmpctBlockShortIDMask)
+}
+
+// AddPrefilledSTx adds a prefilled transaction for the stake transaction tree
+// to the message.
+func (msg *MsgCmpctBlock) AddPrefilledSTx(index uint32, tx *MsgTx) {
+	msg.PrefilledSTxns = append(msg.PrefilledSTxns, PrefilledTx{index, tx})
+}
+
+// NumTxns returns the total number of regular transactions in the block the
+// message represents.
+func (msg *MsgCmpctBlock) NumTxns() int {
+	return len(msg.ShortIDs) + len(msg.PrefilledTxns)
+}
+
+// NumSTxns returns the total number of stake transactions in the block the
+// message represents.
+func (msg *MsgCmpctBlock) NumSTxns() int {
+	return len(msg.STxShortIDs) + len(msg.PrefilledSTxns)
+}
+
+// readCmpctTxTree reads the short IDs and prefilled transactions of a single
+// transaction tree of a compact block from r.
+func readCmpctTxTree(r io.Reader, pver uint32, tree string) ([]uint64, []PrefilledTx, error) {
+	// Prevent more short IDs or prefilled transactions than could possibly
+	// fit into a transaction tree.  It would be possible to cause memory
+	// exhaustion and panics without a sane upper bound on the counts.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count > maxTxPerTree {
+		str := fmt.Sprintf("too many %s short ids to fit into a block "+
+			"[count %d, max %d]", tree, count, maxTxPerTree)
+		return nil, nil, messageError("MsgCmpctBlock.BtcDecode", str)
+	}
+	var shortIDs []uint64
+	if count > 0 {
+		shortIDs = make([]uint64, 0, count)
+	}
+	var shortIDBytes [8]byte
+	for i := uint64(0); i < count; i++ {
+		_, err := io.ReadFull(r, shortIDBytes[:CmpctBlockShortIDSize])
+		if err != nil {
+			return nil, nil, err
+		}
+		shortIDs = append(shortIDs, littleEndian.Uint64(shortIDBytes[:]))
+	}
+
+	count, err = ReadVarInt(r, pver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count+uint64(len(shortIDs)) > maxTxPerTree {
+		str := fmt.Sprintf("too many %s transactions to fit into a "+
+			"block [count %d, max %d]", tree, count+uint64(len(shortIDs)),
+			maxTxPerTree)
+		return nil, nil, messageError("MsgCmpctBlock.BtcDecode", str)
+	}
+	var prefilled []PrefilledTx
+	if count > 0 {
+		prefilled = make([]PrefilledTx, 0, count)
+	}
+	for i := uint64(0); i < count; i++ {
+		index, err := binarySerializer.Uint32(r, littleEndian)
+		if err != nil {
+			return nil, nil, err
+		}
+		var tx MsgTx
+		if err := tx.BtcDecode(r, pver); err != nil {
+			return nil, nil, err
+		}
+		prefilled = append(prefilled, PrefilledTx{index, &tx})
+	}
+
+	return shortIDs, prefilled, nil
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < CmpctBlockVersion {
+		str := fmt.Sprintf("cmpctblock message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgCmpctBlock.BtcDecode", str)
+	}
+
+	err := readBlockHeader(r, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+
+	msg.Nonce, err = binarySerializer.Uint64(r, littleEndian)
+	if err != nil {
+		return err
+	}
+
+	msg.ShortIDs, msg.PrefilledTxns, err = readCmpctTxTree(r, pver,
+		"regular")
+	if err != nil {
+		return err
+	}
+
+	msg.STxShortIDs, msg.PrefilledSTxns, err = readCmpctTxTree(r, pver,
+		"stake")
+	return err
+}
+
+// writeCmpctTxTree writes the short IDs and prefilled transactions of a single
+// transaction tree of a compact block to w.
+func writeCmpctTxTree(w io.Writer, pver uint32, shortIDs []uint64, prefilled []PrefilledTx) error {
+	err := WriteVarInt(w, pver, uint64(len(shortIDs)))
+	if err != nil {
+		return err
+	}
+	var shortIDBytes [8]byte
+	for _, shortID := range shortIDs {
+		littleEndian.PutUint64(shortIDBytes[:], shortID)
+		_, err := w.Write(shortIDBytes[:CmpctBlockShortIDSize])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = WriteVarInt(w, pver, uint64(len(prefilled)))
+	if err != nil {
+		return err
+	}
+	for _, ptx := range prefilled {
+		err := binarySerializer.PutUint32(w, littleEndian, ptx.Index)
+		if err != nil {
+			return err
+		}
+		if err := ptx.Tx.BtcEncode(w, pver); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < CmpctBlockVersion {
+		str := fmt.Sprintf("cmpctblock message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgCmpctBlock.BtcEncode", str)
+	}
+
+	err := writeBlockHeader(w, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+
+	err = binarySerializer.PutUint64(w, littleEndian, msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	err = writeCmpctTxTree(w, pver, msg.ShortIDs, msg.PrefilledTxns)
+	if err != nil {
+		return err
+	}
+
+	return writeCmpctTxTree(w, pver, msg.STxShortIDs, msg.PrefilledSTxns)
+}
+
+// Bytes returns the serialized form of the compact block in bytes.
+func (msg *MsgCmpctBlock) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgCmpctBlock) Command() string {
+	return CmdCmpctBlock
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) MaxPayloadLength(pver uint32) uint32 {
+	// A compact block is never larger than the block it represents aside
+	// from the nonce and prefilled transaction indices.
+	return MaxBlockPayload + 8 + uint32(MaxTxPerTxTree(pver)*2*4)
+}
+
+// CmpctBlockShortIDKey returns the key used to derive the short transaction
+// IDs of a compact block with the provided header and nonce.  Keying the short
+// IDs by the block and a random nonce prevents colliding short IDs from being
+// computed ahead of time.
+func CmpctBlockShortIDKey(header *BlockHeader, nonce uint64) chainhash.Hash {
+	buf := bytes.NewBuffer(make([]byte, 0, MaxBlockHeaderPayload+8))
+	// The errors are ignored here since writing to a bytes buffer can't
+	// fail.
+	_ = writeBlockHeader(buf, 0, header)
+	_ = binarySerializer.PutUint64(buf, littleEndian, nonce)
+	return chainhash.HashH(buf.Bytes())
+}
+
+// CmpctBlockShortID returns the short transaction ID of the transaction with
+// the provided hash for a compact block with the given short ID key.
+func CmpctBlockShortID(key, txHash *chainhash.Hash) uint64 {
+	var buf [chainhash.HashSize * 2]byte
+	copy(buf[:], key[:])
+	copy(buf[chainhash.HashSize:], txHash[:])
+	hash := chainhash.HashH(buf[:])
+	return littleEndian.Uint64(hash[:8]) & cmpctBlockShortIDMask
+}
+
+// NewMsgCmpctBlock returns a new Decred cmpctblock message that conforms to
+// the Message interface.  See MsgCmpctBlock for details.
+func NewMsgCmpctBlock(header *BlockHeader, nonce uint64) *MsgCmpctBlock {
+	return &MsgCmpctBlock{
+		Header: *header,
+		Nonce:  nonce,
+	}
+}
+
+// NewMsgCmpctBlockFromBlock returns a new Decred cmpctblock message that
+// represents the provided block using the given nonce.  The coinbase is
+// prefilled since the receiver can't possibly already have it, while all other
+// transactions are represented by their short transaction IDs.
+func NewMsgCmpctBlockFromBlock(block *MsgBlock, nonce uint64) *MsgCmpctBlock {
+	msg := NewMsgCmpctBlock(&block.Header, nonce)
+	key := CmpctBlockShortIDKey(&block.Header, nonce)
+	for i, tx := range block.Transactions {
+		if i == 0 {
+			msg.AddPrefilledTx(0, tx)
+			continue
+		}
+		txHash := tx.TxHash()
+		msg.AddShortID(CmpctBlockShortID(&key, &txHash))
+	}
+	for _, stx := range block.STransactions {
+		txHash := stx.TxHash()
+		msg.AddSTxShortID(CmpctBlockShortID(&key, &txHash))
+	}
+	return msg
+}
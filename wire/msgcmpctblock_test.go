@@ -0,0 +1,107 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestCmpctBlock tests the MsgCmpctBlock API.
+func TestCmpctBlock(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "cmpctblock"
+	msg := NewMsgCmpctBlockFromBlock(&testBlock, 0x0102030405060708)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Fatalf("NewMsgCmpctBlock: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure the coinbase is prefilled and all other transactions are
+	// represented by their short ids.
+	if len(msg.PrefilledTxns) != 1 || msg.PrefilledTxns[0].Index != 0 ||
+		msg.PrefilledTxns[0].Tx != testBlock.Transactions[0] {
+
+		t.Fatalf("NewMsgCmpctBlockFromBlock: coinbase not prefilled - got %v",
+			spew.Sdump(msg.PrefilledTxns))
+	}
+	if msg.NumTxns() != len(testBlock.Transactions) {
+		t.Fatalf("NewMsgCmpctBlockFromBlock: wrong number of txns - got %d, "+
+			"want %d", msg.NumTxns(), len(testBlock.Transactions))
+	}
+	if msg.NumSTxns() != len(testBlock.STransactions) {
+		t.Fatalf("NewMsgCmpctBlockFromBlock: wrong number of stake txns - "+
+			"got %d, want %d", msg.NumSTxns(), len(testBlock.STransactions))
+	}
+	key := CmpctBlockShortIDKey(&testBlock.Header, msg.Nonce)
+	for i, stx := range testBlock.STransactions {
+		txHash := stx.TxHash()
+		want := CmpctBlockShortID(&key, &txHash)
+		if msg.STxShortIDs[i] != want {
+			t.Fatalf("NewMsgCmpctBlockFromBlock: wrong short id %d - got "+
+				"%x, want %x", i, msg.STxShortIDs[i], want)
+		}
+		if want >= 1<<(CmpctBlockShortIDSize*8) {
+			t.Fatalf("CmpctBlockShortID: short id %x is too large", want)
+		}
+	}
+
+	// Ensure the short id key depends on the nonce.
+	otherKey := CmpctBlockShortIDKey(&testBlock.Header, msg.Nonce+1)
+	if key == otherKey {
+		t.Fatal("CmpctBlockShortIDKey: key does not depend on the nonce")
+	}
+
+	// Ensure the message round trips through the wire encoding.
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, pver); err != nil {
+		t.Fatalf("BtcEncode: %v", err)
+	}
+	if uint32(buf.Len()) > msg.MaxPayloadLength(pver) {
+		t.Fatalf("BtcEncode: encoded size %d exceeds max payload %d",
+			buf.Len(), msg.MaxPayloadLength(pver))
+	}
+	serialized, err := msg.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if !bytes.Equal(serialized, buf.Bytes()) {
+		t.Fatal("Bytes: serialized bytes do not match encoding")
+	}
+	var decoded MsgCmpctBlock
+	if err := decoded.BtcDecode(bytes.NewReader(buf.Bytes()), pver); err != nil {
+		t.Fatalf("BtcDecode: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, msg) {
+		t.Fatalf("BtcDecode: mismatched message - got %v, want %v",
+			spew.Sdump(&decoded), spew.Sdump(msg))
+	}
+
+	// Ensure encoding and decoding fail for protocol versions prior to the
+	// introduction of the message.
+	oldPver := CmpctBlockVersion - 1
+	if err := msg.BtcEncode(&buf, oldPver); err == nil {
+		t.Fatal("BtcEncode: did not fail for old protocol version")
+	}
+	err = decoded.BtcDecode(bytes.NewReader(serialized), oldPver)
+	if err == nil {
+		t.Fatal("BtcDecode: did not fail for old protocol version")
+	}
+
+	// Ensure decoding fails for truncated messages.
+	for i := 0; i < len(serialized); i += 7 {
+		var truncated MsgCmpctBlock
+		err := truncated.BtcDecode(bytes.NewReader(serialized[:i]), pver)
+		if err == nil {
+			t.Fatalf("BtcDecode: did not fail for truncated message of "+
+				"length %d", i)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// readTxIndexes reads a list of transaction indexes for the provided
+// transaction tree from r.
+func readTxIndexes(r io.Reader, pver uint32, tree string) ([]uint32, error) {
+	// Prevent more indexes than could possibly fit into a transaction tree.
+	// It would be possible to cause memory exhaustion and panics without a
+	// sane upper bound on the count.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxTxPerTree {
+		str := fmt.Sprintf("too many %s transaction indexes for message "+
+			"[count %d, max %d]", tree, count, maxTxPerTree)
+		return nil, messageError("MsgGetBlockTxns.BtcDecode", str)
+	}
+
+	var indexes []uint32
+	if count > 0 {
+		indexes = make([]uint32, 0, count)
+	}
+	for i := uint64(0); i < count; i++ {
+		index, err := ReadVarInt(r, pver)
+		if err != nil {
+			return nil, err
+		}
+		if index >= maxTxPerTree {
+			str := fmt.Sprintf("%s transaction index %d is out of range "+
+				"[max %d]", tree, index, maxTxPerTree-1)
+			return nil, messageError("MsgGetBlockTxns.BtcDecode", str)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+
+	return indexes, nil
+}
+
+// writeTxIndexes writes the provided list of transaction indexes to w.
+func writeTxIndexes(w io.Writer, pver uint32, indexes []uint32) error {
+	err := WriteVarInt(w, pver, uint64(len(indexes)))
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if err := WriteVarInt(w, pver, uint64(index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MsgGetBlockTxns implements the Message interface and represents a Decred
+// getblocktxns message.  It is used to request the transactions of a block
+// that could not be reconstructed from a cmpctblock message.  The remote peer
+// responds with a blocktxns message.
+//
+// The indexes are the positions of the requested transactions within the
+// regular and stake transaction trees of the block, respectively, and must be
+// in order of increasing index.
+//
+// This message was not added until protocol versions starting with
+// CmpctBlockVersion.
+type MsgGetBlockTxns struct {
+	BlockHash  chainhash.Hash
+	TxIndexes  []uint32
+	STxIndexes []uint32
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxns) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < CmpctBlockVersion {
+		str := fmt.Sprintf("getblocktxns message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgGetBlockTxns.BtcDecode", str)
+	}
+
+	err := readElement(r, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+
+	msg.TxIndexes, err = readTxIndexes(r, pver, "regular")
+	if err != nil {
+		return err
+	}
+
+	msg.STxIndexes, err = readTxIndexes(r, pver, "stake")
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxns) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < CmpctBlockVersion {
+		str := fmt.Sprintf("getblocktxns message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgGetBlockTxns.BtcEncode", str)
+	}
+
+	err := writeElement(w, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+
+	err = writeTxIndexes(w, pver, msg.TxIndexes)
+	if err != nil {
+		return err
+	}
+
+	return writeTxIndexes(w, pver, msg.STxIndexes)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgGetBlockTxns) Command() string {
+	return CmdGetBlockTxns
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxns) MaxPayloadLength(pver uint32) uint32 {
+	// Block hash + 2 * (num indexes (varInt) + max indexes * max varint
+	// size).
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	return chainhash.HashSize + 2*(MaxVarIntPayload+
+		uint32(maxTxPerTree)*MaxVarIntPayload)
+}
+
+// NewMsgGetBlockTxns returns a new Decred getblocktxns message that conforms
+// to the Message interface using the passed parameters.  See MsgGetBlockTxns
+// for details.
+func NewMsgGetBlockTxns(blockHash *chainhash.Hash, txIndexes, stxIndexes []uint32) *MsgGetBlockTxns {
+	return &MsgGetBlockTxns{
+		BlockHash:  *blockHash,
+		TxIndexes:  txIndexes,
+		STxIndexes: stxIndexes,
+	}
+}
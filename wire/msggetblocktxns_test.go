@@ -0,0 +1,94 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestGetBlockTxns tests the MsgGetBlockTxns API and wire encoding.
+func TestGetBlockTxns(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "getblocktxns"
+	blockHash := chainhash.Hash{0x01, 0x02}
+	msg := NewMsgGetBlockTxns(&blockHash, []uint32{1, 2, 300}, []uint32{0})
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Fatalf("NewMsgGetBlockTxns: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol "+
+			"version %d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+
+	// Ensure the message encodes to the expected bytes and round trips.
+	wantEncoded := append(blockHash[:], []byte{
+		0x03,             // Varint for number of regular indexes
+		0x01,             // Regular index 1
+		0x02,             // Regular index 2
+		0xfd, 0x2c, 0x01, // Regular index 300
+		0x01, // Varint for number of stake indexes
+		0x00, // Stake index 0
+	}...)
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, pver); err != nil {
+		t.Fatalf("BtcEncode: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wantEncoded) {
+		t.Fatalf("BtcEncode: got %s want %s", spew.Sdump(buf.Bytes()),
+			spew.Sdump(wantEncoded))
+	}
+	var decoded MsgGetBlockTxns
+	err := decoded.BtcDecode(bytes.NewReader(wantEncoded), pver)
+	if err != nil {
+		t.Fatalf("BtcDecode: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, msg) {
+		t.Fatalf("BtcDecode: mismatched message - got %v, want %v",
+			spew.Sdump(&decoded), spew.Sdump(msg))
+	}
+
+	// Ensure encoding and decoding fail for protocol versions prior to the
+	// introduction of the message.
+	oldPver := CmpctBlockVersion - 1
+	if err := msg.BtcEncode(&buf, oldPver); err == nil {
+		t.Fatal("BtcEncode: did not fail for old protocol version")
+	}
+	err = decoded.BtcDecode(bytes.NewReader(wantEncoded), oldPver)
+	if err == nil {
+		t.Fatal("BtcDecode: did not fail for old protocol version")
+	}
+
+	// Ensure decoding fails for truncated messages.
+	for i := 0; i < len(wantEncoded); i++ {
+		var truncated MsgGetBlockTxns
+		err := truncated.BtcDecode(bytes.NewReader(wantEncoded[:i]), pver)
+		if err == nil {
+			t.Fatalf("BtcDecode: did not fail for truncated message of "+
+				"length %d", i)
+		}
+	}
+
+	// Ensure decoding fails for more indexes than fit in a transaction tree.
+	var tooMany bytes.Buffer
+	tooMany.Write(blockHash[:])
+	WriteVarInt(&tooMany, pver, MaxTxPerTxTree(pver)+1)
+	err = decoded.BtcDecode(bytes.NewReader(tooMany.Bytes()), pver)
+	if _, ok := err.(*MessageError); !ok {
+		t.Fatalf("BtcDecode: unexpected error for too many indexes - got "+
+			"%v, want MessageError", err)
+	}
+}
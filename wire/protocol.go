@@ -17,7 +17,7 @@ const (
 	InitialProcotolVersion uint32 = 1
 
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 6
+	ProtocolVersion uint32 = 7
 
 	// NodeBloomVersion is the protocol version which added the SFNodeBloom
 	// service flag (unused).
@@ -39,6 +39,11 @@ const (
 	// flag and the cfheaders, cfilter, cftypes, getcfheaders, getcfilter and
 	// getcftypes messages.
 	NodeCFVersion uint32 = 6
+
+	// CmpctBlockVersion is the protocol version which adds the
+	// SFNodeCmpctBlock service flag and the cmpctblock, sendcmpct,
+	// getblocktxns, and blocktxns messages.
+	CmpctBlockVersion uint32 = 7
 )
 
 // ServiceFlag identifies services supported by a Decred peer.
@@ -55,13 +60,24 @@ const (
 	// SFNodeCF is a flag used to indicate a peer supports committed
 	// filters (CFs).
 	SFNodeCF
+
+	// SFNodeCmpctBlock is a flag used to indicate a peer supports compact
+	// block relay.
+	SFNodeCmpctBlock
+
+	// SFNodeNetworkLimited is a flag used to indicate a peer only serves a
+	// limited number of the most recent blocks as opposed to the entire
+	// chain.  It is not set in conjunction with SFNodeNetwork.
+	SFNodeNetworkLimited
 )
 
 // Map of service flags back to their constant names for pretty printing.
 var sfStrings = map[ServiceFlag]string{
-	SFNodeNetwork: "SFNodeNetwork",
-	SFNodeBloom:   "SFNodeBloom",
-	SFNodeCF:      "SFNodeCF",
+	SFNodeNetwork:        "SFNodeNetwork",
+	SFNodeBloom:          "SFNodeBloom",
+	SFNodeCF:             "SFNodeCF",
+	SFNodeCmpctBlock:     "SFNodeCmpctBlock",
+	SFNodeNetworkLimited: "SFNodeNetworkLimited",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to
@@ -70,6 +86,8 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeNetwork,
 	SFNodeBloom,
 	SFNodeCF,
+	SFNodeCmpctBlock,
+	SFNodeNetworkLimited,
 }
 
 // String returns the ServiceFlag in human-readable form.